@@ -1,29 +1,51 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/kubewall/kubewall/backend/config"
 	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/metrics"
 	"github.com/kubewall/kubewall/backend/routes"
+	"github.com/kubewall/kubewall/backend/streams"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func init() {
 	rootCmd.PersistentFlags().String("certFile", "", "absolute path to certificate file")
 	rootCmd.PersistentFlags().String("keyFile", "", "absolute path to key file")
+	rootCmd.PersistentFlags().Bool("self-signed-cert", false, "serve HTTPS with a certificate generated at startup for localhost")
+	rootCmd.PersistentFlags().String("http-redirect", "", "address to serve plain HTTP redirects to HTTPS on (e.g., :7081), requires TLS")
 	rootCmd.PersistentFlags().StringP("port", "p", ":7080", "port to listen on [deprecated, use --listen instead]")
 	rootCmd.PersistentFlags().StringP("listen", "l", "[::]:7080", "IP and port to listen on (e.g., localhost:7080, :7080, or [::]:7080)")
 	rootCmd.PersistentFlags().Int("k8s-client-qps", 100, "maximum QPS to the master from client")
 	rootCmd.PersistentFlags().Int("k8s-client-burst", 200, "Maximum burst for throttle")
 	rootCmd.PersistentFlags().Bool("no-open-browser", false, "Do not open the default browser")
+	rootCmd.PersistentFlags().StringSlice("exclude-namespaces", nil, "namespaces hidden from lists by default (e.g., kube-system,kube-node-lease)")
+	rootCmd.PersistentFlags().StringArray("list-fields", nil, "fields sent in list responses of a kind, repeatable (e.g., Pod=name,namespace,status)")
+	rootCmd.PersistentFlags().Int("max-sse-connections", 500, "maximum concurrent event streams, 0 for unlimited")
+	rootCmd.PersistentFlags().Duration("sse-heartbeat-interval", 15*time.Second, "interval of keep-alive comments on idle event streams, 0 to disable")
+	rootCmd.PersistentFlags().String("max-body-size", "10Mi", "maximum request body size, larger uploads are rejected, 0 for unlimited (e.g., 10Mi, 50M)")
+	rootCmd.PersistentFlags().Bool("enable-node-debug", false, "allow starting privileged debug pods on nodes, every session is audit logged")
+	rootCmd.PersistentFlags().StringSlice("node-debug-images", nil, "images node debug pods may use besides busybox:1.36 (e.g., nicolaka/netshoot:v0.13)")
+	rootCmd.PersistentFlags().Bool("audit-exec-transcript", false, "add the truncated output of exec sessions to their audit log entries")
+	rootCmd.PersistentFlags().StringSlice("allow-resources", nil, "only serve these resources from the generic resource endpoints (e.g., deployments.apps,*.cert-manager.io)")
+	rootCmd.PersistentFlags().StringSlice("block-resources", nil, "never serve these resources from the generic resource endpoints (e.g., secrets,*.vault.example.com)")
+	rootCmd.PersistentFlags().Bool("warm-clients", false, "run API discovery in the background when a kubeconfig is added or reloaded, so the first request to its clusters is fast")
 }
 
 var rootCmd = &cobra.Command{
@@ -82,17 +104,114 @@ func Serve(cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
+	selfSigned, err := cmd.Flags().GetBool("self-signed-cert")
+	if err != nil {
+		return err
+	}
+	redirectAddr, err := cmd.Flags().GetString("http-redirect")
+	if err != nil {
+		return err
+	}
+	if err := validateTLSFlags(certFile, keyFile, selfSigned, redirectAddr); err != nil {
+		return err
+	}
 	noOpen, err := cmd.Flags().GetBool("no-open-browser")
 	if err != nil {
 		return err
 	}
 
-	isSecure := certFile != "" || keyFile != ""
+	excludedNamespaces, err := cmd.Flags().GetStringSlice("exclude-namespaces")
+	if err != nil {
+		return err
+	}
+
+	listFieldValues, err := cmd.Flags().GetStringArray("list-fields")
+	if err != nil {
+		return err
+	}
+	listFields, err := config.ParseListFields(listFieldValues)
+	if err != nil {
+		return err
+	}
+
+	maxSSEConnections, err := cmd.Flags().GetInt("max-sse-connections")
+	if err != nil {
+		return err
+	}
+
+	sseHeartbeatInterval, err := cmd.Flags().GetDuration("sse-heartbeat-interval")
+	if err != nil {
+		return err
+	}
+
+	maxBodySizeValue, err := cmd.Flags().GetString("max-body-size")
+	if err != nil {
+		return err
+	}
+	maxBodySize, err := resource.ParseQuantity(maxBodySizeValue)
+	if err != nil || maxBodySize.Sign() < 0 {
+		return fmt.Errorf("invalid --max-body-size %q, expected a size such as 10Mi", maxBodySizeValue)
+	}
+
+	nodeDebugEnabled, err := cmd.Flags().GetBool("enable-node-debug")
+	if err != nil {
+		return err
+	}
+	nodeDebugImages, err := cmd.Flags().GetStringSlice("node-debug-images")
+	if err != nil {
+		return err
+	}
+
+	execAuditTranscript, err := cmd.Flags().GetBool("audit-exec-transcript")
+	if err != nil {
+		return err
+	}
+
+	allowResourceValues, err := cmd.Flags().GetStringSlice("allow-resources")
+	if err != nil {
+		return err
+	}
+	allowedResources, err := config.ParseResourceList(allowResourceValues)
+	if err != nil {
+		return fmt.Errorf("invalid --allow-resources: %w", err)
+	}
+	blockResourceValues, err := cmd.Flags().GetStringSlice("block-resources")
+	if err != nil {
+		return err
+	}
+	blockedResources, err := config.ParseResourceList(blockResourceValues)
+	if err != nil {
+		return fmt.Errorf("invalid --block-resources: %w", err)
+	}
+
+	warmClients, err := cmd.Flags().GetBool("warm-clients")
+	if err != nil {
+		return err
+	}
+
+	isSecure := certFile != "" || selfSigned
 
 	cfg := config.NewAppConfig(Version, listenAddr, k8sClientQPS, k9sClientBurst, isSecure)
+	cfg.ExcludedNamespaces = excludedNamespaces
+	cfg.ListFields = listFields
+	cfg.MaxSSEConnections = maxSSEConnections
+	cfg.SSEHeartbeatInterval = sseHeartbeatInterval
+	cfg.TLSCertFile = certFile
+	cfg.TLSKeyFile = keyFile
+	cfg.TLSSelfSigned = selfSigned
+	cfg.HTTPRedirectAddr = redirectAddr
+	cfg.MaxRequestBodySize = maxBodySize.Value()
+	cfg.NodeDebugEnabled = nodeDebugEnabled
+	cfg.NodeDebugImages = nodeDebugImages
+	cfg.ExecAuditTranscript = execAuditTranscript
+	cfg.AllowedResources = allowedResources
+	cfg.BlockedResources = blockedResources
+	cfg.WarmClients = warmClients
 	cfg.LoadAppConfig()
 
 	c := container.NewContainer(env, cfg)
+	metrics.RegisterCacheStats("app", c.Cache().Stats)
+	metrics.RegisterClientMetrics()
 	e := echo.New()
 	startBanner()
 	routes.ConfigureRoutes(e, c)
@@ -102,23 +221,81 @@ func Serve(cmd *cobra.Command) error {
 	}
 
 	if !isSecure && !strings.Contains(c.Config().ListenAddr, "[::]:7080") && !strings.Contains(c.Config().ListenAddr, "localhost") {
-		log.Warn("SSE may not work properly without TLS. Use --certFile and --keyFile for HTTPS, or bind to localhost with --listen localhost:7080 to avoid issues.")
+		log.Warn("SSE may not work properly without TLS. Use --certFile and --keyFile or --self-signed-cert for HTTPS, or bind to localhost with --listen localhost:7080 to avoid issues.")
 	}
 
-	if c.Config().IsSecure {
-		e.Pre(middleware.HTTPSRedirect())
-		if err = e.StartTLS(c.Config().ListenAddr, certFile, keyFile); err != nil {
-			return err
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var redirect *http.Server
+	if c.Config().IsSecure && c.Config().HTTPRedirectAddr != "" {
+		redirect = newRedirectServer(c.Config().HTTPRedirectAddr, c.Config().ListenAddr)
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		if c.Config().IsSecure {
+			serveErr <- startTLS(e, c.Config(), redirect)
+			return
 		}
-		return nil
+		log.Info("serving HTTP", "addr", c.Config().ListenAddr)
+		serveErr <- e.Start(c.Config().ListenAddr)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
 	}
+	return shutdown(e, redirect)
+}
 
-	if err = e.Start(c.Config().ListenAddr); err != nil {
+// shutdownTimeout bounds how long requests in flight get to finish.
+const shutdownTimeout = 30 * time.Second
+
+// shutdown stops the server, and the HTTP redirect server when there is one,
+// gracefully. Event streams only end when their client disconnects, they are
+// closed first so they do not hold the shutdown until the deadline.
+func shutdown(e *echo.Echo, redirect *http.Server) error {
+	log.Info("shutting down", "closedStreams", streams.Shutdown())
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
+	if redirect != nil {
+		if err := redirect.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
 	return nil
 }
 
+// startTLS serves e over TLS, and starts redirect when it is not nil.
+func startTLS(e *echo.Echo, cfg *config.AppConfig, redirect *http.Server) error {
+	var cert, key any = cfg.TLSCertFile, cfg.TLSKeyFile
+	source := cfg.TLSCertFile
+	if cfg.TLSSelfSigned {
+		host, _, _ := net.SplitHostPort(cfg.ListenAddr)
+		certPEM, keyPEM, err := selfSignedCert(host, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+		cert, key, source = certPEM, keyPEM, "self-signed"
+	}
+
+	if redirect != nil {
+		go func() {
+			log.Info("redirecting HTTP to HTTPS", "addr", redirect.Addr)
+			if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("HTTP redirect server stopped", "err", err)
+			}
+		}()
+	}
+
+	e.Pre(middleware.HTTPSRedirect())
+	log.Info("serving HTTPS", "addr", cfg.ListenAddr, "certificate", source)
+	return e.StartTLS(cfg.ListenAddr, cert, key)
+}
+
 func openDefaultBrowser(isSecure bool, listenAddr string) {
 	// Split IP and Port
 	host, port, err := net.SplitHostPort(listenAddr)
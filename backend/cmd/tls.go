@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+)
+
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// selfSignedCert returns a PEM certificate and key for localhost and the
+// loopback addresses, plus host when it is a specific name or IP.
+func selfSignedCert(host string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"kubewall"}, CommonName: "localhost"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	switch ip := net.ParseIP(host); {
+	case ip != nil && !ip.IsUnspecified() && !ip.IsLoopback():
+		template.IPAddresses = append(template.IPAddresses, ip)
+	case ip == nil && host != "" && host != "localhost":
+		template.DNSNames = append(template.DNSNames, host)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+func validateTLSFlags(certFile, keyFile string, selfSigned bool, redirectAddr string) error {
+	if (certFile == "") != (keyFile == "") {
+		return errors.New("--certFile and --keyFile must be set together")
+	}
+	if selfSigned && certFile != "" {
+		return errors.New("--self-signed-cert cannot be combined with --certFile and --keyFile")
+	}
+	if redirectAddr != "" && certFile == "" && !selfSigned {
+		return errors.New("--http-redirect requires TLS, set --certFile and --keyFile or --self-signed-cert")
+	}
+	return nil
+}
+
+// redirectReadHeaderTimeout bounds how long a redirect client may take to send
+// its request headers.
+const redirectReadHeaderTimeout = 10 * time.Second
+
+// newRedirectServer returns the server for --http-redirect, shut down with
+// the main server.
+func newRedirectServer(addr, tlsListenAddr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           httpsRedirectHandler(tlsListenAddr),
+		ReadHeaderTimeout: redirectReadHeaderTimeout,
+	}
+}
+
+// httpsRedirectHandler sends plain HTTP requests to the same host on the TLS
+// listen port.
+func httpsRedirectHandler(tlsListenAddr string) http.Handler {
+	_, tlsPort, _ := net.SplitHostPort(tlsListenAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		target := "https://" + net.JoinHostPort(host, tlsPort) + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfSignedCert(t *testing.T) {
+	parse := func(host string) *x509.Certificate {
+		certPEM, keyPEM, err := selfSignedCert(host, time.Now())
+		assert.NoError(t, err)
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		assert.NoError(t, err)
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		assert.NoError(t, err)
+		return cert
+	}
+
+	cert := parse("192.168.1.10")
+	assert.NoError(t, cert.VerifyHostname("localhost"))
+	assert.NoError(t, cert.VerifyHostname("127.0.0.1"))
+	assert.NoError(t, cert.VerifyHostname("192.168.1.10"))
+	assert.True(t, cert.NotAfter.After(time.Now().Add(364*24*time.Hour)))
+
+	// Wildcard listen addresses only get the loopback names.
+	cert = parse("::")
+	assert.Equal(t, []string{"localhost"}, cert.DNSNames)
+	assert.Len(t, cert.IPAddresses, 2)
+
+	cert = parse("kubewall.internal")
+	assert.NoError(t, cert.VerifyHostname("kubewall.internal"))
+}
+
+func TestValidateTLSFlags(t *testing.T) {
+	assert.NoError(t, validateTLSFlags("", "", false, ""))
+	assert.NoError(t, validateTLSFlags("cert.pem", "key.pem", false, ":7081"))
+	assert.NoError(t, validateTLSFlags("", "", true, ":7081"))
+	assert.Error(t, validateTLSFlags("cert.pem", "", false, ""))
+	assert.Error(t, validateTLSFlags("cert.pem", "key.pem", true, ""))
+	assert.Error(t, validateTLSFlags("", "", false, ":7081"))
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	httpsRedirectHandler("[::]:7443").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com:7081/api/v1/app?config=a", nil))
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "https://example.com:7443/api/v1/app?config=a", rec.Header().Get("Location"))
+}
+
+func TestShutdownStopsRedirectServer(t *testing.T) {
+	redirect := newRedirectServer("127.0.0.1:0", "[::]:7443")
+	ln, err := net.Listen("tcp", redirect.Addr)
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- redirect.Serve(ln) }()
+
+	require.NoError(t, shutdown(echo.New(), redirect))
+	select {
+	case err := <-served:
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("redirect server still serving")
+	}
+}
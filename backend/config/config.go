@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"k8s.io/client-go/util/homedir"
 )
@@ -20,6 +23,9 @@ const (
 	InClusterKey         = "incluster"
 )
 
+// DefaultMaxRequestBodySize leaves room for large manifests and kubeconfigs.
+const DefaultMaxRequestBodySize = 10 << 20 // 10MiB
+
 type Env struct {
 	KubeConfigs []KubeConfig `json:"kubeconfigs"`
 }
@@ -29,12 +35,56 @@ type KubeConfig struct {
 	AbsolutePath string `json:"absolutePath"`
 }
 
+// ConfigSummary lists the contexts discovered in one kubeconfig.
+type ConfigSummary struct {
+	ID       string   `json:"id"`
+	Path     string   `json:"path"`
+	Contexts []string `json:"contexts"`
+}
+
 type AppConfig struct {
 	Version    string                     `json:"version"`
 	IsSecure   bool                       `json:"isSecure"`
 	ListenAddr string                     `json:"listenAddr"`
 	KubeConfig map[string]*KubeConfigInfo `json:"kubeConfigs"`
-	mu         sync.RWMutex
+	// ExcludedNamespaces are hidden from list streams unless a request sets
+	// its own excludeNamespaces.
+	ExcludedNamespaces []string `json:"excludedNamespaces"`
+	// ListFields restricts the list responses of a kind, keyed by lower-cased
+	// kind, to the given fields. Kinds without an entry send every field.
+	ListFields map[string][]string `json:"listFields,omitempty"`
+	// MaxSSEConnections caps concurrent event streams; zero means unlimited.
+	MaxSSEConnections int `json:"maxSSEConnections"`
+	// SSEHeartbeatInterval is how often idle streams get a keep-alive
+	// comment; zero disables heartbeats.
+	SSEHeartbeatInterval time.Duration `json:"sseHeartbeatInterval"`
+	// TLSCertFile and TLSKeyFile hold the serving certificate. With
+	// TLSSelfSigned a certificate for localhost is generated at startup instead.
+	TLSCertFile   string `json:"-"`
+	TLSKeyFile    string `json:"-"`
+	TLSSelfSigned bool   `json:"tlsSelfSigned"`
+	// HTTPRedirectAddr, when set with TLS, serves plain HTTP redirects to HTTPS.
+	HTTPRedirectAddr string `json:"httpRedirectAddr,omitempty"`
+	// MaxRequestBodySize caps request bodies in bytes, larger ones are
+	// rejected with 413; zero means unlimited.
+	MaxRequestBodySize int64 `json:"maxRequestBodySize"`
+	// NodeDebugEnabled allows starting privileged debug pods on nodes.
+	NodeDebugEnabled bool `json:"nodeDebugEnabled"`
+	// NodeDebugImages are the images node debug pods may use besides the
+	// default busybox image.
+	NodeDebugImages []string `json:"nodeDebugImages,omitempty"`
+	// ExecAuditTranscript adds the truncated output of exec sessions to
+	// their audit log entries.
+	ExecAuditTranscript bool `json:"execAuditTranscript"`
+	// AllowedResources and BlockedResources restrict the resources the
+	// dynamic endpoints serve, in resource.group form, see ResourceAllowed.
+	AllowedResources []string `json:"allowedResources,omitempty"`
+	BlockedResources []string `json:"blockedResources,omitempty"`
+	// WarmClients runs API discovery for the clusters of added or reloaded
+	// kubeconfigs in the background instead of on their first request.
+	WarmClients bool `json:"warmClients"`
+	loaded      bool
+	mu          sync.RWMutex
 }
 
 func NewEnv() *Env {
@@ -50,10 +100,11 @@ func NewAppConfig(version, listenAddr string, k8sClientQPS, k8sClientBurst int,
 	K8SQPS = k8sClientQPS
 	K8SBURST = k8sClientBurst
 	return &AppConfig{
-		Version:    version,
-		IsSecure:   isSecure,
-		ListenAddr: listenAddr,
-		KubeConfig: make(map[string]*KubeConfigInfo),
+		Version:            version,
+		IsSecure:           isSecure,
+		ListenAddr:         listenAddr,
+		KubeConfig:         make(map[string]*KubeConfigInfo),
+		MaxRequestBodySize: DefaultMaxRequestBodySize,
 	}
 }
 
@@ -70,6 +121,23 @@ func (c *AppConfig) ReloadConfig() {
 	c.loadAppConfigLocked()
 }
 
+// StopInformers stops the informers of every loaded cluster, before the
+// configs are reloaded or dropped.
+func (c *AppConfig) StopInformers() {
+	c.mu.RLock()
+	clusters := make([]*Cluster, 0)
+	for _, info := range c.KubeConfig {
+		for _, cluster := range info.Clusters {
+			clusters = append(clusters, cluster)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, cluster := range clusters {
+		cluster.StopInformers()
+	}
+}
+
 // loadAppConfigLocked performs the actual config loading. Caller must hold c.mu.
 func (c *AppConfig) loadAppConfigLocked() {
 	c.buildKubeConfigs(filepath.Join(homedir.HomeDir(), defaultKubeConfigDir))
@@ -79,6 +147,27 @@ func (c *AppConfig) loadAppConfigLocked() {
 	if err == nil {
 		c.KubeConfig[InClusterKey] = &i
 	}
+	c.loaded = true
+}
+
+// Loaded reports whether the kubeconfigs have been loaded at least once.
+func (c *AppConfig) Loaded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loaded
+}
+
+// Summaries returns the loaded kubeconfigs and their contexts, sorted by ID.
+func (c *AppConfig) Summaries() []ConfigSummary {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	summaries := make([]ConfigSummary, 0, len(c.KubeConfig))
+	for id, info := range c.KubeConfig {
+		summaries = append(summaries, info.summary(id))
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ID < summaries[j].ID })
+	return summaries
 }
 
 // GetKubeConfigInfo returns the KubeConfigInfo for the given config name.
@@ -127,6 +216,32 @@ func (c *AppConfig) RemoveKubeConfig(configName string) error {
 	return os.Remove(filepath.Join(homedir.HomeDir(), AppConfigDir, AppKubeConfigDir, configName))
 }
 
+// RenameKubeConfig moves a kubeconfig added through the app to a new name.
+// Configs read from ~/.kube are not managed by the app and cannot be renamed.
+func (c *AppConfig) RenameKubeConfig(oldName, newName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, ok := c.KubeConfig[oldName]
+	if !ok {
+		return os.ErrNotExist
+	}
+	dir := filepath.Join(homedir.HomeDir(), AppConfigDir, AppKubeConfigDir)
+	if info.AbsolutePath != filepath.Join(dir, oldName) {
+		return fmt.Errorf("config %s is not managed by kubewall", oldName)
+	}
+	newPath := filepath.Join(dir, newName)
+	if err := os.Rename(info.AbsolutePath, newPath); err != nil {
+		return err
+	}
+
+	delete(c.KubeConfig, oldName)
+	info.Name = newPath
+	info.AbsolutePath = newPath
+	c.KubeConfig[newName] = info
+	return nil
+}
+
 func (c *AppConfig) ConfigExists(name string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
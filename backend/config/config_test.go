@@ -384,3 +384,38 @@ func TestAppConfig_ConcurrentReloadAndRead(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestSummaries(t *testing.T) {
+	c := &AppConfig{KubeConfig: map[string]*KubeConfigInfo{
+		"prod": {AbsolutePath: "/kube/prod", Clusters: map[string]*Cluster{"eu": {}, "us": {}}},
+		"dev":  {AbsolutePath: "/kube/dev", Clusters: map[string]*Cluster{"kind": {}}},
+	}}
+
+	assert.Equal(t, []ConfigSummary{
+		{ID: "dev", Path: "/kube/dev", Contexts: []string{"kind"}},
+		{ID: "prod", Path: "/kube/prod", Contexts: []string{"eu", "us"}},
+	}, c.Summaries())
+}
+
+func TestRenameKubeConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, AppConfigDir, AppKubeConfigDir)
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "old"), []byte("{}"), 0600))
+
+	c := &AppConfig{KubeConfig: map[string]*KubeConfigInfo{
+		"old":    {AbsolutePath: filepath.Join(dir, "old")},
+		"config": {AbsolutePath: filepath.Join(home, ".kube", "config")},
+	}}
+
+	assert.NoError(t, c.RenameKubeConfig("old", "new"))
+	assert.False(t, c.ConfigExists("old"))
+	info, ok := c.GetKubeConfigInfo("new")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "new"), info.AbsolutePath)
+	assert.FileExists(t, filepath.Join(dir, "new"))
+
+	assert.Error(t, c.RenameKubeConfig("config", "other"))
+	assert.ErrorIs(t, c.RenameKubeConfig("missing", "other"), os.ErrNotExist)
+}
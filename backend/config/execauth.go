@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// ExecAuthTimeout bounds how long an exec credential plugin may take to return
+// credentials before the cluster is reported as requiring interactive auth.
+const ExecAuthTimeout = 20 * time.Second
+
+var ErrInteractiveAuth = errors.New("cluster requires interactive auth not available in server mode")
+
+// disableExecStdin makes exec credential plugins fail instead of prompting:
+// the server has no terminal to answer them.
+func disableExecStdin(restConfig *rest.Config) {
+	if restConfig.ExecProvider == nil {
+		return
+	}
+	restConfig.ExecProvider.StdinUnavailable = true
+	restConfig.ExecProvider.StdinUnavailableMessage = "kubewall runs without a terminal, log in with the plugin from a shell first"
+}
+
+type execAuthProbe struct {
+	done chan struct{}
+	err  error
+}
+
+// CheckExecAuth verifies that the exec credential plugin of the cluster, if
+// any, returns credentials within timeout. Plugins waiting for a browser login
+// or a prompt would otherwise hang every request to the cluster. A running
+// probe is shared by concurrent callers and a failed one is retried on the
+// next call, e.g. after the user logged in from a shell.
+func (c *Cluster) CheckExecAuth(timeout time.Duration) error {
+	if c.RestConfig == nil || c.RestConfig.ExecProvider == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	p := c.execProbe
+	if p == nil || p.failed() {
+		p = &execAuthProbe{done: make(chan struct{})}
+		c.execProbe = p
+		go func(restConfig *rest.Config) {
+			defer close(p.done)
+			p.err = acquireExecCredentials(restConfig)
+		}(c.RestConfig)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.err
+	case <-time.After(timeout):
+		return ErrInteractiveAuth
+	}
+}
+
+func (p *execAuthProbe) failed() bool {
+	select {
+	case <-p.done:
+		return p.err != nil
+	default:
+		return false
+	}
+}
+
+// acquireExecCredentials sends a request through the exec authenticator to a
+// stub transport, so credentials are obtained without reaching the cluster.
+func acquireExecCredentials(restConfig *rest.Config) error {
+	probeConfig := rest.CopyConfig(restConfig)
+	probeConfig.WrapTransport = func(http.RoundTripper) http.RoundTripper {
+		return stubRoundTripper{}
+	}
+	client, err := rest.HTTPClientFor(probeConfig)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, "https://exec-auth.probe/version", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if strings.Contains(err.Error(), "interactive") {
+			return fmt.Errorf("%w: %v", ErrInteractiveAuth, err)
+		}
+		return fmt.Errorf("exec credential plugin failed: %w", err)
+	}
+	return resp.Body.Close()
+}
+
+type stubRoundTripper struct{}
+
+func (stubRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
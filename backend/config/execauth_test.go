@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func execCluster(command string, args ...string) *Cluster {
+	return &Cluster{RestConfig: &rest.Config{
+		Host: "https://127.0.0.1:6443",
+		ExecProvider: &api.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1",
+			Command:         command,
+			Args:            args,
+			InteractiveMode: api.NeverExecInteractiveMode,
+		},
+	}}
+}
+
+func TestCheckExecAuth(t *testing.T) {
+	t.Run("no exec provider", func(t *testing.T) {
+		c := &Cluster{RestConfig: &rest.Config{Host: "https://127.0.0.1:6443", BearerToken: "token"}}
+		assert.NoError(t, c.CheckExecAuth(time.Second))
+	})
+
+	t.Run("plugin returns credentials", func(t *testing.T) {
+		c := execCluster("sh", "-c", `echo '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"abc"}}'`)
+		assert.NoError(t, c.CheckExecAuth(5*time.Second))
+	})
+
+	t.Run("plugin hangs", func(t *testing.T) {
+		c := execCluster("sleep", "1")
+		assert.ErrorIs(t, c.CheckExecAuth(100*time.Millisecond), ErrInteractiveAuth)
+	})
+
+	t.Run("plugin requires a terminal", func(t *testing.T) {
+		c := execCluster("true")
+		c.RestConfig.ExecProvider.InteractiveMode = api.AlwaysExecInteractiveMode
+		disableExecStdin(c.RestConfig)
+		assert.ErrorIs(t, c.CheckExecAuth(5*time.Second), ErrInteractiveAuth)
+	})
+
+	t.Run("plugin fails", func(t *testing.T) {
+		c := execCluster("false")
+		err := c.CheckExecAuth(5 * time.Second)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInteractiveAuth)
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+	"sort"
 	"sync"
 )
 
@@ -25,8 +26,18 @@ type KubeConfigInfo struct {
 	Clusters     map[string]*Cluster `json:"clusters"`
 }
 
+func (k *KubeConfigInfo) summary(id string) ConfigSummary {
+	contexts := make([]string, 0, len(k.Clusters))
+	for name := range k.Clusters {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return ConfigSummary{ID: id, Path: k.AbsolutePath, Contexts: contexts}
+}
+
 type Cluster struct {
 	Name                     string                                       `json:"name"`
+	ClusterName              string                                       `json:"clusterName"`
 	Namespace                string                                       `json:"namespace"`
 	AuthInfo                 string                                       `json:"authInfo"`
 	Connected                bool                                         `json:"connected"`
@@ -38,7 +49,10 @@ type Cluster struct {
 	ExtensionInformerFactory apiextensionsinformers.SharedInformerFactory `json:"-"`
 	DynamicInformerFactory   dynamicinformer.DynamicSharedInformerFactory `json:"-"`
 	MetricClient             *metricsclient.Clientset                     `json:"-"`
+	Warnings                 *WarningRecorder                             `json:"-"`
 	mu                       sync.Mutex                                   `json:"-"`
+	execProbe                *execAuthProbe
+	stopCh                   chan struct{}
 }
 
 func (c *Cluster) GetClientSet() *kubernetes.Clientset {
@@ -69,6 +83,45 @@ func (c *Cluster) GetMetricClient() *metricsclient.Clientset {
 	return c.MetricClient
 }
 
+// StopCh is the stop channel the cluster's informer factories are started
+// with. It is closed by StopInformers.
+func (c *Cluster) StopCh() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopCh == nil {
+		c.stopCh = make(chan struct{})
+	}
+	return c.stopCh
+}
+
+// StopInformers stops every informer started on StopCh and waits for them to
+// exit. The factories cannot be started again.
+func (c *Cluster) StopInformers() {
+	c.mu.Lock()
+	if c.stopCh == nil {
+		c.stopCh = make(chan struct{})
+	}
+	select {
+	case <-c.stopCh:
+		c.mu.Unlock()
+		return
+	default:
+		close(c.stopCh)
+	}
+	c.mu.Unlock()
+
+	if c.SharedInformerFactory != nil {
+		c.SharedInformerFactory.Shutdown()
+	}
+	if c.ExtensionInformerFactory != nil {
+		c.ExtensionInformerFactory.Shutdown()
+	}
+	if c.DynamicInformerFactory != nil {
+		c.DynamicInformerFactory.Shutdown()
+	}
+}
+
 func (c *Cluster) IsConnected() bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -91,11 +144,13 @@ func LoadInClusterConfig() (KubeConfigInfo, error) {
 	}
 	config.QPS = float32(K8SQPS)
 	config.Burst = K8SBURST
+	withRetries(config)
 	kubeConfig, err := loadClientConfig(config)
 	if err != nil {
 		return KubeConfigInfo{}, err
 	}
 	kubeConfig.Name = InClusterKey
+	kubeConfig.ClusterName = InClusterKey
 	newConfig := KubeConfigInfo{
 		Name:         InClusterKey,
 		AbsolutePath: "",
@@ -133,6 +188,7 @@ func LoadK8ConfigFromFile(path string) (map[string]*Cluster, error) {
 
 		cfg := &Cluster{
 			Name:                     key,
+			ClusterName:              cluster.Cluster,
 			Namespace:                cluster.Namespace,
 			AuthInfo:                 cluster.AuthInfo,
 			RestConfig:               kubeConfig.RestConfig,
@@ -172,6 +228,8 @@ func restConfig(path string, contextName string) (*rest.Config, error) {
 	if restConfig.BearerToken != "" && isTLSClientConfigEmpty(restConfig) {
 		restConfig.Insecure = true
 	}
+	disableExecStdin(restConfig)
+	withRetries(restConfig)
 
 	return restConfig, nil
 }
@@ -187,6 +245,10 @@ func loadClientConfig(restConfig *rest.Config) (*Cluster, error) {
 		return nil, fmt.Errorf("restConfig is nil")
 	}
 
+	// Set before creating the clients, they copy the config.
+	warnings := NewWarningRecorder()
+	restConfig.WarningHandler = warnings
+
 	// Create core clientset first (most critical)
 	clientSet, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -227,5 +289,6 @@ func loadClientConfig(restConfig *rest.Config) (*Cluster, error) {
 		ExtensionInformerFactory: externalInformer,
 		DynamicInformerFactory:   dynamicInformer,
 		MetricClient:             metricClient,
+		Warnings:                 warnings,
 	}, nil
 }
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
@@ -174,6 +177,28 @@ func TestCluster_MarkAsConnected(t *testing.T) {
 	}
 }
 
+func TestCluster_StopInformers(t *testing.T) {
+	c := &Cluster{SharedInformerFactory: informers.NewSharedInformerFactory(fake.NewClientset(), 0)}
+	informer := c.SharedInformerFactory.Core().V1().Pods().Informer()
+	c.SharedInformerFactory.Start(c.StopCh())
+	assert.Eventually(t, informer.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		c.StopInformers()
+		c.StopInformers()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("StopInformers did not return")
+	}
+	assert.True(t, informer.IsStopped())
+	_, open := <-c.StopCh()
+	assert.False(t, open)
+}
+
 func Test_isTLSClientConfigEmpty(t *testing.T) {
 	type args struct {
 		restConfig *rest.Config
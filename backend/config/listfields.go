@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseListFields parses --list-fields values of the form
+// Kind=field,field.nested into a map keyed by lower-cased kind.
+func ParseListFields(values []string) (map[string][]string, error) {
+	listFields := make(map[string][]string, len(values))
+	for _, value := range values {
+		kind, list, ok := strings.Cut(value, "=")
+		kind = strings.TrimSpace(kind)
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("invalid list fields %q, expected Kind=field,field", value)
+		}
+		fields := make([]string, 0)
+		for _, field := range strings.Split(list, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("no fields given for kind %s", kind)
+		}
+		key := strings.ToLower(kind)
+		listFields[key] = append(listFields[key], fields...)
+	}
+	return listFields, nil
+}
+
+// ListFieldsFor returns the fields list responses of kind are projected to,
+// nil when every field is sent.
+func (c *AppConfig) ListFieldsFor(kind string) []string {
+	return c.ListFields[strings.ToLower(kind)]
+}
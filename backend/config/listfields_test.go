@@ -0,0 +1,22 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListFields(t *testing.T) {
+	got, err := ParseListFields([]string{"Pod=name, status.phase", "pod=node", "Deployment=replicas"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"pod": {"name", "status.phase", "node"}, "deployment": {"replicas"}}, got)
+
+	cfg := &AppConfig{ListFields: got}
+	assert.Equal(t, []string{"replicas"}, cfg.ListFieldsFor("Deployment"))
+	assert.Nil(t, cfg.ListFieldsFor("Service"))
+
+	_, err = ParseListFields([]string{"Pod"})
+	assert.Error(t, err)
+	_, err = ParseListFields([]string{"Pod= ,"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ParseResourceList parses --allow-resources and --block-resources values,
+// resources in kubectl's resource.group form, e.g. pods or
+// certificates.cert-manager.io. "*" stands for every resource of a group,
+// e.g. *.cert-manager.io.
+func ParseResourceList(values []string) ([]string, error) {
+	resources := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		gr := schema.ParseGroupResource(value)
+		if gr.Resource == "" || strings.ContainsAny(value, "/ ") {
+			return nil, fmt.Errorf("invalid resource %q, expected resource.group", value)
+		}
+		if !slices.Contains(resources, gr.String()) {
+			resources = append(resources, gr.String())
+		}
+	}
+	return resources, nil
+}
+
+// ResourceAllowed reports whether the dynamic endpoints may serve gr. A
+// blocked resource is never served, with an allowlist only the resources on
+// it are.
+func (c *AppConfig) ResourceAllowed(gr schema.GroupResource) bool {
+	if matchesResource(c.BlockedResources, gr) {
+		return false
+	}
+	return len(c.AllowedResources) == 0 || matchesResource(c.AllowedResources, gr)
+}
+
+func matchesResource(resources []string, gr schema.GroupResource) bool {
+	gr = schema.GroupResource{Group: strings.ToLower(gr.Group), Resource: strings.ToLower(gr.Resource)}
+	wildcard := schema.GroupResource{Group: gr.Group, Resource: "*"}
+	return slices.Contains(resources, gr.String()) || slices.Contains(resources, wildcard.String())
+}
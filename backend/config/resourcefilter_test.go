@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseResourceList(t *testing.T) {
+	got, err := ParseResourceList([]string{"Secrets", " deployments.apps", "*.cert-manager.io", "secrets", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"secrets", "deployments.apps", "*.cert-manager.io"}, got)
+
+	_, err = ParseResourceList([]string{"apps/v1/deployments"})
+	assert.Error(t, err)
+	_, err = ParseResourceList([]string{".apps"})
+	assert.Error(t, err)
+}
+
+func TestResourceAllowed(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	certificates := schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}
+
+	cfg := &AppConfig{}
+	assert.True(t, cfg.ResourceAllowed(secrets))
+
+	cfg = &AppConfig{BlockedResources: []string{"secrets", "*.cert-manager.io"}}
+	assert.False(t, cfg.ResourceAllowed(secrets))
+	assert.False(t, cfg.ResourceAllowed(certificates))
+	assert.True(t, cfg.ResourceAllowed(deployments))
+
+	cfg = &AppConfig{AllowedResources: []string{"deployments.apps", "*.cert-manager.io"}, BlockedResources: []string{"certificates.cert-manager.io"}}
+	assert.True(t, cfg.ResourceAllowed(schema.GroupResource{Group: "apps", Resource: "Deployments"}))
+	assert.False(t, cfg.ResourceAllowed(secrets))
+	assert.False(t, cfg.ResourceAllowed(certificates))
+	assert.True(t, cfg.ResourceAllowed(schema.GroupResource{Group: "cert-manager.io", Resource: "issuers"}))
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	retryAttempts  = 3
+	retryBaseDelay = 200 * time.Millisecond
+	// retryMaxDelay caps the backoff, a longer wait is better spent showing
+	// the error than holding the request.
+	retryMaxDelay = 5 * time.Second
+)
+
+// retryTransport retries reads that failed because a proxy or the API server
+// was briefly unavailable. Writes are never retried, they may have been
+// applied.
+//
+// client-go already retries responses carrying a Retry-After header, e.g.
+// throttling by priority and fairness, and GETs that failed on a dropped
+// connection, up to 10 times per request. Those are left to it so that the
+// two layers don't multiply their attempts.
+type retryTransport struct {
+	next      http.RoundTripper
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// withRetries makes every client built from restConfig retry transient
+// errors on list and get requests.
+func withRetries(restConfig *rest.Config) {
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &retryTransport{next: rt, attempts: retryAttempts, baseDelay: retryBaseDelay, maxDelay: retryMaxDelay}
+	})
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.attempts || !retryable(resp, err) {
+			return resp, err
+		}
+
+		delay := t.delay(attempt)
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		log.Debug("retrying request to the API server", "url", req.URL.Redacted(), "attempt", attempt, "delay", delay, "status", resp.StatusCode)
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil || resp.Header.Get("Retry-After") != "" {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// delay is an exponential backoff with jitter, capped at maxDelay.
+func (t *retryTransport) delay(attempt int) time.Duration {
+	backoff := t.baseDelay << (attempt - 1)
+	backoff += rand.N(backoff/2 + 1)
+	return min(backoff, t.maxDelay)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
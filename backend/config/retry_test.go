@@ -0,0 +1,204 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// flakyRoundTripper answers with the queued responses and errors in turn,
+// then with 200 OK.
+type flakyRoundTripper struct {
+	results []any
+	calls   int
+}
+
+func (f *flakyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if len(f.results) == 0 {
+		return response(http.StatusOK, nil), nil
+	}
+	result := f.results[0]
+	f.results = f.results[1:]
+	if err, ok := result.(error); ok {
+		return nil, err
+	}
+	return result.(*http.Response), nil
+}
+
+func response(status int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader("{}"))}
+}
+
+func newTestRetryTransport(next http.RoundTripper) *retryTransport {
+	return &retryTransport{next: next, attempts: 3, baseDelay: time.Millisecond, maxDelay: 10 * time.Millisecond}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		results    []any
+		wantStatus int
+		wantErr    bool
+		wantCalls  int
+	}{
+		{
+			name:       "unavailable then ok",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusServiceUnavailable, nil), response(http.StatusBadGateway, nil)},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "retry-after is left to client-go",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusServiceUnavailable, http.Header{"Retry-After": {"1"}})},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "throttling without retry-after is not retried",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusTooManyRequests, nil)},
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+		{
+			name:      "connection reset is left to client-go",
+			method:    http.MethodGet,
+			results:   []any{syscall.ECONNRESET},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:       "gives up after the last attempt",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusBadGateway, nil), response(http.StatusBadGateway, nil), response(http.StatusGatewayTimeout, nil)},
+			wantStatus: http.StatusGatewayTimeout,
+			wantCalls:  3,
+		},
+		{
+			name:       "forbidden is not retried",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusForbidden, nil)},
+			wantStatus: http.StatusForbidden,
+			wantCalls:  1,
+		},
+		{
+			name:       "not found is not retried",
+			method:     http.MethodGet,
+			results:    []any{response(http.StatusNotFound, nil)},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		{
+			name:      "other errors are not retried",
+			method:    http.MethodGet,
+			results:   []any{errors.New("x509: certificate signed by unknown authority")},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:       "writes are not retried",
+			method:     http.MethodPost,
+			results:    []any{response(http.StatusServiceUnavailable, nil)},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &flakyRoundTripper{results: tt.results}
+			req, err := http.NewRequest(tt.method, "https://127.0.0.1:6443/api/v1/pods", nil)
+			require.NoError(t, err)
+
+			resp, err := newTestRetryTransport(next).RoundTrip(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			}
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
+
+func TestRetryTransportCancelled(t *testing.T) {
+	next := &flakyRoundTripper{results: []any{response(http.StatusServiceUnavailable, nil)}}
+	transport := newTestRetryTransport(next)
+	transport.baseDelay, transport.maxDelay = time.Minute, time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://127.0.0.1:6443/api/v1/pods", nil)
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestRetryDelay(t *testing.T) {
+	transport := &retryTransport{baseDelay: 100 * time.Millisecond, maxDelay: 5 * time.Second}
+
+	first := transport.delay(1)
+	assert.GreaterOrEqual(t, first, 100*time.Millisecond)
+	assert.LessOrEqual(t, first, 150*time.Millisecond)
+	second := transport.delay(2)
+	assert.GreaterOrEqual(t, second, 200*time.Millisecond)
+	assert.LessOrEqual(t, second, 300*time.Millisecond)
+	assert.Equal(t, 5*time.Second, transport.delay(10))
+}
+
+// TestRetryTransportWithClientGo checks that client-go's own retries and the
+// transport's don't multiply.
+func TestRetryTransportWithClientGo(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantCalls int32
+	}{
+		// client-go retries responses with Retry-After 10 times.
+		{name: "retry-after", header: http.Header{"Retry-After": {"0"}}, wantCalls: 11},
+		{name: "no retry-after", wantCalls: retryAttempts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				maps.Copy(w.Header(), tt.header)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			restConfig := &rest.Config{Host: server.URL, QPS: 1000, Burst: 1000}
+			withRetries(restConfig)
+			clientSet, err := kubernetes.NewForConfig(restConfig)
+			require.NoError(t, err)
+
+			_, err = clientSet.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// maxAPIWarnings bounds the distinct warnings kept per cluster, the least
+// recently seen is dropped first.
+const maxAPIWarnings = 100
+
+// deprecationRegex matches the warnings the API server sends for deprecated
+// APIs, e.g. "policy/v1beta1 PodSecurityPolicy is deprecated in v1.21+,
+// unavailable in v1.25+; use ...".
+var deprecationRegex = regexp.MustCompile(`^(\S+) (\S+) is deprecated`)
+
+// APIWarning is a warning the API server returned, with APIVersion and Kind
+// set when it is about a deprecated API.
+type APIWarning struct {
+	Message    string    `json:"message"`
+	APIVersion string    `json:"apiVersion,omitempty"`
+	Kind       string    `json:"kind,omitempty"`
+	Count      int       `json:"count"`
+	FirstSeen  time.Time `json:"firstSeen"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+// WarningRecorder is the rest.WarningHandler of a cluster's clients. It keeps
+// the recent warnings, informers relisting a deprecated API repeat theirs, so
+// each is logged once and counted after.
+type WarningRecorder struct {
+	mu sync.Mutex
+	// warnings is ordered by LastSeen, oldest first.
+	warnings []APIWarning
+	now      func() time.Time
+}
+
+func NewWarningRecorder() *WarningRecorder {
+	return &WarningRecorder{now: time.Now}
+}
+
+func (r *WarningRecorder) HandleWarningHeader(code int, _ string, message string) {
+	if code != 299 || message == "" {
+		return
+	}
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := slices.IndexFunc(r.warnings, func(w APIWarning) bool { return w.Message == message }); i >= 0 {
+		w := r.warnings[i]
+		w.Count++
+		w.LastSeen = now
+		r.warnings = append(slices.Delete(r.warnings, i, i+1), w)
+		return
+	}
+
+	log.Warn("API server warning", "warning", message)
+	w := APIWarning{Message: message, Count: 1, FirstSeen: now, LastSeen: now}
+	if m := deprecationRegex.FindStringSubmatch(message); m != nil {
+		w.APIVersion, w.Kind = m[1], m[2]
+	}
+	r.warnings = append(r.warnings, w)
+	if len(r.warnings) > maxAPIWarnings {
+		r.warnings = slices.Delete(r.warnings, 0, len(r.warnings)-maxAPIWarnings)
+	}
+}
+
+// Warnings returns the recorded warnings, most recently seen first.
+func (r *WarningRecorder) Warnings() []APIWarning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	warnings := slices.Clone(r.warnings)
+	slices.Reverse(warnings)
+	if warnings == nil {
+		warnings = make([]APIWarning, 0)
+	}
+	return warnings
+}
+
+// Deprecations returns the deprecation warnings about kind in apiVersion.
+func (r *WarningRecorder) Deprecations(apiVersion, kind string) []APIWarning {
+	deprecations := make([]APIWarning, 0)
+	for _, w := range r.Warnings() {
+		if w.APIVersion == apiVersion && w.Kind == kind {
+			deprecations = append(deprecations, w)
+		}
+	}
+	return deprecations
+}
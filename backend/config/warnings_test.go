@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningRecorder(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	r := NewWarningRecorder()
+	r.now = func() time.Time { return now }
+	assert.Empty(t, r.Warnings())
+
+	deprecation := "policy/v1beta1 PodSecurityPolicy is deprecated in v1.21+, unavailable in v1.25+"
+	r.HandleWarningHeader(299, "", deprecation)
+	r.HandleWarningHeader(299, "", "metadata.finalizers: prefer a domain-qualified finalizer name")
+	// Only 299 warnings are API server warnings.
+	r.HandleWarningHeader(199, "", "misc")
+	now = now.Add(time.Minute)
+	r.HandleWarningHeader(299, "", deprecation)
+
+	warnings := r.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, APIWarning{
+		Message:    deprecation,
+		APIVersion: "policy/v1beta1",
+		Kind:       "PodSecurityPolicy",
+		Count:      2,
+		FirstSeen:  now.Add(-time.Minute),
+		LastSeen:   now,
+	}, warnings[0])
+	assert.Empty(t, warnings[1].Kind)
+
+	assert.Len(t, r.Deprecations("policy/v1beta1", "PodSecurityPolicy"), 1)
+	assert.Empty(t, r.Deprecations("policy/v1", "PodSecurityPolicy"))
+
+	for i := range maxAPIWarnings {
+		r.HandleWarningHeader(299, "", fmt.Sprintf("warning %d", i))
+	}
+	warnings = r.Warnings()
+	assert.Len(t, warnings, maxAPIWarnings)
+	assert.Equal(t, fmt.Sprintf("warning %d", maxAPIWarnings-1), warnings[0].Message)
+	assert.Empty(t, r.Deprecations("policy/v1beta1", "PodSecurityPolicy"))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/kubewall/kubewall/backend/config"
 	"github.com/maypok86/otter/v2"
+	"github.com/maypok86/otter/v2/stats"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 )
@@ -36,11 +37,13 @@ type Container interface {
 	SharedInformerFactory(config, cluster string) informers.SharedInformerFactory
 	ExtensionSharedFactoryInformer(config, cluster string) apiextensionsinformers.SharedInformerFactory
 	DynamicSharedInformerFactory(config, cluster string) dynamicinformer.DynamicSharedInformerFactory
+	InformerStopCh(config, cluster string) <-chan struct{}
 	Cache() *otter.Cache[string, any]
 	SSE() *sse.Server
 	SocketUpgrader() *websocket.Upgrader
 	EventProcessor() *event.EventProcessor
 	PortForwarder() *portforward.PortForwarder
+	APIWarnings(config, cluster string) *config.WarningRecorder
 }
 
 // container struct is for sharing data which such as database setting, the setting of application and logger in overall this application.
@@ -60,6 +63,7 @@ func NewContainer(env *config.Env, cfg *config.AppConfig) Container {
 	cache := otter.Must(&otter.Options[string, any]{
 		MaximumSize:      5000,
 		ExpiryCalculator: otter.ExpiryAccessing[string, any](4 * time.Hour),
+		StatsRecorder:    stats.NewCounter(),
 	})
 
 	s := sse.New()
@@ -220,6 +224,32 @@ func (c *container) DynamicSharedInformerFactory(config, cluster string) dynamic
 	return cfg.GetDynamicSharedInformerFactory()
 }
 
+// InformerStopCh returns the channel the informer factories of a cluster are
+// started with, closed when the cluster's config is reloaded or removed.
+func (c *container) InformerStopCh(config, cluster string) <-chan struct{} {
+	kubeConfig, ok := c.config.GetKubeConfigInfo(config)
+	if !ok || kubeConfig == nil {
+		return nil
+	}
+	cfg, ok := kubeConfig.Clusters[cluster]
+	if !ok || cfg == nil {
+		return nil
+	}
+	return cfg.StopCh()
+}
+
+func (c *container) APIWarnings(config, cluster string) *config.WarningRecorder {
+	kubeConfig, ok := c.config.GetKubeConfigInfo(config)
+	if !ok || kubeConfig == nil {
+		return nil
+	}
+	cfg, ok := kubeConfig.Clusters[cluster]
+	if !ok || cfg == nil {
+		return nil
+	}
+	return cfg.Warnings
+}
+
 func (c *container) SocketUpgrader() *websocket.Upgrader {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/kubewall/kubewall/backend/config"
 	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
 	"github.com/labstack/echo/v4"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -30,25 +33,55 @@ func (h *AppConfigHandler) Get(c echo.Context) error {
 }
 
 func (h *AppConfigHandler) Reload(c echo.Context) error {
+	h.reload()
+	return c.Redirect(http.StatusTemporaryRedirect, "/")
+}
+
+// PostReload re-reads the kubeconfig directories, e.g. after a mounted
+// kubeconfig changed on disk, and drops cached clients and handlers so the
+// next request connects with the new credentials.
+func (h *AppConfigHandler) PostReload(c echo.Context) error {
+	h.reload()
+	return c.JSON(http.StatusOK, h.container.Config().Summaries())
+}
+
+// reload stops the informers of the loaded clusters before dropping them,
+// they would otherwise keep watching with the old clients.
+func (h *AppConfigHandler) reload() {
+	h.container.Config().StopInformers()
 	h.container.Cache().InvalidateAll()
+	base.ResetHandlers()
 	h.container.Config().ReloadConfig()
-	return c.Redirect(http.StatusTemporaryRedirect, "/")
+	helpers.WarmAllClients(h.container)
 }
 
+// Post registers a kubeconfig sent in the "file" form field, as a value or
+// an uploaded file, or as the raw request body. configName becomes the config
+// ID, one is generated when it is empty. It responds with the ID and the
+// contexts found.
 func (h *AppConfigHandler) Post(c echo.Context) error {
-	kubeconfig := c.FormValue("file")
-	if strings.TrimSpace(kubeconfig) == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "kubeconfig is empty")
+	kubeconfig, err := readKubeconfig(c)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errKubeconfigTooLarge) || errors.As(err, &maxBytesErr) {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := validateKubeconfig(kubeconfig); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	// Get and validate config name
 	configName := strings.TrimSpace(c.FormValue("configName"))
-	if err := validateConfigName(configName); err != nil {
+	if configName == "" {
+		configName = generateConfigName()
+	} else if err := validateConfigName(configName); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	// Normalize to lowercase (validateConfigName already does this, but be explicit)
-	configName = strings.ToLower(strings.TrimSpace(configName))
+	configName = strings.ToLower(configName)
 
 	// Check for duplicates
 	if h.container.Config().ConfigExists(configName) {
@@ -57,16 +90,21 @@ func (h *AppConfigHandler) Post(c echo.Context) error {
 
 	path := filepath.Join(homeDir(), config.AppConfigDir, config.AppKubeConfigDir, configName)
 
-	if err := writeKubeconfigToFile(path, kubeconfig); err != nil {
+	if err := writeKubeconfigToFile(path, string(kubeconfig)); err != nil {
 		return err
 	}
-	if err := validateKubeconfigFile(path); err != nil {
-		defer os.Remove(path)
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid kubeconfig").SetInternal(err)
-	}
 
 	h.container.Config().SaveKubeConfig(configName)
-	return c.JSON(http.StatusOK, echo.Map{"success": true, "configId": configName})
+	for _, summary := range h.container.Config().Summaries() {
+		if summary.ID == configName {
+			helpers.WarmClients(h.container, configName)
+			return c.JSON(http.StatusOK, echo.Map{"success": true, "configId": configName, "contexts": summary.Contexts})
+		}
+	}
+
+	// SaveKubeConfig skips files without a single usable context.
+	os.Remove(path)
+	return echo.NewHTTPError(http.StatusBadRequest, "kubeconfig has no usable contexts")
 }
 
 func (h *AppConfigHandler) PostBearer(c echo.Context) error {
@@ -104,6 +142,7 @@ func (h *AppConfigHandler) PostBearer(c echo.Context) error {
 	}
 
 	h.container.Config().SaveKubeConfig(configName)
+	helpers.WarmClients(h.container, configName)
 	return c.JSON(http.StatusOK, echo.Map{"success": true, "configId": configName})
 }
 
@@ -164,13 +203,27 @@ func (h *AppConfigHandler) PostCertificate(c echo.Context) error {
 	}
 
 	h.container.Config().SaveKubeConfig(configName)
+	helpers.WarmClients(h.container, configName)
 	return c.JSON(http.StatusOK, echo.Map{"success": true, "configId": configName})
 }
 
+// Delete removes a kubeconfig added through the app. While streams of the
+// config are open it fails with 409, unless ?force=true, which closes them
+// first.
 func (h *AppConfigHandler) Delete(c echo.Context) error {
-	if err := h.container.Config().RemoveKubeConfig(c.Param("configId")); err != nil {
+	id := c.Param("configId")
+	info, ok := h.container.Config().GetKubeConfigInfo(id)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("config '%s' not found", id))
+	}
+	if err := h.checkStreams(c, id); err != nil {
+		return err
+	}
+
+	if err := h.container.Config().RemoveKubeConfig(id); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to remove kubeconfig").SetInternal(err)
 	}
+	h.evict(id, info)
 	return c.JSON(http.StatusOK, echo.Map{"success": true})
 }
 
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// reachabilityTimeout bounds each check, an unreachable API server should not
+// hold up the whole list.
+const reachabilityTimeout = 3 * time.Second
+
+var errNoRestConfig = errors.New("context has no client configuration")
+
+// ContextSummary is one context of one stored kubeconfig.
+type ContextSummary struct {
+	ConfigID    string `json:"configId"`
+	ContextName string `json:"contextName"`
+	ClusterName string `json:"clusterName"`
+	// Reachable is null unless ?reachable=true was requested.
+	Reachable *bool  `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// GetAllContexts lists the contexts of every stored kubeconfig, sorted by
+// config and context. With ?reachable=true the API server of each context is
+// checked, concurrently and with a short timeout.
+func (h *AppConfigHandler) GetAllContexts(c echo.Context) error {
+	checkReachable, _ := strconv.ParseBool(c.QueryParam("reachable"))
+
+	contexts := make([]ContextSummary, 0)
+	restConfigs := make([]*rest.Config, 0)
+	for _, summary := range h.container.Config().Summaries() {
+		info, ok := h.container.Config().GetKubeConfigInfo(summary.ID)
+		if !ok {
+			continue
+		}
+		for _, name := range summary.Contexts {
+			cluster, ok := info.Clusters[name]
+			if !ok {
+				continue
+			}
+			contexts = append(contexts, ContextSummary{
+				ConfigID:    summary.ID,
+				ContextName: name,
+				ClusterName: cluster.ClusterName,
+			})
+			restConfigs = append(restConfigs, cluster.RestConfig)
+		}
+	}
+
+	if checkReachable {
+		var wg sync.WaitGroup
+		for i := range contexts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(c.Request().Context(), reachabilityTimeout)
+				defer cancel()
+				err := reachable(ctx, restConfigs[i])
+				ok := err == nil
+				contexts[i].Reachable = &ok
+				if err != nil {
+					contexts[i].Error = err.Error()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	return c.JSON(http.StatusOK, contexts)
+}
+
+// reachable asks the API server for its version. Any answer from the server,
+// even a 401 or 403, counts, only the credentials are wrong then.
+func reachable(ctx context.Context, restConfig *rest.Config) error {
+	if restConfig == nil {
+		return errNoRestConfig
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(rest.CopyConfig(restConfig))
+	if err != nil {
+		return err
+	}
+	err = client.RESTClient().Get().AbsPath("/version").Do(ctx).Error()
+	if _, ok := err.(apierrors.APIStatus); ok {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetAllContexts(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"major":"1","minor":"33"}`))
+	}))
+	defer up.Close()
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+	}))
+	defer unauthorized.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	cfg := config.NewAppConfig("test", ":0", 10, 10, false)
+	cfg.KubeConfig["work"] = &config.KubeConfigInfo{Clusters: map[string]*config.Cluster{
+		"prod":    {Name: "prod", ClusterName: "prod-eu", RestConfig: &rest.Config{Host: up.URL}},
+		"staging": {Name: "staging", ClusterName: "staging-eu", RestConfig: &rest.Config{Host: unauthorized.URL}},
+	}}
+	cfg.KubeConfig["home"] = &config.KubeConfigInfo{Clusters: map[string]*config.Cluster{
+		"kind": {Name: "kind", ClusterName: "kind-kind", RestConfig: &rest.Config{Host: down.URL}},
+	}}
+	h := NewAppConfigHandler(container.NewContainer(&config.Env{}, cfg))
+
+	get := func(query string) []ContextSummary {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/configs/contexts?"+query, nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, h.GetAllContexts(echo.New().NewContext(req, rec)))
+		var contexts []ContextSummary
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &contexts))
+		return contexts
+	}
+
+	t.Run("without checks", func(t *testing.T) {
+		contexts := get("")
+		assert.Equal(t, []ContextSummary{
+			{ConfigID: "home", ContextName: "kind", ClusterName: "kind-kind"},
+			{ConfigID: "work", ContextName: "prod", ClusterName: "prod-eu"},
+			{ConfigID: "work", ContextName: "staging", ClusterName: "staging-eu"},
+		}, contexts)
+	})
+
+	t.Run("with reachability", func(t *testing.T) {
+		contexts := get("reachable=true")
+		require.Len(t, contexts, 3)
+		for _, c := range contexts {
+			require.NotNil(t, c.Reachable, c.ContextName)
+		}
+		assert.False(t, *contexts[0].Reachable)
+		assert.NotEmpty(t, contexts[0].Error)
+		assert.True(t, *contexts[1].Reachable)
+		// The server answered, only the credentials are rejected.
+		assert.True(t, *contexts[2].Reachable)
+	})
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/kubewall/kubewall/backend/streams"
+	"github.com/labstack/echo/v4"
+)
+
+// RenameConfig gives a kubeconfig added through the app a new ID. Open
+// streams are handled as in Delete, since they refer to the old ID. The
+// config is loaded again under the new ID with fresh clients.
+func (h *AppConfigHandler) RenameConfig(c echo.Context) error {
+	id := c.Param("id")
+	if !h.container.Config().ConfigExists(id) {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("config '%s' not found", id))
+	}
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if err := validateConfigName(name); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	name = strings.ToLower(name)
+	if name == id {
+		return echo.NewHTTPError(http.StatusBadRequest, "new name is the same as the current one")
+	}
+	if h.container.Config().ConfigExists(name) {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("config '%s' already exists", name))
+	}
+	if err := h.checkStreams(c, id); err != nil {
+		return err
+	}
+
+	info, _ := h.container.Config().GetKubeConfigInfo(id)
+	if err := h.container.Config().RenameKubeConfig(id, name); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	h.container.Config().SaveKubeConfig(name)
+	h.evict(id, info)
+
+	for _, summary := range h.container.Config().Summaries() {
+		if summary.ID == name {
+			return c.JSON(http.StatusOK, summary)
+		}
+	}
+	return c.JSON(http.StatusOK, echo.Map{"id": name})
+}
+
+func (h *AppConfigHandler) checkStreams(c echo.Context, id string) error {
+	force, _ := strconv.ParseBool(c.QueryParam("force"))
+	if n := streams.Active(id); n > 0 && !force {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("config '%s' has %d open streams, retry with force=true to close them", id, n))
+	}
+	streams.CancelAll(id)
+	return nil
+}
+
+// evict stops the informers of a removed config and drops its cached
+// handlers and cache entries.
+func (h *AppConfigHandler) evict(id string, info *config.KubeConfigInfo) {
+	if info == nil {
+		return
+	}
+	clusters := make([]string, 0, len(info.Clusters))
+	for name, cluster := range info.Clusters {
+		clusters = append(clusters, name)
+		cluster.StopInformers()
+	}
+
+	base.ResetConfigHandlers(id, clusters)
+	cache := h.container.Cache()
+	for key := range cache.Keys() {
+		if base.HasClusterPrefix(key, id, clusters) {
+			cache.Invalidate(key)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/operations"
+	"github.com/labstack/echo/v4"
+)
+
+// GetOperations lists the cancellable operations in flight.
+func (h *AppConfigHandler) GetOperations(c echo.Context) error {
+	return c.JSON(http.StatusOK, operations.List())
+}
+
+// CancelOperation cancels the context of the request started with the
+// operation ID :id, which stops a drain, wait or stream it drives.
+func (h *AppConfigHandler) CancelOperation(c echo.Context) error {
+	id := c.Param("id")
+	op, ok := operations.Cancel(id)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("operation '%s' not found", id))
+	}
+	log.Info("audit: operation cancelled", "id", id, "method", op.Method, "path", op.Path,
+		"config", op.Config, "cluster", op.Cluster, "remoteAddr", c.RealIP())
+	return c.JSON(http.StatusOK, op)
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const maxKubeconfigSize = 1024 * 1024 // 1MB
+
+var errKubeconfigTooLarge = errors.New("kubeconfig too large (max 1MB)")
+
+// readKubeconfig reads the kubeconfig of Post: the "file" form field, as an
+// uploaded file or a plain value, or the raw request body.
+func readKubeconfig(c echo.Context) ([]byte, error) {
+	var r io.Reader = c.Request().Body
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMEMultipartForm):
+		fh, err := c.FormFile("file")
+		if err != nil {
+			r = strings.NewReader(c.FormValue("file"))
+			break
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	case strings.HasPrefix(contentType, echo.MIMEApplicationForm):
+		r = strings.NewReader(c.FormValue("file"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxKubeconfigSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxKubeconfigSize {
+		return nil, errKubeconfigTooLarge
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, errors.New("kubeconfig is empty")
+	}
+	return data, nil
+}
+
+func validateKubeconfig(data []byte) error {
+	cfg, err := clientcmd.Load(data)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if len(cfg.Contexts) == 0 {
+		return errors.New("kubeconfig has no contexts")
+	}
+	if err := clientcmd.Validate(*cfg); err != nil {
+		return fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	return nil
+}
+
+func generateConfigName() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return "config-" + hex.EncodeToString(b)
+}
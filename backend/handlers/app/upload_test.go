@@ -0,0 +1,101 @@
+package app
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: kind
+contexts:
+- context:
+    cluster: kind
+    user: kind
+  name: kind
+current-context: kind
+users:
+- name: kind
+  user:
+    token: abc
+`
+
+func TestValidateKubeconfig(t *testing.T) {
+	assert.NoError(t, validateKubeconfig([]byte(testKubeconfig)))
+	assert.Error(t, validateKubeconfig([]byte("not: [yaml")))
+	assert.Error(t, validateKubeconfig([]byte("apiVersion: v1\nkind: Config\n")))
+	assert.Error(t, validateKubeconfig([]byte(strings.Replace(testKubeconfig, "cluster: kind\n    user", "cluster: missing\n    user", 1))))
+}
+
+func TestReadKubeconfig(t *testing.T) {
+	t.Run("raw body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testKubeconfig))
+		data, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.NoError(t, err)
+		assert.Equal(t, testKubeconfig, string(data))
+	})
+
+	t.Run("multipart file", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		part, _ := w.CreateFormFile("file", "config")
+		part.Write([]byte(testKubeconfig))
+		w.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+		data, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.NoError(t, err)
+		assert.Equal(t, testKubeconfig, string(data))
+	})
+
+	t.Run("multipart value", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		w.WriteField("file", testKubeconfig)
+		w.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+		data, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.NoError(t, err)
+		assert.Equal(t, testKubeconfig, string(data))
+	})
+
+	t.Run("urlencoded value", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{"file": {testKubeconfig}}.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		data, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.NoError(t, err)
+		assert.Equal(t, testKubeconfig, string(data))
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("  \n"))
+		_, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.Error(t, err)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", maxKubeconfigSize+1)))
+		_, err := readKubeconfig(echo.New().NewContext(req, httptest.NewRecorder()))
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerateConfigName(t *testing.T) {
+	name := generateConfigName()
+	assert.NoError(t, validateConfigName(name))
+	assert.NotEqual(t, name, generateConfigName())
+}
@@ -0,0 +1,56 @@
+package apply
+
+import (
+	"errors"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// allowedRESTMapper refuses to map resources blocked by the server
+// configuration, so no document of a blocked kind gets a dynamic client.
+type allowedRESTMapper struct {
+	meta.RESTMapper
+	check func(schema.GroupResource) error
+}
+
+func (m allowedRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapping, err := m.RESTMapper.RESTMapping(gk, versions...)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.check(mapping.Resource.GroupResource()); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// applyOptions returns the apply options of the selected cluster, checking
+// every mapped resource against the allow and block lists.
+func (h *ApplyHandler) applyOptions() *ApplyOptions {
+	config, cluster := h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster
+	return NewApplyOptions(h.BaseHandler.Container.DynamicClient(config, cluster), h.BaseHandler.Container.DiscoveryClient(config, cluster)).
+		WithResourceCheck(func(gr schema.GroupResource) error {
+			return helpers.CheckResourceAllowed(h.BaseHandler.Container, gr)
+		})
+}
+
+// checkDocumentsAllowed returns the error of the first document whose
+// resource is blocked, before anything is applied. Documents that can't be
+// mapped yet, e.g. custom resources of a CRD in the same manifest, are checked
+// when they are applied.
+func checkDocumentsAllowed(restMapper meta.RESTMapper, docs []unstructured.Unstructured) error {
+	for _, doc := range docs {
+		gvk := doc.GroupVersionKind()
+		if _, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) {
+				return httpErr
+			}
+		}
+	}
+	return nil
+}
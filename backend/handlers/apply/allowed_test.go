@@ -0,0 +1,45 @@
+package apply
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCheckDocumentsAllowed(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper := allowedRESTMapper{RESTMapper: mapper, check: func(gr schema.GroupResource) error {
+		if gr.Resource == "secrets" {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("%s is not allowed by the server configuration", gr))
+		}
+		return nil
+	}}
+	doc := func(apiVersion, kind string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetName("example")
+		return u
+	}
+
+	assert.NoError(t, checkDocumentsAllowed(restMapper, []unstructured.Unstructured{doc("v1", "ConfigMap")}))
+	assert.NoError(t, checkDocumentsAllowed(restMapper, []unstructured.Unstructured{doc("example.com/v1", "Widget")}),
+		"unknown kinds are left to the apply")
+
+	err := checkDocumentsAllowed(restMapper, []unstructured.Unstructured{doc("v1", "ConfigMap"), doc("v1", "Secret")})
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+
+	_, err = restMapper.RESTMapping(schema.GroupKind{Kind: "Secret"}, "v1")
+	assert.Error(t, err, "the mapper refuses blocked resources when documents are applied")
+}
@@ -1,14 +1,24 @@
 package apply
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/kubewall/kubewall/backend/container"
 	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
 	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
 )
 
-const POSTApply = 8
+const (
+	POSTApply             = 8
+	POSTApplyFromURL      = 9
+	POSTApplyBundle       = 10
+	POSTValidate          = 11
+	POSTApplyBundleStream = 12
+)
 
 type ApplyHandler struct {
 	BaseHandler base.BaseHandler
@@ -30,6 +40,14 @@ func NewApplyHandler(container container.Container, routeType base.RouteType) ec
 		switch routeType {
 		case POSTApply:
 			return handler.PostApply(c)
+		case POSTApplyFromURL:
+			return handler.PostApplyFromURL(c)
+		case POSTApplyBundle:
+			return handler.PostApplyBundle(c)
+		case POSTValidate:
+			return handler.PostValidate(c)
+		case POSTApplyBundleStream:
+			return handler.PostApplyBundleStream(c)
 		default:
 			return echo.NewHTTPError(http.StatusNotFound, "Unknown route type")
 		}
@@ -37,9 +55,6 @@ func NewApplyHandler(container container.Container, routeType base.RouteType) ec
 }
 
 func (h *ApplyHandler) PostApply(c echo.Context) error {
-	dynamicClient := h.BaseHandler.Container.DynamicClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
-	discoveryClient := h.BaseHandler.Container.DiscoveryClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
-
 	yamlContent := c.FormValue("yaml")
 	if yamlContent == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "YAML is required")
@@ -47,28 +62,89 @@ func (h *ApplyHandler) PostApply(c echo.Context) error {
 
 	// Add size limit to prevent abuse
 	if len(yamlContent) > 1024*1024 { // 1MB limit
-		return echo.NewHTTPError(http.StatusBadRequest, "YAML content too large (max 1MB)")
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "YAML content too large (max 1MB)")
 	}
 
 	inputYaml := []byte(yamlContent)
 
-	if checkKubectlCLIPresent() {
+	strategy, err := ParseConflictStrategy(c.FormValue("conflictStrategy"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	applyOptions := h.applyOptions().WithConflictStrategy(strategy)
+	docs, err := Decode(inputYaml)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	restMapper, err := applyOptions.ToRESTMapper()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := checkDocumentsAllowed(restMapper, docs); err != nil {
+		return err
+	}
+
+	// The quota check is advisory unless strict=true, admission still has
+	// the final word.
+	warnings := h.quotaWarnings(c.Request().Context(), inputYaml)
+	if c.FormValue("strict") == "true" && len(warnings) > 0 {
+		return c.JSON(http.StatusForbidden, echo.Map{
+			"message":       warnings[0].Message,
+			"quotaWarnings": warnings,
+		})
+	}
+	response := echo.Map{}
+	if len(warnings) > 0 {
+		response["quotaWarnings"] = warnings
+	}
+
+	// kubectl apply is a three-way merge, it cannot honour the other strategies.
+	if strategy == ConflictMerge && checkKubectlCLIPresent() {
 		cluster, _ := h.BaseHandler.Container.Config().GetKubeConfigInfo(h.BaseHandler.QueryConfig)
 		output, err := applyYAML(c.Request().Context(), cluster.AbsolutePath, h.BaseHandler.QueryCluster, string(inputYaml))
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
-		return c.JSON(http.StatusOK, echo.Map{
-			"success": output,
-		})
+		response["success"] = output
+		return c.JSON(http.StatusOK, response)
 	}
 
-	applyOptions := NewApplyOptions(dynamicClient, discoveryClient)
-	err := applyOptions.Apply(c.Request().Context(), inputYaml)
+	err = applyOptions.Apply(c.Request().Context(), inputYaml)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		var conflictErr *ApplyConflictError
+		if errors.As(err, &conflictErr) {
+			return c.JSON(http.StatusConflict, echo.Map{
+				"message":   conflictErr.Error(),
+				"kind":      conflictErr.Kind,
+				"name":      conflictErr.Name,
+				"namespace": conflictErr.Namespace,
+				"conflicts": conflictErr.Conflicts,
+			})
+		}
+		// A stale metadata.resourceVersion in the YAML fails with a Conflict.
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	response["success"] = true
+	return c.JSON(http.StatusOK, response)
+}
+
+// quotaWarnings runs the quota preflight for the documents in data. A failing
+// preflight, e.g. without permission to list quotas, does not block the apply.
+func (h *ApplyHandler) quotaWarnings(ctx context.Context, data []byte) []QuotaWarning {
+	config, cluster := h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster
+	docs, err := Decode(data)
+	if err != nil {
+		return nil
+	}
+	restMapper, err := h.applyOptions().ToRESTMapper()
+	if err != nil {
+		return nil
+	}
+	warnings, err := quotaPreflight(ctx, h.BaseHandler.Container.ClientSet(config, cluster), h.BaseHandler.Container.DynamicClient(config, cluster), restMapper, docs)
+	if err != nil {
+		klog.Warningf("skipping quota preflight: %v", err)
+		return nil
 	}
-	return c.JSON(http.StatusOK, echo.Map{
-		"success": true,
-	})
+	return warnings
 }
@@ -0,0 +1,292 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	statusCreated   = "created"
+	statusUpdated   = "updated"
+	statusUnchanged = "unchanged"
+	statusFailed    = "failed"
+	statusSkipped   = "skipped"
+)
+
+// applyOrder ranks kinds so that everything a document depends on is applied
+// before it. Kinds not listed, which includes custom resources, go last.
+var applyOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+var applyRank = func() map[string]int {
+	rank := make(map[string]int, len(applyOrder))
+	for i, kind := range applyOrder {
+		rank[kind] = i
+	}
+	return rank
+}()
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+const crdEstablishTimeout = 30 * time.Second
+
+// documentApplier applies one document. pendingCRDs are the definitions
+// applied since the previous call, which must be established first.
+type documentApplier func(ctx context.Context, doc unstructured.Unstructured, pendingCRDs []string) (string, error)
+
+// PostApplyBundle server-side applies a multi-document YAML in dependency
+// order and reports whether each document was created, updated or left
+// unchanged. Unless continueOnError is set, the first failure stops the apply
+// and the remaining documents are reported as skipped.
+func (h *ApplyHandler) PostApplyBundle(c echo.Context) error {
+	docs, continueOnError, err := bundleRequest(c)
+	if err != nil {
+		return err
+	}
+	apply, err := h.documentApplier(docs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]DocumentResult, 0, len(docs))
+	applyBundle(c.Request().Context(), docs, continueOnError, apply, func(result DocumentResult) {
+		results = append(results, result)
+	})
+	return c.JSON(http.StatusOK, results)
+}
+
+func bundleRequest(c echo.Context) ([]unstructured.Unstructured, bool, error) {
+	yamlContent := c.FormValue("yaml")
+	if yamlContent == "" {
+		return nil, false, echo.NewHTTPError(http.StatusBadRequest, "YAML is required")
+	}
+	if len(yamlContent) > maxManifestSize {
+		return nil, false, echo.NewHTTPError(http.StatusRequestEntityTooLarge, "YAML content too large (max 1MB)")
+	}
+
+	continueOnError := false
+	if v := c.FormValue("continueOnError"); v != "" {
+		var err error
+		if continueOnError, err = strconv.ParseBool(v); err != nil {
+			return nil, false, echo.NewHTTPError(http.StatusBadRequest, "continueOnError must be a boolean")
+		}
+	}
+
+	docs, err := Decode([]byte(yamlContent))
+	if err != nil {
+		return nil, false, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if len(docs) == 0 {
+		return nil, false, echo.NewHTTPError(http.StatusBadRequest, "manifest contains no documents")
+	}
+	return docs, continueOnError, nil
+}
+
+// documentApplier returns the applier of the selected cluster. Custom
+// resources can only be mapped once their definitions are established and
+// discovery has picked them up, so the REST mapper is rebuilt after CRDs.
+// Documents of blocked resources fail the whole request before anything is
+// applied.
+func (h *ApplyHandler) documentApplier(docs []unstructured.Unstructured) (documentApplier, error) {
+	dynamicClient := h.BaseHandler.Container.DynamicClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	applyOptions := h.applyOptions()
+	restMapper, err := applyOptions.ToRESTMapper()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := checkDocumentsAllowed(restMapper, docs); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, doc unstructured.Unstructured, pendingCRDs []string) (string, error) {
+		if len(pendingCRDs) > 0 {
+			if err := waitForCRDs(ctx, dynamicClient, pendingCRDs); err != nil {
+				return "", err
+			}
+			mapper, err := applyOptions.ToRESTMapper()
+			if err != nil {
+				return "", err
+			}
+			restMapper = mapper
+		}
+		return applyDocument(ctx, dynamicClient, restMapper, doc)
+	}, nil
+}
+
+// applyBundle applies docs in dependency order and reports the result of
+// every document as soon as it is known.
+func applyBundle(ctx context.Context, docs []unstructured.Unstructured, continueOnError bool, apply documentApplier, report func(DocumentResult)) {
+	var pendingCRDs []string
+	failed := false
+	for _, i := range sortForApply(docs) {
+		doc := docs[i]
+		result := DocumentResult{Index: i, Kind: doc.GetKind(), Name: doc.GetName(), Namespace: doc.GetNamespace()}
+		if failed && !continueOnError {
+			result.Status = statusSkipped
+			report(result)
+			continue
+		}
+
+		var waitFor []string
+		if !isCRD(doc) {
+			waitFor, pendingCRDs = pendingCRDs, nil
+		}
+		status, err := apply(ctx, doc, waitFor)
+		if err != nil {
+			failed = true
+			result.Status = statusFailed
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Status = status
+			if isCRD(doc) {
+				pendingCRDs = append(pendingCRDs, doc.GetName())
+			}
+		}
+		report(result)
+	}
+}
+
+// sortForApply returns the indexes of docs in apply order. Documents of the
+// same rank keep their order in the bundle.
+func sortForApply(docs []unstructured.Unstructured) []int {
+	order := make([]int, len(docs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return kindRank(docs[order[a]].GetKind()) < kindRank(docs[order[b]].GetKind())
+	})
+	return order
+}
+
+func kindRank(kind string) int {
+	if rank, ok := applyRank[kind]; ok {
+		return rank
+	}
+	return len(applyOrder)
+}
+
+func isCRD(doc unstructured.Unstructured) bool {
+	return doc.GetKind() == "CustomResourceDefinition" && doc.GroupVersionKind().Group == crdResource.Group
+}
+
+// applyDocument server-side applies doc and compares resource versions to
+// tell whether it was created, updated or unchanged.
+func applyDocument(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, doc unstructured.Unstructured) (string, error) {
+	dri, err := resourceInterface(dynamicClient, restMapper, doc)
+	if err != nil {
+		return "", err
+	}
+
+	previousVersion := ""
+	current, err := dri.Get(ctx, doc.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		previousVersion = current.GetResourceVersion()
+	case !apierrors.IsNotFound(err):
+		return "", err
+	}
+
+	applied, err := ApplyUnstructured(ctx, dynamicClient, restMapper, doc, true)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case previousVersion == "":
+		return statusCreated, nil
+	case applied != nil && applied.GetResourceVersion() == previousVersion:
+		return statusUnchanged, nil
+	default:
+		return statusUpdated, nil
+	}
+}
+
+func resourceInterface(dynamicClient dynamic.Interface, restMapper meta.RESTMapper, doc unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := doc.GroupVersionKind()
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return dynamicClient.Resource(mapping.Resource), nil
+	}
+	namespace := doc.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	return dynamicClient.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+func waitForCRDs(ctx context.Context, dynamicClient dynamic.Interface, names []string) error {
+	for _, name := range names {
+		err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, crdEstablishTimeout, true, func(ctx context.Context) (bool, error) {
+			crd, err := dynamicClient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			return crdEstablished(crd), nil
+		})
+		if err != nil {
+			return fmt.Errorf("custom resource definition %s was not established: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if ok && condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
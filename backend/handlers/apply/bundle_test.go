@@ -0,0 +1,153 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSortForApply(t *testing.T) {
+	docs, err := Decode([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+`))
+	assert.NoError(t, err)
+
+	var names []string
+	for _, i := range sortForApply(docs) {
+		names = append(names, docs[i].GetName())
+	}
+	assert.Equal(t, []string{"app", "widgets.example.com", "first", "second", "app", "widget"}, names)
+}
+
+func TestCRDEstablished(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "NamesAccepted", "status": "True"},
+				map[string]any{"type": "Established", "status": "False"},
+			},
+		},
+	}}
+	assert.False(t, crdEstablished(crd))
+
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	conditions[1] = map[string]any{"type": "Established", "status": "True"}
+	assert.NoError(t, unstructured.SetNestedSlice(crd.Object, conditions, "status", "conditions"))
+	assert.True(t, crdEstablished(crd))
+}
+
+const testBundle = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: broken
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+`
+
+// fakeApplier fails the documents named in fail and records the pending CRDs
+// each document was applied with.
+func fakeApplier(fail string, waited map[string][]string) documentApplier {
+	return func(_ context.Context, doc unstructured.Unstructured, pendingCRDs []string) (string, error) {
+		waited[doc.GetName()] = pendingCRDs
+		if doc.GetName() == fail {
+			return "", errors.New("admission webhook denied the request")
+		}
+		return statusCreated, nil
+	}
+}
+
+func TestApplyBundle(t *testing.T) {
+	docs, err := Decode([]byte(testBundle))
+	require.NoError(t, err)
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		var results []DocumentResult
+		applyBundle(context.Background(), docs, false, fakeApplier("broken", map[string][]string{}), func(r DocumentResult) {
+			results = append(results, r)
+		})
+		assert.Equal(t, []DocumentResult{
+			{Index: 3, Kind: "Namespace", Name: "app", Success: true, Status: statusCreated},
+			{Index: 1, Kind: "CustomResourceDefinition", Name: "widgets.example.com", Success: true, Status: statusCreated},
+			{Index: 2, Kind: "ConfigMap", Name: "broken", Status: statusFailed, Error: "admission webhook denied the request"},
+			{Index: 0, Kind: "Widget", Name: "widget", Status: statusSkipped},
+		}, results)
+	})
+
+	t.Run("waits for definitions before the next document", func(t *testing.T) {
+		waited := map[string][]string{}
+		applyBundle(context.Background(), docs, true, fakeApplier("broken", waited), func(DocumentResult) {})
+		assert.Equal(t, []string{"widgets.example.com"}, waited["broken"])
+		assert.Empty(t, waited["widget"])
+	})
+}
+
+func TestStreamBundleApply(t *testing.T) {
+	docs, err := Decode([]byte(testBundle))
+	require.NoError(t, err)
+
+	var events []BundleApplyEvent
+	streamBundleApply(context.Background(), docs, true, fakeApplier("broken", map[string][]string{}), func(e BundleApplyEvent) {
+		events = append(events, e)
+	})
+
+	require.Len(t, events, 5)
+	for i, e := range events[:4] {
+		assert.Equal(t, BundleApplyDocument, e.Type)
+		assert.Equal(t, i+1, e.Completed)
+		assert.Equal(t, 4, e.Total)
+	}
+	assert.Equal(t, "broken", events[2].Name)
+	assert.Equal(t, statusFailed, events[2].Status)
+	assert.Equal(t, BundleApplyEvent{Type: BundleApplyDone, Completed: 4, Total: 4, Succeeded: 3, Failed: 1}, events[4])
+
+	data, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"document","index":3,"kind":"Namespace","name":"app","success":true,"status":"created","completed":1,"total":4}`, string(data))
+}
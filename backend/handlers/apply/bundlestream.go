@@ -0,0 +1,82 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+const (
+	BundleApplyDocument = "document"
+	BundleApplyDone     = "done"
+)
+
+// BundleApplyEvent is sent on the bundle apply stream: a "document" event
+// with the result of every document as it is applied, then a single "done".
+type BundleApplyEvent struct {
+	Type string `json:"type"`
+	*DocumentResult
+	// Completed documents so far, out of Total.
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	// Succeeded, Failed and Skipped are set on the done event.
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+	Skipped   int `json:"skipped,omitempty"`
+}
+
+// PostApplyBundleStream is PostApplyBundle with the result of every document
+// streamed as soon as it is applied, for manifests large enough that a single
+// response would look stuck. Closing the stream stops the apply.
+func (h *ApplyHandler) PostApplyBundleStream(c echo.Context) error {
+	docs, continueOnError, err := bundleRequest(c)
+	if err != nil {
+		return err
+	}
+	apply, err := h.documentApplier(docs)
+	if err != nil {
+		return err
+	}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-apply-bundle", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	sseServer.CreateStream(streamKey)
+
+	go streamBundleApply(c.Request().Context(), docs, continueOnError, apply, func(e BundleApplyEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			klog.Errorf("failed to marshal bundle apply event: %v", err)
+			return
+		}
+		sseServer.Publish(streamKey, &sse.Event{Data: data})
+	})
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func streamBundleApply(ctx context.Context, docs []unstructured.Unstructured, continueOnError bool, apply documentApplier, publish func(BundleApplyEvent)) {
+	done := BundleApplyEvent{Type: BundleApplyDone, Total: len(docs)}
+	applyBundle(ctx, docs, continueOnError, apply, func(result DocumentResult) {
+		done.Completed++
+		switch {
+		case result.Success:
+			done.Succeeded++
+		case result.Status == statusSkipped:
+			done.Skipped++
+		default:
+			done.Failed++
+		}
+		publish(BundleApplyEvent{Type: BundleApplyDocument, DocumentResult: &result, Completed: done.Completed, Total: done.Total})
+	})
+	if ctx.Err() == nil {
+		publish(done)
+	}
+}
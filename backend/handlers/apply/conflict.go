@@ -0,0 +1,64 @@
+package apply
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldConflict is a field of the applied object owned by another manager.
+type FieldConflict struct {
+	Field   string `json:"field"`
+	Manager string `json:"manager,omitempty"`
+	Message string `json:"message"`
+}
+
+// ApplyConflictError is returned by the fail conflict strategy when a
+// document sets fields owned by another field manager, e.g. Flux.
+type ApplyConflictError struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Conflicts []FieldConflict
+	err       error
+}
+
+func (e *ApplyConflictError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ApplyConflictError) Unwrap() error {
+	return e.err
+}
+
+// conflictManager matches the manager in messages like
+// `conflict with "flux" using apps/v1`.
+var conflictManager = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// fieldConflicts returns the managed fields conflicts of a server-side apply
+// error, none for any other error.
+func fieldConflicts(err error) []FieldConflict {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsConflict(err) {
+		return nil
+	}
+	details := status.Status().Details
+	if details == nil {
+		return nil
+	}
+
+	var conflicts []FieldConflict
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := FieldConflict{Field: cause.Field, Message: cause.Message}
+		if m := conflictManager.FindStringSubmatch(cause.Message); m != nil {
+			conflict.Manager = m[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
@@ -0,0 +1,43 @@
+package apply
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseConflictStrategy(t *testing.T) {
+	for in, want := range map[string]ConflictStrategy{"": ConflictMerge, "fail": ConflictFail, "force": ConflictForce, "merge": ConflictMerge} {
+		got, err := ParseConflictStrategy(in)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseConflictStrategy("replace")
+	assert.Error(t, err)
+}
+
+func TestFieldConflicts(t *testing.T) {
+	conflict := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    409,
+		Reason:  metav1.StatusReasonConflict,
+		Message: `Apply failed with 2 conflicts: conflict with "flux" using apps/v1: .spec.replicas`,
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+			{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "flux" using apps/v1`, Field: ".spec.replicas"},
+			{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" with subresource "scale" using apps/v1`, Field: ".spec.template.spec.containers[name=\"app\"].image"},
+		}},
+	}}
+
+	assert.Equal(t, []FieldConflict{
+		{Field: ".spec.replicas", Manager: "flux", Message: `conflict with "flux" using apps/v1`},
+		{Field: ".spec.template.spec.containers[name=\"app\"].image", Manager: "kubectl-edit", Message: `conflict with "kubectl-edit" with subresource "scale" using apps/v1`},
+	}, fieldConflicts(conflict))
+
+	// Optimistic lock failures are conflicts without managed fields details.
+	assert.Empty(t, fieldConflicts(apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", errors.New("object has been modified"))))
+	assert.Empty(t, fieldConflicts(errors.New("connection refused")))
+}
@@ -33,21 +33,67 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// ConflictStrategy decides what happens when applied fields are owned by
+// another field manager.
+type ConflictStrategy string
+
+const (
+	// ConflictFail server-side applies without force, so conflicts with other
+	// managers are returned instead of overwritten.
+	ConflictFail ConflictStrategy = "fail"
+	// ConflictForce server-side applies with force, taking ownership of
+	// conflicting fields.
+	ConflictForce ConflictStrategy = "force"
+	// ConflictMerge does a client-side three-way merge like kubectl apply.
+	ConflictMerge ConflictStrategy = "merge"
+)
+
+// ParseConflictStrategy parses the conflictStrategy form value. An empty value
+// keeps the kubectl three-way merge existing callers rely on.
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(s); strategy {
+	case "":
+		return ConflictMerge, nil
+	case ConflictFail, ConflictForce, ConflictMerge:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown conflict strategy %q, must be one of fail, force or merge", s)
+	}
+}
+
 type ApplyOptions struct {
-	dynamicClient   dynamic.Interface
-	discoveryClient discovery.DiscoveryInterface
-	serverSide      bool
+	dynamicClient    dynamic.Interface
+	discoveryClient  discovery.DiscoveryInterface
+	conflictStrategy ConflictStrategy
+	checkResource    func(schema.GroupResource) error
 }
 
 func NewApplyOptions(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface) *ApplyOptions {
 	return &ApplyOptions{
-		dynamicClient:   dynamicClient,
-		discoveryClient: discoveryClient,
+		dynamicClient:    dynamicClient,
+		discoveryClient:  discoveryClient,
+		conflictStrategy: ConflictMerge,
 	}
 }
 
 func (o *ApplyOptions) WithServerSide(serverSide bool) *ApplyOptions {
-	o.serverSide = serverSide
+	if serverSide {
+		o.conflictStrategy = ConflictForce
+	} else {
+		o.conflictStrategy = ConflictMerge
+	}
+	return o
+}
+
+func (o *ApplyOptions) WithConflictStrategy(strategy ConflictStrategy) *ApplyOptions {
+	o.conflictStrategy = strategy
+	return o
+}
+
+// WithResourceCheck makes the REST mapper fail for the resources check
+// rejects.
+func (o *ApplyOptions) WithResourceCheck(check func(schema.GroupResource) error) *ApplyOptions {
+	o.checkResource = check
 	return o
 }
 
@@ -58,6 +104,9 @@ func (o *ApplyOptions) ToRESTMapper() (meta.RESTMapper, error) {
 	}
 
 	mapper := restmapper.NewDiscoveryRESTMapper(gr)
+	if o.checkResource != nil {
+		return allowedRESTMapper{RESTMapper: mapper, check: o.checkResource}, nil
+	}
 	return mapper, nil
 }
 
@@ -73,7 +122,16 @@ func (o *ApplyOptions) Apply(ctx context.Context, data []byte) error {
 	}
 
 	for _, unstruct := range unstructList {
-		if _, err := ApplyUnstructured(ctx, o.dynamicClient, restmapper, unstruct, o.serverSide); err != nil {
+		if _, err := applyUnstructured(ctx, o.dynamicClient, restmapper, unstruct, o.conflictStrategy); err != nil {
+			if conflicts := fieldConflicts(err); len(conflicts) > 0 {
+				return &ApplyConflictError{
+					Kind:      unstruct.GetKind(),
+					Name:      unstruct.GetName(),
+					Namespace: unstruct.GetNamespace(),
+					Conflicts: conflicts,
+					err:       err,
+				}
+			}
 			return err
 		}
 		klog.V(2).Infof("%s/%s applyed", strings.ToLower(unstruct.GetKind()), unstruct.GetName())
@@ -122,6 +180,14 @@ func Decode(data []byte) ([]unstructured.Unstructured, error) {
 }
 
 func ApplyUnstructured(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, unstructuredObj unstructured.Unstructured, serverSide bool) (*unstructured.Unstructured, error) {
+	strategy := ConflictMerge
+	if serverSide {
+		strategy = ConflictForce
+	}
+	return applyUnstructured(ctx, dynamicClient, restMapper, unstructuredObj, strategy)
+}
+
+func applyUnstructured(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, unstructuredObj unstructured.Unstructured, strategy ConflictStrategy) (*unstructured.Unstructured, error) {
 	if len(unstructuredObj.GetName()) == 0 {
 		metadata, err := meta.Accessor(unstructuredObj)
 		if err != nil {
@@ -158,7 +224,7 @@ func ApplyUnstructured(ctx context.Context, dynamicClient dynamic.Interface, res
 		dri = dynamicClient.Resource(mapping.Resource)
 	}
 
-	if serverSide {
+	if strategy != ConflictMerge {
 		klog.V(2).Infof("Using server-side apply")
 		if _, ok := unstructuredObj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; ok {
 			annotations := unstructuredObj.GetAnnotations()
@@ -167,16 +233,21 @@ func ApplyUnstructured(ctx context.Context, dynamicClient dynamic.Interface, res
 		}
 		unstructuredObj.SetManagedFields(nil)
 		klog.V(4).Infof("Need remove managedFields before apply, %#v", unstructuredObj)
+		b, err := unstructuredObj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
 
-		force := true
+		force := strategy == ConflictForce
 		opts := metav1.PatchOptions{FieldManager: "k8sutil", Force: &force}
-		if _, err := dri.Patch(ctx, unstructuredObj.GetName(), types.ApplyPatchType, b, opts); err != nil {
+		applied, err := dri.Patch(ctx, unstructuredObj.GetName(), types.ApplyPatchType, b, opts)
+		if err != nil {
 			if isIncompatibleServerError(err) {
 				err = fmt.Errorf("server-side apply not available on the server: (%v)", err)
 			}
 			return nil, err
 		}
-		return nil, nil
+		return applied, nil
 	}
 
 	modified, err := util.GetModifiedConfiguration(obj, true, unstructured.UnstructuredJSONScheme)
@@ -0,0 +1,245 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	resourcequotas "github.com/kubewall/kubewall/backend/handlers/config/resourceQuotas"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// QuotaWarning is a document whose create would exceed what is left of a
+// ResourceQuota in its namespace.
+type QuotaWarning struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Quota     string `json:"quota"`
+	Resource  string `json:"resource"`
+	Requested string `json:"requested"`
+	Remaining string `json:"remaining"`
+	Message   string `json:"message"`
+}
+
+// newObject is a document that does not exist yet and the resource it maps to.
+type newObject struct {
+	doc      unstructured.Unstructured
+	resource schema.GroupResource
+}
+
+// standardRequests are the resources quota also charges under their bare
+// name, "cpu" being the same as "requests.cpu".
+var standardRequests = map[v1.ResourceName]bool{
+	v1.ResourceCPU:              true,
+	v1.ResourceMemory:           true,
+	v1.ResourceEphemeralStorage: true,
+}
+
+// quotaPreflight compares the documents that would be created against the
+// remaining quota of their namespaces. Documents that already exist are
+// updates and are left to admission, the change in usage is not known here.
+func quotaPreflight(ctx context.Context, clientSet kubernetes.Interface, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, docs []unstructured.Unstructured) ([]QuotaWarning, error) {
+	quotas := make(map[string][]v1.ResourceQuota)
+	var objects []newObject
+	for _, doc := range docs {
+		gvk := doc.GroupVersionKind()
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			continue
+		}
+		namespace := doc.GetNamespace()
+		if namespace == "" {
+			namespace = "default"
+			doc.SetNamespace(namespace)
+		}
+
+		if _, ok := quotas[namespace]; !ok {
+			list, err := clientSet.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			quotas[namespace] = list.Items
+		}
+		if len(quotas[namespace]) == 0 {
+			continue
+		}
+
+		if doc.GetName() != "" {
+			_, err = dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(ctx, doc.GetName(), metav1.GetOptions{})
+			switch {
+			case err == nil:
+				continue
+			case !apierrors.IsNotFound(err):
+				return nil, err
+			}
+		}
+		objects = append(objects, newObject{doc: doc, resource: mapping.Resource.GroupResource()})
+	}
+	return checkQuotas(quotas, objects), nil
+}
+
+// checkQuotas charges the objects in order against the remaining quota, so a
+// bundle that only fits in part warns about the documents that do not fit.
+// Scoped quotas are skipped, matching their scopes needs the admission logic.
+func checkQuotas(quotas map[string][]v1.ResourceQuota, objects []newObject) []QuotaWarning {
+	remaining := make(map[string]v1.ResourceList)
+	warnings := make([]QuotaWarning, 0)
+	for _, obj := range objects {
+		demand, err := quotaDemand(obj)
+		if err != nil {
+			continue
+		}
+		names := make([]string, 0, len(demand))
+		for name := range demand {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+
+		namespace := obj.doc.GetNamespace()
+		for _, quota := range quotas[namespace] {
+			if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+				continue
+			}
+			key := namespace + "/" + quota.Name
+			left, ok := remaining[key]
+			if !ok {
+				left = resourcequotas.Remaining(quota.Status)
+				remaining[key] = left
+			}
+
+			for _, name := range names {
+				available, limited := left[v1.ResourceName(name)]
+				if !limited {
+					continue
+				}
+				requested := demand[v1.ResourceName(name)]
+				if requested.Cmp(available) > 0 {
+					warnings = append(warnings, QuotaWarning{
+						Kind:      obj.doc.GetKind(),
+						Name:      obj.doc.GetName(),
+						Namespace: namespace,
+						Quota:     quota.Name,
+						Resource:  name,
+						Requested: requested.String(),
+						Remaining: available.String(),
+						Message:   fmt.Sprintf("%s %s needs %s %s but quota %s has %s left", obj.doc.GetKind(), obj.doc.GetName(), requested.String(), name, quota.Name, available.String()),
+					})
+				}
+				available.Sub(requested)
+				left[v1.ResourceName(name)] = available
+			}
+		}
+	}
+	return warnings
+}
+
+// quotaDemand is what creating obj adds to quota usage: its object count and,
+// for workloads, the requests and limits of the pods it starts right away.
+func quotaDemand(obj newObject) (v1.ResourceList, error) {
+	demand := v1.ResourceList{
+		v1.ResourceName("count/" + obj.resource.String()): resource.MustParse("1"),
+	}
+	if obj.resource.Group == "" {
+		switch obj.resource.Resource {
+		case "services", "configmaps", "secrets", "persistentvolumeclaims", "replicationcontrollers", "resourcequotas":
+			demand[v1.ResourceName(obj.resource.Resource)] = resource.MustParse("1")
+		}
+	}
+
+	switch obj.resource {
+	case schema.GroupResource{Resource: "persistentvolumeclaims"}:
+		pvc := new(v1.PersistentVolumeClaim)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.doc.Object, pvc); err != nil {
+			return nil, err
+		}
+		if storage, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			demand[v1.ResourceRequestsStorage] = storage
+			if class := pvc.Spec.StorageClassName; class != nil && *class != "" {
+				demand[v1.ResourceName(*class+".storageclass.storage.k8s.io/requests.storage")] = storage
+				demand[v1.ResourceName(*class+".storageclass.storage.k8s.io/persistentvolumeclaims")] = resource.MustParse("1")
+			}
+		}
+	case schema.GroupResource{Resource: "services"}:
+		svc := new(v1.Service)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.doc.Object, svc); err != nil {
+			return nil, err
+		}
+		switch svc.Spec.Type {
+		case v1.ServiceTypeLoadBalancer:
+			demand[v1.ResourceServicesLoadBalancers] = resource.MustParse("1")
+			fallthrough
+		case v1.ServiceTypeNodePort:
+			demand[v1.ResourceServicesNodePorts] = *resource.NewQuantity(int64(len(svc.Spec.Ports)), resource.DecimalSI)
+		}
+	}
+
+	spec, pods, err := podTemplate(obj)
+	if err != nil || spec == nil || pods == 0 {
+		return demand, err
+	}
+	demand[v1.ResourcePods] = *resource.NewQuantity(pods, resource.DecimalSI)
+	for name, quantity := range helpers.PodRequests(spec) {
+		total := scaleQuantity(quantity, pods)
+		demand[v1.ResourceName("requests."+string(name))] = total
+		if standardRequests[name] {
+			demand[name] = total
+		}
+	}
+	for name, quantity := range helpers.PodLimits(spec) {
+		demand[v1.ResourceName("limits."+string(name))] = scaleQuantity(quantity, pods)
+	}
+	return demand, nil
+}
+
+// podTemplate returns the pod spec of a workload and how many pods creating
+// it starts. DaemonSets and CronJobs are skipped, their pod count is not
+// known up front.
+func podTemplate(obj newObject) (*v1.PodSpec, int64, error) {
+	path := []string{"spec", "template", "spec"}
+	pods := int64(1)
+	switch obj.resource {
+	case schema.GroupResource{Resource: "pods"}:
+		path = []string{"spec"}
+	case schema.GroupResource{Group: "apps", Resource: "deployments"},
+		schema.GroupResource{Group: "apps", Resource: "replicasets"},
+		schema.GroupResource{Group: "apps", Resource: "statefulsets"},
+		schema.GroupResource{Resource: "replicationcontrollers"}:
+		if replicas, found, _ := unstructured.NestedInt64(obj.doc.Object, "spec", "replicas"); found {
+			pods = replicas
+		}
+	case schema.GroupResource{Group: "batch", Resource: "jobs"}:
+		if parallelism, found, _ := unstructured.NestedInt64(obj.doc.Object, "spec", "parallelism"); found {
+			pods = parallelism
+		}
+	default:
+		return nil, 0, nil
+	}
+
+	raw, found, err := unstructured.NestedMap(obj.doc.Object, path...)
+	if err != nil || !found {
+		return nil, 0, err
+	}
+	spec := new(v1.PodSpec)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, spec); err != nil {
+		return nil, 0, err
+	}
+	return spec, pods, nil
+}
+
+func scaleQuantity(quantity resource.Quantity, n int64) resource.Quantity {
+	return *resource.NewMilliQuantity(quantity.MilliValue()*n, quantity.Format)
+}
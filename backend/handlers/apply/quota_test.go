@@ -0,0 +1,111 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const quotaDocs = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: team
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          requests: {cpu: 250m, memory: 128Mi}
+          limits: {cpu: 500m}
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: team
+spec:
+  storageClassName: fast
+  resources:
+    requests: {storage: 10Gi}
+`
+
+func TestQuotaDemand(t *testing.T) {
+	docs, err := Decode([]byte(quotaDocs))
+	require.NoError(t, err)
+
+	demand, err := quotaDemand(newObject{doc: docs[0], resource: schema.GroupResource{Group: "apps", Resource: "deployments"}})
+	require.NoError(t, err)
+	assert.Equal(t, "3", demand.Pods().String())
+	assert.Equal(t, int64(750), demand.Name(v1.ResourceRequestsCPU, resource.DecimalSI).MilliValue())
+	assert.Equal(t, int64(750), demand.Cpu().MilliValue())
+	assert.Equal(t, int64(384<<20), demand.Name(v1.ResourceRequestsMemory, resource.BinarySI).Value())
+	assert.Equal(t, int64(1500), demand.Name(v1.ResourceLimitsCPU, resource.DecimalSI).MilliValue())
+	assert.Equal(t, "1", demand.Name("count/deployments.apps", resource.DecimalSI).String())
+
+	demand, err = quotaDemand(newObject{doc: docs[1], resource: schema.GroupResource{Resource: "persistentvolumeclaims"}})
+	require.NoError(t, err)
+	assert.Equal(t, "10Gi", demand.Name(v1.ResourceRequestsStorage, resource.BinarySI).String())
+	assert.Equal(t, "10Gi", demand.Name("fast.storageclass.storage.k8s.io/requests.storage", resource.BinarySI).String())
+	assert.Equal(t, "1", demand.Name(v1.ResourcePersistentVolumeClaims, resource.DecimalSI).String())
+}
+
+func TestCheckQuotas(t *testing.T) {
+	docs, err := Decode([]byte(quotaDocs + `---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+  namespace: team
+spec:
+  containers:
+  - name: shell
+    resources:
+      requests: {cpu: 100m}
+`))
+	require.NoError(t, err)
+	objects := []newObject{
+		{doc: docs[0], resource: schema.GroupResource{Group: "apps", Resource: "deployments"}},
+		{doc: docs[1], resource: schema.GroupResource{Resource: "persistentvolumeclaims"}},
+		{doc: docs[2], resource: schema.GroupResource{Resource: "pods"}},
+	}
+	quotas := map[string][]v1.ResourceQuota{"team": {
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute"},
+			Status: v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("1"), v1.ResourcePods: resource.MustParse("10")},
+				Used: v1.ResourceList{v1.ResourceRequestsCPU: resource.MustParse("200m"), v1.ResourcePods: resource.MustParse("2")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage"},
+			Status: v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourceRequestsStorage: resource.MustParse("5Gi")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "best-effort"},
+			Spec:       v1.ResourceQuotaSpec{Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeBestEffort}},
+			Status:     v1.ResourceQuotaStatus{Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("0")}},
+		},
+	}}
+
+	got := checkQuotas(quotas, objects)
+	require.Len(t, got, 2)
+	assert.Equal(t, QuotaWarning{
+		Kind: "PersistentVolumeClaim", Name: "data", Namespace: "team", Quota: "storage",
+		Resource: "requests.storage", Requested: "10Gi", Remaining: "5Gi",
+		Message: "PersistentVolumeClaim data needs 10Gi requests.storage but quota storage has 5Gi left",
+	}, got[0])
+	// The deployment fits (750m of 800m) but leaves too little for the pod.
+	assert.Equal(t, "debug", got[1].Name)
+	assert.Equal(t, "requests.cpu", got[1].Resource)
+	assert.Equal(t, "50m", got[1].Remaining)
+}
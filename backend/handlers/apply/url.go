@@ -0,0 +1,188 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxManifestSize = 1024 * 1024 // 1MB, same as PostApply
+
+// manifestContentTypes are the response types accepted for remote manifests.
+// Raw file hosts commonly serve YAML as text/plain or octet-stream.
+var manifestContentTypes = map[string]bool{
+	"application/yaml":         true,
+	"application/x-yaml":       true,
+	"text/yaml":                true,
+	"text/x-yaml":              true,
+	"application/json":         true,
+	"text/plain":               true,
+	"application/octet-stream": true,
+}
+
+// deniedPrefixes are address ranges that are not publicly routable. The server
+// holds cluster credentials, so remote manifests must never be fetched from
+// them.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+type DocumentResult struct {
+	Index     int    `json:"index"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Success   bool   `json:"success"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PostApplyFromURL fetches a manifest from an HTTPS URL and server-side
+// applies each of its documents, returning a result per document.
+func (h *ApplyHandler) PostApplyFromURL(c echo.Context) error {
+	rawURL := c.FormValue("url")
+	if rawURL == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "url is required")
+	}
+
+	data, err := fetchManifest(c.Request().Context(), newManifestClient(), rawURL)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	docs, err := Decode(data)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if len(docs) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "manifest contains no documents")
+	}
+
+	dynamicClient := h.BaseHandler.Container.DynamicClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	restMapper, err := h.applyOptions().ToRESTMapper()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := checkDocumentsAllowed(restMapper, docs); err != nil {
+		return err
+	}
+
+	results := make([]DocumentResult, 0, len(docs))
+	for i, doc := range docs {
+		result := DocumentResult{Index: i, Kind: doc.GetKind(), Name: doc.GetName(), Namespace: doc.GetNamespace()}
+		if _, err := ApplyUnstructured(c.Request().Context(), dynamicClient, restMapper, doc, true); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+// newManifestClient returns an HTTP client that refuses to connect to
+// non-public addresses. The check runs on the dialed address, after DNS
+// resolution, so rebinding a public name to an internal IP does not bypass it.
+func newManifestClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			return checkPublicAddress(address)
+		},
+	}
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to non-https url %s", req.URL.Redacted())
+			}
+			return nil
+		},
+	}
+}
+
+func fetchManifest(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("url must be an absolute https url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch manifest: %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || !manifestContentTypes[mediaType] {
+			return nil, fmt.Errorf("unsupported manifest content type %q", ct)
+		}
+	}
+	if resp.ContentLength > maxManifestSize {
+		return nil, errors.New("manifest too large (max 1MB)")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, errors.New("manifest too large (max 1MB)")
+	}
+	return data, nil
+}
+
+func checkPublicAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return fmt.Errorf("refusing to connect to non-public address %s", ip)
+	}
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("refusing to connect to non-public address %s", ip)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package apply
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPublicAddress(t *testing.T) {
+	denied := []string{
+		"127.0.0.1:443", "10.0.0.1:443", "172.16.5.4:443", "192.168.1.1:443",
+		"169.254.169.254:80", "100.64.0.1:443", "0.0.0.0:443",
+		"[::1]:443", "[fd00::1]:443", "[fe80::1]:443", "[::ffff:127.0.0.1]:443",
+	}
+	for _, address := range denied {
+		assert.Error(t, checkPublicAddress(address), address)
+	}
+
+	allowed := []string{"140.82.112.3:443", "[2606:50c0:8000::154]:443"}
+	for _, address := range allowed {
+		assert.NoError(t, checkPublicAddress(address), address)
+	}
+}
+
+func TestFetchManifest(t *testing.T) {
+	t.Run("rejects non-https urls", func(t *testing.T) {
+		_, err := fetchManifest(context.Background(), newManifestClient(), "http://example.com/manifest.yaml")
+		assert.ErrorContains(t, err, "https")
+	})
+
+	t.Run("refuses to connect to internal addresses", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("kind: ConfigMap"))
+		}))
+		defer srv.Close()
+
+		_, err := fetchManifest(context.Background(), newManifestClient(), srv.URL)
+		assert.ErrorContains(t, err, "non-public address")
+	})
+
+	t.Run("enforces content type and size", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/page":
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = w.Write([]byte("<html></html>"))
+			case "/large":
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(strings.Repeat("a", maxManifestSize+1)))
+			default:
+				w.Header().Set("Content-Type", "application/yaml")
+				_, _ = w.Write([]byte("kind: ConfigMap"))
+			}
+		}))
+		defer srv.Close()
+
+		// The test server listens on loopback, so use its client, which skips
+		// the address check, to exercise the response handling.
+		_, err := fetchManifest(context.Background(), srv.Client(), srv.URL+"/page")
+		assert.ErrorContains(t, err, "content type")
+
+		_, err = fetchManifest(context.Background(), srv.Client(), srv.URL+"/large")
+		assert.ErrorContains(t, err, "too large")
+
+		data, err := fetchManifest(context.Background(), srv.Client(), srv.URL+"/manifest.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, "kind: ConfigMap", string(data))
+	})
+}
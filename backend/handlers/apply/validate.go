@@ -0,0 +1,158 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	ValidationSchema    = "schema"
+	ValidationAdmission = "admission"
+	ValidationOther     = "other"
+)
+
+type ValidationError struct {
+	// Type is "schema" for errors from the API server's own validation,
+	// "admission" for rejections by webhooks or admission policies, and
+	// "other" for everything else, e.g. missing permissions.
+	Type    string `json:"type"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ValidationResult struct {
+	Index     int               `json:"index"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Valid     bool              `json:"valid"`
+	Errors    []ValidationError `json:"errors,omitempty"`
+}
+
+type ValidationResponse struct {
+	Valid   bool               `json:"valid"`
+	Results []ValidationResult `json:"results"`
+}
+
+// PostValidate runs a server-side dry-run apply of each document so schema
+// violations and admission rejections surface without persisting anything.
+func (h *ApplyHandler) PostValidate(c echo.Context) error {
+	yamlContent := c.FormValue("yaml")
+	if yamlContent == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "YAML is required")
+	}
+	if len(yamlContent) > maxManifestSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "YAML content too large (max 1MB)")
+	}
+
+	docs, err := Decode([]byte(yamlContent))
+	if err != nil {
+		return c.JSON(http.StatusOK, ValidationResponse{
+			Results: []ValidationResult{{Errors: []ValidationError{{Type: ValidationSchema, Message: err.Error()}}}},
+		})
+	}
+	if len(docs) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "manifest contains no documents")
+	}
+
+	dynamicClient := h.BaseHandler.Container.DynamicClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	restMapper, err := h.applyOptions().ToRESTMapper()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := checkDocumentsAllowed(restMapper, docs); err != nil {
+		return err
+	}
+
+	response := ValidationResponse{Valid: true, Results: make([]ValidationResult, 0, len(docs))}
+	for i, doc := range docs {
+		result := ValidationResult{Index: i, Kind: doc.GetKind(), Name: doc.GetName(), Namespace: doc.GetNamespace(), Valid: true}
+		if err := dryRunApply(c.Request().Context(), dynamicClient, restMapper, doc); err != nil {
+			result.Valid = false
+			result.Errors = classifyValidationError(err)
+			response.Valid = false
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func dryRunApply(ctx context.Context, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, doc unstructured.Unstructured) error {
+	if doc.GetName() == "" {
+		return errors.New("metadata.name is required for apply")
+	}
+	dri, err := resourceInterface(dynamicClient, restMapper, doc)
+	if err != nil {
+		return err
+	}
+
+	annotations := doc.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	doc.SetAnnotations(annotations)
+	doc.SetManagedFields(nil)
+	b, err := doc.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	force := true
+	_, err = dri.Patch(ctx, doc.GetName(), types.ApplyPatchType, b, metav1.PatchOptions{
+		DryRun:          []string{metav1.DryRunAll},
+		FieldManager:    "k8sutil",
+		FieldValidation: metav1.FieldValidationStrict,
+		Force:           &force,
+	})
+	return err
+}
+
+// classifyValidationError splits an apply error into per-field validation
+// errors, telling admission rejections apart from schema violations.
+func classifyValidationError(err error) []ValidationError {
+	if meta.IsNoMatchError(err) {
+		return []ValidationError{{Type: ValidationSchema, Message: err.Error()}}
+	}
+
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return []ValidationError{{Type: ValidationOther, Message: err.Error()}}
+	}
+	s := status.Status()
+
+	if isAdmissionRejection(s.Message) {
+		return []ValidationError{{Type: ValidationAdmission, Message: s.Message}}
+	}
+
+	switch {
+	case s.Reason == metav1.StatusReasonInvalid:
+		if s.Details == nil || len(s.Details.Causes) == 0 {
+			return []ValidationError{{Type: ValidationSchema, Message: s.Message}}
+		}
+		errs := make([]ValidationError, 0, len(s.Details.Causes))
+		for _, cause := range s.Details.Causes {
+			errs = append(errs, ValidationError{Type: ValidationSchema, Field: cause.Field, Message: cause.Message})
+		}
+		return errs
+	case s.Reason == metav1.StatusReasonBadRequest:
+		// Strict field validation and malformed values come back as bad requests.
+		return []ValidationError{{Type: ValidationSchema, Message: s.Message}}
+	default:
+		return []ValidationError{{Type: ValidationOther, Message: s.Message}}
+	}
+}
+
+func isAdmissionRejection(message string) bool {
+	return strings.Contains(message, "admission webhook") ||
+		strings.Contains(message, "ValidatingAdmissionPolicy")
+}
@@ -0,0 +1,42 @@
+package apply
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestClassifyValidationError(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+
+	invalid := apierrors.NewInvalid(gk, "app", field.ErrorList{
+		field.Required(field.NewPath("spec", "selector"), ""),
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+	})
+	errs := classifyValidationError(invalid)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, ValidationSchema, errs[0].Type)
+	assert.Equal(t, "spec.selector", errs[0].Field)
+	assert.Equal(t, "spec.replicas", errs[1].Field)
+
+	webhook := apierrors.NewBadRequest(`admission webhook "validate.example.com" denied the request: image tag latest is not allowed`)
+	assert.Equal(t, []ValidationError{{Type: ValidationAdmission, Message: webhook.Error()}}, classifyValidationError(webhook))
+
+	policy := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app",
+		errors.New("ValidatingAdmissionPolicy 'replicas' with binding 'replicas' denied request"))
+	assert.Equal(t, ValidationAdmission, classifyValidationError(policy)[0].Type)
+
+	strict := apierrors.NewBadRequest(`.spec.replica: field not declared in schema`)
+	assert.Equal(t, ValidationSchema, classifyValidationError(strict)[0].Type)
+
+	noMatch := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"}, SearchedVersions: []string{"v1"}}
+	assert.Equal(t, ValidationSchema, classifyValidationError(noMatch)[0].Type)
+
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", errors.New("user cannot patch"))
+	assert.Equal(t, ValidationOther, classifyValidationError(forbidden)[0].Type)
+}
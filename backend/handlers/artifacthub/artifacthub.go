@@ -0,0 +1,286 @@
+package artifacthub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	artifactHubURL = "https://artifacthub.io/api/v1"
+	// helmKind is the Artifact Hub repository kind of Helm charts.
+	helmKind = "0"
+
+	searchCacheTTL = 10 * time.Minute
+	schemaCacheTTL = time.Hour
+
+	searchCacheKeyFormat  = "artifacthub-search-%s-%d-%d"
+	schemaCacheKeyFormat  = "artifacthub-schema-%s-%s"
+	pkgIDRepoPathFormat   = "artifacthub-pkg-repo-path-%s"
+	repoPathPkgIDFormat   = "artifacthub-repo-path-pkg-%s"
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 60
+	maxArtifactHubPayload = 5 * 1024 * 1024
+)
+
+// errNotFound is returned for packages, versions or schemas Artifact Hub
+// does not know.
+var errNotFound = errors.New("not found on Artifact Hub")
+
+type Repository struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	URL         string `json:"url"`
+	Official    bool   `json:"official"`
+	Verified    bool   `json:"verifiedPublisher"`
+}
+
+type Chart struct {
+	PackageID   string     `json:"packageId"`
+	Name        string     `json:"name"`
+	Version     string     `json:"version"`
+	AppVersion  string     `json:"appVersion,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Stars       int        `json:"stars"`
+	Repository  Repository `json:"repository"`
+	// RepoPath is the chart reference as "repository/chart".
+	RepoPath string `json:"repoPath"`
+}
+
+type SearchResult struct {
+	Charts []Chart `json:"charts"`
+}
+
+type ValuesSchema struct {
+	PackageID string          `json:"packageId"`
+	RepoPath  string          `json:"repoPath"`
+	Version   string          `json:"version"`
+	Schema    json.RawMessage `json:"schema"`
+}
+
+// artifactHubPackage is the subset of an Artifact Hub package we read.
+type artifactHubPackage struct {
+	PackageID   string `json:"package_id"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+	Stars       int    `json:"stars"`
+	Repository  struct {
+		Name              string `json:"name"`
+		DisplayName       string `json:"display_name"`
+		URL               string `json:"url"`
+		Official          bool   `json:"official"`
+		VerifiedPublisher bool   `json:"verified_publisher"`
+	} `json:"repository"`
+}
+
+type ArtifactHubHandler struct {
+	container container.Container
+	client    *http.Client
+	baseURL   string
+}
+
+func NewArtifactHubHandler(container container.Container) *ArtifactHubHandler {
+	return &ArtifactHubHandler{
+		container: container,
+		client:    &http.Client{Timeout: 15 * time.Second},
+		baseURL:   artifactHubURL,
+	}
+}
+
+// SearchCharts searches Artifact Hub for Helm charts. Results are cached
+// briefly and remember the package ID of every chart they list, so a later
+// values schema request by repository path needs no extra lookup.
+func (h *ArtifactHubHandler) SearchCharts(c echo.Context) error {
+	query := c.QueryParam("q")
+	if query == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "q query param is required")
+	}
+	limit := defaultSearchLimit
+	if v := c.QueryParam("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > maxSearchLimit {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit))
+		}
+		limit = parsed
+	}
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if c.QueryParam("offset") != "" && (err != nil || offset < 0) {
+		return echo.NewHTTPError(http.StatusBadRequest, "offset must be a non-negative integer")
+	}
+
+	result, err := h.searchCharts(c.Request().Context(), query, limit, offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetChartValuesSchema returns the values.schema.json of a chart, picked by
+// packageId and version, or by repo and chart name at version or the latest
+// version.
+func (h *ArtifactHubHandler) GetChartValuesSchema(c echo.Context) error {
+	packageID, version := c.QueryParam("packageId"), c.QueryParam("version")
+	repo, chart := c.QueryParam("repo"), c.QueryParam("chart")
+	switch {
+	case packageID != "" && version == "":
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required with packageId")
+	case packageID == "" && (repo == "" || chart == ""):
+		return echo.NewHTTPError(http.StatusBadRequest, "packageId or repo and chart query params are required")
+	}
+
+	schema, err := h.valuesSchema(c.Request().Context(), packageID, repo, chart, version)
+	switch {
+	case errors.Is(err, errNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	return c.JSON(http.StatusOK, schema)
+}
+
+func (h *ArtifactHubHandler) searchCharts(ctx context.Context, query string, limit, offset int) (SearchResult, error) {
+	cacheKey := fmt.Sprintf(searchCacheKeyFormat, query, limit, offset)
+	if cached, ok := h.container.Cache().GetIfPresent(cacheKey); ok {
+		if result, ok := cached.(SearchResult); ok {
+			return result, nil
+		}
+	}
+
+	params := url.Values{
+		"ts_query_web": {query},
+		"kind":         {helmKind},
+		"limit":        {strconv.Itoa(limit)},
+		"offset":       {strconv.Itoa(offset)},
+	}
+	var response struct {
+		Packages []artifactHubPackage `json:"packages"`
+	}
+	if err := h.get(ctx, "/packages/search?"+params.Encode(), &response); err != nil {
+		return SearchResult{}, err
+	}
+
+	result := SearchResult{Charts: make([]Chart, 0, len(response.Packages))}
+	for _, pkg := range response.Packages {
+		chart := toChart(pkg)
+		h.rememberRepoPath(chart.PackageID, chart.RepoPath)
+		result.Charts = append(result.Charts, chart)
+	}
+	h.container.Cache().Set(cacheKey, result)
+	h.container.Cache().SetExpiresAfter(cacheKey, searchCacheTTL)
+	return result, nil
+}
+
+func (h *ArtifactHubHandler) valuesSchema(ctx context.Context, packageID, repo, chart, version string) (ValuesSchema, error) {
+	repoPath := repo + "/" + chart
+	if packageID != "" {
+		repoPath = ""
+		if cached, ok := h.container.Cache().GetIfPresent(fmt.Sprintf(pkgIDRepoPathFormat, packageID)); ok {
+			repoPath, _ = cached.(string)
+		}
+	} else if cached, ok := h.container.Cache().GetIfPresent(fmt.Sprintf(repoPathPkgIDFormat, repoPath)); ok {
+		packageID, _ = cached.(string)
+	}
+
+	// The schema endpoint needs a package ID and a version. Charts not seen
+	// in a search and requests for the latest version are looked up first.
+	if packageID == "" || version == "" {
+		path := "/packages/helm/" + url.PathEscape(repo) + "/" + url.PathEscape(chart)
+		if version != "" {
+			path += "/" + url.PathEscape(version)
+		}
+		var pkg artifactHubPackage
+		if err := h.get(ctx, path, &pkg); err != nil {
+			if errors.Is(err, errNotFound) {
+				return ValuesSchema{}, fmt.Errorf("chart %s: %w", repoPath, err)
+			}
+			return ValuesSchema{}, err
+		}
+		found := toChart(pkg)
+		h.rememberRepoPath(found.PackageID, found.RepoPath)
+		packageID, repoPath, version = found.PackageID, found.RepoPath, found.Version
+	}
+
+	cacheKey := fmt.Sprintf(schemaCacheKeyFormat, packageID, version)
+	if cached, ok := h.container.Cache().GetIfPresent(cacheKey); ok {
+		if schema, ok := cached.(ValuesSchema); ok {
+			return schema, nil
+		}
+	}
+
+	var raw json.RawMessage
+	if err := h.get(ctx, "/packages/"+url.PathEscape(packageID)+"/"+url.PathEscape(version)+"/values-schema", &raw); err != nil {
+		if errors.Is(err, errNotFound) {
+			return ValuesSchema{}, fmt.Errorf("chart %s %s has no values.schema.json: %w", repoPath, version, err)
+		}
+		return ValuesSchema{}, err
+	}
+	schema := ValuesSchema{PackageID: packageID, RepoPath: repoPath, Version: version, Schema: raw}
+	h.container.Cache().Set(cacheKey, schema)
+	h.container.Cache().SetExpiresAfter(cacheKey, schemaCacheTTL)
+	return schema, nil
+}
+
+// rememberRepoPath records both directions of the package ID to
+// "repository/chart" mapping.
+func (h *ArtifactHubHandler) rememberRepoPath(packageID, repoPath string) {
+	if packageID == "" {
+		return
+	}
+	h.container.Cache().Set(fmt.Sprintf(pkgIDRepoPathFormat, packageID), repoPath)
+	h.container.Cache().Set(fmt.Sprintf(repoPathPkgIDFormat, repoPath), packageID)
+}
+
+func (h *ArtifactHubHandler) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Artifact Hub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected Artifact Hub response: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactHubPayload))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+func toChart(pkg artifactHubPackage) Chart {
+	return Chart{
+		PackageID:   pkg.PackageID,
+		Name:        pkg.Name,
+		Version:     pkg.Version,
+		AppVersion:  pkg.AppVersion,
+		Description: pkg.Description,
+		Stars:       pkg.Stars,
+		Repository: Repository{
+			Name:        pkg.Repository.Name,
+			DisplayName: pkg.Repository.DisplayName,
+			URL:         pkg.Repository.URL,
+			Official:    pkg.Repository.Official,
+			Verified:    pkg.Repository.VerifiedPublisher,
+		},
+		RepoPath: pkg.Repository.Name + "/" + pkg.Name,
+	}
+}
@@ -0,0 +1,74 @@
+package artifacthub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) (*ArtifactHubHandler, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/packages/search":
+			assert.Equal(t, "redis", r.URL.Query().Get("ts_query_web"))
+			assert.Equal(t, "0", r.URL.Query().Get("kind"))
+			w.Write([]byte(`{"packages":[{"package_id":"p1","name":"redis","version":"20.1.0","app_version":"7.4","repository":{"name":"bitnami","url":"https://charts.bitnami.com/bitnami","verified_publisher":true}}]}`))
+		case "/packages/helm/bitnami/nginx":
+			w.Write([]byte(`{"package_id":"p2","name":"nginx","version":"18.0.0","repository":{"name":"bitnami"}}`))
+		case "/packages/p1/20.1.0/values-schema", "/packages/p2/18.0.0/values-schema":
+			w.Write([]byte(`{"type":"object","properties":{"replicaCount":{"type":"integer"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	appContainer := container.NewContainer(&config.Env{}, config.NewAppConfig("test", ":0", 10, 10, false))
+	h := NewArtifactHubHandler(appContainer)
+	h.baseURL = server.URL
+	return h, &calls
+}
+
+func TestSearchCharts(t *testing.T) {
+	h, calls := newTestHandler(t)
+
+	result, err := h.searchCharts(context.Background(), "redis", 20, 0)
+	require.NoError(t, err)
+	require.Len(t, result.Charts, 1)
+	assert.Equal(t, "bitnami/redis", result.Charts[0].RepoPath)
+	assert.True(t, result.Charts[0].Repository.Verified)
+
+	_, err = h.searchCharts(context.Background(), "redis", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// The search remembered the package ID, the schema needs a single request.
+	schema, err := h.valuesSchema(context.Background(), "", "bitnami", "redis", "20.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "p1", schema.PackageID)
+	assert.JSONEq(t, `{"type":"object","properties":{"replicaCount":{"type":"integer"}}}`, string(schema.Schema))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestValuesSchema(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	schema, err := h.valuesSchema(context.Background(), "", "bitnami", "nginx", "")
+	require.NoError(t, err)
+	assert.Equal(t, ValuesSchema{PackageID: "p2", RepoPath: "bitnami/nginx", Version: "18.0.0", Schema: schema.Schema}, schema)
+
+	_, err = h.valuesSchema(context.Background(), "p2", "", "", "17.0.0")
+	assert.ErrorIs(t, err, errNotFound)
+
+	_, err = h.valuesSchema(context.Background(), "", "bitnami", "missing", "")
+	assert.ErrorIs(t, err, errNotFound)
+}
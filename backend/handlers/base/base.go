@@ -1,10 +1,13 @@
 package base
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/gorilla/websocket"
 	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
 	"github.com/labstack/echo/v4"
 	"github.com/r3labs/sse/v2"
 	"k8s.io/client-go/rest"
@@ -21,6 +24,7 @@ const (
 	Delete
 	GetLogs
 	Create
+	SetImage
 )
 
 type BaseHandler struct {
@@ -34,14 +38,33 @@ type BaseHandler struct {
 	InformerCacheKey string
 
 	TransformFunc func([]any, *BaseHandler) ([]byte, error)
+	// DetailTransformFunc, when set, replaces an item before it is sent on a
+	// details stream.
+	DetailTransformFunc func(any) any
 }
 
 func (h *BaseHandler) GetList(c echo.Context) error {
-	streamID := fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind)
+	if websocket.IsWebSocketUpgrade(c.Request()) {
+		view, err := h.listView(c)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		h.serveListWatch(c, view)
+		return nil
+	}
+
+	streamID, release, err := h.listStreamID(c)
+	if errors.Is(err, helpers.ErrTooManyViews) {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer release()
 	// Handlers are cached across requests, so publish the current list for
 	// this new subscriber instead of relying on construction-time sync.
-	h.Container.EventProcessor().AddEvent(streamID, h.processListEvents(""))
-	h.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	h.Container.EventProcessor().AddEvent(fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind), h.processListEvents(""))
+	h.serveStream(streamID, c)
 	return nil
 }
 
@@ -54,7 +77,7 @@ func (h *BaseHandler) GetDetails(c echo.Context) error {
 		Data: h.marshalDetailData(item, exists),
 	})
 
-	h.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	h.serveStream(streamID, c)
 	return nil
 }
 
@@ -67,7 +90,7 @@ func (h *BaseHandler) GetYaml(c echo.Context) error {
 		Data: h.marshalYAML(item, exists),
 	})
 
-	h.Container.SSE().ServeHTTP(fmt.Sprintf("%s-yaml", streamID), c.Response(), c.Request())
+	h.serveStream(fmt.Sprintf("%s-yaml", streamID), c)
 	return nil
 }
 
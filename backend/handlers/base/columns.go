@@ -0,0 +1,160 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	maxColumns          = 10
+	maxColumnExprLength = 256
+	// maxColumnValueLength truncates values, a path matching a whole
+	// subtree would otherwise bloat every list event.
+	maxColumnValueLength = 1024
+)
+
+var columnNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,63}$`)
+
+// customColumn is one NAME:EXPR entry of the columns param, as in kubectl's
+// custom-columns output.
+type customColumn struct {
+	name string
+	expr string
+	path *jsonpath.JSONPath
+}
+
+// parseColumns parses a comma-separated list of NAME:EXPR columns. Only a
+// single field path is allowed per column, recursive descent and range
+// templates are rejected to keep evaluation bounded.
+func parseColumns(spec string) ([]customColumn, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	entries := strings.Split(spec, ",")
+	if len(entries) > maxColumns {
+		return nil, fmt.Errorf("at most %d columns are allowed", maxColumns)
+	}
+
+	columns := make([]customColumn, 0, len(entries))
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name, expr, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || !columnNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid column %q, expected NAME:EXPR", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		seen[name] = true
+
+		expr, err := relaxedJSONPath(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid column %s: %w", name, err)
+		}
+		path := jsonpath.New(name).AllowMissingKeys(true)
+		if err := path.Parse(expr); err != nil {
+			return nil, fmt.Errorf("invalid column %s: %w", name, err)
+		}
+		columns = append(columns, customColumn{name: name, expr: expr, path: path})
+	}
+	return columns, nil
+}
+
+// relaxedJSONPath accepts ".spec.nodeName", "spec.nodeName" and
+// "{.spec.nodeName}" and returns the braced form.
+func relaxedJSONPath(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if len(expr) > maxColumnExprLength {
+		return "", fmt.Errorf("expression longer than %d characters", maxColumnExprLength)
+	}
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	if expr == "" {
+		return "", fmt.Errorf("empty expression")
+	}
+	if strings.ContainsAny(expr, "{}") || strings.Contains(expr, "..") {
+		return "", fmt.Errorf("only a single field path is allowed: %q", expr)
+	}
+	if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "[") {
+		expr = "." + expr
+	}
+	return "{" + expr + "}", nil
+}
+
+func columnsStreamID(columns []customColumn) string {
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, column.name+":"+column.expr)
+	}
+	return strings.Join(parts, ",")
+}
+
+// addColumns sets the "columns" of every list entry to the values of the
+// columns evaluated against its object, matched by UID.
+func addColumns(data []byte, items []any, columns []customColumn) []byte {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return data
+	}
+	objects := make(map[string]any, len(items))
+	for _, item := range items {
+		if accessor, err := meta.Accessor(item); err == nil {
+			objects[string(accessor.GetUID())] = item
+		}
+	}
+
+	for i := range entries {
+		uid, _ := entries[i]["uid"].(string)
+		item, ok := objects[uid]
+		if !ok {
+			continue
+		}
+		obj, err := toUnstructured(item)
+		if err != nil {
+			continue
+		}
+		values := make(map[string]string, len(columns))
+		for _, column := range columns {
+			values[column.name] = column.value(obj)
+		}
+		entries[i]["columns"] = values
+	}
+
+	withColumns, err := json.Marshal(entries)
+	if err != nil {
+		return data
+	}
+	return withColumns
+}
+
+// value joins the matches like kubectl does, missing fields give "".
+func (c customColumn) value(obj map[string]any) string {
+	results, err := c.path.FindResults(obj)
+	if err != nil || len(results) == 0 {
+		return ""
+	}
+	values := make([]string, 0, len(results[0]))
+	for _, result := range results[0] {
+		values = append(values, fmt.Sprint(result.Interface()))
+	}
+	value := strings.Join(values, ",")
+	if len(value) > maxColumnValueLength {
+		value = value[:maxColumnValueLength]
+	}
+	return value
+}
+
+func toUnstructured(item any) (map[string]any, error) {
+	if u, ok := item.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+}
@@ -0,0 +1,77 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseColumns(t *testing.T) {
+	columns, err := parseColumns("NODE:.spec.nodeName, IMAGES:{.spec.containers[*].image},IP:status.podIP")
+	require.NoError(t, err)
+	require.Len(t, columns, 3)
+	assert.Equal(t, "{.spec.nodeName}", columns[0].expr)
+	assert.Equal(t, "{.spec.containers[*].image}", columns[1].expr)
+	assert.Equal(t, "{.status.podIP}", columns[2].expr)
+
+	columns, err = parseColumns("")
+	assert.NoError(t, err)
+	assert.Empty(t, columns)
+
+	for _, spec := range []string{
+		".spec.nodeName",
+		"NODE:",
+		"bad name:.spec",
+		"A:.a,A:.b",
+		"ALL:{..image}",
+		"RANGE:{range .spec.containers[*]}{.name}{end}",
+		"BAD:{.spec[}",
+		"A:.a,B:.b,C:.c,D:.d,E:.e,F:.f,G:.g,H:.h,I:.i,J:.j,K:.k",
+	} {
+		_, err := parseColumns(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestAddColumns(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{UID: "1", Name: "web"},
+		Spec: v1.PodSpec{
+			NodeName:   "node-a",
+			Containers: []v1.Container{{Name: "app", Image: "nginx:1"}, {Name: "proxy", Image: "envoy:2"}},
+		},
+	}
+	widget := newCustomResource("gadget")
+	widget.SetUID("2")
+
+	columns, err := parseColumns("NODE:.spec.nodeName,IMAGES:.spec.containers[*].image")
+	require.NoError(t, err)
+	data := addColumns([]byte(`[{"uid":"1","name":"web"},{"uid":"2","name":"gadget"},{"uid":"3","name":"gone"}]`), []any{pod, widget}, columns)
+
+	assert.JSONEq(t, `[
+		{"uid":"1","name":"web","columns":{"NODE":"node-a","IMAGES":"nginx:1,envoy:2"}},
+		{"uid":"2","name":"gadget","columns":{"NODE":"","IMAGES":""}},
+		{"uid":"3","name":"gone"}
+	]`, string(data))
+}
+
+func TestListStreamIDColumns(t *testing.T) {
+	h := newTestHandler("Widget")
+	c := func(query string) echo.Context {
+		return echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), httptest.NewRecorder())
+	}
+
+	streamID, release, err := h.listStreamID(c("?columns=NODE:.spec.nodeName"))
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, "test-config-test-cluster-Widget-columns-NODE:{.spec.nodeName}", streamID)
+
+	_, _, err = h.listStreamID(c("?columns=NODE:{..nodeName}"))
+	assert.Error(t, err)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -14,7 +17,71 @@ import (
 )
 
 func (h *BaseHandler) buildEventStreamID(c echo.Context) string {
-	return fmt.Sprintf("%s-%s-%s-%s-events", h.QueryConfig, h.QueryCluster, c.QueryParam("namespace"), c.Param("name"))
+	streamID := fmt.Sprintf("%s-%s-%s-%s-events", h.QueryConfig, h.QueryCluster, c.QueryParam("namespace"), c.Param("name"))
+	if filter := parseEventFilter(c); filter != (eventFilter{}) {
+		streamID = fmt.Sprintf("%s-%s-%s-%d", streamID, filter.eventType, filter.reason, filter.limit)
+	}
+	return streamID
+}
+
+// eventFilter narrows the events of an object to a type (Normal or Warning)
+// and a reason, keeping at most limit of the newest ones.
+type eventFilter struct {
+	eventType string
+	reason    string
+	limit     int
+}
+
+func parseEventFilter(c echo.Context) eventFilter {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+	return eventFilter{
+		eventType: strings.TrimSpace(c.QueryParam("type")),
+		reason:    strings.TrimSpace(c.QueryParam("reason")),
+		limit:     limit,
+	}
+}
+
+// apply filters events newest first. Type and reason are matched here rather
+// than in the field selector since not every API version supports them.
+func (f eventFilter) apply(events []coreV1.Event) []coreV1.Event {
+	filtered := make([]coreV1.Event, 0, len(events))
+	for _, event := range events {
+		if f.eventType != "" && !strings.EqualFold(event.Type, f.eventType) {
+			continue
+		}
+		if f.reason != "" && event.Reason != f.reason {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return eventTime(filtered[i]).After(eventTime(filtered[j]))
+	})
+	if f.limit > 0 && len(filtered) > f.limit {
+		filtered = filtered[:f.limit]
+	}
+	return filtered
+}
+
+// eventTime is when an event last occurred. Core events set lastTimestamp,
+// events written through events.k8s.io set the series or eventTime instead.
+func eventTime(event coreV1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
 }
 
 func (h *BaseHandler) fetchEvents(c echo.Context) []coreV1.Event {
@@ -41,7 +108,7 @@ func (h *BaseHandler) fetchEvents(c echo.Context) []coreV1.Event {
 		event.ManagedFields = nil
 		events = append(events, event)
 	}
-	return events
+	return parseEventFilter(c).apply(events)
 }
 
 func (h *BaseHandler) marshalEvents(events []coreV1.Event) []byte {
@@ -0,0 +1,72 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEventFilter(t *testing.T) {
+	now := time.Now()
+	newEvent := func(name, eventType, reason string, age time.Duration) coreV1.Event {
+		return coreV1.Event{
+			ObjectMeta:    metaV1.ObjectMeta{Name: name},
+			Type:          eventType,
+			Reason:        reason,
+			LastTimestamp: metaV1.NewTime(now.Add(-age)),
+		}
+	}
+	// events.k8s.io events only carry an eventTime.
+	scheduled := coreV1.Event{ObjectMeta: metaV1.ObjectMeta{Name: "scheduled"}, Type: "Normal", Reason: "Scheduled", EventTime: metaV1.NewMicroTime(now.Add(-3 * time.Minute))}
+	events := []coreV1.Event{
+		newEvent("pulled", "Normal", "Pulled", 2*time.Minute),
+		scheduled,
+		newEvent("backoff", "Warning", "BackOff", 10*time.Second),
+		newEvent("unhealthy", "Warning", "Unhealthy", time.Minute),
+	}
+	names := func(events []coreV1.Event) []string {
+		out := make([]string, 0, len(events))
+		for _, e := range events {
+			out = append(out, e.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "newest first", query: "", want: []string{"backoff", "unhealthy", "pulled", "scheduled"}},
+		{name: "type", query: "?type=warning", want: []string{"backoff", "unhealthy"}},
+		{name: "reason", query: "?reason=Pulled", want: []string{"pulled"}},
+		{name: "limit", query: "?type=Normal&limit=1", want: []string{"pulled"}},
+		{name: "invalid limit is ignored", query: "?limit=-1", want: []string{"backoff", "unhealthy", "pulled", "scheduled"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil), httptest.NewRecorder())
+			assert.Equal(t, tt.want, names(parseEventFilter(c).apply(events)))
+		})
+	}
+}
+
+func TestEventStreamIDIncludesFilter(t *testing.T) {
+	h := newTestHandler("Pod")
+	newContext := func(query string) echo.Context {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), httptest.NewRecorder())
+		c.SetParamNames("name")
+		c.SetParamValues("web")
+		return c
+	}
+
+	plain := h.buildEventStreamID(newContext("?namespace=default"))
+	assert.Equal(t, "test-config-test-cluster-default-web-events", plain)
+	assert.NotEqual(t, plain, h.buildEventStreamID(newContext("?namespace=default&type=Warning")))
+}
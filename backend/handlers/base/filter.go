@@ -0,0 +1,206 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// maxListViews bounds the distinct variants of one list stream open at once.
+const maxListViews = 32
+
+// listViews maps a list stream ID to the variants subscribed to it, a
+// *helpers.StreamViews[listView] keyed by view stream ID.
+var listViews sync.Map
+
+// listView is a per-request variant of a kind's list stream.
+type listView struct {
+	excluded    []string
+	humanizeAge bool
+	columns     []customColumn
+}
+
+func (v listView) isDefault() bool {
+	return len(v.excluded) == 0 && !v.humanizeAge && len(v.columns) == 0
+}
+
+func (v listView) streamID(listStreamID string) string {
+	streamID := listStreamID
+	if len(v.excluded) > 0 {
+		streamID = fmt.Sprintf("%s-exclude-%s", streamID, strings.Join(v.excluded, ","))
+	}
+	if v.humanizeAge {
+		streamID += "-humanized"
+	}
+	if len(v.columns) > 0 {
+		streamID = fmt.Sprintf("%s-columns-%s", streamID, columnsStreamID(v.columns))
+	}
+	return streamID
+}
+
+// excludedNamespaces returns the namespaces a list request hides, from the
+// comma-separated excludeNamespaces param or the configured default when the
+// param is absent. An empty param disables the default.
+func (h *BaseHandler) excludedNamespaces(c echo.Context) []string {
+	value, ok := c.QueryParams()["excludeNamespaces"]
+	if !ok {
+		return h.Container.Config().ExcludedNamespaces
+	}
+
+	namespaces := make([]string, 0)
+	for _, v := range value {
+		for _, ns := range strings.Split(v, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces
+}
+
+// listView returns the variant of the kind's list a request asks for. It
+// fails on an invalid columns param.
+func (h *BaseHandler) listView(c echo.Context) (listView, error) {
+	columns, err := parseColumns(c.QueryParam("columns"))
+	if err != nil {
+		return listView{}, err
+	}
+
+	excluded := slices.Clone(h.excludedNamespaces(c))
+	slices.Sort(excluded)
+	return listView{
+		excluded:    slices.Compact(excluded),
+		humanizeAge: c.QueryParam("humanizeAge") == "true",
+		columns:     columns,
+	}, nil
+}
+
+// listStreamID returns the stream a list request subscribes to, registering
+// a variant of the kind's list stream when the request asks for one until
+// release is called. It fails on an invalid columns param, or with
+// helpers.ErrTooManyViews.
+func (h *BaseHandler) listStreamID(c echo.Context) (string, func(), error) {
+	streamID := fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind)
+	view, err := h.listView(c)
+	if err != nil {
+		return "", nil, err
+	}
+	if view.isDefault() {
+		return streamID, func() {}, nil
+	}
+
+	viewID := view.streamID(streamID)
+	views, _ := listViews.LoadOrStore(streamID, helpers.NewStreamViews[listView](maxListViews, h.Container.SSE().RemoveStream))
+	release, err := views.(*helpers.StreamViews[listView]).Acquire(viewID, view)
+	if err != nil {
+		return "", nil, err
+	}
+	return viewID, release, nil
+}
+
+// publishListViews pushes items to every variant of the kind's list stream.
+func (h *BaseHandler) publishListViews(streamID string, items []any, resourceName string) {
+	views, ok := listViews.Load(streamID)
+	if !ok {
+		return
+	}
+	views.(*helpers.StreamViews[listView]).Range(func(viewID string, view listView) {
+		h.Container.SSE().Publish(viewID, &sse.Event{
+			Data: h.marshalListView(items, view, resourceName),
+		})
+	})
+}
+
+// marshalListView marshals the list entries of items as view shows them.
+func (h *BaseHandler) marshalListView(items []any, view listView, resourceName string) []byte {
+	items = excludeNamespaces(items, view.excluded)
+	data := h.marshalListData(items, resourceName)
+	if len(view.columns) > 0 {
+		data = addColumns(data, items, view.columns)
+	}
+	if view.humanizeAge {
+		data = humanizeAges(data, time.Now())
+	}
+	return data
+}
+
+func excludeNamespaces(items []any, excluded []string) []any {
+	if len(excluded) == 0 {
+		return items
+	}
+	filtered := make([]any, 0, len(items))
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err == nil && slices.Contains(excluded, accessor.GetNamespace()) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// humanizeAges replaces the RFC3339 age of every list entry with a
+// kubectl-style duration. Entries without a parsable age are left as is.
+func humanizeAges(data []byte, now time.Time) []byte {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return data
+	}
+	for i := range entries {
+		age, ok := entries[i]["age"].(string)
+		if !ok {
+			continue
+		}
+		if created, err := time.Parse(time.RFC3339, age); err == nil {
+			entries[i]["age"] = helpers.HumanizeAge(created, now)
+		}
+	}
+
+	humanized, err := json.Marshal(entries)
+	if err != nil {
+		return data
+	}
+	return humanized
+}
+
+// identityFields are kept by every projection, the client keys rows on them.
+var identityFields = []string{"uid", "name", "namespace"}
+
+// projectFields keeps only the given fields of a transformed list entry.
+// Dotted fields select nested values, e.g. "status.phase".
+func projectFields(entry map[string]any, fields []string) map[string]any {
+	projected := make(map[string]any, len(fields)+len(identityFields))
+	for _, field := range append(slices.Clone(identityFields), fields...) {
+		copyField(projected, entry, strings.Split(field, "."))
+	}
+	return projected
+}
+
+func copyField(dst, src map[string]any, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+	nested, ok := value.(map[string]any)
+	if !ok {
+		return
+	}
+	child, ok := dst[path[0]].(map[string]any)
+	if !ok {
+		child = make(map[string]any)
+		dst[path[0]] = child
+	}
+	copyField(child, nested, path[1:])
+}
@@ -0,0 +1,110 @@
+package base
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestListStreamIDExcludeNamespaces(t *testing.T) {
+	h := newTestHandler("Widget")
+	h.Container.Config().ExcludedNamespaces = []string{"kube-system"}
+	base := "test-config-test-cluster-Widget"
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "configured default", query: "", want: base + "-exclude-kube-system"},
+		{name: "empty param disables default", query: "?excludeNamespaces=", want: base},
+		{name: "param is sorted and deduplicated", query: "?excludeNamespaces=b,%20a,b", want: base + "-exclude-a,b"},
+		{name: "humanized age", query: "?excludeNamespaces=&humanizeAge=true", want: base + "-humanized"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			streamID, release, err := h.listStreamID(c)
+			assert.NoError(t, err)
+			release()
+			assert.Equal(t, tt.want, streamID)
+		})
+	}
+}
+
+func TestExcludeNamespaces(t *testing.T) {
+	system := newCustomResource("a")
+	system.SetNamespace("kube-system")
+	app := newCustomResource("b")
+	app.SetNamespace("default")
+
+	items := excludeNamespaces([]any{system, app}, []string{"kube-system"})
+
+	assert.Equal(t, []any{app}, items)
+	assert.IsType(t, &unstructured.Unstructured{}, items[0])
+}
+
+func TestHumanizeAges(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	data := []byte(`[{"name":"a","age":"2026-01-04T21:00:00Z"},{"name":"b","age":"0001-01-01T00:00:00Z"},{"name":"c"}]`)
+
+	assert.JSONEq(t, `[{"name":"a","age":"5d3h"},{"name":"b","age":""},{"name":"c"}]`, string(humanizeAges(data, now)))
+}
+
+func TestMarshalListDataProjection(t *testing.T) {
+	h := newTestHandler("Widget")
+	h.TransformFunc = func(items []any, _ *BaseHandler) ([]byte, error) {
+		return []byte(`[{"uid":"1","name":"a","namespace":"default","age":"2024-01-01T00:00:00Z","status":{"phase":"Running","ready":"1/1"}}]`), nil
+	}
+	h.Container.Config().ListFields = map[string][]string{"widget": {"status.phase"}}
+
+	data := h.marshalListData([]any{newCustomResource("a")}, "a")
+	assert.JSONEq(t, `[{"uid":"1","name":"a","namespace":"default","status":{"phase":"Running"},"hasUpdated":true}]`, string(data))
+
+	h.Container.Config().ListFields = nil
+	data = h.marshalListData([]any{newCustomResource("a")}, "")
+	assert.Contains(t, string(data), `"age"`)
+}
+
+func TestListStreamIDRelease(t *testing.T) {
+	h := newTestHandler("Gadget")
+	c := func(query string) echo.Context {
+		return echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/"+query, nil), httptest.NewRecorder())
+	}
+	views := func() int {
+		v, ok := listViews.Load("test-config-test-cluster-Gadget")
+		require.True(t, ok)
+		return v.(*helpers.StreamViews[listView]).Len()
+	}
+
+	_, releaseA, err := h.listStreamID(c("?columns=NODE:.spec.nodeName"))
+	require.NoError(t, err)
+	_, releaseB, err := h.listStreamID(c("?columns=NODE:.spec.nodeName"))
+	require.NoError(t, err)
+	releaseA()
+	assert.Equal(t, 1, views())
+	releaseB()
+	assert.Zero(t, views())
+
+	releases := make([]func(), 0, maxListViews)
+	for i := range maxListViews {
+		_, release, err := h.listStreamID(c(fmt.Sprintf("?columns=C%d:.metadata.name", i)))
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+	_, _, err = h.listStreamID(c("?columns=EXTRA:.metadata.name"))
+	assert.ErrorIs(t, err, helpers.ErrTooManyViews)
+	for _, release := range releases {
+		release()
+	}
+	assert.Zero(t, views())
+}
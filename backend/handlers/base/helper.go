@@ -25,6 +25,9 @@ func (h *BaseHandler) marshalDetailData(item any, exists bool) []byte {
 	if !exists {
 		return []byte("{}")
 	}
+	if h.DetailTransformFunc != nil {
+		item = h.DetailTransformFunc(item)
+	}
 	data, err := json.Marshal(item)
 	if err != nil {
 		return []byte("{}")
@@ -65,7 +68,11 @@ func (h *BaseHandler) marshalListData(items []any, resourceName string) []byte {
 		return data
 	}
 
+	fields := h.Container.Config().ListFieldsFor(h.Kind)
 	for i := range entries {
+		if len(fields) > 0 {
+			entries[i] = projectFields(entries[i], fields)
+		}
 		entries[i]["hasUpdated"] = h.isResourceUpdated(entries[i], resourceName)
 	}
 
@@ -0,0 +1,94 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type WorkloadImage struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+}
+
+// SetWorkloadImage sets the image of one container of a workload's pod
+// template with a strategic merge patch, which triggers a rollout. It returns
+// the patched workload. A resourceVersion makes the patch conditional.
+func (h *BaseHandler) SetWorkloadImage(c echo.Context) error {
+	r := new(WorkloadImage)
+	if err := c.Bind(r); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	if r.Container == "" || r.Image == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": "container and image are required"})
+	}
+
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	obj, exists, err := h.Informer.GetStore().GetByKey(fmt.Sprintf("%s/%s", namespace, name))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+	if !exists {
+		return c.JSON(http.StatusNotFound, echo.Map{"message": fmt.Sprintf("%s %s/%s not found", h.Kind, namespace, name)})
+	}
+
+	field, err := templateContainerField(obj, r.Container)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+
+	patch, err := json.Marshal(helpers.PreconditionPatch(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					field: []map[string]string{{"name": r.Container, "image": r.Image}},
+				},
+			},
+		},
+	}, helpers.ResourceVersion(c)))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+	}
+
+	resource := h.GetResourceByKind(h.Kind)
+	body, err := h.RestClient.Patch(types.StrategicMergePatchType).
+		Resource(resource.Name).
+		Namespace(namespace).
+		Name(name).
+		Body(patch).
+		Do(c.Request().Context()).
+		Raw()
+	if err != nil {
+		return c.JSON(helpers.WriteErrorStatus(err), echo.Map{"message": err.Error()})
+	}
+
+	return c.JSONBlob(http.StatusOK, body)
+}
+
+// templateContainerField returns whether the named container of a workload's
+// pod template is listed under containers or initContainers.
+func templateContainerField(obj any, container string) (string, error) {
+	if _, ok := obj.(runtime.Object); !ok {
+		return "", fmt.Errorf("unexpected object %T", obj)
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _, _ := unstructured.NestedSlice(u, "spec", "template", "spec", field)
+		for _, item := range containers {
+			if c, ok := item.(map[string]any); ok && c["name"] == container {
+				return field, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("container %q not found in pod template", container)
+}
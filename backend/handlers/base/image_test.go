@@ -0,0 +1,33 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestTemplateContainerField(t *testing.T) {
+	dep := &appsV1.Deployment{
+		Spec: appsV1.DeploymentSpec{
+			Template: coreV1.PodTemplateSpec{
+				Spec: coreV1.PodSpec{
+					InitContainers: []coreV1.Container{{Name: "migrate", Image: "app:1"}},
+					Containers:     []coreV1.Container{{Name: "app", Image: "app:1"}},
+				},
+			},
+		},
+	}
+
+	field, err := templateContainerField(dep, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "containers", field)
+
+	field, err = templateContainerField(dep, "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, "initContainers", field)
+
+	_, err = templateContainerField(dep, "missing")
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
 	"github.com/r3labs/sse/v2"
 	"k8s.io/client-go/tools/cache"
 )
@@ -25,8 +26,10 @@ func ResourceEventHandler[T Resource](handler *BaseHandler, additionalEvents ...
 		if !ok {
 			return
 		}
-		// GetList
-		handler.Container.EventProcessor().AddEvent(handler.Kind, handler.processListEvents(resource.GetName()))
+		// GetList, keyed by stream so same-kind handlers of other clusters or
+		// custom resource groups don't replace each other's pending refresh
+		listStreamID := fmt.Sprintf("%s-%s-%s", handler.QueryConfig, handler.QueryCluster, handler.Kind)
+		handler.Container.EventProcessor().AddEvent(listStreamID, handler.processListEvents(resource.GetName()))
 
 		var streamName string
 		if resource.GetNamespace() == "" {
@@ -56,17 +59,17 @@ func ResourceEventHandler[T Resource](handler *BaseHandler, additionalEvents ...
 
 func (h *BaseHandler) StartInformer(events cache.ResourceEventHandlerFuncs) {
 	h.baseInformer(events)
-	go h.Container.SharedInformerFactory(h.QueryConfig, h.QueryCluster).Start(context.Background().Done())
+	go h.Container.SharedInformerFactory(h.QueryConfig, h.QueryCluster).Start(h.Container.InformerStopCh(h.QueryConfig, h.QueryCluster))
 }
 
 func (h *BaseHandler) StartExtensionInformer(events cache.ResourceEventHandlerFuncs) {
 	h.baseInformer(events)
-	go h.Container.ExtensionSharedFactoryInformer(h.QueryConfig, h.QueryCluster).Start(context.Background().Done())
+	go h.Container.ExtensionSharedFactoryInformer(h.QueryConfig, h.QueryCluster).Start(h.Container.InformerStopCh(h.QueryConfig, h.QueryCluster))
 }
 
 func (h *BaseHandler) StartDynamicInformer(events cache.ResourceEventHandlerFuncs) {
 	h.baseInformer(events)
-	go h.Container.DynamicSharedInformerFactory(h.QueryConfig, h.QueryCluster).Start(context.Background().Done())
+	go h.Container.DynamicSharedInformerFactory(h.QueryConfig, h.QueryCluster).Start(h.Container.InformerStopCh(h.QueryConfig, h.QueryCluster))
 }
 
 func (h *BaseHandler) baseInformer(events cache.ResourceEventHandlerFuncs) {
@@ -75,6 +78,10 @@ func (h *BaseHandler) baseInformer(events cache.ResourceEventHandlerFuncs) {
 		h.Container.Cache().Set(h.InformerCacheKey, true)
 		_ = h.Informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
 			log.Warn("failed to watch, will backoff and retry", "error", err, "kind", h.Kind)
+			// Tell open list streams, their data is stale until the watch
+			// recovers, and whether reconnecting can help.
+			streamID := fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind)
+			h.Container.SSE().Publish(streamID, helpers.NewStreamError(err).SSEEvent())
 		})
 		if _, err := h.Informer.AddEventHandler(events); err != nil {
 			log.Warn("failed to load baseInformer", "error", err, "kind", h.Kind)
@@ -91,7 +98,7 @@ func (h *BaseHandler) WaitForSync(ctx context.Context) {
 		return
 	}
 
-	h.Container.EventProcessor().AddEvent(h.Kind, h.processListEvents(""))
+	h.Container.EventProcessor().AddEvent(fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind), h.processListEvents(""))
 }
 
 func (h *BaseHandler) processListEvents(resourceName string) func() {
@@ -102,6 +109,7 @@ func (h *BaseHandler) processListEvents(resourceName string) func() {
 		h.Container.SSE().Publish(streamID, &sse.Event{
 			Data: data,
 		})
+		h.publishListViews(streamID, items, resourceName)
 	}
 }
 
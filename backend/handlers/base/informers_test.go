@@ -0,0 +1,113 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/mcp/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTestHandler(kind string) *BaseHandler {
+	appContainer := container.NewContainer(&config.Env{}, config.NewAppConfig("test", ":0", 10, 10, false))
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+
+	return &BaseHandler{
+		Kind:         kind,
+		Container:    appContainer,
+		Informer:     informer,
+		QueryConfig:  "test-config",
+		QueryCluster: "test-cluster",
+		TransformFunc: func(items []any, _ *BaseHandler) ([]byte, error) {
+			names := make([]map[string]any, 0)
+			for _, obj := range items {
+				if item, ok := obj.(*unstructured.Unstructured); ok {
+					names = append(names, map[string]any{"name": item.GetName()})
+				}
+			}
+			return json.Marshal(names)
+		},
+	}
+}
+
+func newCustomResource(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Widget")
+	u.SetName(name)
+	return u
+}
+
+// readFirstMessage subscribes to streamID and returns the first SSE data payload.
+func readFirstMessage(t *testing.T, h *BaseHandler, streamID string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Container.SSE().ServeHTTP(streamID, w, r)
+	}))
+	defer server.Close()
+
+	result := make(chan string, 1)
+	go func() {
+		message, _ := helpers.ReadFirstSSEMessage(server.URL)
+		result <- message
+	}()
+
+	select {
+	case message := <-result:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no message received on stream %s", streamID)
+		return ""
+	}
+}
+
+func TestResourceEventHandlerPushesCustomResourceUpdates(t *testing.T) {
+	h := newTestHandler("Widget")
+	streamID := fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind)
+	h.Container.SSE().CreateStream(streamID)
+
+	handlers := ResourceEventHandler[*unstructured.Unstructured](h)
+
+	created := newCustomResource("first")
+	require.NoError(t, h.Informer.GetStore().Add(created))
+	handlers.OnAdd(created, false)
+
+	var list []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(readFirstMessage(t, h, streamID)), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "first", list[0]["name"])
+	assert.Equal(t, true, list[0]["hasUpdated"])
+}
+
+func TestResourceEventHandlerKeepsClustersApart(t *testing.T) {
+	first := newTestHandler("Widget")
+	second := newTestHandler("Widget")
+	second.Container = first.Container
+	second.QueryCluster = "other-cluster"
+
+	firstStream := fmt.Sprintf("%s-%s-%s", first.QueryConfig, first.QueryCluster, first.Kind)
+	secondStream := fmt.Sprintf("%s-%s-%s", second.QueryConfig, second.QueryCluster, second.Kind)
+	first.Container.SSE().CreateStream(firstStream)
+	first.Container.SSE().CreateStream(secondStream)
+
+	a := newCustomResource("a")
+	b := newCustomResource("b")
+	require.NoError(t, first.Informer.GetStore().Add(a))
+	require.NoError(t, second.Informer.GetStore().Add(b))
+
+	// Both updates land in the same processing window; neither may be dropped.
+	ResourceEventHandler[*unstructured.Unstructured](first).OnAdd(a, false)
+	ResourceEventHandler[*unstructured.Unstructured](second).OnAdd(b, false)
+
+	assert.Contains(t, readFirstMessage(t, first, firstStream), `"name":"a"`)
+	assert.Contains(t, readFirstMessage(t, second, secondStream), `"name":"b"`)
+}
@@ -1,6 +1,9 @@
 package base
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
 // handlerRegistry caches handler instances per (config, cluster) so each HTTP
 // request reuses the wrapper around the shared informer instead of rebuilding
@@ -18,3 +21,35 @@ func GetOrCreateHandler[T any](key string, create func() T) T {
 	v, _ := handlerRegistry.LoadOrStore(key, create())
 	return v.(T)
 }
+
+// ResetHandlers drops every cached handler and informer registration so they
+// are rebuilt against fresh clients, e.g. after the kubeconfigs are reloaded.
+func ResetHandlers() {
+	handlerRegistry.Clear()
+	informerInitOnce.Clear()
+}
+
+// ResetConfigHandlers drops the cached handlers and informer registrations of
+// the given clusters of config. Keys start with "<config>-<cluster>-", so they
+// are matched per cluster rather than by the "<config>-" prefix, which other
+// configs may share.
+func ResetConfigHandlers(config string, clusters []string) {
+	for _, m := range []*sync.Map{&handlerRegistry, &informerInitOnce} {
+		m.Range(func(key, _ any) bool {
+			if k, ok := key.(string); ok && HasClusterPrefix(k, config, clusters) {
+				m.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// HasClusterPrefix reports whether key belongs to one of clusters of config.
+func HasClusterPrefix(key, config string, clusters []string) bool {
+	for _, cluster := range clusters {
+		if strings.HasPrefix(key, config+"-"+cluster+"-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package base
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetConfigHandlers(t *testing.T) {
+	defer ResetHandlers()
+	for _, key := range []string{"prod-main-podInformer", "prod-eu-main-podInformer", "prod-main-deploymentInformer", "staging-main-podInformer"} {
+		GetOrCreateHandler(key, func() string { return key })
+		informerInitOnce.Store(key, &sync.Once{})
+	}
+
+	ResetConfigHandlers("prod", []string{"main"})
+
+	for _, m := range []*sync.Map{&handlerRegistry, &informerInitOnce} {
+		var keys []string
+		m.Range(func(key, _ any) bool {
+			keys = append(keys, key.(string))
+			return true
+		})
+		assert.ElementsMatch(t, []string{"prod-eu-main-podInformer", "staging-main-podInformer"}, keys)
+	}
+}
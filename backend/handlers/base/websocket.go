@@ -0,0 +1,182 @@
+package base
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// websocketWriteTimeout bounds a write to a WebSocket client, a stalled
+// client is disconnected rather than holding its informer handler.
+const websocketWriteTimeout = 10 * time.Second
+
+// WatchEvent is a list change sent over a WebSocket list stream. Object is
+// the entry as it appears in the SSE list payload.
+type WatchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// serveStream serves streamID over SSE, or over a WebSocket when the client
+// opens one. Both transports carry the same payloads, so clients behind
+// proxies that buffer event-streams can switch without other changes.
+func (h *BaseHandler) serveStream(streamID string, c echo.Context) {
+	if !websocket.IsWebSocketUpgrade(c.Request()) {
+		h.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+		return
+	}
+
+	conn, ctx, cancel, ok := h.upgrade(c, streamID)
+	if !ok {
+		return
+	}
+	defer conn.Close()
+	defer cancel()
+
+	w := &websocketEventWriter{conn: conn, header: http.Header{}, cancel: cancel}
+	h.Container.SSE().ServeHTTP(streamID, w, c.Request().WithContext(ctx))
+}
+
+// serveListWatch sends the kind's list over a WebSocket as WatchEvents: an
+// ADDED event for every current item, then ADDED, MODIFIED and DELETED as
+// the informer sees changes. It ends when the client disconnects or the
+// request context, registered with the streams package, is cancelled.
+func (h *BaseHandler) serveListWatch(c echo.Context, view listView) {
+	streamID := fmt.Sprintf("%s-%s-%s", h.QueryConfig, h.QueryCluster, h.Kind)
+	conn, ctx, cancel, ok := h.upgrade(c, streamID)
+	if !ok {
+		return
+	}
+	defer conn.Close()
+	defer cancel()
+
+	var mu sync.Mutex
+	send := func(eventType watch.EventType, obj any) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		entry, ok := h.listEntry(obj, view)
+		if !ok {
+			return
+		}
+		data, err := json.Marshal(WatchEvent{Type: eventType, Object: entry})
+		if err != nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		_ = conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			cancel()
+		}
+	}
+
+	registration, err := h.Informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { send(watch.Added, obj) },
+		UpdateFunc: func(_, newObj any) { send(watch.Modified, newObj) },
+		DeleteFunc: func(obj any) { send(watch.Deleted, obj) },
+	})
+	if err != nil {
+		log.Warn("failed to watch list over websocket", "error", err, "stream", streamID)
+		return
+	}
+	defer func() {
+		if err := h.Informer.RemoveEventHandler(registration); err != nil {
+			log.Warn("failed to remove websocket list handler", "error", err, "stream", streamID)
+		}
+	}()
+	<-ctx.Done()
+}
+
+// listEntry returns the list entry of obj as view shows it, false when view
+// hides obj.
+func (h *BaseHandler) listEntry(obj any, view listView) (json.RawMessage, bool) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(h.marshalListView([]any{obj}, view, ""), &entries); err != nil || len(entries) != 1 {
+		return nil, false
+	}
+	return entries[0], true
+}
+
+// upgrade switches the request to a WebSocket. The returned context ends with
+// the request context or when the client closes the connection.
+func (h *BaseHandler) upgrade(c echo.Context, streamID string) (*websocket.Conn, context.Context, context.CancelFunc, bool) {
+	conn, err := h.Container.SocketUpgrader().Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		log.Warn("failed to upgrade stream to websocket", "error", err, "stream", streamID)
+		return nil, nil, nil, false
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	// The client never sends data; reading only surfaces the close frame.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	return conn, ctx, cancel, true
+}
+
+// websocketEventWriter adapts the SSE server's output to WebSocket messages,
+// sending the data of each event as one text message.
+type websocketEventWriter struct {
+	conn   *websocket.Conn
+	header http.Header
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	buf    bytes.Buffer
+}
+
+func (w *websocketEventWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *websocketEventWriter) WriteHeader(int) {}
+
+func (w *websocketEventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// Flush is called by the SSE server after every complete event.
+func (w *websocketEventWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var data []string
+	scanner := bufio.NewScanner(&w.buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), w.buf.Len()+1)
+	for scanner.Scan() {
+		if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			data = append(data, line)
+		}
+	}
+	w.buf.Reset()
+
+	if len(data) == 0 {
+		return
+	}
+	if err := w.conn.WriteMessage(websocket.TextMessage, []byte(strings.Join(data, "\n"))); err != nil {
+		w.cancel()
+	}
+}
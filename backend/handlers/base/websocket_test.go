@@ -0,0 +1,91 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestServeStreamOverWebSocket(t *testing.T) {
+	h := newTestHandler("Widget")
+	streamID := "test-config-test-cluster-Widget"
+
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		h.serveStream(streamID, c)
+		return nil
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	h.Container.SSE().CreateStream(streamID)
+	h.Container.SSE().Publish(streamID, &sse.Event{Data: []byte(`[{"name":"a"}]`)})
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"a"}]`, string(message))
+}
+
+func TestListWatchOverWebSocket(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"}, newCustomResource("a"))
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	h := newTestHandler("Widget")
+	h.Informer = factory.ForResource(widgets).Informer()
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+	require.True(t, cache.WaitForCacheSync(stop, h.Informer.HasSynced))
+
+	e := echo.New()
+	e.GET("/", h.GetList)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?excludeNamespaces=", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assertEvent := func(eventType watch.EventType, name string) {
+		t.Helper()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var event WatchEvent
+		require.NoError(t, conn.ReadJSON(&event))
+		assert.Equal(t, eventType, event.Type)
+		assert.JSONEq(t, fmt.Sprintf(`{"name":%q,"hasUpdated":false}`, name), string(event.Object))
+	}
+
+	assertEvent(watch.Added, "a")
+	ctx := context.Background()
+	b, err := client.Resource(widgets).Create(ctx, newCustomResource("b"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assertEvent(watch.Added, "b")
+	b.SetLabels(map[string]string{"tier": "web"})
+	_, err = client.Resource(widgets).Update(ctx, b, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assertEvent(watch.Modified, "b")
+	require.NoError(t, client.Resource(widgets).Delete(ctx, "b", metav1.DeleteOptions{}))
+	assertEvent(watch.Deleted, "b")
+}
@@ -0,0 +1,264 @@
+package clusterhealth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	StatusOK        = "ok"
+	StatusFailed    = "failed"
+	StatusForbidden = "forbidden"
+	StatusError     = "error"
+	// StatusNotFound is used for addons that are not installed.
+	StatusNotFound = "notFound"
+)
+
+const (
+	ClusterHealthy   = "healthy"
+	ClusterDegraded  = "degraded"
+	ClusterUnhealthy = "unhealthy"
+	ClusterUnknown   = "unknown"
+)
+
+// EndpointHealth is the result of one of the API server health endpoints.
+type EndpointHealth struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// FailedChecks lists the individual checks reported as failed, e.g. "etcd".
+	FailedChecks []string `json:"failedChecks,omitempty"`
+}
+
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type AddonHealth struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind,omitempty"`
+	Status  string `json:"status"`
+	Ready   int32  `json:"ready"`
+	Desired int32  `json:"desired"`
+	Message string `json:"message,omitempty"`
+}
+
+type ClusterHealth struct {
+	// Status is "healthy", "degraded" when only components or addons are
+	// unhealthy, "unhealthy" when the API server is, and "unknown" when no
+	// health endpoint could be read.
+	Status    string           `json:"status"`
+	Endpoints []EndpointHealth `json:"endpoints"`
+	// Components come from the deprecated ComponentStatus API and are empty
+	// on clusters that no longer serve it.
+	Components []ComponentHealth `json:"components"`
+	Addons     []AddonHealth     `json:"addons"`
+}
+
+var healthEndpoints = []string{"/livez", "/readyz", "/healthz"}
+
+type addon struct {
+	name     string
+	selector string
+}
+
+// coreAddons are looked up in kube-system as Deployments and DaemonSets.
+var coreAddons = []addon{
+	{name: "coredns", selector: "k8s-app=kube-dns"},
+	{name: "kube-proxy", selector: "k8s-app=kube-proxy"},
+	{name: "metrics-server", selector: "k8s-app=metrics-server"},
+}
+
+// probeFunc fetches a raw path of the API server.
+type probeFunc func(ctx context.Context, path string) ([]byte, error)
+
+type HealthHandler struct {
+	container container.Container
+}
+
+func NewHealthHandler(container container.Container) *HealthHandler {
+	return &HealthHandler{container: container}
+}
+
+// GetClusterHealth returns the health of the API server, of the control plane
+// components where ComponentStatus is still served, and of the core addons.
+func (h *HealthHandler) GetClusterHealth(c echo.Context) error {
+	clientSet := h.container.ClientSet(c.QueryParam("config"), c.QueryParam("cluster"))
+	probe := func(ctx context.Context, path string) ([]byte, error) {
+		return clientSet.Discovery().RESTClient().Get().AbsPath(path).Param("verbose", "").DoRaw(ctx)
+	}
+	return c.JSON(http.StatusOK, clusterHealth(c.Request().Context(), clientSet, probe))
+}
+
+func clusterHealth(ctx context.Context, clientSet kubernetes.Interface, probe probeFunc) ClusterHealth {
+	health := ClusterHealth{
+		Endpoints: make([]EndpointHealth, len(healthEndpoints)),
+		Addons:    make([]AddonHealth, len(coreAddons)),
+	}
+
+	var wg sync.WaitGroup
+	run := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	for i, path := range healthEndpoints {
+		run(func() { health.Endpoints[i] = endpointHealth(ctx, probe, path) })
+	}
+	for i, a := range coreAddons {
+		run(func() { health.Addons[i] = addonHealth(ctx, clientSet, a) })
+	}
+	run(func() { health.Components = componentHealth(ctx, clientSet) })
+	wg.Wait()
+
+	health.Status = overallStatus(health)
+	return health
+}
+
+func endpointHealth(ctx context.Context, probe probeFunc, path string) EndpointHealth {
+	body, err := probe(ctx, path)
+	result := EndpointHealth{Path: path, Status: StatusOK, FailedChecks: failedChecks(string(body))}
+	switch {
+	case err == nil:
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		result.Status, result.Message = StatusForbidden, err.Error()
+	case len(result.FailedChecks) > 0 || apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err):
+		// Failing checks are answered with a 500 and the verbose report.
+		result.Status, result.Message = StatusFailed, err.Error()
+	default:
+		result.Status, result.Message = StatusError, err.Error()
+	}
+	return result
+}
+
+// failedChecks parses the verbose output of a health endpoint, where every
+// check is reported as "[+]name ok" or "[-]name failed: reason withheld".
+func failedChecks(body string) []string {
+	var failed []string
+	for _, line := range strings.Split(body, "\n") {
+		if check, ok := strings.CutPrefix(line, "[-]"); ok {
+			name, _, _ := strings.Cut(check, " ")
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
+
+func componentHealth(ctx context.Context, clientSet kubernetes.Interface) []ComponentHealth {
+	components := make([]ComponentHealth, 0)
+	// ComponentStatus is deprecated, but older clusters still report the
+	// scheduler, controller manager and etcd through it.
+	list, err := clientSet.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return components
+	}
+	for _, cs := range list.Items {
+		component := ComponentHealth{Name: cs.Name, Status: StatusFailed}
+		for _, condition := range cs.Conditions {
+			if condition.Type != coreV1.ComponentHealthy {
+				continue
+			}
+			if condition.Status == coreV1.ConditionTrue {
+				component.Status = StatusOK
+			}
+			component.Message = condition.Message
+			if condition.Error != "" {
+				component.Message = condition.Error
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+func addonHealth(ctx context.Context, clientSet kubernetes.Interface, a addon) AddonHealth {
+	result := AddonHealth{Name: a.name, Status: StatusNotFound}
+	opts := metav1.ListOptions{LabelSelector: a.selector}
+
+	deployments, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).List(ctx, opts)
+	if err != nil {
+		return addonError(result, err)
+	}
+	daemonSets, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).List(ctx, opts)
+	if err != nil {
+		return addonError(result, err)
+	}
+
+	for _, d := range deployments.Items {
+		result.Kind = "Deployment"
+		result.Ready += d.Status.AvailableReplicas
+		result.Desired += deploymentReplicas(d)
+	}
+	for _, ds := range daemonSets.Items {
+		result.Kind = "DaemonSet"
+		result.Ready += ds.Status.NumberAvailable
+		result.Desired += ds.Status.DesiredNumberScheduled
+	}
+	if len(deployments.Items)+len(daemonSets.Items) == 0 {
+		return result
+	}
+
+	result.Status = StatusOK
+	if result.Ready < result.Desired {
+		result.Status = StatusFailed
+		result.Message = fmt.Sprintf("%d of %d replicas available", result.Ready, result.Desired)
+	}
+	return result
+}
+
+func addonError(result AddonHealth, err error) AddonHealth {
+	result.Status, result.Message = StatusError, err.Error()
+	if apierrors.IsForbidden(err) {
+		result.Status = StatusForbidden
+	}
+	return result
+}
+
+func deploymentReplicas(d appsV1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
+func overallStatus(health ClusterHealth) string {
+	known := false
+	for _, e := range health.Endpoints {
+		switch e.Status {
+		case StatusOK:
+			known = true
+		case StatusFailed:
+			return ClusterUnhealthy
+		}
+	}
+	if !known {
+		return ClusterUnknown
+	}
+
+	for _, c := range health.Components {
+		if c.Status != StatusOK {
+			return ClusterDegraded
+		}
+	}
+	for _, a := range health.Addons {
+		if a.Status == StatusFailed {
+			return ClusterDegraded
+		}
+	}
+	return ClusterHealthy
+}
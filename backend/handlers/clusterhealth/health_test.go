@@ -0,0 +1,70 @@
+package clusterhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestClusterHealth(t *testing.T) {
+	coredns := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-dns"}},
+		Spec:       appsV1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		Status:     appsV1.DeploymentStatus{AvailableReplicas: 2},
+	}
+	kubeProxy := &appsV1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-proxy"}},
+		Status:     appsV1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 2},
+	}
+	etcd := &coreV1.ComponentStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"},
+		Conditions: []coreV1.ComponentCondition{{Type: coreV1.ComponentHealthy, Status: coreV1.ConditionTrue, Message: "ok"}},
+	}
+	healthy := func(context.Context, string) ([]byte, error) {
+		return []byte("[+]ping ok\nhealthz check passed\n"), nil
+	}
+
+	t.Run("degraded addon", func(t *testing.T) {
+		got := clusterHealth(context.Background(), fake.NewClientset(coredns, kubeProxy, etcd), healthy)
+		assert.Equal(t, ClusterDegraded, got.Status)
+		assert.Equal(t, []ComponentHealth{{Name: "etcd-0", Status: StatusOK, Message: "ok"}}, got.Components)
+		assert.Equal(t, []AddonHealth{
+			{Name: "coredns", Kind: "Deployment", Status: StatusOK, Ready: 2, Desired: 2},
+			{Name: "kube-proxy", Kind: "DaemonSet", Status: StatusFailed, Ready: 2, Desired: 3, Message: "2 of 3 replicas available"},
+			{Name: "metrics-server", Status: StatusNotFound},
+		}, got.Addons)
+	})
+
+	t.Run("failing readyz check", func(t *testing.T) {
+		probe := func(_ context.Context, path string) ([]byte, error) {
+			if path == "/readyz" {
+				return []byte("[+]ping ok\n[-]etcd failed: reason withheld\nreadyz check failed\n"), apierrors.NewInternalError(errors.New("readyz check failed"))
+			}
+			return healthy(context.Background(), path)
+		}
+		got := clusterHealth(context.Background(), fake.NewClientset(coredns), probe)
+		assert.Equal(t, ClusterUnhealthy, got.Status)
+		assert.Equal(t, StatusFailed, got.Endpoints[1].Status)
+		assert.Equal(t, []string{"etcd"}, got.Endpoints[1].FailedChecks)
+	})
+
+	t.Run("health endpoints forbidden", func(t *testing.T) {
+		probe := func(_ context.Context, path string) ([]byte, error) {
+			return nil, apierrors.NewForbidden(schema.GroupResource{}, "", errors.New(`cannot get path "`+path+`"`))
+		}
+		got := clusterHealth(context.Background(), fake.NewClientset(), probe)
+		assert.Equal(t, ClusterUnknown, got.Status)
+		for _, e := range got.Endpoints {
+			assert.Equal(t, StatusForbidden, e.Status)
+		}
+	})
+}
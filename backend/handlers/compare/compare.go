@@ -0,0 +1,87 @@
+package compare
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceRef identifies one side of a comparison. Each side carries its own
+// config and cluster so resources can be compared across clusters.
+type ResourceRef struct {
+	Config    string `json:"config"`
+	Cluster   string `json:"cluster"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type CompareRequest struct {
+	Left  ResourceRef `json:"left"`
+	Right ResourceRef `json:"right"`
+}
+
+type CompareResponse struct {
+	Identical bool                `json:"identical"`
+	Diffs     []helpers.FieldDiff `json:"diffs"`
+}
+
+type CompareHandler struct {
+	container container.Container
+}
+
+func NewCompareHandler(container container.Container) *CompareHandler {
+	return &CompareHandler{container: container}
+}
+
+// CompareResources returns a field-level diff of two resources, ignoring
+// status and server-managed metadata.
+func (h *CompareHandler) CompareResources(c echo.Context) error {
+	r := new(CompareRequest)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	left, err := h.get(c, r.Left)
+	if err != nil {
+		return err
+	}
+	right, err := h.get(c, r.Right)
+	if err != nil {
+		return err
+	}
+
+	diffs := helpers.Diff(helpers.PruneForDiff(left.Object), helpers.PruneForDiff(right.Object))
+	return c.JSON(http.StatusOK, CompareResponse{
+		Identical: len(diffs) == 0,
+		Diffs:     diffs,
+	})
+}
+
+func (h *CompareHandler) get(c echo.Context, ref ResourceRef) (*unstructured.Unstructured, error) {
+	if ref.Version == "" || ref.Resource == "" || ref.Name == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "version, resource and name are required on both sides")
+	}
+	client := h.container.DynamicClient(ref.Config, ref.Cluster)
+	if client == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", ref.Cluster, ref.Config))
+	}
+
+	gvr := schema.GroupVersionResource{Group: ref.Group, Version: ref.Version, Resource: ref.Resource}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return nil, err
+	}
+	obj, err := client.Resource(gvr).Namespace(ref.Namespace).Get(c.Request().Context(), ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return obj, nil
+}
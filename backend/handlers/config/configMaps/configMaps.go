@@ -13,6 +13,11 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 )
 
+const (
+	GetConfigMapConsumers base.RouteType = 12
+	GetConfigMapKey       base.RouteType = 13
+)
+
 type ConfigMapsHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -32,6 +37,10 @@ func NewConfigMapsRouteHandler(container container.Container, routeType base.Rou
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetConfigMapConsumers:
+			return handler.GetConfigMapConsumers(c)
+		case GetConfigMapKey:
+			return handler.GetConfigMapKey(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -51,14 +60,15 @@ func newConfigMapsHandler(ctx context.Context, config, cluster string, container
 
 	handler := &ConfigMapsHandler{
 		BaseHandler: base.BaseHandler{
-			Kind:             "ConfigMap",
-			Container:        container,
-			Informer:         informer,
-			RestClient:       container.ClientSet(config, cluster).CoreV1().RESTClient(),
-			QueryConfig:      config,
-			QueryCluster:     cluster,
-			InformerCacheKey: fmt.Sprintf("%s-%s-configMapInformer", config, cluster),
-			TransformFunc:    transformItems,
+			Kind:                "ConfigMap",
+			Container:           container,
+			Informer:            informer,
+			RestClient:          container.ClientSet(config, cluster).CoreV1().RESTClient(),
+			QueryConfig:         config,
+			QueryCluster:        cluster,
+			InformerCacheKey:    fmt.Sprintf("%s-%s-configMapInformer", config, cluster),
+			TransformFunc:       transformItems,
+			DetailTransformFunc: transformDetail,
 		},
 	}
 	cache := base.ResourceEventHandler[*coreV1.ConfigMap](&handler.BaseHandler)
@@ -79,3 +89,49 @@ func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 
 	return json.Marshal(t)
 }
+
+// GetConfigMapConsumers lists the pods that reference the ConfigMap through
+// volumes, env or envFrom.
+func (h *ConfigMapsHandler) GetConfigMapConsumers(c echo.Context) error {
+	podInformer := helpers.PodInformer(c.Request().Context(), h.BaseHandler.Container, h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	return helpers.ServePodConsumers(c, podInformer, "ConfigMap")
+}
+
+type configMapDetail struct {
+	*coreV1.ConfigMap
+	// OmittedBinaryData holds the sizes of binaryData values too large to
+	// send inline; fetch them with GetConfigMapKey.
+	OmittedBinaryData map[string]int `json:"omittedBinaryData,omitempty"`
+}
+
+func transformDetail(item any) any {
+	configMap, ok := item.(*coreV1.ConfigMap)
+	if !ok {
+		return item
+	}
+	configMap = configMap.DeepCopy()
+	d := configMapDetail{ConfigMap: configMap}
+	configMap.BinaryData, d.OmittedBinaryData = helpers.OmitLargeValues(configMap.BinaryData)
+	return d
+}
+
+// GetConfigMapKey serves the value of a single data or binaryData key.
+func (h *ConfigMapsHandler) GetConfigMapKey(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	configMap, ok := obj.(*coreV1.ConfigMap)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("configmap %s not found", key))
+	}
+
+	if value, ok := configMap.Data[c.Param("key")]; ok {
+		return helpers.ServeDataValue(c, c.Param("key"), []byte(value))
+	}
+	if value, ok := configMap.BinaryData[c.Param("key")]; ok {
+		return helpers.ServeDataValue(c, c.Param("key"), value)
+	}
+	return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("key %s not found in configmap %s", c.Param("key"), key))
+}
@@ -12,12 +12,14 @@ import (
 )
 
 type ConfigMapList struct {
-	UID       types.UID `json:"uid"`
-	Namespace string    `json:"namespace"`
-	Name      string    `json:"name"`
-	Keys      []string  `json:"keys"`
-	Count     int       `json:"count"`
-	Age       time.Time `json:"age"`
+	UID        types.UID `json:"uid"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Keys       []string  `json:"keys"`
+	BinaryKeys []string  `json:"binaryKeys"`
+	Count      int       `json:"count"`
+	Size       int       `json:"size"`
+	Age        time.Time `json:"age"`
 }
 
 func TransformConfigMapList(configMaps []v1.ConfigMap) []ConfigMapList {
@@ -36,12 +38,14 @@ func TransformConfigMapList(configMaps []v1.ConfigMap) []ConfigMapList {
 
 func TransConfigMapsItem(configMap v1.ConfigMap) ConfigMapList {
 	return ConfigMapList{
-		UID:       configMap.GetUID(),
-		Namespace: configMap.GetNamespace(),
-		Name:      configMap.GetName(),
-		Keys:      getKeysNames(configMap.Data),
-		Count:     len(configMap.Data),
-		Age:       configMap.CreationTimestamp.Time,
+		UID:        configMap.GetUID(),
+		Namespace:  configMap.GetNamespace(),
+		Name:       configMap.GetName(),
+		Keys:       getKeysNames(configMap.Data),
+		BinaryKeys: getBinaryKeysNames(configMap.BinaryData),
+		Count:      len(configMap.Data) + len(configMap.BinaryData),
+		Size:       dataSize(configMap),
+		Age:        configMap.CreationTimestamp.Time,
 	}
 }
 
@@ -55,3 +59,25 @@ func getKeysNames(data map[string]string) []string {
 
 	return output
 }
+
+func getBinaryKeysNames(data map[string][]byte) []string {
+	output := make([]string, 0)
+	for k := range data {
+		output = append(output, k)
+	}
+	sort.Strings(output)
+
+	return output
+}
+
+// dataSize is the total size in bytes of the ConfigMap values.
+func dataSize(configMap v1.ConfigMap) int {
+	size := 0
+	for _, v := range configMap.Data {
+		size += len(v)
+	}
+	for _, v := range configMap.BinaryData {
+		size += len(v)
+	}
+	return size
+}
@@ -0,0 +1,101 @@
+package horizontalpodautoscalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	autoScalingV2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// HPABounds is the body of SetHPABounds. Omitted fields keep their value.
+type HPABounds struct {
+	MinReplicas *int32 `json:"minReplicas"`
+	MaxReplicas *int32 `json:"maxReplicas"`
+}
+
+// SetHPABounds updates the min and max replicas of an HPA and returns it. A
+// resourceVersion makes the update conditional.
+func (h *HorizontalPodAutoScalerHandler) SetHPABounds(c echo.Context) error {
+	bounds := new(HPABounds)
+	if err := c.Bind(bounds); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if bounds.MinReplicas == nil && bounds.MaxReplicas == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "minReplicas or maxReplicas is required")
+	}
+
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	hpa, err := setHPABounds(c.Request().Context(), clientSet, c.QueryParam("namespace"), c.Param("name"), *bounds, helpers.ResourceVersion(c))
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return c.JSON(http.StatusOK, hpa)
+}
+
+func setHPABounds(ctx context.Context, clientSet kubernetes.Interface, namespace, name string, bounds HPABounds, resourceVersion string) (*autoScalingV2.HorizontalPodAutoscaler, error) {
+	hpas := clientSet.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+	hpa, err := hpas.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := helpers.CheckResourceVersion(hpa, autoScalingV2.Resource("horizontalpodautoscalers"), resourceVersion); err != nil {
+		return nil, err
+	}
+
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	if bounds.MinReplicas != nil {
+		minReplicas = *bounds.MinReplicas
+	}
+	maxReplicas := hpa.Spec.MaxReplicas
+	if bounds.MaxReplicas != nil {
+		maxReplicas = *bounds.MaxReplicas
+	}
+	if err := validateBounds(minReplicas, maxReplicas, hpa.Spec.Metrics); err != nil {
+		return nil, err
+	}
+
+	patch, err := json.Marshal(helpers.PreconditionPatch(map[string]any{
+		"spec": map[string]any{"minReplicas": minReplicas, "maxReplicas": maxReplicas},
+	}, resourceVersion))
+	if err != nil {
+		return nil, err
+	}
+	return hpas.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// validateBounds applies the HPA API rules. A minimum of zero is only valid
+// when every metric is an Object or External metric, since there is nothing
+// to measure per pod once the target is scaled to zero.
+func validateBounds(minReplicas, maxReplicas int32, metrics []autoScalingV2.MetricSpec) error {
+	if minReplicas < 0 {
+		return errors.New("minReplicas must be greater than or equal to 0")
+	}
+	if maxReplicas < 1 {
+		return errors.New("maxReplicas must be greater than or equal to 1")
+	}
+	if minReplicas > maxReplicas {
+		return fmt.Errorf("minReplicas (%d) must be less than or equal to maxReplicas (%d)", minReplicas, maxReplicas)
+	}
+	if minReplicas == 0 {
+		if len(metrics) == 0 {
+			return errors.New("minReplicas can only be 0 with at least one Object or External metric")
+		}
+		for _, m := range metrics {
+			if m.Type != autoScalingV2.ObjectMetricSourceType && m.Type != autoScalingV2.ExternalMetricSourceType {
+				return errors.New("minReplicas can only be 0 when all metrics are Object or External metrics")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package horizontalpodautoscalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoScalingV2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateBounds(t *testing.T) {
+	resource := []autoScalingV2.MetricSpec{{Type: autoScalingV2.ResourceMetricSourceType}}
+	external := []autoScalingV2.MetricSpec{{Type: autoScalingV2.ExternalMetricSourceType}}
+
+	assert.NoError(t, validateBounds(1, 5, resource))
+	assert.NoError(t, validateBounds(3, 3, resource))
+	assert.NoError(t, validateBounds(0, 5, external))
+	assert.Error(t, validateBounds(-1, 5, resource))
+	assert.Error(t, validateBounds(6, 5, resource))
+	assert.Error(t, validateBounds(0, 0, external))
+	assert.Error(t, validateBounds(0, 5, resource))
+	assert.Error(t, validateBounds(0, 5, nil))
+}
+
+func TestSetHPABounds(t *testing.T) {
+	minReplicas := int32(2)
+	clientSet := fake.NewClientset(&autoScalingV2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "7"},
+		Spec:       autoScalingV2.HorizontalPodAutoscalerSpec{MinReplicas: &minReplicas, MaxReplicas: 5},
+	})
+	int32Ptr := func(v int32) *int32 { return &v }
+
+	hpa, err := setHPABounds(context.Background(), clientSet, "default", "web", HPABounds{MaxReplicas: int32Ptr(10)}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+
+	_, err = setHPABounds(context.Background(), clientSet, "default", "web", HPABounds{MinReplicas: int32Ptr(11)}, "")
+	assert.ErrorContains(t, err, "less than or equal to maxReplicas")
+
+	_, err = setHPABounds(context.Background(), clientSet, "default", "missing", HPABounds{MinReplicas: int32Ptr(1)}, "")
+	assert.Error(t, err)
+
+	_, err = setHPABounds(context.Background(), clientSet, "default", "web", HPABounds{MaxReplicas: int32Ptr(8)}, "6")
+	assert.True(t, apierrors.IsConflict(err))
+}
@@ -14,6 +14,11 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	GetHPAStatus base.RouteType = 12
+	SetHPABounds base.RouteType = 13
+)
+
 type HorizontalPodAutoScalerHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -33,6 +38,10 @@ func NewHorizontalPodAutoscalersRouteHandler(container container.Container, rout
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetHPAStatus:
+			return handler.GetHPAStatus(c)
+		case SetHPABounds:
+			return handler.SetHPABounds(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -81,3 +90,18 @@ func transformItems(items []any, _ *base.BaseHandler) ([]byte, error) {
 
 	return json.Marshal(t)
 }
+
+// GetHPAStatus returns the scaling status of an HPA: replica counts, current
+// against target metric values, and the controller's conditions.
+func (h *HorizontalPodAutoScalerHandler) GetHPAStatus(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	item, ok := obj.(*autoScalingV2.HorizontalPodAutoscaler)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("horizontal pod autoscaler %s not found", key))
+	}
+	return c.JSON(http.StatusOK, TransformHPAStatus(*item))
+}
@@ -12,11 +12,14 @@ import (
 )
 
 type ResourceQuota struct {
-	UID       types.UID `json:"uid"`
-	Namespace string    `json:"namespace"`
-	Name      string    `json:"name"`
-	Spec      Spec      `json:"spec"`
-	Age       time.Time `json:"age"`
+	UID            types.UID      `json:"uid"`
+	Namespace      string         `json:"namespace"`
+	Name           string         `json:"name"`
+	Spec           Spec           `json:"spec"`
+	ScaleTargetRef ScaleTargetRef `json:"scaleTargetRef"`
+	Status         Status         `json:"status"`
+	Metrics        []Metric       `json:"metrics"`
+	Age            time.Time      `json:"age"`
 }
 
 type Spec struct {
@@ -24,6 +27,42 @@ type Spec struct {
 	MaxPods int32  `json:"maxPods"`
 }
 
+type ScaleTargetRef struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+type Status struct {
+	CurrentReplicas int32      `json:"currentReplicas"`
+	DesiredReplicas int32      `json:"desiredReplicas"`
+	LastScaleTime   *time.Time `json:"lastScaleTime,omitempty"`
+}
+
+// Metric pairs a metric from the spec with its last observed value. Current
+// is empty until the controller has read the metric.
+type Metric struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Target  string `json:"target"`
+}
+
+type Condition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// HPAStatus is the detail view of an HPA, including the conditions that
+// explain why it is or isn't scaling.
+type HPAStatus struct {
+	ResourceQuota
+	Conditions []Condition `json:"conditions"`
+}
+
 func TransformHorizontalPodAutoscaler(items []autoScalingV2.HorizontalPodAutoscaler) []ResourceQuota {
 	list := make([]ResourceQuota, 0)
 
@@ -39,6 +78,14 @@ func TransformHorizontalPodAutoscaler(items []autoScalingV2.HorizontalPodAutosca
 }
 
 func TransformLimitRangeItem(item autoScalingV2.HorizontalPodAutoscaler) ResourceQuota {
+	status := Status{
+		CurrentReplicas: item.Status.CurrentReplicas,
+		DesiredReplicas: item.Status.DesiredReplicas,
+	}
+	if item.Status.LastScaleTime != nil {
+		status.LastScaleTime = &item.Status.LastScaleTime.Time
+	}
+
 	return ResourceQuota{
 		UID:       item.GetUID(),
 		Namespace: item.GetNamespace(),
@@ -47,6 +94,128 @@ func TransformLimitRangeItem(item autoScalingV2.HorizontalPodAutoscaler) Resourc
 			MinPods: item.Spec.MinReplicas,
 			MaxPods: item.Spec.MaxReplicas,
 		},
-		Age: item.CreationTimestamp.Time,
+		ScaleTargetRef: ScaleTargetRef{
+			Kind:       item.Spec.ScaleTargetRef.Kind,
+			Name:       item.Spec.ScaleTargetRef.Name,
+			APIVersion: item.Spec.ScaleTargetRef.APIVersion,
+		},
+		Status:  status,
+		Metrics: transformMetrics(item.Spec.Metrics, item.Status.CurrentMetrics),
+		Age:     item.CreationTimestamp.Time,
+	}
+}
+
+func TransformHPAStatus(item autoScalingV2.HorizontalPodAutoscaler) HPAStatus {
+	conditions := make([]Condition, 0, len(item.Status.Conditions))
+	for _, c := range item.Status.Conditions {
+		conditions = append(conditions, Condition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return HPAStatus{
+		ResourceQuota: TransformLimitRangeItem(item),
+		Conditions:    conditions,
+	}
+}
+
+// transformMetrics matches current metrics to the spec by type and name, as
+// the status list is not guaranteed to follow the spec order.
+func transformMetrics(specs []autoScalingV2.MetricSpec, statuses []autoScalingV2.MetricStatus) []Metric {
+	current := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		name, value := metricStatusValue(s)
+		current[string(s.Type)+"/"+name] = value
+	}
+
+	metrics := make([]Metric, 0, len(specs))
+	for _, s := range specs {
+		name, target := metricSpecTarget(s)
+		metrics = append(metrics, Metric{
+			Type:    string(s.Type),
+			Name:    name,
+			Current: current[string(s.Type)+"/"+name],
+			Target:  target,
+		})
+	}
+	return metrics
+}
+
+func metricSpecTarget(s autoScalingV2.MetricSpec) (string, string) {
+	switch s.Type {
+	case autoScalingV2.ResourceMetricSourceType:
+		if s.Resource != nil {
+			return string(s.Resource.Name), formatTarget(s.Resource.Target)
+		}
+	case autoScalingV2.ContainerResourceMetricSourceType:
+		if s.ContainerResource != nil {
+			return fmt.Sprintf("%s/%s", s.ContainerResource.Container, s.ContainerResource.Name), formatTarget(s.ContainerResource.Target)
+		}
+	case autoScalingV2.PodsMetricSourceType:
+		if s.Pods != nil {
+			return s.Pods.Metric.Name, formatTarget(s.Pods.Target)
+		}
+	case autoScalingV2.ObjectMetricSourceType:
+		if s.Object != nil {
+			return fmt.Sprintf("%s/%s/%s", s.Object.DescribedObject.Kind, s.Object.DescribedObject.Name, s.Object.Metric.Name), formatTarget(s.Object.Target)
+		}
+	case autoScalingV2.ExternalMetricSourceType:
+		if s.External != nil {
+			return s.External.Metric.Name, formatTarget(s.External.Target)
+		}
+	}
+	return "", ""
+}
+
+func metricStatusValue(s autoScalingV2.MetricStatus) (string, string) {
+	switch s.Type {
+	case autoScalingV2.ResourceMetricSourceType:
+		if s.Resource != nil {
+			return string(s.Resource.Name), formatValue(s.Resource.Current)
+		}
+	case autoScalingV2.ContainerResourceMetricSourceType:
+		if s.ContainerResource != nil {
+			return fmt.Sprintf("%s/%s", s.ContainerResource.Container, s.ContainerResource.Name), formatValue(s.ContainerResource.Current)
+		}
+	case autoScalingV2.PodsMetricSourceType:
+		if s.Pods != nil {
+			return s.Pods.Metric.Name, formatValue(s.Pods.Current)
+		}
+	case autoScalingV2.ObjectMetricSourceType:
+		if s.Object != nil {
+			return fmt.Sprintf("%s/%s/%s", s.Object.DescribedObject.Kind, s.Object.DescribedObject.Name, s.Object.Metric.Name), formatValue(s.Object.Current)
+		}
+	case autoScalingV2.ExternalMetricSourceType:
+		if s.External != nil {
+			return s.External.Metric.Name, formatValue(s.External.Current)
+		}
+	}
+	return "", ""
+}
+
+func formatTarget(t autoScalingV2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return t.AverageValue.String()
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
+
+func formatValue(v autoScalingV2.MetricValueStatus) string {
+	switch {
+	case v.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *v.AverageUtilization)
+	case v.AverageValue != nil:
+		return v.AverageValue.String()
+	case v.Value != nil:
+		return v.Value.String()
 	}
+	return ""
 }
@@ -0,0 +1,64 @@
+package horizontalpodautoscalers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoScalingV2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestTransformMetrics(t *testing.T) {
+	utilization := int32(80)
+	currentUtilization := int32(45)
+	averageValue := resource.MustParse("100")
+	currentAverage := resource.MustParse("120")
+
+	specs := []autoScalingV2.MetricSpec{
+		{
+			Type: autoScalingV2.ResourceMetricSourceType,
+			Resource: &autoScalingV2.ResourceMetricSource{
+				Name:   v1.ResourceCPU,
+				Target: autoScalingV2.MetricTarget{Type: autoScalingV2.UtilizationMetricType, AverageUtilization: &utilization},
+			},
+		},
+		{
+			Type: autoScalingV2.PodsMetricSourceType,
+			Pods: &autoScalingV2.PodsMetricSource{
+				Metric: autoScalingV2.MetricIdentifier{Name: "requests_per_second"},
+				Target: autoScalingV2.MetricTarget{Type: autoScalingV2.AverageValueMetricType, AverageValue: &averageValue},
+			},
+		},
+		{
+			Type: autoScalingV2.ResourceMetricSourceType,
+			Resource: &autoScalingV2.ResourceMetricSource{
+				Name:   v1.ResourceMemory,
+				Target: autoScalingV2.MetricTarget{Type: autoScalingV2.UtilizationMetricType, AverageUtilization: &utilization},
+			},
+		},
+	}
+	// Status order differs from the spec and memory has not been read yet.
+	statuses := []autoScalingV2.MetricStatus{
+		{
+			Type: autoScalingV2.PodsMetricSourceType,
+			Pods: &autoScalingV2.PodsMetricStatus{
+				Metric:  autoScalingV2.MetricIdentifier{Name: "requests_per_second"},
+				Current: autoScalingV2.MetricValueStatus{AverageValue: &currentAverage},
+			},
+		},
+		{
+			Type: autoScalingV2.ResourceMetricSourceType,
+			Resource: &autoScalingV2.ResourceMetricStatus{
+				Name:    v1.ResourceCPU,
+				Current: autoScalingV2.MetricValueStatus{AverageUtilization: &currentUtilization},
+			},
+		},
+	}
+
+	assert.Equal(t, []Metric{
+		{Type: "Resource", Name: "cpu", Current: "45%", Target: "80%"},
+		{Type: "Pods", Name: "requests_per_second", Current: "120", Target: "100"},
+		{Type: "Resource", Name: "memory", Current: "", Target: "80%"},
+	}, transformMetrics(specs, statuses))
+}
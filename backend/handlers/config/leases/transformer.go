@@ -11,12 +11,17 @@ import (
 )
 
 type Lease struct {
-	UID                  types.UID `json:"uid"`
-	Namespace            string    `json:"namespace"`
-	Name                 string    `json:"name"`
-	HolderIdentity       *string   `json:"holderIdentity"`
-	LeaseDurationSeconds *int32    `json:"leaseDurationSeconds"`
-	Age                  time.Time `json:"age"`
+	UID                  types.UID  `json:"uid"`
+	Namespace            string     `json:"namespace"`
+	Name                 string     `json:"name"`
+	HolderIdentity       *string    `json:"holderIdentity"`
+	LeaseDurationSeconds *int32     `json:"leaseDurationSeconds"`
+	RenewTime            *time.Time `json:"renewTime"`
+	// Stale is set when the holder did not renew within the lease duration,
+	// which usually means the controller or node holding it is gone. It is
+	// computed when the list is sent, clients can recheck it from renewTime.
+	Stale bool      `json:"stale"`
+	Age   time.Time `json:"age"`
 }
 
 func TransformLeaseList(secrets []v1.Lease) []Lease {
@@ -34,12 +39,26 @@ func TransformLeaseList(secrets []v1.Lease) []Lease {
 }
 
 func TransformRunTimeClassItem(item v1.Lease) Lease {
+	var renewTime *time.Time
+	if item.Spec.RenewTime != nil {
+		renewTime = &item.Spec.RenewTime.Time
+	}
 	return Lease{
 		UID:                  item.GetUID(),
 		Namespace:            item.GetNamespace(),
 		Name:                 item.GetName(),
 		HolderIdentity:       item.Spec.HolderIdentity,
 		LeaseDurationSeconds: item.Spec.LeaseDurationSeconds,
+		RenewTime:            renewTime,
+		Stale:                isStale(item.Spec, time.Now()),
 		Age:                  item.CreationTimestamp.Time,
 	}
 }
+
+func isStale(spec v1.LeaseSpec, now time.Time) bool {
+	if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+	return expiry.Before(now)
+}
@@ -0,0 +1,35 @@
+package leases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	duration := int32(40)
+	renewed := func(ago time.Duration) *metav1.MicroTime {
+		t := metav1.NewMicroTime(now.Add(-ago))
+		return &t
+	}
+
+	tests := []struct {
+		name string
+		spec v1.LeaseSpec
+		want bool
+	}{
+		{name: "recently renewed", spec: v1.LeaseSpec{RenewTime: renewed(10 * time.Second), LeaseDurationSeconds: &duration}},
+		{name: "expired", spec: v1.LeaseSpec{RenewTime: renewed(time.Minute), LeaseDurationSeconds: &duration}, want: true},
+		{name: "never renewed", spec: v1.LeaseSpec{LeaseDurationSeconds: &duration}},
+		{name: "no duration", spec: v1.LeaseSpec{RenewTime: renewed(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isStale(tt.spec, now))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package limitranges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// EffectiveLimit is what the LimitRanges of a namespace enforce for one
+// resource of one limit type. Default and DefaultRequest each come from the
+// first LimitRange setting them, named by DefaultFrom and DefaultRequestFrom.
+// Min, Max and MaxLimitRequestRatio are the tightest of all of them, as
+// every LimitRange is enforced.
+type EffectiveLimit struct {
+	Type                 coreV1.LimitType `json:"type"`
+	Resource             string           `json:"resource"`
+	Default              string           `json:"default,omitempty"`
+	DefaultRequest       string           `json:"defaultRequest,omitempty"`
+	DefaultFrom          string           `json:"defaultFrom,omitempty"`
+	DefaultRequestFrom   string           `json:"defaultRequestFrom,omitempty"`
+	Min                  string           `json:"min,omitempty"`
+	Max                  string           `json:"max,omitempty"`
+	MaxLimitRequestRatio string           `json:"maxLimitRequestRatio,omitempty"`
+}
+
+type NamespaceDefaults struct {
+	Namespace   string           `json:"namespace"`
+	LimitRanges []string         `json:"limitRanges"`
+	Limits      []EffectiveLimit `json:"limits"`
+}
+
+// GetNamespaceDefaults returns the default requests and limits, and the
+// bounds, the LimitRanges of a namespace apply to its pods and claims.
+func (h *LimitRangesHandler) GetNamespaceDefaults(c echo.Context) error {
+	namespace := c.Param("name")
+	objs, err := h.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	limitRanges := make([]coreV1.LimitRange, 0, len(objs))
+	for _, obj := range objs {
+		if limitRange, ok := obj.(*coreV1.LimitRange); ok {
+			limitRanges = append(limitRanges, *limitRange)
+		}
+	}
+	return c.JSON(http.StatusOK, namespaceDefaults(namespace, limitRanges))
+}
+
+func namespaceDefaults(namespace string, limitRanges []coreV1.LimitRange) NamespaceDefaults {
+	// The LimitRanger admission plugin applies the defaults of the
+	// LimitRanges in list order, which the API server returns by name.
+	sort.Slice(limitRanges, func(i, j int) bool { return limitRanges[i].Name < limitRanges[j].Name })
+
+	type limitKey struct {
+		limitType coreV1.LimitType
+		resource  coreV1.ResourceName
+	}
+	type effective struct {
+		defaultFrom, defaultRequestFrom         string
+		def, defaultRequest, min, max, maxRatio *resource.Quantity
+	}
+	limits := make(map[limitKey]*effective)
+	var keys []limitKey
+	get := func(key limitKey) *effective {
+		if limits[key] == nil {
+			limits[key] = &effective{}
+			keys = append(keys, key)
+		}
+		return limits[key]
+	}
+	tighter := func(current *resource.Quantity, q resource.Quantity, lower bool) *resource.Quantity {
+		if current == nil || (lower && q.Cmp(*current) > 0) || (!lower && q.Cmp(*current) < 0) {
+			return &q
+		}
+		return current
+	}
+
+	result := NamespaceDefaults{Namespace: namespace, LimitRanges: make([]string, 0, len(limitRanges)), Limits: make([]EffectiveLimit, 0)}
+	for _, limitRange := range limitRanges {
+		result.LimitRanges = append(result.LimitRanges, limitRange.Name)
+		for _, item := range limitRange.Spec.Limits {
+			for name, q := range item.Default {
+				if e := get(limitKey{item.Type, name}); e.def == nil {
+					e.def, e.defaultFrom = &q, limitRange.Name
+				}
+			}
+			for name, q := range item.DefaultRequest {
+				if e := get(limitKey{item.Type, name}); e.defaultRequest == nil {
+					e.defaultRequest, e.defaultRequestFrom = &q, limitRange.Name
+				}
+			}
+			for name, q := range item.Min {
+				e := get(limitKey{item.Type, name})
+				e.min = tighter(e.min, q, true)
+			}
+			for name, q := range item.Max {
+				e := get(limitKey{item.Type, name})
+				e.max = tighter(e.max, q, false)
+			}
+			for name, q := range item.MaxLimitRequestRatio {
+				e := get(limitKey{item.Type, name})
+				e.maxRatio = tighter(e.maxRatio, q, false)
+			}
+		}
+	}
+
+	format := func(q *resource.Quantity) string {
+		if q == nil {
+			return ""
+		}
+		return q.String()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].limitType != keys[j].limitType {
+			return keys[i].limitType < keys[j].limitType
+		}
+		return keys[i].resource < keys[j].resource
+	})
+	for _, key := range keys {
+		e := limits[key]
+		result.Limits = append(result.Limits, EffectiveLimit{
+			Type:                 key.limitType,
+			Resource:             string(key.resource),
+			Default:              format(e.def),
+			DefaultRequest:       format(e.defaultRequest),
+			DefaultFrom:          e.defaultFrom,
+			DefaultRequestFrom:   e.defaultRequestFrom,
+			Min:                  format(e.min),
+			Max:                  format(e.max),
+			MaxLimitRequestRatio: format(e.maxRatio),
+		})
+	}
+	return result
+}
+
+// SaveLimitRange creates the LimitRange named in the path with the spec in
+// the body, or replaces the spec of an existing one. A resourceVersion makes
+// the update conditional. The spec is validated first, so mistakes fail with
+// every problem listed instead of one at a time.
+func (h *LimitRangesHandler) SaveLimitRange(c echo.Context) error {
+	spec := new(coreV1.LimitRangeSpec)
+	if err := c.Bind(spec); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := validateLimitRangeSpec(spec); err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	limitRange, created, err := saveLimitRange(c.Request().Context(), clientSet, c.QueryParam("namespace"), c.Param("name"), *spec, helpers.ResourceVersion(c))
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	if created {
+		return c.JSON(http.StatusCreated, limitRange)
+	}
+	return c.JSON(http.StatusOK, limitRange)
+}
+
+func saveLimitRange(ctx context.Context, clientSet kubernetes.Interface, namespace, name string, spec coreV1.LimitRangeSpec, resourceVersion string) (*coreV1.LimitRange, bool, error) {
+	limitRanges := clientSet.CoreV1().LimitRanges(namespace)
+	current, err := limitRanges.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && resourceVersion == "" {
+		created, err := limitRanges.Create(ctx, &coreV1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       spec,
+		}, metav1.CreateOptions{})
+		return created, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := helpers.CheckResourceVersion(current, coreV1.Resource("limitranges"), resourceVersion); err != nil {
+		return nil, false, err
+	}
+	// Update sends the resourceVersion read, so a change in between
+	// conflicts instead of being overwritten.
+	current.Spec = spec
+	updated, err := limitRanges.Update(ctx, current, metav1.UpdateOptions{})
+	return updated, false, err
+}
+
+// validateLimitRangeSpec applies the API server's LimitRange rules: known
+// limit types, no defaults for pods, storage bounds for claims, and for
+// every resource min <= defaultRequest <= default <= max with the default
+// limit to request ratio within maxLimitRequestRatio.
+func validateLimitRangeSpec(spec *coreV1.LimitRangeSpec) error {
+	var errs field.ErrorList
+	limitsPath := field.NewPath("spec", "limits")
+	if len(spec.Limits) == 0 {
+		errs = append(errs, field.Required(limitsPath, "at least one limit is required"))
+	}
+	for i, item := range spec.Limits {
+		path := limitsPath.Index(i)
+		switch item.Type {
+		case coreV1.LimitTypeContainer:
+		case coreV1.LimitTypePod:
+			if len(item.Default) > 0 {
+				errs = append(errs, field.Forbidden(path.Child("default"), "not supported when limit type is Pod"))
+			}
+			if len(item.DefaultRequest) > 0 {
+				errs = append(errs, field.Forbidden(path.Child("defaultRequest"), "not supported when limit type is Pod"))
+			}
+		case coreV1.LimitTypePersistentVolumeClaim:
+			_, minOk := item.Min[coreV1.ResourceStorage]
+			_, maxOk := item.Max[coreV1.ResourceStorage]
+			if !minOk && !maxOk {
+				errs = append(errs, field.Required(path, "either minimum or maximum storage value is required"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(path.Child("type"), item.Type, []coreV1.LimitType{coreV1.LimitTypeContainer, coreV1.LimitTypePod, coreV1.LimitTypePersistentVolumeClaim}))
+		}
+
+		for _, values := range []struct {
+			name string
+			list coreV1.ResourceList
+		}{{"max", item.Max}, {"min", item.Min}, {"default", item.Default}, {"defaultRequest", item.DefaultRequest}, {"maxLimitRequestRatio", item.MaxLimitRequestRatio}} {
+			for name, q := range values.list {
+				if q.Sign() < 0 {
+					errs = append(errs, field.Invalid(path.Child(values.name).Key(string(name)), q.String(), "must be greater than or equal to 0"))
+				}
+			}
+		}
+
+		less := func(lowName string, low coreV1.ResourceList, highName string, high coreV1.ResourceList) {
+			for name, lowQ := range low {
+				if highQ, ok := high[name]; ok && lowQ.Cmp(highQ) > 0 {
+					errs = append(errs, field.Invalid(path.Child(lowName).Key(string(name)), lowQ.String(),
+						fmt.Sprintf("must be less than or equal to %s value %s", highName, highQ.String())))
+				}
+			}
+		}
+		less("min", item.Min, "max", item.Max)
+		less("min", item.Min, "defaultRequest", item.DefaultRequest)
+		less("defaultRequest", item.DefaultRequest, "default", item.Default)
+		less("default", item.Default, "max", item.Max)
+		less("min", item.Min, "default", item.Default)
+		less("defaultRequest", item.DefaultRequest, "max", item.Max)
+
+		for name, ratio := range item.MaxLimitRequestRatio {
+			ratioPath := path.Child("maxLimitRequestRatio").Key(string(name))
+			if ratio.Cmp(resource.MustParse("1")) < 0 {
+				errs = append(errs, field.Invalid(ratioPath, ratio.String(), "must be greater than or equal to 1"))
+				continue
+			}
+			if maxQ, ok := item.Max[name]; ok {
+				if minQ, ok := item.Min[name]; ok && minQ.Sign() > 0 && float64(maxQ.MilliValue())/float64(minQ.MilliValue()) < ratio.AsApproximateFloat64() {
+					errs = append(errs, field.Invalid(ratioPath, ratio.String(),
+						fmt.Sprintf("ratio cannot be greater than max/min = %v", float64(maxQ.MilliValue())/float64(minQ.MilliValue()))))
+				}
+			}
+			if def, ok := item.Default[name]; ok {
+				if request, ok := item.DefaultRequest[name]; ok && request.Sign() > 0 && float64(def.MilliValue())/float64(request.MilliValue()) > ratio.AsApproximateFloat64() {
+					errs = append(errs, field.Invalid(path.Child("default").Key(string(name)), def.String(),
+						fmt.Sprintf("default to defaultRequest ratio must not exceed maxLimitRequestRatio %s", ratio.String())))
+				}
+			}
+		}
+	}
+	return errs.ToAggregate()
+}
@@ -0,0 +1,113 @@
+package limitranges
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func resources(values ...string) coreV1.ResourceList {
+	list := coreV1.ResourceList{}
+	for i := 0; i < len(values); i += 2 {
+		list[coreV1.ResourceName(values[i])] = resource.MustParse(values[i+1])
+	}
+	return list
+}
+
+func TestNamespaceDefaults(t *testing.T) {
+	limitRanges := []coreV1.LimitRange{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "strict", Namespace: "team"},
+			Spec: coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{
+				{Type: coreV1.LimitTypeContainer, Max: resources("cpu", "1"), Default: resources("memory", "512Mi")},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team"},
+			Spec: coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{
+				{
+					Type:           coreV1.LimitTypeContainer,
+					Default:        resources("cpu", "500m", "memory", "256Mi"),
+					DefaultRequest: resources("cpu", "100m"),
+					Max:            resources("cpu", "2"),
+					Min:            resources("cpu", "50m"),
+				},
+				{Type: coreV1.LimitTypePersistentVolumeClaim, Max: resources("storage", "10Gi")},
+			}},
+		},
+	}
+
+	defaults := namespaceDefaults("team", limitRanges)
+	assert.Equal(t, []string{"base", "strict"}, defaults.LimitRanges)
+	assert.Equal(t, []EffectiveLimit{
+		{Type: coreV1.LimitTypeContainer, Resource: "cpu", Default: "500m", DefaultFrom: "base", DefaultRequest: "100m", DefaultRequestFrom: "base", Min: "50m", Max: "1"},
+		{Type: coreV1.LimitTypeContainer, Resource: "memory", Default: "256Mi", DefaultFrom: "base"},
+		{Type: coreV1.LimitTypePersistentVolumeClaim, Resource: "storage", Max: "10Gi"},
+	}, defaults.Limits)
+
+	empty := namespaceDefaults("other", nil)
+	assert.Empty(t, empty.LimitRanges)
+	assert.Empty(t, empty.Limits)
+}
+
+func TestValidateLimitRangeSpec(t *testing.T) {
+	valid := &coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{
+		{
+			Type:                 coreV1.LimitTypeContainer,
+			Min:                  resources("cpu", "50m"),
+			DefaultRequest:       resources("cpu", "100m"),
+			Default:              resources("cpu", "500m"),
+			Max:                  resources("cpu", "2"),
+			MaxLimitRequestRatio: resources("cpu", "10"),
+		},
+		{Type: coreV1.LimitTypePod, Max: resources("memory", "4Gi")},
+		{Type: coreV1.LimitTypePersistentVolumeClaim, Min: resources("storage", "1Gi")},
+	}}
+	assert.NoError(t, validateLimitRangeSpec(valid))
+
+	for name, item := range map[string]coreV1.LimitRangeItem{
+		"unknown type":        {Type: "Node", Max: resources("cpu", "1")},
+		"pod default":         {Type: coreV1.LimitTypePod, Default: resources("cpu", "1")},
+		"claim without bound": {Type: coreV1.LimitTypePersistentVolumeClaim, Max: resources("cpu", "1")},
+		"min above max":       {Type: coreV1.LimitTypeContainer, Min: resources("cpu", "2"), Max: resources("cpu", "1")},
+		"request above limit": {Type: coreV1.LimitTypeContainer, DefaultRequest: resources("cpu", "2"), Default: resources("cpu", "1")},
+		"default above max":   {Type: coreV1.LimitTypeContainer, Default: resources("memory", "2Gi"), Max: resources("memory", "1Gi")},
+		"negative":            {Type: coreV1.LimitTypeContainer, Max: resources("cpu", "-1")},
+		"ratio below one":     {Type: coreV1.LimitTypeContainer, MaxLimitRequestRatio: resources("cpu", "500m")},
+		"ratio above max/min": {Type: coreV1.LimitTypeContainer, Min: resources("cpu", "1"), Max: resources("cpu", "2"), MaxLimitRequestRatio: resources("cpu", "4")},
+		"defaults over ratio": {Type: coreV1.LimitTypeContainer, DefaultRequest: resources("cpu", "100m"), Default: resources("cpu", "1"), MaxLimitRequestRatio: resources("cpu", "2")},
+	} {
+		err := validateLimitRangeSpec(&coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{item}})
+		assert.Error(t, err, name)
+	}
+	assert.Error(t, validateLimitRangeSpec(&coreV1.LimitRangeSpec{}))
+}
+
+func TestSaveLimitRange(t *testing.T) {
+	clientSet := fake.NewClientset()
+	spec := coreV1.LimitRangeSpec{Limits: []coreV1.LimitRangeItem{{Type: coreV1.LimitTypeContainer, Default: resources("cpu", "500m")}}}
+
+	limitRange, created, err := saveLimitRange(context.Background(), clientSet, "team", "defaults", spec, "")
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "team", limitRange.Namespace)
+
+	spec.Limits[0].Default = resources("cpu", "1")
+	limitRange, created, err = saveLimitRange(context.Background(), clientSet, "team", "defaults", spec, "")
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, resource.MustParse("1"), limitRange.Spec.Limits[0].Default[coreV1.ResourceCPU])
+
+	_, _, err = saveLimitRange(context.Background(), clientSet, "team", "defaults", spec, "stale")
+	assert.True(t, apierrors.IsConflict(err))
+	// A resourceVersion means the caller expects the LimitRange to exist.
+	_, _, err = saveLimitRange(context.Background(), clientSet, "team", "missing", spec, "1")
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -13,6 +13,11 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 )
 
+const (
+	GetNamespaceDefaults base.RouteType = 12
+	SaveLimitRange       base.RouteType = 13
+)
+
 type LimitRangesHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -32,6 +37,10 @@ func NewLimitRangesRouteHandler(container container.Container, routeType base.Ro
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetNamespaceDefaults:
+			return handler.GetNamespaceDefaults(c)
+		case SaveLimitRange:
+			return handler.SaveLimitRange(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -18,6 +18,7 @@ type PriorityClass struct {
 	Value            int32                `json:"value"`
 	GlobalDefault    bool                 `json:"globalDefault"`
 	PreemptionPolicy *v1.PreemptionPolicy `json:"preemptionPolicy"`
+	Description      string               `json:"description"`
 	Age              time.Time            `json:"age"`
 }
 
@@ -43,6 +44,7 @@ func TransformPriorityClassItem(item v12.PriorityClass) PriorityClass {
 		Value:            item.Value,
 		GlobalDefault:    item.GlobalDefault,
 		PreemptionPolicy: item.PreemptionPolicy,
+		Description:      item.Description,
 		Age:              item.CreationTimestamp.Time,
 	}
 }
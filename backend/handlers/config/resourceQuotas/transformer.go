@@ -3,6 +3,7 @@ package resourcequotas
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/maruel/natural"
@@ -16,6 +17,10 @@ type ResourceQuota struct {
 	Namespace string    `json:"namespace"`
 	Name      string    `json:"name"`
 	Age       time.Time `json:"age"`
+	// Request and Limit summarise usage as kubectl get quota does, e.g.
+	// "pods: 3/10, requests.cpu: 500m/2".
+	Request string `json:"request"`
+	Limit   string `json:"limit"`
 }
 
 func TransformLimitRange(items []v1.ResourceQuota) []ResourceQuota {
@@ -38,5 +43,45 @@ func TransformLimitRangeItem(item v1.ResourceQuota) ResourceQuota {
 		Namespace: item.GetNamespace(),
 		Name:      item.GetName(),
 		Age:       item.CreationTimestamp.Time,
+		Request:   quotaUsage(item.Status, false),
+		Limit:     quotaUsage(item.Status, true),
 	}
 }
+
+// quotaUsage lists used/hard per resource, either for the limits.* resources
+// or for everything else.
+func quotaUsage(status v1.ResourceQuotaStatus, limits bool) string {
+	names := make([]string, 0, len(status.Hard))
+	for name := range status.Hard {
+		if strings.HasPrefix(string(name), "limits.") == limits {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+
+	usage := make([]string, 0, len(names))
+	for _, name := range names {
+		hard := status.Hard[v1.ResourceName(name)]
+		used, ok := status.Used[v1.ResourceName(name)]
+		usedValue := "0"
+		if ok {
+			usedValue = used.String()
+		}
+		usage = append(usage, fmt.Sprintf("%s: %s/%s", name, usedValue, hard.String()))
+	}
+	return strings.Join(usage, ", ")
+}
+
+// Remaining returns hard minus used for every resource the quota limits. It
+// is negative when usage already exceeds a lowered hard limit.
+func Remaining(status v1.ResourceQuotaStatus) v1.ResourceList {
+	remaining := make(v1.ResourceList, len(status.Hard))
+	for name, hard := range status.Hard {
+		left := hard.DeepCopy()
+		if used, ok := status.Used[name]; ok {
+			left.Sub(used)
+		}
+		remaining[name] = left
+	}
+	return remaining
+}
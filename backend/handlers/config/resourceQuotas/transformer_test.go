@@ -0,0 +1,46 @@
+package resourcequotas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestQuotaUsage(t *testing.T) {
+	status := v1.ResourceQuotaStatus{
+		Hard: v1.ResourceList{
+			v1.ResourcePods:           resource.MustParse("10"),
+			v1.ResourceRequestsCPU:    resource.MustParse("2"),
+			v1.ResourceLimitsCPU:      resource.MustParse("4"),
+			v1.ResourceLimitsMemory:   resource.MustParse("8Gi"),
+			v1.ResourceRequestsMemory: resource.MustParse("4Gi"),
+		},
+		Used: v1.ResourceList{
+			v1.ResourcePods:        resource.MustParse("3"),
+			v1.ResourceRequestsCPU: resource.MustParse("500m"),
+			v1.ResourceLimitsCPU:   resource.MustParse("1"),
+		},
+	}
+	assert.Equal(t, "pods: 3/10, requests.cpu: 500m/2, requests.memory: 0/4Gi", quotaUsage(status, false))
+	assert.Equal(t, "limits.cpu: 1/4, limits.memory: 0/8Gi", quotaUsage(status, true))
+	assert.Equal(t, "", quotaUsage(v1.ResourceQuotaStatus{}, true))
+}
+
+func TestRemaining(t *testing.T) {
+	status := v1.ResourceQuotaStatus{
+		Hard: v1.ResourceList{
+			v1.ResourcePods:        resource.MustParse("10"),
+			v1.ResourceRequestsCPU: resource.MustParse("2"),
+		},
+		Used: v1.ResourceList{
+			v1.ResourcePods:        resource.MustParse("12"),
+			v1.ResourceRequestsCPU: resource.MustParse("500m"),
+		},
+	}
+	got := Remaining(status)
+	assert.Equal(t, int64(-2), got.Pods().Value())
+	cpu := got[v1.ResourceRequestsCPU]
+	assert.Equal(t, int64(1500), cpu.MilliValue())
+}
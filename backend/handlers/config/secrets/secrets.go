@@ -13,6 +13,11 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 )
 
+const (
+	GetSecretConsumers base.RouteType = 12
+	GetSecretKey       base.RouteType = 13
+)
+
 type SecretsHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -32,6 +37,10 @@ func NewSecretsRouteHandler(container container.Container, routeType base.RouteT
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetSecretConsumers:
+			return handler.GetSecretConsumers(c)
+		case GetSecretKey:
+			return handler.GetSecretKey(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -51,14 +60,15 @@ func newSecretsHandler(ctx context.Context, config, cluster string, container co
 
 	handler := &SecretsHandler{
 		BaseHandler: base.BaseHandler{
-			Kind:             "Secret",
-			Container:        container,
-			Informer:         informer,
-			RestClient:       container.ClientSet(config, cluster).CoreV1().RESTClient(),
-			QueryConfig:      config,
-			QueryCluster:     cluster,
-			InformerCacheKey: fmt.Sprintf("%s-%s-secretsInformer", config, cluster),
-			TransformFunc:    transformItems,
+			Kind:                "Secret",
+			Container:           container,
+			Informer:            informer,
+			RestClient:          container.ClientSet(config, cluster).CoreV1().RESTClient(),
+			QueryConfig:         config,
+			QueryCluster:        cluster,
+			InformerCacheKey:    fmt.Sprintf("%s-%s-secretsInformer", config, cluster),
+			TransformFunc:       transformItems,
+			DetailTransformFunc: transformDetail,
 		},
 	}
 	cache := base.ResourceEventHandler[*coreV1.Secret](&handler.BaseHandler)
@@ -81,3 +91,47 @@ func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 
 	return json.Marshal(t)
 }
+
+// GetSecretConsumers lists the pods that reference the Secret through volumes,
+// env, envFrom or imagePullSecrets.
+func (h *SecretsHandler) GetSecretConsumers(c echo.Context) error {
+	podInformer := helpers.PodInformer(c.Request().Context(), h.BaseHandler.Container, h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	return helpers.ServePodConsumers(c, podInformer, "Secret")
+}
+
+type secretDetail struct {
+	*coreV1.Secret
+	// OmittedData holds the sizes of data values too large to send inline;
+	// fetch them with GetSecretKey.
+	OmittedData map[string]int `json:"omittedData,omitempty"`
+}
+
+func transformDetail(item any) any {
+	secret, ok := item.(*coreV1.Secret)
+	if !ok {
+		return item
+	}
+	secret = secret.DeepCopy()
+	d := secretDetail{Secret: secret}
+	secret.Data, d.OmittedData = helpers.OmitLargeValues(secret.Data)
+	return d
+}
+
+// GetSecretKey serves the decoded value of a single data key.
+func (h *SecretsHandler) GetSecretKey(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	secret, ok := obj.(*coreV1.Secret)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("secret %s not found", key))
+	}
+
+	value, ok := secret.Data[c.Param("key")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("key %s not found in secret %s", c.Param("key"), key))
+	}
+	return helpers.ServeDataValue(c, c.Param("key"), value)
+}
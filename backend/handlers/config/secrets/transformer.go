@@ -18,6 +18,7 @@ type SecretsList struct {
 	Type      string    `json:"type"`
 	Keys      []string  `json:"keys"`
 	Data      int       `json:"data"`
+	Size      int       `json:"size"`
 	Age       time.Time `json:"age"`
 }
 
@@ -43,6 +44,7 @@ func TransConfigMapsItem(configMap v1.Secret) SecretsList {
 		Keys:      getKeys(configMap.Data),
 		Type:      string(configMap.Type),
 		Data:      len(getKeys(configMap.Data)),
+		Size:      dataSize(configMap.Data),
 		Age:       configMap.CreationTimestamp.Time,
 	}
 }
@@ -57,3 +59,12 @@ func getKeys(data map[string][]byte) []string {
 
 	return output
 }
+
+// dataSize is the total size in bytes of the decoded Secret values.
+func dataSize(data map[string][]byte) int {
+	size := 0
+	for _, v := range data {
+		size += len(v)
+	}
+	return size
+}
@@ -5,20 +5,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/kubewall/kubewall/backend/container"
 	"github.com/kubewall/kubewall/backend/handlers/base"
 	"github.com/kubewall/kubewall/backend/handlers/helpers"
 	"github.com/labstack/echo/v4"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
+const (
+	GetSchema base.RouteType = 8
+)
+
+const maxPageLimit = 500
+
+var customResourceDefinitionGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
 type CRDHandler struct {
 	BaseHandler base.BaseHandler
 }
 
 func NewCRDRouteHandler(container container.Container, routeType base.RouteType) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		// Paged lists are read straight from the API server so they don't
+		// wait on the informer's initial sync of every CRD in the cluster.
+		if routeType == base.GetList && c.QueryParam("limit") != "" {
+			return GetListPage(c, container)
+		}
 		handler := NewCRDHandler(c.Request().Context(), c.QueryParam("config"), c.QueryParam("cluster"), container)
 
 		switch routeType {
@@ -32,6 +54,8 @@ func NewCRDRouteHandler(container container.Container, routeType base.RouteType)
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.Delete(c)
+		case GetSchema:
+			return handler.GetSchema(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -69,12 +93,14 @@ func newCRDHandler(ctx context.Context, config, cluster string, container contai
 	return handler
 }
 
+// transformItems builds the list stream payload. Schemas are dropped first,
+// the informer keeps them for the detail and schema endpoints.
 func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 	var list []apiextensionsv1.CustomResourceDefinition
 
 	for _, obj := range items {
 		if item, ok := obj.(*apiextensionsv1.CustomResourceDefinition); ok {
-			list = append(list, *item)
+			list = append(list, withoutSchemas(item))
 		}
 	}
 
@@ -83,6 +109,54 @@ func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 	return json.Marshal(t)
 }
 
+type ListPage struct {
+	Items              []CustomResourceDefinition `json:"items"`
+	Continue           string                     `json:"continue"`
+	RemainingItemCount *int64                     `json:"remainingItemCount,omitempty"`
+}
+
+// GetListPage returns one page of CRD summaries using the API server's
+// limit/continue paging.
+func GetListPage(c echo.Context, container container.Container) error {
+	return listPage(c, container.DynamicClient(c.QueryParam("config"), c.QueryParam("cluster")))
+}
+
+func listPage(c echo.Context, client dynamic.Interface) error {
+	limit, err := strconv.ParseInt(c.QueryParam("limit"), 10, 64)
+	if err != nil || limit <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "limit must be a positive number")
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	list, err := client.Resource(customResourceDefinitionGVR).List(c.Request().Context(), metav1.ListOptions{
+		Limit:    limit,
+		Continue: c.QueryParam("continue"),
+	})
+	if err != nil {
+		if apierrors.IsResourceExpired(err) {
+			return echo.NewHTTPError(http.StatusGone, "continue token expired, restart listing from the first page")
+		}
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+
+	definitions := make([]apiextensionsv1.CustomResourceDefinition, 0, len(list.Items))
+	for _, item := range list.Items {
+		crd, err := ProjectCRD(item)
+		if err != nil {
+			continue
+		}
+		definitions = append(definitions, crd)
+	}
+
+	return c.JSON(http.StatusOK, ListPage{
+		Items:              TransformCRD(definitions),
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	})
+}
+
 func (h *CRDHandler) Delete(c echo.Context) error {
 	type InputData struct {
 		Name string `json:"name"`
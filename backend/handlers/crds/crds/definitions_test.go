@@ -0,0 +1,94 @@
+package crds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// pagedCRDs serves CRDs in pages of one, keyed by the continue token. The
+// dynamic fake client drops Continue from cluster scoped list actions, so
+// List is stubbed directly.
+type pagedCRDs struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+}
+
+func (p pagedCRDs) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return p
+}
+
+func (p pagedCRDs) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	switch opts.Continue {
+	case "":
+		list.Items = []unstructured.Unstructured{newListedCRD(1)}
+		list.SetContinue("page-2")
+	case "page-2":
+		item := newListedCRD(1)
+		item.SetName("gadgets.example.com")
+		list.Items = []unstructured.Unstructured{item}
+	case "expired":
+		return nil, apierrors.NewResourceExpired("too old resource version")
+	case "missing":
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, "")
+	default:
+		return nil, errors.New("invalid continue token")
+	}
+	return list, nil
+}
+
+func TestListPage(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantNames    []string
+		wantContinue string
+	}{
+		{name: "first page", query: "limit=1", wantStatus: http.StatusOK, wantNames: []string{"widgets.example.com"}, wantContinue: "page-2"},
+		{name: "last page", query: "limit=1&continue=page-2", wantStatus: http.StatusOK, wantNames: []string{"gadgets.example.com"}},
+		{name: "expired continue token", query: "limit=1&continue=expired", wantStatus: http.StatusGone},
+		{name: "not found", query: "limit=1&continue=missing", wantStatus: http.StatusNotFound},
+		{name: "other errors", query: "limit=1&continue=bogus", wantStatus: http.StatusBadRequest},
+		{name: "invalid limit", query: "limit=0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/customresourcedefinitions?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			err := listPage(e.NewContext(req, rec), pagedCRDs{})
+			if tt.wantStatus != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantStatus, httpErr.Code)
+				return
+			}
+			require.NoError(t, err)
+
+			var page ListPage
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+			var names []string
+			for _, item := range page.Items {
+				names = append(names, item.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantContinue, page.Continue)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package crds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type SchemaResponse struct {
+	Name       string           `json:"name"`
+	Version    string           `json:"version"`
+	Versions   []string         `json:"versions"`
+	Structural bool             `json:"structural"`
+	Properties []SchemaProperty `json:"properties"`
+}
+
+// SchemaProperty is a single field of a flattened openAPIV3Schema. Path uses
+// dots for object fields, "[]" for array items and "{}" for map values, e.g.
+// spec.containers[].env{}.
+type SchemaProperty struct {
+	Path                  string   `json:"path"`
+	Type                  string   `json:"type"`
+	Format                string   `json:"format,omitempty"`
+	Description           string   `json:"description,omitempty"`
+	Required              bool     `json:"required"`
+	Enum                  []string `json:"enum,omitempty"`
+	Default               string   `json:"default,omitempty"`
+	PreserveUnknownFields bool     `json:"preserveUnknownFields,omitempty"`
+}
+
+// GetSchema returns the flattened schema of a served CRD version, defaulting to
+// the same version the list view uses.
+func (h *CRDHandler) GetSchema(c echo.Context) error {
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(c.Param("name"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("customresourcedefinition %s not found", c.Param("name")))
+	}
+
+	version := c.QueryParam("version")
+	if version == "" {
+		version = selectedVersion(*crd)
+	}
+
+	versions := make([]string, 0, len(crd.Spec.Versions))
+	var selected *apiextensionsv1.CustomResourceDefinitionVersion
+	for i, v := range crd.Spec.Versions {
+		if !v.Served {
+			continue
+		}
+		versions = append(versions, v.Name)
+		if v.Name == version {
+			selected = &crd.Spec.Versions[i]
+		}
+	}
+	if selected == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("version %q is not served by %s", version, crd.Name))
+	}
+	if selected.Schema == nil || selected.Schema.OpenAPIV3Schema == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("version %q of %s has no schema", version, crd.Name))
+	}
+
+	return c.JSON(http.StatusOK, SchemaResponse{
+		Name:       crd.Name,
+		Version:    version,
+		Versions:   versions,
+		Structural: isStructural(crd),
+		Properties: FlattenSchema(selected.Schema.OpenAPIV3Schema),
+	})
+}
+
+// isStructural reports whether the API server accepted the schema as
+// structural; non-structural schemas may have untyped nodes, which are
+// flattened with an empty type.
+func isStructural(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.NonStructuralSchema && condition.Status == apiextensionsv1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// FlattenSchema walks an openAPIV3Schema depth-first and returns every
+// property, sorted by path.
+func FlattenSchema(schema *apiextensionsv1.JSONSchemaProps) []SchemaProperty {
+	properties := make([]SchemaProperty, 0)
+	flattenProperties(schema, "", &properties)
+
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Path < properties[j].Path
+	})
+	return properties
+}
+
+func flattenProperties(schema *apiextensionsv1.JSONSchemaProps, prefix string, out *[]SchemaProperty) {
+	for name, child := range schema.Properties {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		appendProperty(&child, path, slices.Contains(schema.Required, name), out)
+	}
+}
+
+func appendProperty(schema *apiextensionsv1.JSONSchemaProps, path string, required bool, out *[]SchemaProperty) {
+	*out = append(*out, SchemaProperty{
+		Path:                  path,
+		Type:                  schemaType(schema),
+		Format:                schema.Format,
+		Description:           schema.Description,
+		Required:              required,
+		Enum:                  enumValues(schema.Enum),
+		Default:               jsonValue(schema.Default),
+		PreserveUnknownFields: schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields,
+	})
+
+	flattenProperties(schema, path, out)
+	if schema.Items != nil && schema.Items.Schema != nil {
+		appendProperty(schema.Items.Schema, path+"[]", false, out)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		appendProperty(schema.AdditionalProperties.Schema, path+"{}", false, out)
+	}
+}
+
+func schemaType(schema *apiextensionsv1.JSONSchemaProps) string {
+	if schema.XIntOrString {
+		return "int-or-string"
+	}
+	return schema.Type
+}
+
+func enumValues(values []apiextensionsv1.JSON) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		var s string
+		// string enums are the common case, show them without JSON quotes
+		if err := json.Unmarshal(v.Raw, &s); err == nil {
+			out = append(out, s)
+			continue
+		}
+		out = append(out, string(v.Raw))
+	}
+	return out
+}
+
+func jsonValue(value *apiextensionsv1.JSON) string {
+	if value == nil {
+		return ""
+	}
+	return string(value.Raw)
+}
@@ -0,0 +1,66 @@
+package crds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestFlattenSchema(t *testing.T) {
+	preserve := true
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type:     "object",
+				Required: []string{"replicas"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"replicas": {Type: "integer", Format: "int32", Default: &apiextensionsv1.JSON{Raw: []byte("1")}},
+					"mode": {
+						Type:        "string",
+						Description: "Rollout mode.",
+						Enum:        []apiextensionsv1.JSON{{Raw: []byte(`"Auto"`)}, {Raw: []byte(`"Manual"`)}},
+					},
+					"port":   {XIntOrString: true},
+					"config": {XPreserveUnknownFields: &preserve},
+					"ports": {
+						Type: "array",
+						Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{"name": {Type: "string"}},
+						}},
+					},
+					"labels": {
+						Type:                 "object",
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+					},
+				},
+			},
+		},
+	}
+
+	properties := FlattenSchema(schema)
+	byPath := make(map[string]SchemaProperty, len(properties))
+	paths := make([]string, 0, len(properties))
+	for _, p := range properties {
+		byPath[p.Path] = p
+		paths = append(paths, p.Path)
+	}
+
+	assert.Equal(t, []string{
+		"spec", "spec.config", "spec.labels", "spec.labels{}", "spec.mode", "spec.port",
+		"spec.ports", "spec.ports[]", "spec.ports[].name", "spec.replicas",
+	}, paths)
+
+	require.Contains(t, byPath, "spec.replicas")
+	assert.True(t, byPath["spec.replicas"].Required)
+	assert.Equal(t, "int32", byPath["spec.replicas"].Format)
+	assert.Equal(t, "1", byPath["spec.replicas"].Default)
+	assert.False(t, byPath["spec.mode"].Required)
+	assert.Equal(t, []string{"Auto", "Manual"}, byPath["spec.mode"].Enum)
+	assert.Equal(t, "int-or-string", byPath["spec.port"].Type)
+	assert.True(t, byPath["spec.config"].PreserveUnknownFields)
+	assert.Empty(t, byPath["spec.config"].Type)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/kubewall/kubewall/backend/handlers/crds/resources"
 	"github.com/maruel/natural"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -79,6 +81,60 @@ func TransformCRDItem(item apiextensionsv1.CustomResourceDefinition) CustomResou
 	}
 }
 
+// ProjectCRD converts a listed CRD into the typed object without its
+// per-version openAPIV3Schema. Schemas make up most of a CRD's size and are
+// only needed by the single-CRD detail endpoints, which read the informer store.
+func ProjectCRD(item unstructured.Unstructured) (apiextensionsv1.CustomResourceDefinition, error) {
+	var crd apiextensionsv1.CustomResourceDefinition
+
+	field, _, _ := unstructured.NestedFieldNoCopy(item.Object, "spec", "versions")
+	versions, _ := field.([]any)
+	projected := make([]any, 0, len(versions))
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		trimmed := make(map[string]any, len(version))
+		for key, value := range version {
+			if key != "schema" {
+				trimmed[key] = value
+			}
+		}
+		projected = append(projected, trimmed)
+	}
+
+	// shallow copies, so the schemas are never deep-copied or converted
+	spec, _, _ := unstructured.NestedFieldNoCopy(item.Object, "spec")
+	specMap, _ := spec.(map[string]any)
+	trimmedSpec := make(map[string]any, len(specMap))
+	for key, value := range specMap {
+		trimmedSpec[key] = value
+	}
+	trimmedSpec["versions"] = projected
+
+	object := make(map[string]any, len(item.Object))
+	for key, value := range item.Object {
+		object[key] = value
+	}
+	object["spec"] = trimmedSpec
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &crd)
+	return crd, err
+}
+
+// withoutSchemas returns a copy of a typed CRD without its per-version
+// openAPIV3Schema, for list payloads. The informer's object is not modified.
+func withoutSchemas(item *apiextensionsv1.CustomResourceDefinition) apiextensionsv1.CustomResourceDefinition {
+	crd := *item
+	crd.Spec.Versions = make([]apiextensionsv1.CustomResourceDefinitionVersion, len(item.Spec.Versions))
+	for i, version := range item.Spec.Versions {
+		version.Schema = nil
+		crd.Spec.Versions[i] = version
+	}
+	return crd
+}
+
 func customResourceColumnDefinition(item apiextensionsv1.CustomResourceDefinition, activeVersion string) []apiextensionsv1.CustomResourceColumnDefinition {
 	printerColumns := make([]apiextensionsv1.CustomResourceColumnDefinition, 0)
 	for _, v := range item.Spec.Versions {
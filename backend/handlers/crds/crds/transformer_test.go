@@ -0,0 +1,113 @@
+package crds
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// newListedCRD builds a CRD as returned by a LIST call, with a schema of
+// roughly the size real operators ship (hundreds of documented properties).
+func newListedCRD(properties int) unstructured.Unstructured {
+	props := make(map[string]any, properties)
+	for i := range properties {
+		props[fmt.Sprintf("field%d", i)] = map[string]any{
+			"type":        "string",
+			"description": "Field documentation as generated from the operator's Go types, usually a sentence or two long.",
+		}
+	}
+	schema := map[string]any{
+		"openAPIV3Schema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"spec": map[string]any{"type": "object", "properties": props},
+			},
+		},
+	}
+
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name":              "widgets.example.com",
+			"uid":               "1234",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+		},
+		"spec": map[string]any{
+			"group": "example.com",
+			"scope": "Namespaced",
+			"names": map[string]any{
+				"kind":     "Widget",
+				"listKind": "WidgetList",
+				"plural":   "widgets",
+				"singular": "widget",
+			},
+			"versions": []any{
+				map[string]any{"name": "v1alpha1", "served": true, "storage": false, "deprecated": true, "schema": schema},
+				map[string]any{
+					"name": "v1", "served": true, "storage": true, "schema": schema,
+					"additionalPrinterColumns": []any{
+						map[string]any{"name": "Ready", "type": "string", "jsonPath": ".status.ready"},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestProjectCRDDropsSchemas(t *testing.T) {
+	item := newListedCRD(500)
+
+	crd, err := ProjectCRD(item)
+	require.NoError(t, err)
+
+	for _, v := range crd.Spec.Versions {
+		assert.Nil(t, v.Schema, "version %s", v.Name)
+	}
+
+	// the source object is left untouched for any other reader
+	versions, _, _ := unstructured.NestedSlice(item.Object, "spec", "versions")
+	assert.Contains(t, versions[0].(map[string]any), "schema")
+
+	summary := TransformCRDItem(crd)
+	assert.Equal(t, "v1", summary.ActiveVersion)
+	assert.Equal(t, "Widget", summary.Spec.Names.Kind)
+	assert.Equal(t, 2, summary.Versions)
+	assert.Equal(t, "Ready", summary.AdditionalPrinterColumns[2].Name)
+}
+
+func TestTransformItemsPayloadReduction(t *testing.T) {
+	item := newListedCRD(500)
+	full, err := json.Marshal(item.Object)
+	require.NoError(t, err)
+
+	var crd apiextensionsv1.CustomResourceDefinition
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &crd))
+	require.NotNil(t, crd.Spec.Versions[0].Schema)
+
+	// the payload the list stream sends for the informer's objects
+	trimmed, err := transformItems([]any{&crd}, nil)
+	require.NoError(t, err)
+
+	// With a 500-property schema served in two versions the full object is
+	// ~140KB while the list item is well under 1KB (>99% smaller).
+	t.Logf("full CRD: %d bytes, list item: %d bytes (%.1f%% smaller)",
+		len(full), len(trimmed), 100*(1-float64(len(trimmed))/float64(len(full))))
+	assert.NotContains(t, string(trimmed), "openAPIV3Schema")
+	assert.NotContains(t, string(trimmed), "field0")
+	assert.Less(t, len(trimmed)*100, len(full))
+
+	var list []CustomResourceDefinition
+	require.NoError(t, json.Unmarshal(trimmed, &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "v1", list[0].ActiveVersion)
+
+	// the informer's object keeps its schemas for the detail endpoints
+	assert.NotNil(t, crd.Spec.Versions[0].Schema)
+}
@@ -0,0 +1,91 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	resourceAccessCacheKeyFormat = "%s-%s-resource-access-%s"
+	// resourceAccessCacheTTL only applies to allowed resources, a denied or
+	// missing one is checked again on the next request.
+	resourceAccessCacheTTL = time.Minute
+)
+
+// checkResourceAccess runs before the informer of a resource is started, an
+// informer on a resource that is not served or not listable never syncs. The
+// result is cached briefly for allowed resources.
+func checkResourceAccess(ctx context.Context, container container.Container, config, cluster string, gvr schema.GroupVersionResource) error {
+	cacheKey := fmt.Sprintf(resourceAccessCacheKeyFormat, config, cluster, gvr.String())
+	if _, ok := container.Cache().GetIfPresent(cacheKey); ok {
+		return nil
+	}
+	clientSet := container.ClientSet(config, cluster)
+	if clientSet == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	if err := resourceAccess(ctx, clientSet, gvr); err != nil {
+		return err
+	}
+	container.Cache().Set(cacheKey, true)
+	container.Cache().SetExpiresAfter(cacheKey, resourceAccessCacheTTL)
+	return nil
+}
+
+// resourceAccess answers 404, listing the resource types of the group version,
+// when the resource is not served and 403 when the user may not list and
+// watch it across namespaces.
+func resourceAccess(ctx context.Context, clientSet kubernetes.Interface, gvr schema.GroupVersionResource) error {
+	resources, err := clientSet.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("the server doesn't serve %s", gvr.GroupVersion()))
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	supported := make([]string, 0, len(resources.APIResources))
+	found := false
+	for _, r := range resources.APIResources {
+		if strings.Contains(r.Name, "/") {
+			continue
+		}
+		supported = append(supported, r.Name)
+		found = found || r.Name == gvr.Resource
+	}
+	if !found {
+		sort.Strings(supported)
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("unsupported resource type %q in %s, supported types: %s", gvr.Resource, gvr.GroupVersion(), strings.Join(supported, ", ")))
+	}
+
+	for _, verb := range []string{"list", "watch"} {
+		review, err := clientSet.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationV1.SelfSubjectAccessReview{
+			Spec: authorizationV1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationV1.ResourceAttributes{
+					Verb:     verb,
+					Group:    gvr.Group,
+					Version:  gvr.Version,
+					Resource: gvr.Resource,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+		}
+		if !review.Status.Allowed {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("not allowed to %s %s", verb, gvr.GroupResource()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestResourceAccess(t *testing.T) {
+	newClientSet := func(allowed bool) *fake.Clientset {
+		clientSet := fake.NewClientset()
+		clientSet.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets"}, {Name: "widgets/status"}, {Name: "gadgets"}},
+		}}
+		clientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationV1.SelfSubjectAccessReview)
+			review.Status.Allowed = allowed
+			return true, review, nil
+		})
+		return clientSet
+	}
+	status := func(t *testing.T, err error) (int, string) {
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		return httpErr.Code, httpErr.Message.(string)
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		assert.NoError(t, resourceAccess(context.Background(), newClientSet(true), schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}))
+	})
+
+	t.Run("unsupported resource type", func(t *testing.T) {
+		code, msg := status(t, resourceAccess(context.Background(), newClientSet(true), schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gizmos"}))
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Contains(t, msg, "supported types: gadgets, widgets")
+	})
+
+	t.Run("group version not served", func(t *testing.T) {
+		code, _ := status(t, resourceAccess(context.Background(), newClientSet(true), schema.GroupVersionResource{Group: "example.com", Version: "v2", Resource: "widgets"}))
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		code, msg := status(t, resourceAccess(context.Background(), newClientSet(false), schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}))
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "not allowed to list widgets.example.com", msg)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/kubewall/kubewall/backend/config"
 	"github.com/kubewall/kubewall/backend/container"
 	"github.com/kubewall/kubewall/backend/handlers/base"
 	"github.com/kubewall/kubewall/backend/handlers/helpers"
@@ -18,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -28,6 +30,9 @@ const (
 type Output struct {
 	AdditionalPrinterColumns []apiextensionsv1.CustomResourceColumnDefinition `json:"additionalPrinterColumns"`
 	List                     []unstructured.Unstructured                      `json:"list"`
+	// Deprecations are the warnings the API server sent about the listed
+	// version being deprecated.
+	Deprecations []config.APIWarning `json:"deprecations,omitempty"`
 }
 
 type UnstructuredHandler struct {
@@ -36,6 +41,18 @@ type UnstructuredHandler struct {
 
 func NewUnstructuredRouteHandler(container container.Container, routeType base.RouteType) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if c.QueryParam("version") == "" || c.QueryParam("resource") == "" || c.QueryParam("kind") == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "version, resource and kind query params are required")
+		}
+		gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.QueryParam("resource")}
+		if err := helpers.CheckResourceAllowed(container, gvr.GroupResource()); err != nil {
+			return err
+		}
+		if routeType != base.Delete {
+			if err := checkResourceAccess(c.Request().Context(), container, c.QueryParam("config"), c.QueryParam("cluster"), gvr); err != nil {
+				return err
+			}
+		}
 		handler := NewUnstructuredHandler(c.Request().Context(), c.QueryParam("config"), c.QueryParam("cluster"), c.QueryParam("kind"), c.QueryParam("group"), c.QueryParam("version"), c.QueryParam("resource"), container)
 
 		switch routeType {
@@ -46,7 +63,7 @@ func NewUnstructuredRouteHandler(container container.Container, routeType base.R
 		case base.GetEvents:
 			return handler.BaseHandler.GetEvents(c)
 		case GetYAML:
-			return handler.BaseHandler.GetYaml(c)
+			return handler.GetYAML(c)
 		case base.Delete:
 			return handler.Delete(c)
 		default:
@@ -99,6 +116,51 @@ func (h *UnstructuredHandler) Get(c echo.Context) error {
 	return nil
 }
 
+// GetYAML serves the YAML stream of a custom resource. The namespace comes from
+// the path for namespaced resources, so it is resolved here rather than in
+// BaseHandler.GetYaml, which only reads the namespace query param. The stream ID
+// matches the one ResourceEventHandler publishes to, so informer updates keep
+// refreshing it.
+func (h *UnstructuredHandler) GetYAML(c echo.Context) error {
+	namespace := c.Param("namespace")
+	if namespace == "" {
+		namespace = c.QueryParam("namespace")
+	}
+
+	itemKey := c.Param("name")
+	streamKey := fmt.Sprintf("%s-%s-%s-%s-yaml", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, h.BaseHandler.Kind, c.Param("name"))
+	if namespace != "" {
+		itemKey = fmt.Sprintf("%s/%s", namespace, c.Param("name"))
+		streamKey = fmt.Sprintf("%s-%s-%s-%s-%s-yaml", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, h.BaseHandler.Kind, namespace, c.Param("name"))
+	}
+
+	go h.BaseHandler.Container.EventProcessor().AddEvent(streamKey, h.ProcessYAML(itemKey, streamKey))
+	h.BaseHandler.Container.SSE().ServeHTTP(streamKey, c.Response(), c.Request())
+
+	return nil
+}
+
+func (h *UnstructuredHandler) ProcessYAML(itemKey, streamKey string) func() {
+	return func() {
+		var b []byte
+		l, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(itemKey)
+		if err != nil || !exists {
+			b = []byte("{}")
+		} else {
+			y, err := yaml.Marshal(l)
+			if err != nil {
+				b = []byte("{}")
+			} else {
+				b, _ = json.Marshal(echo.Map{"data": y})
+			}
+		}
+
+		h.BaseHandler.Container.SSE().Publish(streamKey, &sse.Event{
+			Data: b,
+		})
+	}
+}
+
 func (h *UnstructuredHandler) ProcessDetails(itemKey, steamKey string) func() {
 	return func() {
 		var b []byte
@@ -219,6 +281,9 @@ func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 	if len(output.AdditionalPrinterColumns) == 0 {
 		output.AdditionalPrinterColumns = []apiextensionsv1.CustomResourceColumnDefinition{}
 	}
+	if warnings := b.Container.APIWarnings(b.QueryConfig, b.QueryCluster); warnings != nil {
+		output.Deprecations = warnings.Deprecations(list[0].GetAPIVersion(), kind)
+	}
 
 	sort.Slice(output.List, func(i, j int) bool {
 		return natural.Less(
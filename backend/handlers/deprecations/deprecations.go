@@ -0,0 +1,42 @@
+package deprecations
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+type DeprecationsHandler struct {
+	container container.Container
+}
+
+func NewDeprecationsHandler(container container.Container) *DeprecationsHandler {
+	return &DeprecationsHandler{container: container}
+}
+
+// GetDeprecationWarnings returns the warnings the API server sent this
+// cluster's clients, most recently seen first. Those about deprecated APIs
+// have an apiVersion and kind, ?deprecatedOnly=true leaves out the others.
+// Warnings are only seen once an API is used, e.g. by opening its list.
+func (h *DeprecationsHandler) GetDeprecationWarnings(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	warnings := h.container.APIWarnings(config, cluster)
+	if warnings == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+
+	list := warnings.Warnings()
+	if c.QueryParam("deprecatedOnly") == "true" {
+		deprecated := list[:0]
+		for _, w := range list {
+			if w.Kind != "" {
+				deprecated = append(deprecated, w)
+			}
+		}
+		list = deprecated
+	}
+	return c.JSON(http.StatusOK, list)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/charmbracelet/log"
 	"github.com/kubewall/kubewall/backend/container"
 	"github.com/kubewall/kubewall/backend/handlers/base"
 	"github.com/kubewall/kubewall/backend/handlers/helpers"
@@ -13,8 +14,17 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+const (
+	GetNamespaceEventsStream base.RouteType = 12
+	GetClusterWarnings       base.RouteType = 13
+	GetResourcePressure      base.RouteType = 14
+)
+
 type EventsHandler struct {
 	BaseHandler base.BaseHandler
+	// namespaceStreams holds the filter of every subscribed namespace events
+	// stream
+	namespaceStreams *helpers.StreamViews[namespaceEventsFilter]
 }
 
 func NewEventsRouteHandler(container container.Container, routeType base.RouteType) echo.HandlerFunc {
@@ -26,6 +36,12 @@ func NewEventsRouteHandler(container container.Container, routeType base.RouteTy
 			return handler.BaseHandler.GetList(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetNamespaceEventsStream:
+			return handler.GetNamespaceEventsStream(c)
+		case GetClusterWarnings:
+			return handler.GetClusterWarnings(c)
+		case GetResourcePressure:
+			return handler.GetResourcePressureEvents(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -51,12 +67,19 @@ func newEventsHandler(ctx context.Context, config, cluster string, container con
 			RestClient:       container.ClientSet(config, cluster).CoreV1().RESTClient(),
 			QueryConfig:      config,
 			QueryCluster:     cluster,
-			InformerCacheKey: fmt.Sprintf("%s-%s-namespaceInformer", config, cluster),
+			InformerCacheKey: fmt.Sprintf("%s-%s-eventInformer", config, cluster),
 			TransformFunc:    transformItems,
 		},
+		namespaceStreams: helpers.NewStreamViews[namespaceEventsFilter](0, container.SSE().RemoveStream),
 	}
-	cache := base.ResourceEventHandler[*v1.Event](&handler.BaseHandler)
+	cache := base.ResourceEventHandler[*v1.Event](&handler.BaseHandler, map[string]func(){
+		handler.clusterWarningsStreamID():  handler.processClusterWarnings(),
+		handler.resourcePressureStreamID(): handler.processResourcePressure(),
+	})
 	handler.BaseHandler.StartInformer(cache)
+	if _, err := informer.AddEventHandler(handler.namespaceEventHandler()); err != nil {
+		log.Warn("failed to add namespace events handler", "error", err)
+	}
 	handler.BaseHandler.WaitForSync(ctx)
 	return handler
 }
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespaceEventsFilter selects the events pushed on a namespace events stream.
+// Empty fields match everything.
+type namespaceEventsFilter struct {
+	namespace string
+	eventType string
+	kind      string
+}
+
+func (f namespaceEventsFilter) matches(event *v1.Event) bool {
+	if event.Namespace != f.namespace {
+		return false
+	}
+	if f.eventType != "" && event.Type != f.eventType {
+		return false
+	}
+	if f.kind != "" && !strings.EqualFold(event.InvolvedObject.Kind, f.kind) {
+		return false
+	}
+	return true
+}
+
+// GetNamespaceEventsStream streams every event of a namespace, optionally
+// filtered by ?type=Normal|Warning and ?kind=<involvedObject kind>.
+func (h *EventsHandler) GetNamespaceEventsStream(c echo.Context) error {
+	filter := namespaceEventsFilter{
+		namespace: c.Param("name"),
+		eventType: c.QueryParam("type"),
+		kind:      c.QueryParam("kind"),
+	}
+	if filter.eventType != "" && filter.eventType != v1.EventTypeNormal && filter.eventType != v1.EventTypeWarning {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("type must be %s or %s", v1.EventTypeNormal, v1.EventTypeWarning))
+	}
+
+	streamID := fmt.Sprintf("%s-%s-%s-namespace-events-%s-%s", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, filter.namespace, filter.eventType, strings.ToLower(filter.kind))
+	release, err := h.namespaceStreams.Acquire(streamID, filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+	defer release()
+	h.processNamespaceEvents(streamID, filter)()
+
+	h.BaseHandler.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	return nil
+}
+
+func (h *EventsHandler) processNamespaceEvents(streamID string, filter namespaceEventsFilter) func() {
+	return func() {
+		items, err := h.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, filter.namespace)
+		if err != nil {
+			items = h.BaseHandler.Informer.GetStore().List()
+		}
+
+		events := make([]v1.Event, 0)
+		for _, obj := range items {
+			if event, ok := obj.(*v1.Event); ok && filter.matches(event) {
+				events = append(events, *event)
+			}
+		}
+
+		data, err := json.Marshal(TransformEvents(events))
+		if err != nil {
+			return
+		}
+		h.BaseHandler.Container.SSE().Publish(streamID, &sse.Event{
+			Data: data,
+		})
+	}
+}
+
+// namespaceEventHandler refreshes the namespace streams an event belongs to.
+func (h *EventsHandler) namespaceEventHandler() cache.ResourceEventHandlerFuncs {
+	handleEvent := func(obj any) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		event, ok := obj.(*v1.Event)
+		if !ok {
+			return
+		}
+		h.namespaceStreams.Range(func(streamID string, filter namespaceEventsFilter) {
+			if filter.matches(event) {
+				h.BaseHandler.Container.EventProcessor().AddEvent(streamID, h.processNamespaceEvents(streamID, filter))
+			}
+		})
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    handleEvent,
+		UpdateFunc: func(oldObj, newObj any) { handleEvent(newObj) },
+		DeleteFunc: handleEvent,
+	}
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceEventsFilterMatches(t *testing.T) {
+	event := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web"},
+		Type:           v1.EventTypeWarning,
+	}
+
+	tests := []struct {
+		name   string
+		filter namespaceEventsFilter
+		want   bool
+	}{
+		{"namespace only", namespaceEventsFilter{namespace: "default"}, true},
+		{"other namespace", namespaceEventsFilter{namespace: "kube-system"}, false},
+		{"matching type", namespaceEventsFilter{namespace: "default", eventType: v1.EventTypeWarning}, true},
+		{"other type", namespaceEventsFilter{namespace: "default", eventType: v1.EventTypeNormal}, false},
+		{"kind is case insensitive", namespaceEventsFilter{namespace: "default", kind: "pod"}, true},
+		{"other kind", namespaceEventsFilter{namespace: "default", kind: "Deployment"}, false},
+		{"type and kind", namespaceEventsFilter{namespace: "default", eventType: v1.EventTypeWarning, kind: "Pod"}, true},
+		{"type matches but kind does not", namespaceEventsFilter{namespace: "default", eventType: v1.EventTypeWarning, kind: "Node"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matches(event))
+		})
+	}
+}
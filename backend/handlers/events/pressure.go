@@ -0,0 +1,161 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	PressureOOM          = "oom"
+	PressureEviction     = "eviction"
+	PressureScheduling   = "scheduling"
+	PressureNodePressure = "nodePressure"
+)
+
+// maxPressureEvents bounds the resource pressure feed like the warnings feed.
+const maxPressureEvents = 200
+
+// pressureReasons maps event reasons to their pressure category.
+// FailedScheduling is classified by its message instead.
+var pressureReasons = map[string]string{
+	"OOMKilling":                PressureOOM,
+	"SystemOOM":                 PressureOOM,
+	"Evicted":                   PressureEviction,
+	"Preempted":                 PressureEviction,
+	"EvictionThresholdMet":      PressureNodePressure,
+	"NodeHasInsufficientMemory": PressureNodePressure,
+	"NodeHasDiskPressure":       PressureNodePressure,
+	"NodeHasInsufficientPID":    PressureNodePressure,
+	"FreeDiskSpaceFailed":       PressureNodePressure,
+}
+
+// schedulingPressure are the parts of a FailedScheduling message that point
+// at missing capacity rather than at affinity or volume constraints.
+var schedulingPressure = []string{
+	"Insufficient ",
+	"Too many pods",
+	"node.kubernetes.io/memory-pressure",
+	"node.kubernetes.io/disk-pressure",
+	"node.kubernetes.io/pid-pressure",
+}
+
+// PressureEvent is every event of one category about one object folded
+// together, with the latest message.
+type PressureEvent struct {
+	Category       string `json:"category"`
+	InvolvedObject struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"involvedObject"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	Events    int       `json:"events"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// GetResourcePressureEvents streams OOM kills, evictions, scheduling failures
+// for lack of resources and node pressure events of every namespace,
+// aggregated by involved object, most recent first.
+func (h *EventsHandler) GetResourcePressureEvents(c echo.Context) error {
+	streamID := h.resourcePressureStreamID()
+	h.processResourcePressure()()
+
+	h.BaseHandler.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	return nil
+}
+
+func (h *EventsHandler) resourcePressureStreamID() string {
+	return fmt.Sprintf("%s-%s-%s-pressure", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, h.BaseHandler.Kind)
+}
+
+func (h *EventsHandler) processResourcePressure() func() {
+	return func() {
+		events := make([]v1.Event, 0)
+		for _, obj := range h.BaseHandler.Informer.GetStore().List() {
+			if event, ok := obj.(*v1.Event); ok {
+				events = append(events, *event)
+			}
+		}
+
+		data, err := json.Marshal(AggregatePressureEvents(events, maxPressureEvents))
+		if err != nil {
+			return
+		}
+		h.BaseHandler.Container.SSE().Publish(h.resourcePressureStreamID(), &sse.Event{
+			Data: data,
+		})
+	}
+}
+
+// pressureCategory returns the category of a resource pressure event, or
+// an empty string for any other event.
+func pressureCategory(e v1.Event) string {
+	if e.Reason == "FailedScheduling" {
+		for _, s := range schedulingPressure {
+			if strings.Contains(e.Message, s) {
+				return PressureScheduling
+			}
+		}
+		return ""
+	}
+	return pressureReasons[e.Reason]
+}
+
+// AggregatePressureEvents keeps the resource pressure events and merges
+// those of one category about the same object, then returns at most limit of
+// them, latest first.
+func AggregatePressureEvents(events []v1.Event, limit int) []PressureEvent {
+	byKey := make(map[string]*PressureEvent)
+	for _, e := range events {
+		category := pressureCategory(e)
+		if category == "" {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Namespace, e.InvolvedObject.Name, category)
+		first, last := eventFirstSeen(e), eventLastSeen(e)
+
+		p, ok := byKey[key]
+		if !ok {
+			p = &PressureEvent{Category: category, Reason: e.Reason, Message: e.Message, FirstSeen: first, LastSeen: last}
+			p.InvolvedObject.Kind = e.InvolvedObject.Kind
+			p.InvolvedObject.Name = e.InvolvedObject.Name
+			p.InvolvedObject.Namespace = e.InvolvedObject.Namespace
+			byKey[key] = p
+		}
+		p.Count += eventCount(e)
+		p.Events++
+		if first.Before(p.FirstSeen) {
+			p.FirstSeen = first
+		}
+		if last.After(p.LastSeen) {
+			p.LastSeen = last
+			p.Reason, p.Message = e.Reason, e.Message
+		}
+	}
+
+	list := make([]PressureEvent, 0, len(byKey))
+	for _, p := range byKey {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].LastSeen.After(list[j].LastSeen)
+	})
+
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
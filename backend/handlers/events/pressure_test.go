@@ -0,0 +1,44 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestAggregatePressureEvents(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduling := newWarning("web", "FailedScheduling", 2, now.Add(-time.Minute))
+	scheduling.Message = "0/3 nodes are available: 3 Insufficient memory."
+	laterScheduling := newWarning("web", "FailedScheduling", 1, now)
+	laterScheduling.Message = "0/3 nodes are available: 3 Insufficient cpu."
+	affinity := newWarning("db", "FailedScheduling", 1, now)
+	affinity.Message = "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector."
+	nodePressure := newWarning("node-1", "NodeHasDiskPressure", 1, now.Add(-time.Hour))
+	nodePressure.InvolvedObject = v1.ObjectReference{Kind: "Node", Name: "node-1"}
+	nodePressure.Type = v1.EventTypeNormal
+
+	got := AggregatePressureEvents([]v1.Event{
+		scheduling,
+		laterScheduling,
+		affinity,
+		newWarning("worker", "Evicted", 1, now.Add(-2*time.Minute)),
+		newWarning("web", "BackOff", 5, now),
+		nodePressure,
+	}, 0)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, PressureScheduling, got[0].Category)
+	assert.Equal(t, "web", got[0].InvolvedObject.Name)
+	assert.Equal(t, int32(3), got[0].Count)
+	assert.Equal(t, 2, got[0].Events)
+	assert.Equal(t, "0/3 nodes are available: 3 Insufficient cpu.", got[0].Message)
+
+	assert.Equal(t, PressureEviction, got[1].Category)
+	assert.Equal(t, PressureNodePressure, got[2].Category)
+	assert.Equal(t, "Node", got[2].InvolvedObject.Kind)
+}
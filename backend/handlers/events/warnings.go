@@ -0,0 +1,142 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	v1 "k8s.io/api/core/v1"
+)
+
+// maxClusterWarnings bounds the warnings feed to the most recent distinct
+// warnings so busy clusters don't grow the stream payload without limit.
+const maxClusterWarnings = 200
+
+type Warning struct {
+	InvolvedObject struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"involvedObject"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	Events    int       `json:"events"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// GetClusterWarnings streams Warning events of every namespace, repeated
+// occurrences folded together, most recent first.
+func (h *EventsHandler) GetClusterWarnings(c echo.Context) error {
+	streamID := h.clusterWarningsStreamID()
+	h.processClusterWarnings()()
+
+	h.BaseHandler.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	return nil
+}
+
+func (h *EventsHandler) clusterWarningsStreamID() string {
+	return fmt.Sprintf("%s-%s-%s-warnings", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, h.BaseHandler.Kind)
+}
+
+func (h *EventsHandler) processClusterWarnings() func() {
+	return func() {
+		events := make([]v1.Event, 0)
+		for _, obj := range h.BaseHandler.Informer.GetStore().List() {
+			if event, ok := obj.(*v1.Event); ok && event.Type == v1.EventTypeWarning {
+				events = append(events, *event)
+			}
+		}
+
+		data, err := json.Marshal(AggregateWarnings(events, maxClusterWarnings))
+		if err != nil {
+			return
+		}
+		h.BaseHandler.Container.SSE().Publish(h.clusterWarningsStreamID(), &sse.Event{
+			Data: data,
+		})
+	}
+}
+
+// AggregateWarnings merges events reported for the same object with the same
+// reason and message, then returns at most limit of them, latest first.
+func AggregateWarnings(events []v1.Event, limit int) []Warning {
+	byKey := make(map[string]*Warning)
+	for _, e := range events {
+		key := fmt.Sprintf("%s/%s/%s/%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Namespace, e.InvolvedObject.Name, e.Reason, e.Message)
+		first, last := eventFirstSeen(e), eventLastSeen(e)
+
+		w, ok := byKey[key]
+		if !ok {
+			w = &Warning{Reason: e.Reason, Message: e.Message, FirstSeen: first, LastSeen: last}
+			w.InvolvedObject.Kind = e.InvolvedObject.Kind
+			w.InvolvedObject.Name = e.InvolvedObject.Name
+			w.InvolvedObject.Namespace = e.InvolvedObject.Namespace
+			byKey[key] = w
+		}
+		w.Count += eventCount(e)
+		w.Events++
+		if first.Before(w.FirstSeen) {
+			w.FirstSeen = first
+		}
+		if last.After(w.LastSeen) {
+			w.LastSeen = last
+		}
+	}
+
+	list := make([]Warning, 0, len(byKey))
+	for _, w := range byKey {
+		list = append(list, *w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].LastSeen.After(list[j].LastSeen)
+	})
+
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// eventCount prefers the series count used by the events.k8s.io API over the
+// deprecated core count; an event is always at least one occurrence.
+func eventCount(e v1.Event) int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	if e.Count > 0 {
+		return e.Count
+	}
+	return 1
+}
+
+func eventFirstSeen(e v1.Event) time.Time {
+	switch {
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+func eventLastSeen(e v1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newWarning(object, reason string, count int32, last time.Time) v1.Event {
+	return v1.Event{
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: object},
+		Reason:         reason,
+		Message:        reason + " for " + object,
+		Type:           v1.EventTypeWarning,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(last.Add(-time.Minute)),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func TestAggregateWarnings(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	series := newWarning("web", "BackOff", 0, now.Add(-time.Hour))
+	series.Series = &v1.EventSeries{Count: 7, LastObservedTime: metav1.NewMicroTime(now)}
+
+	warnings := AggregateWarnings([]v1.Event{
+		newWarning("web", "BackOff", 3, now.Add(-2*time.Minute)),
+		series,
+		newWarning("db", "FailedMount", 1, now.Add(-time.Minute)),
+		newWarning("cache", "Unhealthy", 0, now.Add(-time.Hour)),
+	}, 0)
+
+	require.Len(t, warnings, 3)
+	assert.Equal(t, "web", warnings[0].InvolvedObject.Name)
+	assert.Equal(t, int32(10), warnings[0].Count)
+	assert.Equal(t, 2, warnings[0].Events)
+	assert.Equal(t, now, warnings[0].LastSeen)
+	assert.Equal(t, now.Add(-61*time.Minute), warnings[0].FirstSeen)
+
+	assert.Equal(t, "db", warnings[1].InvolvedObject.Name)
+	assert.Equal(t, int32(1), warnings[2].Count, "events without a count happened once")
+}
+
+func TestAggregateWarningsKeepsMostRecent(t *testing.T) {
+	now := time.Now()
+	events := make([]v1.Event, 0)
+	for i := range 10 {
+		events = append(events, newWarning(string(rune('a'+i)), "BackOff", 1, now.Add(time.Duration(i)*time.Second)))
+	}
+
+	warnings := AggregateWarnings(events, 3)
+
+	require.Len(t, warnings, 3)
+	assert.Equal(t, "j", warnings[0].InvolvedObject.Name)
+	assert.Equal(t, "h", warnings[2].InvolvedObject.Name)
+}
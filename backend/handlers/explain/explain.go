@@ -0,0 +1,306 @@
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+const openAPICacheKeyFormat = "openapi-v3-%s-%s-%s"
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// Field is one field of the explained schema.
+type Field struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// Explanation is the kubectl explain output for a kind or one of its fields.
+type Explanation struct {
+	Group       string   `json:"group"`
+	Version     string   `json:"version"`
+	Kind        string   `json:"kind"`
+	Path        string   `json:"path,omitempty"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Fields      []Field  `json:"fields"`
+}
+
+type groupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// openAPISchema is the subset of an OpenAPI v3 schema object explain reads.
+type openAPISchema struct {
+	Type                 string                    `json:"type"`
+	Format               string                    `json:"format"`
+	Description          string                    `json:"description"`
+	Ref                  string                    `json:"$ref"`
+	AllOf                []*openAPISchema          `json:"allOf"`
+	Items                *openAPISchema            `json:"items"`
+	AdditionalProperties additionalProperties      `json:"additionalProperties"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	Required             []string                  `json:"required"`
+	Enum                 []any                     `json:"enum"`
+	IntOrString          bool                      `json:"x-kubernetes-int-or-string"`
+	GroupVersionKinds    []groupVersionKind        `json:"x-kubernetes-group-version-kind"`
+}
+
+// additionalProperties is either a schema or a boolean, only the schema is
+// of interest.
+type additionalProperties struct {
+	schema *openAPISchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		a.schema = new(openAPISchema)
+		return json.Unmarshal(data, a.schema)
+	}
+	return nil
+}
+
+// schemas are the component schemas of one group version, by name.
+type schemas map[string]*openAPISchema
+
+type ExplainHandler struct {
+	container container.Container
+}
+
+func NewExplainHandler(container container.Container) *ExplainHandler {
+	return &ExplainHandler{container: container}
+}
+
+// ExplainResource is the kubectl explain equivalent: it documents a resource,
+// or the field at ?field=spec.template, from the cluster's OpenAPI v3 spec.
+// The parsed spec of each group version is cached per cluster.
+func (h *ExplainHandler) ExplainResource(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+
+	discoveryClient := h.container.DiscoveryClient(config, cluster)
+	if discoveryClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	version := c.QueryParam("version")
+	if version == "" {
+		version = "v1"
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: version, Resource: c.Param("resource")}
+
+	kind, err := resourceKind(discoveryClient, gvr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	specs, err := h.groupVersionSchemas(discoveryClient, config, cluster, gvr.GroupVersion())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	explanation, err := explain(specs, gvr.GroupVersion().WithKind(kind), c.QueryParam("field"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.JSON(http.StatusOK, explanation)
+}
+
+func resourceKind(discoveryClient discovery.DiscoveryInterface, gvr schema.GroupVersionResource) (string, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		return "", err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == gvr.Resource {
+			return r.Kind, nil
+		}
+	}
+	return "", fmt.Errorf("the server doesn't have a resource type %q in %s", gvr.Resource, gvr.GroupVersion())
+}
+
+func (h *ExplainHandler) groupVersionSchemas(discoveryClient discovery.DiscoveryInterface, config, cluster string, gv schema.GroupVersion) (schemas, error) {
+	path := "apis/" + gv.String()
+	if gv.Group == "" {
+		path = "api/" + gv.Version
+	}
+	cacheKey := fmt.Sprintf(openAPICacheKeyFormat, config, cluster, path)
+	if cached, ok := h.container.Cache().GetIfPresent(cacheKey); ok {
+		return cached.(schemas), nil
+	}
+
+	paths, err := discoveryClient.OpenAPIV3().Paths()
+	if err != nil {
+		return nil, err
+	}
+	groupVersion, ok := paths[path]
+	if !ok {
+		return nil, fmt.Errorf("the server publishes no OpenAPI v3 spec for %s", gv)
+	}
+	data, err := groupVersion.Schema(runtime.ContentTypeJSON)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := parseSchemas(data)
+	if err != nil {
+		return nil, err
+	}
+	h.container.Cache().Set(cacheKey, specs)
+	return specs, nil
+}
+
+func parseSchemas(data []byte) (schemas, error) {
+	var doc struct {
+		Components struct {
+			Schemas schemas `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Components.Schemas, nil
+}
+
+// explain walks fieldPath from the schema of gvk. Arrays and maps are
+// stepped through, as kubectl explain does, so spec.containers.image works.
+func explain(specs schemas, gvk schema.GroupVersionKind, fieldPath string) (Explanation, error) {
+	root := specs.kindSchema(gvk)
+	if root == nil {
+		return Explanation{}, fmt.Errorf("no schema found for %s", gvk)
+	}
+
+	explanation := Explanation{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Path: fieldPath}
+	current := root
+	description := root.Description
+	if fieldPath != "" {
+		for _, name := range strings.Split(fieldPath, ".") {
+			parent := specs.elem(specs.resolve(current))
+			child, ok := parent.Properties[name]
+			if !ok {
+				return Explanation{}, fmt.Errorf("field %q does not exist", fieldPath)
+			}
+			current = child
+			description = specs.description(child)
+		}
+	}
+
+	explanation.Type = gvk.Kind
+	if fieldPath != "" {
+		explanation.Type = specs.typeName(current)
+	}
+	explanation.Description = description
+	resolved := specs.resolve(current)
+	for _, value := range resolved.Enum {
+		explanation.Enum = append(explanation.Enum, fmt.Sprint(value))
+	}
+
+	fields := specs.elem(resolved)
+	explanation.Fields = make([]Field, 0, len(fields.Properties))
+	for name, field := range fields.Properties {
+		explanation.Fields = append(explanation.Fields, Field{
+			Name:        name,
+			Type:        specs.typeName(field),
+			Description: specs.description(field),
+			Required:    slices.Contains(fields.Required, name),
+		})
+	}
+	sort.Slice(explanation.Fields, func(i, j int) bool { return explanation.Fields[i].Name < explanation.Fields[j].Name })
+	return explanation, nil
+}
+
+func (s schemas) kindSchema(gvk schema.GroupVersionKind) *openAPISchema {
+	for _, candidate := range s {
+		for _, k := range candidate.GroupVersionKinds {
+			if k.Group == gvk.Group && k.Version == gvk.Version && k.Kind == gvk.Kind {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows $ref and the single-element allOf v3 wraps references in.
+func (s schemas) resolve(schema *openAPISchema) *openAPISchema {
+	for range 32 {
+		switch {
+		case schema.Ref != "":
+			target, ok := s[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+			if !ok {
+				return schema
+			}
+			schema = target
+		case len(schema.AllOf) == 1 && len(schema.Properties) == 0:
+			schema = schema.AllOf[0]
+		default:
+			return schema
+		}
+	}
+	return schema
+}
+
+// elem steps through arrays and maps to the schema of their elements.
+func (s schemas) elem(schema *openAPISchema) *openAPISchema {
+	for range 32 {
+		switch {
+		case schema.Items != nil:
+			schema = s.resolve(schema.Items)
+		case schema.AdditionalProperties.schema != nil && len(schema.Properties) == 0:
+			schema = s.resolve(schema.AdditionalProperties.schema)
+		default:
+			return schema
+		}
+	}
+	return schema
+}
+
+// description prefers the field's own description over the referenced type's.
+func (s schemas) description(schema *openAPISchema) string {
+	if schema.Description != "" {
+		return schema.Description
+	}
+	return s.resolve(schema).Description
+}
+
+// typeName renders a schema type the way kubectl explain does, e.g.
+// "[]Container", "map[string]string" or "ObjectMeta".
+func (s schemas) typeName(schema *openAPISchema) string {
+	ref := schema.Ref
+	if ref == "" && len(schema.AllOf) == 1 {
+		ref = schema.AllOf[0].Ref
+	}
+	if ref != "" {
+		name := strings.TrimPrefix(ref, schemaRefPrefix)
+		if target, ok := s[name]; ok && target.Type != "" && target.Type != "object" {
+			return s.typeName(target)
+		}
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+
+	switch {
+	case schema.IntOrString:
+		return "IntOrString"
+	case schema.Type == "array" && schema.Items != nil:
+		return "[]" + s.typeName(schema.Items)
+	case schema.Type == "object" && schema.AdditionalProperties.schema != nil:
+		return "map[string]" + s.typeName(schema.AdditionalProperties.schema)
+	case schema.Type == "":
+		return "Object"
+	case schema.Format != "" && schema.Type != "string":
+		return schema.Type + " (" + schema.Format + ")"
+	default:
+		return schema.Type
+	}
+}
@@ -0,0 +1,74 @@
+package explain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const openAPIDoc = `{"components":{"schemas":{
+  "io.k8s.api.apps.v1.Deployment":{"type":"object","description":"Deployment enables declarative updates.",
+    "properties":{
+      "metadata":{"allOf":[{"$ref":"#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}],"description":"Standard object metadata."},
+      "spec":{"allOf":[{"$ref":"#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}]}},
+    "x-kubernetes-group-version-kind":[{"group":"apps","kind":"Deployment","version":"v1"}]},
+  "io.k8s.api.apps.v1.DeploymentSpec":{"type":"object","description":"DeploymentSpec is the specification.",
+    "required":["selector"],
+    "properties":{
+      "replicas":{"type":"integer","format":"int32","description":"Number of desired pods."},
+      "selector":{"allOf":[{"$ref":"#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"}]},
+      "strategyType":{"type":"string","enum":["Recreate","RollingUpdate"]},
+      "containers":{"type":"array","items":{"allOf":[{"$ref":"#/components/schemas/io.k8s.api.core.v1.Container"}]}},
+      "maxSurge":{"allOf":[{"$ref":"#/components/schemas/io.k8s.apimachinery.pkg.util.intstr.IntOrString"}]}}},
+  "io.k8s.api.core.v1.Container":{"type":"object","properties":{"image":{"type":"string","description":"Container image name."}}},
+  "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta":{"type":"object","description":"ObjectMeta is metadata.",
+    "properties":{"labels":{"type":"object","additionalProperties":{"type":"string","default":""}}},"additionalProperties":false},
+  "io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector":{"type":"object","description":"A label selector."},
+  "io.k8s.apimachinery.pkg.util.intstr.IntOrString":{"type":"string","format":"int-or-string","x-kubernetes-int-or-string":true}
+}}}`
+
+func TestExplain(t *testing.T) {
+	specs, err := parseSchemas([]byte(openAPIDoc))
+	require.NoError(t, err)
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+	got, err := explain(specs, gvk, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Deployment", got.Type)
+	assert.Equal(t, "Deployment enables declarative updates.", got.Description)
+	assert.Equal(t, []Field{
+		{Name: "metadata", Type: "ObjectMeta", Description: "Standard object metadata."},
+		{Name: "spec", Type: "DeploymentSpec", Description: "DeploymentSpec is the specification."},
+	}, got.Fields)
+
+	got, err = explain(specs, gvk, "spec")
+	require.NoError(t, err)
+	assert.Equal(t, []Field{
+		{Name: "containers", Type: "[]Container"},
+		{Name: "maxSurge", Type: "IntOrString"},
+		{Name: "replicas", Type: "integer (int32)", Description: "Number of desired pods."},
+		{Name: "selector", Type: "LabelSelector", Description: "A label selector.", Required: true},
+		{Name: "strategyType", Type: "string"},
+	}, got.Fields)
+
+	got, err = explain(specs, gvk, "spec.containers.image")
+	require.NoError(t, err)
+	assert.Equal(t, "string", got.Type)
+	assert.Equal(t, "Container image name.", got.Description)
+	assert.Empty(t, got.Fields)
+
+	got, err = explain(specs, gvk, "spec.strategyType")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Recreate", "RollingUpdate"}, got.Enum)
+
+	got, err = explain(specs, gvk, "metadata.labels")
+	require.NoError(t, err)
+	assert.Equal(t, "map[string]string", got.Type)
+
+	_, err = explain(specs, gvk, "spec.replica")
+	assert.EqualError(t, err, `field "spec.replica" does not exist`)
+	_, err = explain(specs, schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "")
+	assert.Error(t, err)
+}
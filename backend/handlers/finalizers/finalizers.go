@@ -0,0 +1,122 @@
+package finalizers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+type FinalizersHandler struct {
+	container container.Container
+}
+
+func NewFinalizersHandler(container container.Container) *FinalizersHandler {
+	return &FinalizersHandler{container: container}
+}
+
+// RemoveFinalizer removes the finalizers named by the repeated ?finalizer=
+// param from an object of any resource, or all of them with ?all=true, and
+// returns the updated object. It unsticks objects whose controller is gone,
+// at the cost of skipping that controller's cleanup, so every call is audited.
+func (h *FinalizersHandler) RemoveFinalizer(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	name := c.Param("name")
+	if c.QueryParam("version") == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required")
+	}
+	all, _ := strconv.ParseBool(c.QueryParam("all"))
+	finalizers := c.QueryParams()["finalizer"]
+	if all == (len(finalizers) > 0) {
+		return echo.NewHTTPError(http.StatusBadRequest, "name the finalizers to remove with finalizer, or set all=true to remove every one")
+	}
+
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if dynamicClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, removed, err := removeFinalizers(c.Request().Context(), resource, name, finalizers, all)
+	if err != nil {
+		return err
+	}
+	log.Info("audit: finalizers removed",
+		"config", config, "cluster", cluster, "resource", gvr.GroupResource().String(),
+		"namespace", namespace, "name", name, "finalizers", removed, "remoteAddr", c.RealIP())
+	_, _ = helpers.StripUnusedFields(obj)
+	return c.JSON(http.StatusOK, obj)
+}
+
+// removeFinalizers patches the finalizers out and returns the object and the
+// finalizers removed. Named finalizers are removed with a JSON patch that
+// tests each entry first, so a concurrent change makes it fail instead of
+// removing the wrong one.
+func removeFinalizers(ctx context.Context, resource dynamic.ResourceInterface, name string, finalizers []string, all bool) (*unstructured.Unstructured, []string, error) {
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	current := obj.GetFinalizers()
+	if len(current) == 0 {
+		return obj, []string{}, nil
+	}
+
+	var patchType types.PatchType
+	var patch []byte
+	removed := make([]string, 0)
+	if all {
+		removed = current
+		patchType = types.MergePatchType
+		patch, _ = json.Marshal(helpers.PreconditionPatch(map[string]any{"metadata": map[string]any{"finalizers": nil}}, obj.GetResourceVersion()))
+	} else {
+		var indexes []int
+		for _, finalizer := range finalizers {
+			i := slices.Index(current, finalizer)
+			if i < 0 {
+				return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("finalizer %s not found on %s", finalizer, name))
+			}
+			if !slices.Contains(indexes, i) {
+				indexes = append(indexes, i)
+				removed = append(removed, finalizer)
+			}
+		}
+		// Removing from the end keeps the earlier indexes valid.
+		slices.Sort(indexes)
+		slices.Reverse(indexes)
+		ops := make([]map[string]any, 0, 2*len(indexes))
+		for _, i := range indexes {
+			path := fmt.Sprintf("/metadata/finalizers/%d", i)
+			ops = append(ops, map[string]any{"op": "test", "path": path, "value": current[i]}, map[string]any{"op": "remove", "path": path})
+		}
+		patchType = types.JSONPatchType
+		patch, _ = json.Marshal(ops)
+	}
+
+	updated, err := resource.Patch(ctx, name, patchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return updated, removed, nil
+}
@@ -0,0 +1,64 @@
+package finalizers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var widgets = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func newClient(finalizers ...string) *dynamicfake.FakeDynamicClient {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Widget")
+	u.SetNamespace("apps")
+	u.SetName("gadget")
+	u.SetFinalizers(finalizers)
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"}, u)
+}
+
+func TestRemoveFinalizers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("named", func(t *testing.T) {
+		client := newClient("a.example.com/one", "b.example.com/two", "c.example.com/three")
+		obj, removed, err := removeFinalizers(ctx, client.Resource(widgets).Namespace("apps"), "gadget", []string{"a.example.com/one", "c.example.com/three", "a.example.com/one"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.example.com/one", "c.example.com/three"}, removed)
+		assert.Equal(t, []string{"b.example.com/two"}, obj.GetFinalizers())
+	})
+
+	t.Run("all", func(t *testing.T) {
+		client := newClient("a.example.com/one", "b.example.com/two")
+		obj, removed, err := removeFinalizers(ctx, client.Resource(widgets).Namespace("apps"), "gadget", nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.example.com/one", "b.example.com/two"}, removed)
+		assert.Empty(t, obj.GetFinalizers())
+	})
+
+	t.Run("unknown finalizer", func(t *testing.T) {
+		client := newClient("a.example.com/one")
+		_, _, err := removeFinalizers(ctx, client.Resource(widgets).Namespace("apps"), "gadget", []string{"b.example.com/two"}, false)
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		client := newClient()
+		_, _, err := removeFinalizers(ctx, client.Resource(widgets).Namespace("apps"), "other", []string{"a.example.com/one"}, false)
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+}
@@ -0,0 +1,154 @@
+package helmreleases
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	SortByUpdated   = "updated"
+	SortByName      = "name"
+	SortByNamespace = "namespace"
+)
+
+const (
+	releasesCacheKeyFormat = "helm-releases-%s-%s-%s-%s-%s"
+	// releasesCacheTTL keeps the view briefly, installs and upgrades should
+	// show up without a reload.
+	releasesCacheTTL = 30 * time.Second
+)
+
+type Release struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Updated      time.Time `json:"updated"`
+}
+
+// ListReleases lists the latest revision of every Helm release, in
+// ?namespace= or all namespaces. sortBy is "updated", newest first and the
+// default, "name" or "namespace"; chart keeps the releases of one chart.
+func (h *HelmReleasesHandler) ListReleases(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	chart := c.QueryParam("chart")
+
+	clientSet := h.container.ClientSet(config, cluster)
+	if clientSet == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	sortBy := c.QueryParam("sortBy")
+	switch sortBy {
+	case "":
+		sortBy = SortByUpdated
+	case SortByUpdated, SortByName, SortByNamespace:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("sortBy must be %s, %s or %s", SortByUpdated, SortByName, SortByNamespace))
+	}
+
+	cacheKey := fmt.Sprintf(releasesCacheKeyFormat, config, cluster, namespace, sortBy, strings.ToLower(chart))
+	if cached, ok := h.container.Cache().GetIfPresent(cacheKey); ok {
+		return c.JSON(http.StatusOK, cached)
+	}
+
+	releases, err := h.listReleases(clientSet, namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	releases = sortReleases(filterReleases(releases, chart), sortBy)
+	h.container.Cache().Set(cacheKey, releases)
+	h.container.Cache().SetExpiresAfter(cacheKey, releasesCacheTTL)
+	return c.JSON(http.StatusOK, releases)
+}
+
+// listReleases reads the latest revision of every release from Helm's
+// release storage.
+func (h *HelmReleasesHandler) listReleases(clientSet kubernetes.Interface, namespace string) ([]Release, error) {
+	stored, err := releaseStorage(clientSet, namespace).ListReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]*helmrelease.Release)
+	for _, rls := range stored {
+		key := rls.Namespace + "/" + rls.Name
+		if current, ok := latest[key]; !ok || rls.Version > current.Version {
+			latest[key] = rls
+		}
+	}
+
+	releases := make([]Release, 0, len(latest))
+	for _, rls := range latest {
+		releases = append(releases, releaseSummary(rls))
+	}
+	return releases, nil
+}
+
+func releaseSummary(rls *helmrelease.Release) Release {
+	summary := Release{
+		Name:      rls.Name,
+		Namespace: rls.Namespace,
+		Revision:  rls.Version,
+	}
+	if rls.Info != nil {
+		summary.Status = rls.Info.Status.String()
+		summary.Updated = rls.Info.LastDeployed.Time
+	}
+	if rls.Chart != nil && rls.Chart.Metadata != nil {
+		summary.Chart = rls.Chart.Metadata.Name
+		summary.ChartVersion = rls.Chart.Metadata.Version
+		summary.AppVersion = rls.Chart.Metadata.AppVersion
+	}
+	return summary
+}
+
+func filterReleases(releases []Release, chart string) []Release {
+	if chart == "" {
+		return releases
+	}
+	filtered := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		if strings.EqualFold(r.Chart, chart) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// sortReleases sorts in place. Ties are broken by namespace and name so the
+// order is stable between requests.
+func sortReleases(releases []Release, sortBy string) []Release {
+	byNamespaceName := func(a, b Release) bool {
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		switch sortBy {
+		case SortByUpdated:
+			if !a.Updated.Equal(b.Updated) {
+				return a.Updated.After(b.Updated)
+			}
+		case SortByName:
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		}
+		return byNamespaceName(a, b)
+	})
+	return releases
+}
@@ -0,0 +1,67 @@
+package helmreleases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+func chartRelease(namespace, name string, revision int, chartName, version string, deployed time.Time) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   revision,
+		Info:      &release.Info{Status: release.StatusDeployed, LastDeployed: helmtime.Time{Time: deployed}},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: chartName, Version: version, AppVersion: "1.0.0"}},
+	}
+}
+
+func TestListReleases(t *testing.T) {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	clientSet := storedReleases(t,
+		chartRelease("apps", "web", 1, "nginx", "15.0.0", day),
+		chartRelease("apps", "web", 2, "nginx", "15.1.0", day.Add(48*time.Hour)),
+		chartRelease("data", "cache", 1, "redis", "19.0.0", day.Add(24*time.Hour)),
+		chartRelease("apps", "api", 1, "nginx", "15.1.0", day),
+	)
+	h := NewHelmReleasesHandler(container.NewContainer(&config.Env{}, config.NewAppConfig("test", ":0", 10, 10, false)))
+
+	releases, err := h.listReleases(clientSet, "")
+	require.NoError(t, err)
+	require.Len(t, releases, 3)
+
+	names := func(releases []Release) []string {
+		var names []string
+		for _, r := range releases {
+			names = append(names, r.Namespace+"/"+r.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"apps/web", "data/cache", "apps/api"}, names(sortReleases(releases, SortByUpdated)))
+	assert.Equal(t, []string{"apps/api", "data/cache", "apps/web"}, names(sortReleases(releases, SortByName)))
+	assert.Equal(t, []string{"apps/api", "apps/web", "data/cache"}, names(sortReleases(releases, SortByNamespace)))
+	assert.Equal(t, []string{"apps/api", "apps/web"}, names(filterReleases(sortReleases(releases, SortByNamespace), "NGINX")))
+
+	web := sortReleases(releases, SortByUpdated)[0]
+	assert.Equal(t, Release{
+		Name:         "web",
+		Namespace:    "apps",
+		Revision:     2,
+		Status:       "deployed",
+		Chart:        "nginx",
+		ChartVersion: "15.1.0",
+		AppVersion:   "1.0.0",
+		Updated:      day.Add(48 * time.Hour),
+	}, web)
+
+	namespaced, err := h.listReleases(clientSet, "data")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data/cache"}, names(namespaced))
+}
@@ -0,0 +1,270 @@
+package helmreleases
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/pmezard/go-difflib/difflib"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+const manifestCacheKeyFormat = "helm-manifest-%s-%s-%s-%s-%d"
+
+// ResourceDiff is the change of one object rendered by the release.
+type ResourceDiff struct {
+	Kind      string              `json:"kind"`
+	Name      string              `json:"name"`
+	Namespace string              `json:"namespace,omitempty"`
+	Type      string              `json:"type"`
+	Diffs     []helpers.FieldDiff `json:"diffs,omitempty"`
+}
+
+type RevisionDiff struct {
+	Release   string         `json:"release"`
+	Namespace string         `json:"namespace"`
+	From      int            `json:"from"`
+	To        int            `json:"to"`
+	Identical bool           `json:"identical"`
+	Diff      string         `json:"diff"`
+	Resources []ResourceDiff `json:"resources"`
+}
+
+type HelmReleasesHandler struct {
+	container      container.Container
+	settings       *cli.EnvSettings
+	registryClient *registry.Client
+}
+
+func NewHelmReleasesHandler(container container.Container) *HelmReleasesHandler {
+	settings := cli.New()
+	return &HelmReleasesHandler{
+		container:      container,
+		settings:       settings,
+		registryClient: newRegistryClient(settings),
+	}
+}
+
+// DiffReleaseRevisions diffs the manifests of two revisions of a release, by
+// default the latest one against the one before it. Manifests are read from
+// Helm's release storage.
+func (h *HelmReleasesHandler) DiffReleaseRevisions(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	name := c.Param("name")
+
+	clientSet := h.container.ClientSet(config, cluster)
+	if clientSet == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	if namespace == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "namespace query param is required")
+	}
+
+	from, to, err := parseRevisions(c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if to == 0 {
+		if to, err = latestRevision(clientSet, namespace, name); err != nil {
+			return releaseError(err, fmt.Sprintf("release %s not found in namespace %s", name, namespace))
+		}
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("release %s has no revision before %d", name, to))
+	}
+
+	manifests := make([]string, 2)
+	for i, revision := range []int{from, to} {
+		if manifests[i], err = h.revisionManifest(clientSet, config, cluster, namespace, name, revision); err != nil {
+			return err
+		}
+	}
+
+	result, err := diffManifests(manifests[0], manifests[1], from, to)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	result.Release, result.Namespace = name, namespace
+	return c.JSON(http.StatusOK, result)
+}
+
+// revisionManifest returns the manifest of a stored revision as an
+// *echo.HTTPError on failure.
+func (h *HelmReleasesHandler) revisionManifest(clientSet kubernetes.Interface, config, cluster, namespace, name string, revision int) (string, error) {
+	cacheKey := fmt.Sprintf(manifestCacheKeyFormat, config, cluster, namespace, name, revision)
+	if cached, ok := h.container.Cache().GetIfPresent(cacheKey); ok {
+		return cached.(string), nil
+	}
+	manifest, err := releaseManifest(clientSet, namespace, name, revision)
+	if err != nil {
+		return "", releaseError(err, fmt.Sprintf("revision %d of release %s not found", revision, name))
+	}
+	// A revision's manifest never changes once it is stored.
+	h.container.Cache().Set(cacheKey, manifest)
+	return manifest, nil
+}
+
+func parseRevisions(fromParam, toParam string) (int, int, error) {
+	revisions := make([]int, 2)
+	for i, value := range []string{fromParam, toParam} {
+		if value == "" {
+			continue
+		}
+		revision, err := strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			return 0, 0, fmt.Errorf("invalid revision %q", value)
+		}
+		revisions[i] = revision
+	}
+	return revisions[0], revisions[1], nil
+}
+
+// releaseStorage reads the releases of namespace, all namespaces if empty,
+// from the storage driver Helm uses: HELM_DRIVER, Secrets by default.
+func releaseStorage(clientSet kubernetes.Interface, namespace string) *storage.Storage {
+	var d driver.Driver
+	switch os.Getenv("HELM_DRIVER") {
+	case "configmap", "configmaps":
+		d = driver.NewConfigMaps(clientSet.CoreV1().ConfigMaps(namespace))
+	default:
+		d = driver.NewSecrets(clientSet.CoreV1().Secrets(namespace))
+	}
+	return storage.Init(d)
+}
+
+func latestRevision(clientSet kubernetes.Interface, namespace, name string) (int, error) {
+	rls, err := releaseStorage(clientSet, namespace).Last(name)
+	if err != nil {
+		return 0, err
+	}
+	return rls.Version, nil
+}
+
+func releaseManifest(clientSet kubernetes.Interface, namespace, name string, revision int) (string, error) {
+	rls, err := releaseStorage(clientSet, namespace).Get(name, revision)
+	if err != nil {
+		return "", err
+	}
+	return rls.Manifest, nil
+}
+
+// releaseError maps a storage error to an *echo.HTTPError.
+func releaseError(err error, notFound string) error {
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, notFound)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// diffManifests returns the unified diff of two manifests and the field
+// changes of every object they render, matched by kind, namespace and name.
+func diffManifests(fromManifest, toManifest string, from, to int) (RevisionDiff, error) {
+	unified, resources, err := manifestDiff(fromManifest, toManifest, fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to))
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	return RevisionDiff{
+		From:      from,
+		To:        to,
+		Identical: unified == "",
+		Diff:      unified,
+		Resources: resources,
+	}, nil
+}
+
+func manifestDiff(fromManifest, toManifest, fromFile, toFile string) (string, []ResourceDiff, error) {
+	unified, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromManifest),
+		B:        difflib.SplitLines(toManifest),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	fromObjects, err := manifestObjects(fromManifest)
+	if err != nil {
+		return "", nil, err
+	}
+	toObjects, err := manifestObjects(toManifest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resources := make([]ResourceDiff, 0)
+	for key, left := range fromObjects {
+		right, ok := toObjects[key]
+		if !ok {
+			resources = append(resources, key.diff(helpers.DiffRemoved, nil))
+			continue
+		}
+		if diffs := helpers.Diff(left, right); len(diffs) > 0 {
+			resources = append(resources, key.diff(helpers.DiffChanged, diffs))
+		}
+	}
+	for key := range toObjects {
+		if _, ok := fromObjects[key]; !ok {
+			resources = append(resources, key.diff(helpers.DiffAdded, nil))
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		if resources[i].Namespace != resources[j].Namespace {
+			return resources[i].Namespace < resources[j].Namespace
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return unified, resources, nil
+}
+
+type objectKey struct {
+	kind, namespace, name string
+}
+
+func (k objectKey) diff(diffType string, diffs []helpers.FieldDiff) ResourceDiff {
+	return ResourceDiff{Kind: k.kind, Namespace: k.namespace, Name: k.name, Type: diffType, Diffs: diffs}
+}
+
+func manifestObjects(manifest string) (map[objectKey]map[string]any, error) {
+	objects := make(map[objectKey]map[string]any)
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest)), 4096)
+	for {
+		obj := make(map[string]any)
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		kind, _ := obj["kind"].(string)
+		metadata, _ := obj["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		namespace, _ := metadata["namespace"].(string)
+		objects[objectKey{kind: kind, namespace: namespace, name: name}] = obj
+	}
+}
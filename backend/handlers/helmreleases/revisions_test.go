@@ -0,0 +1,100 @@
+package helmreleases
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const manifestV1 = `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+# Source: web/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  mode: dev
+`
+
+const manifestV2 = `---
+# Source: web/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func manifestRelease(name string, revision int, manifest string) *release.Release {
+	return &release.Release{Name: name, Namespace: "apps", Version: revision, Manifest: manifest, Info: &release.Info{Status: release.StatusDeployed}}
+}
+
+// storedReleases returns a clientset holding releases, written by Helm's
+// Secret storage driver.
+func storedReleases(t *testing.T, releases ...*release.Release) *fake.Clientset {
+	clientSet := fake.NewClientset()
+	for _, rls := range releases {
+		secrets := driver.NewSecrets(clientSet.CoreV1().Secrets(rls.Namespace))
+		require.NoError(t, secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rls.Name, rls.Version), rls))
+	}
+	return clientSet
+}
+
+func TestReleaseManifest(t *testing.T) {
+	clientSet := storedReleases(t, manifestRelease("web", 1, manifestV1), manifestRelease("web", 2, manifestV2))
+
+	latest, err := latestRevision(clientSet, "apps", "web")
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest)
+
+	manifest, err := releaseManifest(clientSet, "apps", "web", 1)
+	require.NoError(t, err)
+	assert.Equal(t, manifestV1, manifest)
+
+	_, err = latestRevision(clientSet, "apps", "api")
+	assert.ErrorIs(t, err, driver.ErrReleaseNotFound)
+	_, err = releaseManifest(clientSet, "apps", "web", 3)
+	assert.ErrorIs(t, err, driver.ErrReleaseNotFound)
+}
+
+func TestDiffManifests(t *testing.T) {
+	got, err := diffManifests(manifestV1, manifestV2, 1, 2)
+	require.NoError(t, err)
+
+	assert.False(t, got.Identical)
+	assert.Contains(t, got.Diff, "--- revision 1\n+++ revision 2\n")
+	assert.Contains(t, got.Diff, "-  replicas: 1\n+  replicas: 3\n")
+	assert.Equal(t, []ResourceDiff{
+		{Kind: "ConfigMap", Name: "web-config", Type: helpers.DiffRemoved},
+		{Kind: "Deployment", Name: "web", Type: helpers.DiffChanged, Diffs: []helpers.FieldDiff{
+			{Path: "spec.replicas", Type: helpers.DiffChanged, Left: float64(1), Right: float64(3)},
+		}},
+		{Kind: "Service", Name: "web", Type: helpers.DiffAdded},
+	}, got.Resources)
+
+	same, err := diffManifests(manifestV1, manifestV1, 1, 1)
+	require.NoError(t, err)
+	assert.True(t, same.Identical)
+	assert.Empty(t, same.Resources)
+}
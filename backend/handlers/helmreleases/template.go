@@ -0,0 +1,157 @@
+package helmreleases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultReleaseName is the release name helm template uses.
+const defaultReleaseName = "release-name"
+
+// ChartRef points at a chart in a repository: "repo/chart" of a repository
+// added with helm repo add, a chart name with RepoURL, or an oci:// reference.
+type ChartRef struct {
+	Chart   string
+	Version string
+	RepoURL string
+}
+
+type RenderedChart struct {
+	Chart      string `json:"chart"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+	Manifest   string `json:"manifest"`
+}
+
+// TemplateChart renders a chart with the "values" form field, like helm
+// template, and returns the manifest. Rendering is client only, nothing is
+// sent to the cluster.
+func (h *HelmReleasesHandler) TemplateChart(c echo.Context) error {
+	ref := chartRef(c)
+	if ref.Chart == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "chart is required")
+	}
+	values, err := parseValues(c.FormValue("values"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	releaseName := c.FormValue("releaseName")
+	if releaseName == "" {
+		releaseName = defaultReleaseName
+	}
+	namespace := c.QueryParam("namespace")
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	chrt, err := h.loadChart(ref)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	manifest, err := renderChart(c.Request().Context(), chrt, releaseName, namespace, values, false)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, RenderedChart{
+		Chart:      chrt.Metadata.Name,
+		Version:    chrt.Metadata.Version,
+		AppVersion: chrt.Metadata.AppVersion,
+		Manifest:   manifest,
+	})
+}
+
+func chartRef(c echo.Context) ChartRef {
+	return ChartRef{
+		Chart:   strings.TrimSpace(c.FormValue("chart")),
+		Version: strings.TrimSpace(c.FormValue("version")),
+		RepoURL: strings.TrimSpace(c.FormValue("repoURL")),
+	}
+}
+
+func parseValues(raw string) (map[string]any, error) {
+	values := make(map[string]any)
+	if strings.TrimSpace(raw) == "" {
+		return values, nil
+	}
+	if err := yaml.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, fmt.Errorf("invalid values: %w", err)
+	}
+	return values, nil
+}
+
+// loadChart downloads the chart into the Helm repository cache and loads it.
+// Charts on the server's filesystem are refused, only repositories and
+// registries are read.
+func (h *HelmReleasesHandler) loadChart(ref ChartRef) (*chart.Chart, error) {
+	if !registry.IsOCI(ref.Chart) {
+		if filepath.IsAbs(ref.Chart) || strings.HasPrefix(ref.Chart, ".") {
+			return nil, fmt.Errorf("chart %q is a local path, use a repository or oci:// reference", ref.Chart)
+		}
+		if _, err := os.Stat(ref.Chart); err == nil {
+			return nil, fmt.Errorf("chart %q is a local path, use a repository or oci:// reference", ref.Chart)
+		}
+	}
+
+	install := action.NewInstall(&action.Configuration{})
+	install.SetRegistryClient(h.registryClient)
+	install.Version, install.RepoURL = ref.Version, ref.RepoURL
+	path, err := install.LocateChart(ref.Chart, h.settings)
+	if err != nil {
+		return nil, err
+	}
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if chrt.Metadata.Dependencies != nil {
+		if err := action.CheckDependencies(chrt, chrt.Metadata.Dependencies); err != nil {
+			return nil, err
+		}
+	}
+	return chrt, nil
+}
+
+// renderChart renders chrt as release name in namespace, client only, and
+// returns the release manifest. Hooks are left out, as in stored releases.
+func renderChart(ctx context.Context, chrt *chart.Chart, name, namespace string, values map[string]any, isUpgrade bool) (string, error) {
+	install := action.NewInstall(&action.Configuration{
+		Log: func(format string, v ...any) { log.Debug(fmt.Sprintf(format, v...)) },
+	})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IsUpgrade = isUpgrade
+	install.ReleaseName = name
+	install.Namespace = namespace
+	rls, err := install.RunWithContext(ctx, chrt, values)
+	if err != nil {
+		return "", err
+	}
+	if rls == nil {
+		return "", errors.New("chart rendered no release")
+	}
+	return rls.Manifest, nil
+}
+
+func newRegistryClient(settings *cli.EnvSettings) *registry.Client {
+	client, err := registry.NewClient(registry.ClientOptCredentialsFile(settings.RegistryConfig))
+	if err != nil {
+		log.Error("failed to create Helm registry client, oci:// charts are unavailable", "err", err)
+		return nil
+	}
+	return client
+}
@@ -0,0 +1,59 @@
+package helmreleases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  mode: {{ .Values.mode }}
+  upgrade: {{ .Release.IsUpgrade | quote }}
+`
+
+func testChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "web", Version: "0.1.0"},
+		Values:   map[string]any{"mode": "dev"},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte(configMapTemplate)},
+		},
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	manifest, err := renderChart(context.Background(), testChart(), "web", "apps", map[string]any{}, false)
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "name: web-config\n")
+	assert.Contains(t, manifest, "namespace: apps\n")
+	assert.Contains(t, manifest, "mode: dev\n")
+	assert.Contains(t, manifest, `upgrade: "false"`)
+
+	values, err := parseValues("mode: prod\n")
+	require.NoError(t, err)
+	manifest, err = renderChart(context.Background(), testChart(), "web", "apps", values, true)
+	require.NoError(t, err)
+	assert.Contains(t, manifest, "mode: prod\n")
+	assert.Contains(t, manifest, `upgrade: "true"`)
+
+	_, err = parseValues("mode: [")
+	assert.Error(t, err)
+}
+
+func TestLoadChartRefusesLocalPaths(t *testing.T) {
+	h := NewHelmReleasesHandler(container.NewContainer(&config.Env{}, config.NewAppConfig("test", ":0", 10, 10, false)))
+	for _, name := range []string{"/etc", "./charts/web", "../web", "."} {
+		_, err := h.loadChart(ChartRef{Chart: name})
+		assert.ErrorContains(t, err, "local path", name)
+	}
+}
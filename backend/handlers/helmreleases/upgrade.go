@@ -0,0 +1,130 @@
+package helmreleases
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	UpgradeDiffResource = "resource"
+	UpgradeDiffDone     = "done"
+)
+
+// UpgradeDiffEvent is sent on the upgrade diff stream: a "resource" event for
+// every object the upgrade adds, changes or removes, then a single "done".
+type UpgradeDiffEvent struct {
+	Type string `json:"type"`
+	*ResourceDiff
+	// Completed resources so far, out of Total.
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+	// The done event carries the counts, the unified diff of the manifests
+	// and the revision it was made against.
+	Revision  int    `json:"revision,omitempty"`
+	Added     int    `json:"added,omitempty"`
+	Changed   int    `json:"changed,omitempty"`
+	Removed   int    `json:"removed,omitempty"`
+	Identical bool   `json:"identical,omitempty"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// PostUpgradeDiff streams what upgrading a release would change, compared to
+// the manifest of its latest revision. The target is rendered here, client
+// only: the chart in the chart, version and repoURL form fields, or the
+// release's chart when chart is empty, with the "values" form field. With
+// reuseValues=true the values are merged over the release's ones, like
+// helm upgrade --reuse-values. Nothing is applied; it is the review step
+// before an upgrade.
+func (h *HelmReleasesHandler) PostUpgradeDiff(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	name := c.Param("name")
+
+	clientSet := h.container.ClientSet(config, cluster)
+	if clientSet == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	if namespace == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "namespace query param is required")
+	}
+	values, err := parseValues(c.FormValue("values"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	deployed, err := releaseStorage(clientSet, namespace).Last(name)
+	if err != nil {
+		return releaseError(err, fmt.Sprintf("release %s not found in namespace %s", name, namespace))
+	}
+	if c.FormValue("reuseValues") == "true" {
+		values = chartutil.CoalesceTables(values, deployed.Config)
+	}
+	chrt := deployed.Chart
+	if ref := chartRef(c); ref.Chart != "" {
+		if chrt, err = h.loadChart(ref); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	} else if chrt == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("revision %d of release %s has no chart, chart is required", deployed.Version, name))
+	}
+
+	ctx := c.Request().Context()
+	target, err := renderChart(ctx, chrt, name, namespace, values, true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("failed to render the upgrade: %s", err))
+	}
+	revision := deployed.Version
+	unified, resources, err := manifestDiff(deployed.Manifest, target, fmt.Sprintf("revision %d", revision), "upgrade")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-helm-upgrade-diff-%s-%s", config, cluster, namespace, name)
+	sseServer.CreateStream(streamKey)
+
+	go func() {
+		for _, e := range upgradeDiffEvents(revision, unified, resources) {
+			if ctx.Err() != nil {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Error("failed to marshal upgrade diff event", "err", err)
+				return
+			}
+			sseServer.Publish(streamKey, &sse.Event{Data: data})
+		}
+	}()
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func upgradeDiffEvents(revision int, unified string, resources []ResourceDiff) []UpgradeDiffEvent {
+	events := make([]UpgradeDiffEvent, 0, len(resources)+1)
+	done := UpgradeDiffEvent{Type: UpgradeDiffDone, Total: len(resources), Revision: revision, Identical: unified == "", Diff: unified}
+	for i := range resources {
+		switch resources[i].Type {
+		case helpers.DiffAdded:
+			done.Added++
+		case helpers.DiffChanged:
+			done.Changed++
+		case helpers.DiffRemoved:
+			done.Removed++
+		}
+		done.Completed++
+		events = append(events, UpgradeDiffEvent{Type: UpgradeDiffResource, ResourceDiff: &resources[i], Completed: done.Completed, Total: done.Total})
+	}
+	return append(events, done)
+}
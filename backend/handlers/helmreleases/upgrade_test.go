@@ -0,0 +1,60 @@
+package helmreleases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestUpgradeDiffEvents(t *testing.T) {
+	unified, resources, err := manifestDiff(manifestV1, manifestV2, "revision 1", "upgrade")
+	require.NoError(t, err)
+	assert.Contains(t, unified, "--- revision 1\n+++ upgrade\n")
+
+	events := upgradeDiffEvents(1, unified, resources)
+	require.Len(t, events, 4)
+	for i, e := range events[:3] {
+		assert.Equal(t, UpgradeDiffResource, e.Type)
+		assert.Equal(t, i+1, e.Completed)
+		assert.Equal(t, 3, e.Total)
+	}
+	assert.Equal(t, helpers.DiffRemoved, events[0].ResourceDiff.Type)
+	assert.Equal(t, UpgradeDiffEvent{
+		Type: UpgradeDiffDone, Completed: 3, Total: 3, Revision: 1,
+		Added: 1, Changed: 1, Removed: 1, Diff: unified,
+	}, events[3])
+
+	_, resources, err = manifestDiff(manifestV1, manifestV1, "revision 1", "upgrade")
+	require.NoError(t, err)
+	events = upgradeDiffEvents(1, "", resources)
+	assert.Equal(t, []UpgradeDiffEvent{{Type: UpgradeDiffDone, Revision: 1, Identical: true}}, events)
+}
+
+func TestUpgradeDiffRendersTarget(t *testing.T) {
+	deployedManifest, err := renderChart(context.Background(), testChart(), "web", "apps", map[string]any{}, false)
+	require.NoError(t, err)
+	clientSet := storedReleases(t, &release.Release{
+		Name: "web", Namespace: "apps", Version: 1, Manifest: deployedManifest, Chart: testChart(),
+		Info: &release.Info{Status: release.StatusDeployed},
+	})
+
+	deployed, err := releaseStorage(clientSet, "apps").Last("web")
+	require.NoError(t, err)
+	target, err := renderChart(context.Background(), deployed.Chart, "web", "apps", map[string]any{"mode": "prod"}, true)
+	require.NoError(t, err)
+
+	_, resources, err := manifestDiff(deployed.Manifest, target, "revision 1", "upgrade")
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, ResourceDiff{
+		Kind: "ConfigMap", Namespace: "apps", Name: "web-config", Type: helpers.DiffChanged,
+		Diffs: []helpers.FieldDiff{
+			{Path: "data.mode", Type: helpers.DiffChanged, Left: "dev", Right: "prod"},
+			{Path: "data.upgrade", Type: helpers.DiffChanged, Left: "false", Right: "true"},
+		},
+	}, resources[0])
+}
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// HumanizeDuration formats d the way kubectl prints ages, e.g. 45s, 12m, 5d3h, 2y.
+// Negative durations, from clock skew with the API server, read as 0s.
+func HumanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return duration.HumanDuration(d)
+}
+
+// HumanizeAge returns the age of a creation timestamp, or "" for a zero time.
+func HumanizeAge(created time.Time, now time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	return HumanizeDuration(now.Sub(created))
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{in: -5 * time.Second, want: "0s"},
+		{in: 0, want: "0s"},
+		{in: 59 * time.Second, want: "59s"},
+		{in: 119 * time.Second, want: "119s"},
+		{in: 2 * time.Minute, want: "2m"},
+		{in: 9*time.Minute + 30*time.Second, want: "9m30s"},
+		{in: 12 * time.Minute, want: "12m"},
+		{in: 179 * time.Minute, want: "179m"},
+		{in: 3 * time.Hour, want: "3h"},
+		{in: 7*time.Hour + 59*time.Minute, want: "7h59m"},
+		{in: 8 * time.Hour, want: "8h"},
+		{in: 47 * time.Hour, want: "47h"},
+		{in: 48 * time.Hour, want: "2d"},
+		{in: 5*24*time.Hour + 3*time.Hour, want: "5d3h"},
+		{in: 8 * 24 * time.Hour, want: "8d"},
+		{in: 364 * 24 * time.Hour, want: "364d"},
+		{in: 2 * 365 * 24 * time.Hour, want: "2y"},
+		{in: (3*365 + 10) * 24 * time.Hour, want: "3y10d"},
+		{in: 9 * 365 * 24 * time.Hour, want: "9y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, HumanizeDuration(tt.in))
+		})
+	}
+}
+
+func TestHumanizeAge(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "", HumanizeAge(time.Time{}, now))
+	assert.Equal(t, "9d", HumanizeAge(now.Add(-9*24*time.Hour), now))
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceVersion returns the resourceVersion a write is conditional on, from
+// ?resourceVersion= or an If-Match header. Empty makes the write
+// unconditional.
+func ResourceVersion(c echo.Context) string {
+	if rv := c.QueryParam("resourceVersion"); rv != "" {
+		return rv
+	}
+	return strings.Trim(c.Request().Header.Get("If-Match"), `"`)
+}
+
+// PreconditionPatch adds resourceVersion to a merge or strategic merge patch.
+// The API server then rejects the patch with a 409 Conflict if the object
+// changed since the client read it.
+func PreconditionPatch(patch map[string]any, resourceVersion string) map[string]any {
+	if resourceVersion == "" {
+		return patch
+	}
+	metadata, _ := patch["metadata"].(map[string]any)
+	if metadata == nil {
+		metadata = make(map[string]any)
+		patch["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = resourceVersion
+	return patch
+}
+
+// CheckResourceVersion returns a Conflict error when obj is no longer at the
+// resourceVersion the client read, so stale edits fail before validation.
+func CheckResourceVersion(obj metav1.Object, resource schema.GroupResource, resourceVersion string) error {
+	if resourceVersion == "" || obj.GetResourceVersion() == resourceVersion {
+		return nil
+	}
+	return apierrors.NewConflict(resource, obj.GetName(), fmt.Errorf("the object has been modified, resourceVersion is %s, not %s", obj.GetResourceVersion(), resourceVersion))
+}
+
+// WriteErrorStatus is the HTTP status a failed write is answered with.
+func WriteErrorStatus(err error) int {
+	switch {
+	case apierrors.IsConflict(err):
+		return http.StatusConflict
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
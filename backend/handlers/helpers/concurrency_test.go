@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceVersion(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/?resourceVersion=42", nil)
+	assert.Equal(t, "42", ResourceVersion(e.NewContext(req, httptest.NewRecorder())))
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("If-Match", `"43"`)
+	assert.Equal(t, "43", ResourceVersion(e.NewContext(req, httptest.NewRecorder())))
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.Equal(t, "", ResourceVersion(e.NewContext(req, httptest.NewRecorder())))
+}
+
+func TestPreconditionPatch(t *testing.T) {
+	patch := map[string]any{"spec": map[string]any{"replicas": 2}}
+	assert.Equal(t, map[string]any{"spec": map[string]any{"replicas": 2}}, PreconditionPatch(patch, ""))
+
+	patch = map[string]any{"metadata": map[string]any{"labels": map[string]string{"a": "b"}}}
+	assert.Equal(t, map[string]any{"metadata": map[string]any{
+		"labels":          map[string]string{"a": "b"},
+		"resourceVersion": "42",
+	}}, PreconditionPatch(patch, "42"))
+}
+
+func TestCheckResourceVersion(t *testing.T) {
+	pod := &coreV1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", ResourceVersion: "42"}}
+	assert.NoError(t, CheckResourceVersion(pod, coreV1.Resource("pods"), ""))
+	assert.NoError(t, CheckResourceVersion(pod, coreV1.Resource("pods"), "42"))
+
+	err := CheckResourceVersion(pod, coreV1.Resource("pods"), "41")
+	assert.True(t, apierrors.IsConflict(err))
+	assert.Equal(t, http.StatusConflict, WriteErrorStatus(err))
+	assert.Equal(t, http.StatusNotFound, WriteErrorStatus(apierrors.NewNotFound(coreV1.Resource("pods"), "web")))
+	assert.Equal(t, http.StatusBadRequest, WriteErrorStatus(errors.New("invalid")))
+}
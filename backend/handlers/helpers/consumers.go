@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type PodConsumer struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// References says how the pod refers to the object, e.g. "volume:config",
+	// "env:app/DB_PASSWORD", "envFrom:app" or "imagePullSecrets".
+	References []string `json:"references"`
+}
+
+// PodConsumers returns the pods in the pod informer whose spec references the
+// named ConfigMap or Secret. Pods can only reference objects in their own
+// namespace, so an empty namespace matches same-named objects across the
+// cluster.
+func PodConsumers(podInformer cache.SharedIndexInformer, kind, namespace, name string) []PodConsumer {
+	var items []any
+	if namespace != "" {
+		items, _ = podInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	} else {
+		items = podInformer.GetStore().List()
+	}
+
+	consumers := make([]PodConsumer, 0)
+	for _, obj := range items {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			continue
+		}
+		if refs := PodSpecReferences(&pod.Spec, kind, name); len(refs) > 0 {
+			consumers = append(consumers, PodConsumer{Namespace: pod.Namespace, Name: pod.Name, References: refs})
+		}
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].Namespace != consumers[j].Namespace {
+			return consumers[i].Namespace < consumers[j].Namespace
+		}
+		return consumers[i].Name < consumers[j].Name
+	})
+	return consumers
+}
+
+// podInformerSyncTimeout bounds how long a consumers request waits for the
+// pod informer to sync, like the handlers do at construction.
+const podInformerSyncTimeout = 30 * time.Second
+
+// PodInformer returns the shared pod informer of a cluster, started and
+// synced. It is the informer the pods handler uses; when that handler has not
+// set it up yet, it is registered with the same transform.
+func PodInformer(ctx context.Context, container container.Container, config, cluster string) cache.SharedIndexInformer {
+	factory := container.SharedInformerFactory(config, cluster)
+	informer := factory.Core().V1().Pods().Informer()
+	// Fails once the informer runs, the transform is set already then.
+	_ = informer.SetTransform(StripUnusedFields)
+	factory.Start(container.InformerStopCh(config, cluster))
+
+	ctx, cancel := context.WithTimeout(ctx, podInformerSyncTimeout)
+	defer cancel()
+	cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
+	return informer
+}
+
+// ServePodConsumers writes the consumers of the ConfigMap or Secret named in
+// the request path, scoped by ?namespace=.
+func ServePodConsumers(c echo.Context, podInformer cache.SharedIndexInformer, kind string) error {
+	if !podInformer.HasSynced() {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "pods are not loaded yet")
+	}
+	return c.JSON(http.StatusOK, PodConsumers(podInformer, kind, c.QueryParam("namespace"), c.Param("name")))
+}
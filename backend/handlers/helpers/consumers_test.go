@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestPodConsumers(t *testing.T) {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Pod{}, 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{{Name: "tls", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "creds"}}}},
+				Containers: []v1.Container{{
+					Name: "app",
+					Env: []v1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &v1.EnvVarSource{
+						SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}, Key: "password"},
+					}}},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+			Spec: v1.PodSpec{
+				ImagePullSecrets: []v1.LocalObjectReference{{Name: "creds"}},
+				Containers: []v1.Container{{
+					Name:    "app",
+					EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}}},
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "worker"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{
+					Name:    "app",
+					EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "creds"}}}},
+				}},
+			},
+		},
+	}
+	for _, pod := range pods {
+		require.NoError(t, informer.GetIndexer().Add(pod))
+	}
+
+	assert.Equal(t, []PodConsumer{
+		{Namespace: "default", Name: "api", References: []string{"imagePullSecrets"}},
+		{Namespace: "default", Name: "web", References: []string{"volume:tls", "env:app/DB_PASSWORD"}},
+	}, PodConsumers(informer, "Secret", "default", "creds"))
+
+	assert.Len(t, PodConsumers(informer, "Secret", "", "creds"), 3, "an empty namespace matches every namespace")
+	assert.Equal(t, []PodConsumer{
+		{Namespace: "default", Name: "api", References: []string{"envFrom:app"}},
+	}, PodConsumers(informer, "ConfigMap", "default", "settings"))
+	assert.Empty(t, PodConsumers(informer, "ConfigMap", "default", "creds"))
+}
+
+func TestServePodConsumersNotSynced(t *testing.T) {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Pod{}, 0, cache.Indexers{})
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	err := ServePodConsumers(c, informer, "Secret")
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.Code)
+}
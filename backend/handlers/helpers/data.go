@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"mime"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MaxInlineDataSize is the largest ConfigMap binaryData or Secret data value
+// sent inline on details streams. Larger values are fetched per key.
+const MaxInlineDataSize = 4 * 1024
+
+// OmitLargeValues returns data without the values over MaxInlineDataSize,
+// and the sizes of the values it left out.
+func OmitLargeValues(data map[string][]byte) (map[string][]byte, map[string]int) {
+	var omitted map[string]int
+	kept := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if len(v) > MaxInlineDataSize {
+			if omitted == nil {
+				omitted = make(map[string]int)
+			}
+			omitted[k] = len(v)
+			continue
+		}
+		kept[k] = v
+	}
+	return kept, omitted
+}
+
+// ServeDataValue writes a single ConfigMap or Secret value as a download,
+// served as text when it is valid UTF-8 and as octet-stream otherwise.
+func ServeDataValue(c echo.Context, key string, value []byte) error {
+	contentType, disposition := "application/octet-stream", "attachment"
+	if utf8.Valid(value) {
+		contentType, disposition = "text/plain; charset=utf-8", "inline"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": key}))
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.Blob(http.StatusOK, contentType, value)
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOmitLargeValues(t *testing.T) {
+	data := map[string][]byte{
+		"small": []byte("value"),
+		"large": make([]byte, MaxInlineDataSize+1),
+	}
+
+	kept, omitted := OmitLargeValues(data)
+	assert.Equal(t, map[string][]byte{"small": []byte("value")}, kept)
+	assert.Equal(t, map[string]int{"large": MaxInlineDataSize + 1}, omitted)
+
+	_, omitted = OmitLargeValues(map[string][]byte{"small": []byte("value")})
+	assert.Nil(t, omitted)
+}
+
+func TestServeDataValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       []byte
+		contentType string
+		disposition string
+	}{
+		{"text", []byte("key=value"), "text/plain; charset=utf-8", `inline; filename=app.conf`},
+		{"binary", []byte{0xff, 0xfe, 0x00}, "application/octet-stream", `attachment; filename=app.conf`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			assert.NoError(t, ServeDataValue(c, "app.conf", tt.value))
+			assert.Equal(t, tt.contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tt.disposition, rec.Header().Get(echo.HeaderContentDisposition))
+			assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+			assert.Equal(t, tt.value, rec.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// FieldDiff is one differing field between two objects. Path uses dots for
+// map keys and [i] for list indexes, e.g. spec.template.spec.containers[0].image.
+type FieldDiff struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Left  any    `json:"left,omitempty"`
+	Right any    `json:"right,omitempty"`
+}
+
+// PruneForDiff returns a copy of an unstructured object without the fields the
+// API server manages: status, and all of metadata except labels and
+// annotations.
+func PruneForDiff(obj map[string]any) map[string]any {
+	pruned := make(map[string]any, len(obj))
+	for k, v := range obj {
+		switch k {
+		case "status":
+		case "metadata":
+			meta, _ := v.(map[string]any)
+			kept := make(map[string]any)
+			for _, field := range []string{"labels", "annotations"} {
+				if value, ok := meta[field]; ok {
+					kept[field] = value
+				}
+			}
+			if annotations, ok := kept["annotations"].(map[string]any); ok {
+				copied := make(map[string]any, len(annotations))
+				for k, v := range annotations {
+					if k != "kubectl.kubernetes.io/last-applied-configuration" && k != "deployment.kubernetes.io/revision" {
+						copied[k] = v
+					}
+				}
+				kept["annotations"] = copied
+			}
+			pruned[k] = kept
+		default:
+			pruned[k] = v
+		}
+	}
+	return pruned
+}
+
+// Diff returns the field-level differences between two unstructured values,
+// sorted by path.
+func Diff(left, right any) []FieldDiff {
+	diffs := make([]FieldDiff, 0)
+	diff("", left, right, &diffs)
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+func diff(path string, left, right any, diffs *[]FieldDiff) {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			for k, lv := range l {
+				rv, exists := r[k]
+				if !exists {
+					*diffs = append(*diffs, FieldDiff{Path: joinPath(path, k), Type: DiffRemoved, Left: lv})
+					continue
+				}
+				diff(joinPath(path, k), lv, rv, diffs)
+			}
+			for k, rv := range r {
+				if _, exists := l[k]; !exists {
+					*diffs = append(*diffs, FieldDiff{Path: joinPath(path, k), Type: DiffAdded, Right: rv})
+				}
+			}
+			return
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			for i := 0; i < len(l) || i < len(r); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(r):
+					*diffs = append(*diffs, FieldDiff{Path: p, Type: DiffRemoved, Left: l[i]})
+				case i >= len(l):
+					*diffs = append(*diffs, FieldDiff{Path: p, Type: DiffAdded, Right: r[i]})
+				default:
+					diff(p, l[i], r[i], diffs)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Type: DiffChanged, Left: left, Right: right})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneForDiff(t *testing.T) {
+	obj := map[string]any{
+		"kind": "ConfigMap",
+		"metadata": map[string]any{
+			"name":            "app",
+			"resourceVersion": "12",
+			"managedFields":   []any{map[string]any{"manager": "kubectl"}},
+			"labels":          map[string]any{"app": "web"},
+			"annotations":     map[string]any{"kubectl.kubernetes.io/last-applied-configuration": "{}", "team": "a"},
+		},
+		"data":   map[string]any{"key": "value"},
+		"status": map[string]any{"phase": "Active"},
+	}
+
+	assert.Equal(t, map[string]any{
+		"kind": "ConfigMap",
+		"metadata": map[string]any{
+			"labels":      map[string]any{"app": "web"},
+			"annotations": map[string]any{"team": "a"},
+		},
+		"data": map[string]any{"key": "value"},
+	}, PruneForDiff(obj))
+}
+
+func TestDiff(t *testing.T) {
+	left := map[string]any{
+		"spec": map[string]any{
+			"replicas":   int64(2),
+			"containers": []any{map[string]any{"image": "app:1"}, map[string]any{"image": "sidecar:1"}},
+			"paused":     true,
+		},
+	}
+	right := map[string]any{
+		"spec": map[string]any{
+			"replicas":   int64(3),
+			"containers": []any{map[string]any{"image": "app:2"}},
+			"strategy":   "Recreate",
+		},
+	}
+
+	assert.Equal(t, []FieldDiff{
+		{Path: "spec.containers[0].image", Type: DiffChanged, Left: "app:1", Right: "app:2"},
+		{Path: "spec.containers[1]", Type: DiffRemoved, Left: map[string]any{"image": "sidecar:1"}},
+		{Path: "spec.paused", Type: DiffRemoved, Left: true},
+		{Path: "spec.replicas", Type: DiffChanged, Left: int64(2), Right: int64(3)},
+		{Path: "spec.strategy", Type: DiffAdded, Right: "Recreate"},
+	}, Diff(left, right))
+
+	assert.Empty(t, Diff(left, left))
+}
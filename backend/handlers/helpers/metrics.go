@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// MetricsStaleWindow is how long a last-known sample may stand in for one the
+// metrics API failed to return.
+const MetricsStaleWindow = 2 * time.Minute
+
+// MetricsCache keeps the last-known metrics sample per object so a failed or
+// partial metrics response degrades to slightly old values instead of zeros.
+type MetricsCache[T any] struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	entries map[string]metricsEntry[T]
+}
+
+type metricsEntry[T any] struct {
+	value     T
+	fetchedAt time.Time
+}
+
+func NewMetricsCache[T any](window time.Duration) *MetricsCache[T] {
+	return &MetricsCache[T]{window: window, now: time.Now, entries: make(map[string]metricsEntry[T])}
+}
+
+// Merge records the samples of a metrics fetch and returns them together with
+// the last-known samples of objects missing from it. When err is set the whole
+// fetch failed and only last-known samples are returned. The second map marks
+// which of the returned keys are stale.
+func (c *MetricsCache[T]) Merge(fresh map[string]T, err error) (map[string]T, map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	items := make(map[string]T, len(c.entries))
+	stale := make(map[string]bool)
+	if err == nil {
+		for key, value := range fresh {
+			c.entries[key] = metricsEntry[T]{value: value, fetchedAt: now}
+			items[key] = value
+		}
+	}
+
+	for key, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > c.window {
+			delete(c.entries, key)
+			continue
+		}
+		if _, ok := items[key]; !ok {
+			items[key] = entry.value
+			stale[key] = true
+		}
+	}
+	return items, stale
+}
+
+// Prune drops the last-known samples of objects that are gone from store, so
+// deleted pods and nodes aren't reported until the stale window runs out.
+// Keys are store keys: namespace/name, or name for cluster scoped objects.
+func (c *MetricsCache[T]) Prune(store cache.Store) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if _, exists, err := store.GetByKey(key); err == nil && !exists {
+			delete(c.entries, key)
+		}
+	}
+}
+
+var (
+	podMetricsCaches  sync.Map
+	nodeMetricsCaches sync.Map
+)
+
+// LastKnownPodMetrics returns the pod metrics cache of a cluster, keyed by
+// namespace/name.
+func LastKnownPodMetrics(config, cluster string) *MetricsCache[v1beta1.PodMetrics] {
+	c, _ := podMetricsCaches.LoadOrStore(fmt.Sprintf("%s-%s", config, cluster), NewMetricsCache[v1beta1.PodMetrics](MetricsStaleWindow))
+	return c.(*MetricsCache[v1beta1.PodMetrics])
+}
+
+// LastKnownNodeMetrics returns the node metrics cache of a cluster, keyed by
+// node name.
+func LastKnownNodeMetrics(config, cluster string) *MetricsCache[v1beta1.NodeMetrics] {
+	c, _ := nodeMetricsCaches.LoadOrStore(fmt.Sprintf("%s-%s", config, cluster), NewMetricsCache[v1beta1.NodeMetrics](MetricsStaleWindow))
+	return c.(*MetricsCache[v1beta1.NodeMetrics])
+}
@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestMetricsCacheMerge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMetricsCache[int](time.Minute)
+	c.now = func() time.Time { return now }
+	unavailable := errors.New("the server is currently unable to handle the request")
+
+	items, stale := c.Merge(map[string]int{"a": 1, "b": 2}, nil)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, items)
+	assert.Empty(t, stale)
+
+	// The whole call fails, last-known values are served as stale.
+	now = now.Add(20 * time.Second)
+	items, stale = c.Merge(nil, unavailable)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, items)
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, stale)
+
+	// A partial response only falls back for what is missing.
+	now = now.Add(20 * time.Second)
+	items, stale = c.Merge(map[string]int{"a": 5}, nil)
+	assert.Equal(t, map[string]int{"a": 5, "b": 2}, items)
+	assert.Equal(t, map[string]bool{"b": true}, stale)
+
+	// Past the window, stale values are dropped rather than served.
+	now = now.Add(30 * time.Second)
+	items, stale = c.Merge(nil, unavailable)
+	assert.Equal(t, map[string]int{"a": 5}, items)
+	assert.Equal(t, map[string]bool{"a": true}, stale)
+
+	now = now.Add(2 * time.Minute)
+	items, stale = c.Merge(nil, unavailable)
+	assert.Empty(t, items)
+	assert.Empty(t, stale)
+}
+
+func TestMetricsCachePrune(t *testing.T) {
+	c := NewMetricsCache[int](time.Minute)
+	c.Merge(map[string]int{"apps/api": 1, "apps/web": 2, "node-a": 3}, nil)
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	assert.NoError(t, store.Add(&metav1.ObjectMeta{Namespace: "apps", Name: "api"}))
+	assert.NoError(t, store.Add(&metav1.ObjectMeta{Name: "node-a"}))
+	c.Prune(store)
+
+	// The deleted pod is no longer served as stale, fresh samples still are.
+	items, stale := c.Merge(map[string]int{"apps/new": 4}, nil)
+	assert.Equal(t, map[string]int{"apps/api": 1, "apps/new": 4, "node-a": 3}, items)
+	assert.Equal(t, map[string]bool{"apps/api": true, "node-a": true}, stale)
+}
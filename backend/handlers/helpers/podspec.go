@@ -0,0 +1,150 @@
+package helpers
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+type containerEnv struct {
+	name    string
+	env     []v1.EnvVar
+	envFrom []v1.EnvFromSource
+}
+
+// PodSpecReferences lists how a pod spec references the named ConfigMap,
+// Secret or PersistentVolumeClaim, e.g. "volume:config",
+// "env:app/DB_PASSWORD", "envFrom:app" or "imagePullSecrets". It returns nil
+// when the spec does not reference it. kind must be one of ConfigMap, Secret
+// or PersistentVolumeClaim.
+func PodSpecReferences(spec *v1.PodSpec, kind, name string) []string {
+	var refs []string
+
+	for _, volume := range spec.Volumes {
+		if volumeReferences(volume, kind, name) {
+			refs = append(refs, "volume:"+volume.Name)
+		}
+	}
+
+	if kind == "PersistentVolumeClaim" {
+		return refs
+	}
+
+	containers := make([]containerEnv, 0, len(spec.InitContainers)+len(spec.Containers)+len(spec.EphemeralContainers))
+	for _, c := range spec.InitContainers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+	for _, c := range spec.Containers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+	for _, c := range spec.EphemeralContainers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+
+	for _, c := range containers {
+		for _, env := range c.env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if (kind == "ConfigMap" && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name) ||
+				(kind == "Secret" && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name) {
+				refs = append(refs, fmt.Sprintf("env:%s/%s", c.name, env.Name))
+			}
+		}
+		for _, envFrom := range c.envFrom {
+			if (kind == "ConfigMap" && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name) ||
+				(kind == "Secret" && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name) {
+				refs = append(refs, "envFrom:"+c.name)
+			}
+		}
+	}
+
+	if kind == "Secret" {
+		for _, s := range spec.ImagePullSecrets {
+			if s.Name == name {
+				refs = append(refs, "imagePullSecrets")
+			}
+		}
+	}
+
+	return refs
+}
+
+func volumeReferences(volume v1.Volume, kind, name string) bool {
+	switch kind {
+	case "ConfigMap":
+		if volume.ConfigMap != nil && volume.ConfigMap.Name == name {
+			return true
+		}
+	case "Secret":
+		if volume.Secret != nil && volume.Secret.SecretName == name {
+			return true
+		}
+	case "PersistentVolumeClaim":
+		return volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == name
+	}
+
+	if volume.Projected != nil {
+		for _, source := range volume.Projected.Sources {
+			if (kind == "ConfigMap" && source.ConfigMap != nil && source.ConfigMap.Name == name) ||
+				(kind == "Secret" && source.Secret != nil && source.Secret.Name == name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PodRequests returns the effective resource requests of a pod the way the
+// scheduler computes them: the larger of the app containers plus sidecars and
+// the biggest init container step, plus pod overhead.
+func PodRequests(spec *v1.PodSpec) v1.ResourceList {
+	return podResources(spec, func(r v1.ResourceRequirements) v1.ResourceList { return r.Requests })
+}
+
+// PodLimits returns the effective resource limits of a pod, computed like
+// PodRequests. Quota charges limits.* this way.
+func PodLimits(spec *v1.PodSpec) v1.ResourceList {
+	return podResources(spec, func(r v1.ResourceRequirements) v1.ResourceList { return r.Limits })
+}
+
+func podResources(spec *v1.PodSpec, pick func(v1.ResourceRequirements) v1.ResourceList) v1.ResourceList {
+	total := v1.ResourceList{}
+	for _, c := range spec.Containers {
+		addResources(total, pick(c.Resources))
+	}
+
+	sidecars := v1.ResourceList{}
+	initMax := v1.ResourceList{}
+	for _, c := range spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			addResources(sidecars, pick(c.Resources))
+			maxResources(initMax, sidecars)
+			continue
+		}
+		step := sidecars.DeepCopy()
+		addResources(step, pick(c.Resources))
+		maxResources(initMax, step)
+	}
+
+	addResources(total, sidecars)
+	maxResources(total, initMax)
+	addResources(total, spec.Overhead)
+	return total
+}
+
+func addResources(total, add v1.ResourceList) {
+	for name, quantity := range add {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, other v1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
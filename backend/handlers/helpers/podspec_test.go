@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodSpecReferences(t *testing.T) {
+	spec := &v1.PodSpec{
+		Volumes: []v1.Volume{
+			{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}}},
+			{Name: "certs", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+				{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "tls"}}},
+			}}}},
+			{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+		},
+		InitContainers: []v1.Container{{
+			Name:    "migrate",
+			EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "tls"}}}},
+		}},
+		Containers: []v1.Container{{
+			Name: "app",
+			Env: []v1.EnvVar{{Name: "MODE", ValueFrom: &v1.EnvVarSource{
+				ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "app"}, Key: "mode"},
+			}}},
+		}},
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "tls"}},
+	}
+
+	assert.Equal(t, []string{"volume:config", "env:app/MODE"}, PodSpecReferences(spec, "ConfigMap", "app"))
+	assert.Equal(t, []string{"volume:certs", "envFrom:migrate", "imagePullSecrets"}, PodSpecReferences(spec, "Secret", "tls"))
+	assert.Equal(t, []string{"volume:data"}, PodSpecReferences(spec, "PersistentVolumeClaim", "data"))
+	assert.Nil(t, PodSpecReferences(spec, "Secret", "app"))
+}
+
+func TestPodRequests(t *testing.T) {
+	requests := func(cpu, memory string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	always := v1.ContainerRestartPolicyAlways
+
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Name: "proxy", RestartPolicy: &always, Resources: requests("100m", "64Mi")},
+			{Name: "migrate", Resources: requests("1", "128Mi")},
+		},
+		Containers: []v1.Container{
+			{Name: "app", Resources: requests("250m", "256Mi")},
+			{Name: "worker", Resources: requests("250m", "256Mi")},
+		},
+		Overhead: v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m")},
+	}
+	got := PodRequests(spec)
+	// CPU: init step proxy+migrate (1100m) beats app+worker+proxy (600m).
+	assert.Equal(t, int64(1110), got.Cpu().MilliValue())
+	// Memory: app+worker+proxy (576Mi) beats proxy+migrate (192Mi).
+	assert.Equal(t, int64(576<<20), got.Memory().Value())
+}
+
+func TestPodLimits(t *testing.T) {
+	limits := func(cpu string) v1.ResourceRequirements {
+		return v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}}
+	}
+	spec := &v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "migrate", Resources: limits("2")}},
+		Containers:     []v1.Container{{Name: "app", Resources: limits("500m")}, {Name: "worker", Resources: limits("1")}},
+	}
+	got := PodLimits(spec)
+	assert.Equal(t, int64(2000), got.Cpu().MilliValue())
+	assert.Empty(t, PodRequests(spec))
+}
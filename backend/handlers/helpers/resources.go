@@ -2,10 +2,13 @@ package helpers
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 )
 
@@ -76,3 +79,13 @@ func FindResourceByKind(container container.Container, config, cluster, kind str
 	}
 	return Resource{}, false
 }
+
+// CheckResourceAllowed returns a 403 error when the allow and block lists of
+// the app config keep the generic resource endpoints from serving gr. It does
+// not replace RBAC, it only narrows what the dashboard shows.
+func CheckResourceAllowed(container container.Container, gr schema.GroupResource) error {
+	if container.Config().ResourceAllowed(gr) {
+		return nil
+	}
+	return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("%s is not allowed by the server configuration", gr))
+}
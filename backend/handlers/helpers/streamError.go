@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/r3labs/sse/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// StreamErrorEvent is the SSE event name of StreamError payloads. It is not
+// "error", which EventSource reserves for connection failures.
+const StreamErrorEvent = "streamError"
+
+const (
+	defaultRetryAfter  = 3 * time.Second
+	throttleRetryAfter = 10 * time.Second
+)
+
+// StreamError tells stream clients whether reconnecting can help, and how
+// long to wait first, so transient failures do not cause reconnect storms
+// and terminal ones do not cause endless retries.
+type StreamError struct {
+	Message      string `json:"message"`
+	Code         int32  `json:"code,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Retryable    bool   `json:"retryable"`
+	RetryAfterMs int64  `json:"retryAfterMs,omitempty"`
+}
+
+// NewStreamError classifies err. Timeouts, throttling, unavailable or
+// failing API servers and dropped connections are retryable, everything else,
+// e.g. Forbidden or NotFound, is terminal.
+func NewStreamError(err error) StreamError {
+	streamError := StreamError{Message: err.Error()}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		streamError.Code = status.Status().Code
+		streamError.Reason = string(status.Status().Reason)
+	}
+
+	var retryAfter time.Duration
+	switch {
+	case apierrors.IsTooManyRequests(err):
+		retryAfter = throttleRetryAfter
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsServiceUnavailable(err),
+		apierrors.IsInternalError(err), apierrors.IsResourceExpired(err), apierrors.IsGone(err),
+		errors.Is(err, context.DeadlineExceeded), isNetworkError(err):
+		retryAfter = defaultRetryAfter
+	default:
+		return streamError
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	streamError.Retryable = true
+	streamError.RetryAfterMs = retryAfter.Milliseconds()
+	return streamError
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return (errors.As(err, &netErr) && netErr.Timeout()) ||
+		utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+}
+
+// SSEEvent is the error as a streamError event. Retryable errors also set
+// the event's retry field, which EventSource uses as its reconnection delay.
+func (e StreamError) SSEEvent() *sse.Event {
+	data, _ := json.Marshal(e)
+	event := &sse.Event{Event: []byte(StreamErrorEvent), Data: data}
+	if e.Retryable {
+		event.Retry = []byte(strconv.FormatInt(e.RetryAfterMs, 10))
+	}
+	return event
+}
@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewStreamError(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name         string
+		err          error
+		retryable    bool
+		retryAfterMs int64
+		code         int32
+	}{
+		{"forbidden", apierrors.NewForbidden(pods, "", errors.New("no access")), false, 0, 403},
+		{"not found", apierrors.NewNotFound(pods, "web"), false, 0, 404},
+		{"throttled", apierrors.NewTooManyRequests("slow down", 0), true, 10000, 429},
+		{"throttled with delay", apierrors.NewTooManyRequests("slow down", 30), true, 30000, 429},
+		{"unavailable", apierrors.NewServiceUnavailable("restarting"), true, 3000, 503},
+		{"server timeout", apierrors.NewTimeoutError("watch", 0), true, 3000, 504},
+		{"deadline", fmt.Errorf("list pods: %w", context.DeadlineExceeded), true, 3000, 0},
+		{"dropped connection", io.ErrUnexpectedEOF, true, 3000, 0},
+		{"other", errors.New("boom"), false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewStreamError(tt.err)
+			assert.Equal(t, tt.retryable, got.Retryable)
+			assert.Equal(t, tt.retryAfterMs, got.RetryAfterMs)
+			assert.Equal(t, tt.code, got.Code)
+			assert.Equal(t, tt.err.Error(), got.Message)
+		})
+	}
+}
+
+func TestStreamErrorSSEEvent(t *testing.T) {
+	event := NewStreamError(apierrors.NewServiceUnavailable("restarting")).SSEEvent()
+	assert.Equal(t, StreamErrorEvent, string(event.Event))
+	assert.Equal(t, "3000", string(event.Retry))
+	assert.Contains(t, string(event.Data), `"retryable":true`)
+
+	event = NewStreamError(errors.New("boom")).SSEEvent()
+	assert.Empty(t, event.Retry)
+}
@@ -0,0 +1,84 @@
+package helpers
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrTooManyViews = errors.New("too many distinct views of this stream are open")
+
+// StreamViews tracks the per-request variants of a stream, e.g. a list with
+// some namespaces excluded, keyed by their stream ID. Each is kept while a
+// client is subscribed to it, so variants made up by query params don't
+// accumulate and get published to for the life of the process.
+type StreamViews[V any] struct {
+	mu    sync.Mutex
+	max   int
+	views map[string]*streamView[V]
+	// onRemove is called with the lock held when the last client of a view
+	// left, before a new client of it can subscribe.
+	onRemove func(id string)
+}
+
+type streamView[V any] struct {
+	value       V
+	subscribers int
+}
+
+// NewStreamViews returns a StreamViews holding at most max views, any number
+// when max is zero; onRemove may be nil.
+func NewStreamViews[V any](max int, onRemove func(id string)) *StreamViews[V] {
+	return &StreamViews[V]{max: max, views: make(map[string]*streamView[V]), onRemove: onRemove}
+}
+
+// Acquire subscribes a client to the view id, adding it with value if it is
+// new. release must be called once the client left. It fails with
+// ErrTooManyViews when adding the view would exceed the limit.
+func (s *StreamViews[V]) Acquire(id string, value V) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	view, ok := s.views[id]
+	if !ok {
+		if s.max > 0 && len(s.views) >= s.max {
+			return nil, ErrTooManyViews
+		}
+		view = &streamView[V]{value: value}
+		s.views[id] = view
+	}
+	view.subscribers++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if view.subscribers--; view.subscribers == 0 {
+				delete(s.views, id)
+				if s.onRemove != nil {
+					s.onRemove(id)
+				}
+			}
+		})
+	}, nil
+}
+
+// Range calls f for every view with subscribers, on a snapshot taken first.
+func (s *StreamViews[V]) Range(f func(id string, value V)) {
+	s.mu.Lock()
+	snapshot := make(map[string]V, len(s.views))
+	for id, view := range s.views {
+		snapshot[id] = view.value
+	}
+	s.mu.Unlock()
+
+	for id, value := range snapshot {
+		f(id, value)
+	}
+}
+
+// Len returns the number of views with subscribers.
+func (s *StreamViews[V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.views)
+}
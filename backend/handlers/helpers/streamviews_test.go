@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamViews(t *testing.T) {
+	var removed []string
+	views := NewStreamViews[string](2, func(id string) { removed = append(removed, id) })
+
+	releaseA1, err := views.Acquire("a", "first")
+	require.NoError(t, err)
+	releaseA2, err := views.Acquire("a", "second")
+	require.NoError(t, err)
+	releaseB, err := views.Acquire("b", "b")
+	require.NoError(t, err)
+	_, err = views.Acquire("c", "c")
+	assert.ErrorIs(t, err, ErrTooManyViews)
+
+	got := make(map[string]string)
+	views.Range(func(id, value string) { got[id] = value })
+	assert.Equal(t, map[string]string{"a": "first", "b": "b"}, got)
+
+	releaseA1()
+	releaseA1()
+	assert.Equal(t, 2, views.Len())
+	assert.Empty(t, removed)
+	releaseA2()
+	assert.Equal(t, []string{"a"}, removed)
+
+	releaseC, err := views.Acquire("c", "c")
+	require.NoError(t, err)
+	releaseB()
+	releaseC()
+	assert.Zero(t, views.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, removed)
+}
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+const defaultTopLimit = 10
+
+// TopQuery holds the ?sortBy=cpu|memory and ?limit= params of the top endpoints.
+type TopQuery struct {
+	SortBy string
+	Limit  int
+}
+
+func ParseTopQuery(c echo.Context) (TopQuery, error) {
+	q := TopQuery{SortBy: c.QueryParam("sortBy"), Limit: defaultTopLimit}
+	switch q.SortBy {
+	case "":
+		q.SortBy = "cpu"
+	case "cpu", "memory":
+	default:
+		return q, echo.NewHTTPError(http.StatusBadRequest, "sortBy must be cpu or memory")
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return q, echo.NewHTTPError(http.StatusBadRequest, "limit must be a positive integer")
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// TopN sorts items by the queried usage, highest first, and keeps the first
+// Limit entries.
+func TopN[T any](items []T, q TopQuery, cpu, memory func(T) int64) []T {
+	usage := cpu
+	if q.SortBy == "memory" {
+		usage = memory
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return usage(items[i]) > usage(items[j])
+	})
+	if len(items) > q.Limit {
+		items = items[:q.Limit]
+	}
+	return items
+}
+
+// IsMetricServerAvailable reports whether discovery found metrics.k8s.io on the cluster.
+func IsMetricServerAvailable(container container.Container, config, cluster string) bool {
+	value, exists := container.Cache().GetIfPresent(fmt.Sprintf(IsMetricServerAvailableCacheKeyFormat, config, cluster))
+	available, ok := value.(bool)
+	return exists && ok && available
+}
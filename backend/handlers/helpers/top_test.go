@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTopQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    TopQuery
+		wantErr bool
+	}{
+		{query: "", want: TopQuery{SortBy: "cpu", Limit: defaultTopLimit}},
+		{query: "?sortBy=memory&limit=3", want: TopQuery{SortBy: "memory", Limit: 3}},
+		{query: "?sortBy=disk", wantErr: true},
+		{query: "?limit=0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/"+tt.query, nil), httptest.NewRecorder())
+			got, err := ParseTopQuery(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTopN(t *testing.T) {
+	type usage struct{ cpu, memory int64 }
+	items := []usage{{cpu: 1, memory: 30}, {cpu: 3, memory: 10}, {cpu: 2, memory: 20}}
+	cpu := func(u usage) int64 { return u.cpu }
+	memory := func(u usage) int64 { return u.memory }
+
+	assert.Equal(t, []usage{{3, 10}, {2, 20}}, TopN(append([]usage{}, items...), TopQuery{SortBy: "cpu", Limit: 2}, cpu, memory))
+	assert.Equal(t, []usage{{1, 30}, {2, 20}, {3, 10}}, TopN(append([]usage{}, items...), TopQuery{SortBy: "memory", Limit: 5}, cpu, memory))
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"time"
+
+	"github.com/charmbracelet/log"
+	cfg "github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+)
+
+// WarmClients runs API discovery for every cluster of a kubeconfig in the
+// background when --warm-clients is set. The clients of a cluster are built
+// when its kubeconfig is loaded, but the TLS handshake and discovery are
+// otherwise paid for by the first request to it. Clusters whose exec
+// credential plugin needs an interactive login are left for that request.
+func WarmClients(container container.Container, config string) {
+	if !container.Config().WarmClients {
+		return
+	}
+	kubeConfig, ok := container.Config().GetKubeConfigInfo(config)
+	if !ok || kubeConfig == nil {
+		return
+	}
+	for cluster, conn := range kubeConfig.Clusters {
+		go func() {
+			if err := conn.CheckExecAuth(cfg.ExecAuthTimeout); err != nil {
+				log.Debug("skipping client warm-up", "config", config, "cluster", cluster, "err", err)
+				return
+			}
+			start := time.Now()
+			if err := CacheAllResources(container, config, cluster); err != nil {
+				log.Warn("failed to warm up clients", "config", config, "cluster", cluster, "err", err)
+				return
+			}
+			log.Info("warmed up clients", "config", config, "cluster", cluster, "duration", time.Since(start))
+		}()
+	}
+}
+
+// WarmAllClients warms the clients of every loaded kubeconfig, see WarmClients.
+func WarmAllClients(container container.Container) {
+	for _, summary := range container.Config().Summaries() {
+		WarmClients(container, summary.ID)
+	}
+}
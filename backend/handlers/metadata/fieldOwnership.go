@@ -0,0 +1,104 @@
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// FieldManager is one entry of metadata.managedFields. A manager that both
+// updated the object and its status has an entry for each.
+type FieldManager struct {
+	Manager     string       `json:"manager"`
+	Operation   string       `json:"operation"`
+	Subresource string       `json:"subresource,omitempty"`
+	APIVersion  string       `json:"apiVersion"`
+	Time        *metav1.Time `json:"time,omitempty"`
+	// Fields is the number of leaf fields the entry owns.
+	Fields int `json:"fields"`
+}
+
+type FieldOwnership struct {
+	// Fields maps the path of every owned leaf field, e.g.
+	// .spec.containers[name="app"].image, to its managers. Fields set by
+	// server-side apply can be shared by several.
+	Fields   map[string][]string `json:"fields"`
+	Managers []FieldManager      `json:"managers"`
+}
+
+// GetFieldOwnership returns which field managers, e.g. kubectl, a GitOps
+// controller or the controller-manager, set each field of an object of any
+// resource, from its managedFields.
+func (h *MetadataHandler) GetFieldOwnership(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	if c.QueryParam("version") == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required")
+	}
+
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if dynamicClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, err := resource.Get(c.Request().Context(), c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	ownership, err := fieldOwnership(obj.GetManagedFields())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, ownership)
+}
+
+// fieldOwnership flattens the field sets of managedFields to their leaves.
+// Parent fields are only listed in a set to mark that the manager created
+// them, so they are left out.
+func fieldOwnership(entries []metav1.ManagedFieldsEntry) (FieldOwnership, error) {
+	ownership := FieldOwnership{Fields: make(map[string][]string), Managers: make([]FieldManager, 0, len(entries))}
+	for _, entry := range entries {
+		manager := FieldManager{
+			Manager:     entry.Manager,
+			Operation:   string(entry.Operation),
+			Subresource: entry.Subresource,
+			APIVersion:  entry.APIVersion,
+			Time:        entry.Time,
+		}
+		if entry.FieldsV1 != nil {
+			set := &fieldpath.Set{}
+			if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+				return FieldOwnership{}, fmt.Errorf("invalid managedFields of %s: %w", entry.Manager, err)
+			}
+			set.Leaves().Iterate(func(path fieldpath.Path) {
+				manager.Fields++
+				key := path.String()
+				if !slices.Contains(ownership.Fields[key], entry.Manager) {
+					ownership.Fields[key] = append(ownership.Fields[key], entry.Manager)
+				}
+			})
+		}
+		ownership.Managers = append(ownership.Managers, manager)
+	}
+	for _, managers := range ownership.Fields {
+		slices.Sort(managers)
+	}
+	return ownership, nil
+}
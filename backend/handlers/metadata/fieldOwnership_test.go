@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFieldOwnership(t *testing.T) {
+	entries := []metav1.ManagedFieldsEntry{
+		{
+			Manager:    "argocd-controller",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "apps/v1",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:replicas":{},` +
+				`"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`)},
+		},
+		{
+			Manager:    "kubectl",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: "apps/v1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{},"f:team":{}}}}`)},
+		},
+		{
+			Manager:     "kube-controller-manager",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			APIVersion:  "apps/v1",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicas":{}}}`)},
+		},
+	}
+
+	ownership, err := fieldOwnership(entries)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		".metadata.labels.app":                             {"argocd-controller", "kubectl"},
+		".metadata.labels.team":                            {"kubectl"},
+		".spec.replicas":                                   {"argocd-controller"},
+		`.spec.template.spec.containers[name="app"].image`: {"argocd-controller"},
+		`.spec.template.spec.containers[name="app"].name`:  {"argocd-controller"},
+		".status.replicas":                                 {"kube-controller-manager"},
+	}, ownership.Fields)
+	require.Len(t, ownership.Managers, 3)
+	assert.Equal(t, 4, ownership.Managers[0].Fields)
+	assert.Equal(t, "Apply", ownership.Managers[1].Operation)
+	assert.Equal(t, "status", ownership.Managers[2].Subresource)
+
+	_, err = fieldOwnership([]metav1.ManagedFieldsEntry{{Manager: "broken", FieldsV1: &metav1.FieldsV1{Raw: []byte(`[`)}}})
+	assert.Error(t, err)
+}
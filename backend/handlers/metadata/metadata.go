@@ -0,0 +1,155 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	labelsField      = "labels"
+	annotationsField = "annotations"
+)
+
+type MetadataHandler struct {
+	container container.Container
+}
+
+func NewMetadataHandler(container container.Container) *MetadataHandler {
+	return &MetadataHandler{container: container}
+}
+
+// SetLabels patches the labels of an object of any resource with the JSON
+// object in the body, where null removes a key. With ?mode=replace the
+// labels not in the body are removed as well. A resourceVersion makes the
+// update conditional.
+func (h *MetadataHandler) SetLabels(c echo.Context) error {
+	return h.set(c, labelsField)
+}
+
+// SetAnnotations is SetLabels for annotations.
+func (h *MetadataHandler) SetAnnotations(c echo.Context) error {
+	return h.set(c, annotationsField)
+}
+
+func (h *MetadataHandler) set(c echo.Context, metadataField string) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	if c.QueryParam("version") == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required")
+	}
+	var replace bool
+	switch c.QueryParam("mode") {
+	case "", "merge":
+	case "replace":
+		replace = true
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid mode %q, expected merge or replace", c.QueryParam("mode")))
+	}
+	var values map[string]*string
+	if err := json.NewDecoder(c.Request().Body).Decode(&values); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("body must be a JSON object of string or null values: %s", err))
+	}
+
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if dynamicClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, err := setMetadata(c.Request().Context(), resource, c.Param("name"), metadataField, values, replace, helpers.ResourceVersion(c))
+	if err != nil {
+		return err
+	}
+	_, _ = helpers.StripUnusedFields(obj)
+	return c.JSON(http.StatusOK, obj)
+}
+
+// setMetadata merge patches the labels or annotations. Replacing first reads
+// the object to null out the other keys, and makes the patch conditional on
+// what was read so keys added in between are not dropped unseen.
+func setMetadata(ctx context.Context, resource dynamic.ResourceInterface, name, metadataField string, values map[string]*string, replace bool, resourceVersion string) (*unstructured.Unstructured, error) {
+	if err := validateMetadata(metadataField, values); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	patchValues := make(map[string]any, len(values))
+	for key, value := range values {
+		if value == nil {
+			patchValues[key] = nil
+		} else {
+			patchValues[key] = *value
+		}
+	}
+	if replace {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+		}
+		if resourceVersion != "" && obj.GetResourceVersion() != resourceVersion {
+			return nil, echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("the object has been modified, resourceVersion is %s, not %s", obj.GetResourceVersion(), resourceVersion))
+		}
+		current, _, _ := unstructured.NestedStringMap(obj.Object, "metadata", metadataField)
+		for key := range current {
+			if _, ok := values[key]; !ok {
+				patchValues[key] = nil
+			}
+		}
+		resourceVersion = obj.GetResourceVersion()
+	}
+
+	patch, err := json.Marshal(helpers.PreconditionPatch(map[string]any{
+		"metadata": map[string]any{metadataField: patchValues},
+	}, resourceVersion))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	updated, err := resource.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return updated, nil
+}
+
+// validateMetadata applies the API server's syntax rules to the keys being
+// set, so mistakes fail with a clear message before the round trip.
+func validateMetadata(metadataField string, values map[string]*string) error {
+	if values == nil {
+		return fmt.Errorf("body must be a JSON object")
+	}
+	set := make(map[string]string, len(values))
+	for key, value := range values {
+		if value != nil {
+			set[key] = *value
+		}
+	}
+	path := field.NewPath("metadata", metadataField)
+	var errs field.ErrorList
+	if metadataField == labelsField {
+		errs = metav1validation.ValidateLabels(set, path)
+	} else {
+		errs = apivalidation.ValidateAnnotations(set, path)
+	}
+	return errs.ToAggregate()
+}
@@ -0,0 +1,106 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var widgets = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func newClient(labels map[string]string) *dynamicfake.FakeDynamicClient {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Widget")
+	u.SetNamespace("apps")
+	u.SetName("gadget")
+	u.SetLabels(labels)
+	u.SetAnnotations(map[string]string{"note": "keep"})
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"}, u)
+}
+
+func ptr(s string) *string { return &s }
+
+func TestSetMetadata(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]string{"app": "web", "tier": "frontend", "team": "shop"}
+
+	t.Run("merge", func(t *testing.T) {
+		client := newClient(current)
+		obj, err := setMetadata(ctx, client.Resource(widgets).Namespace("apps"), "gadget", labelsField,
+			map[string]*string{"tier": ptr("backend"), "team": nil}, false, "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app": "web", "tier": "backend"}, obj.GetLabels())
+		assert.Equal(t, map[string]string{"note": "keep"}, obj.GetAnnotations())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		client := newClient(current)
+		obj, err := setMetadata(ctx, client.Resource(widgets).Namespace("apps"), "gadget", labelsField,
+			map[string]*string{"app": ptr("web"), "env": ptr("prod")}, true, "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app": "web", "env": "prod"}, obj.GetLabels())
+	})
+
+	t.Run("annotations accept free-form values", func(t *testing.T) {
+		client := newClient(current)
+		obj, err := setMetadata(ctx, client.Resource(widgets).Namespace("apps"), "gadget", annotationsField,
+			map[string]*string{"description": ptr("serves the shop, see https://example.com")}, false, "")
+		require.NoError(t, err)
+		assert.Equal(t, "serves the shop, see https://example.com", obj.GetAnnotations()["description"])
+	})
+
+	t.Run("stale resourceVersion on replace", func(t *testing.T) {
+		client := newClient(current)
+		_, err := setMetadata(ctx, client.Resource(widgets).Namespace("apps"), "gadget", labelsField,
+			map[string]*string{"app": ptr("web")}, true, "999")
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusConflict, httpErr.Code)
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		client := newClient(current)
+		_, err := setMetadata(ctx, client.Resource(widgets).Namespace("apps"), "other", labelsField,
+			map[string]*string{"app": ptr("web")}, true, "")
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		values  map[string]*string
+		wantErr bool
+	}{
+		{"valid label", labelsField, map[string]*string{"app.kubernetes.io/name": ptr("web")}, false},
+		{"removal", labelsField, map[string]*string{"app": nil}, false},
+		{"invalid label key", labelsField, map[string]*string{"bad key": ptr("web")}, true},
+		{"invalid label value", labelsField, map[string]*string{"app": ptr("has spaces")}, true},
+		{"annotation value with spaces", annotationsField, map[string]*string{"note": ptr("has spaces")}, false},
+		{"invalid annotation key", annotationsField, map[string]*string{"/note": ptr("x")}, true},
+		{"null body", labelsField, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetadata(tt.field, tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package namespacedlist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// listConcurrency bounds the number of list calls in flight at once.
+const listConcurrency = 8
+
+type NamespacedList struct {
+	Items []unstructured.Unstructured `json:"items"`
+	// Errors holds the namespaces that could not be listed, the items of the
+	// others are still returned.
+	Errors []NamespaceError `json:"errors"`
+}
+
+type NamespaceError struct {
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason"`
+}
+
+type NamespacedListHandler struct {
+	container container.Container
+}
+
+func NewNamespacedListHandler(container container.Container) *NamespacedListHandler {
+	return &NamespacedListHandler{container: container}
+}
+
+// GetList lists one kind in the namespaces of the repeated or comma-separated
+// ?namespace= param, one list call per namespace. Unlike the list streams,
+// which watch all namespaces, it works for users only allowed to list in a
+// few of them.
+func (h *NamespacedListHandler) GetList(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	if c.QueryParam("version") == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required")
+	}
+	namespaces := namespacesParam(c.QueryParams()["namespace"])
+	if len(namespaces) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "at least one namespace is required")
+	}
+
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if dynamicClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	opts := metav1.ListOptions{LabelSelector: c.QueryParam("labelSelector")}
+	return c.JSON(http.StatusOK, listNamespaces(c.Request().Context(), dynamicClient, gvr, namespaces, opts))
+}
+
+func namespacesParam(values []string) []string {
+	seen := make(map[string]bool)
+	namespaces := make([]string, 0)
+	for _, v := range values {
+		for _, ns := range strings.Split(v, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" && !seen[ns] {
+				seen[ns] = true
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces
+}
+
+func listNamespaces(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, namespaces []string, opts metav1.ListOptions) NamespacedList {
+	result := NamespacedList{Items: make([]unstructured.Unstructured, 0), Errors: make([]NamespaceError, 0)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, listConcurrency)
+	for _, namespace := range namespaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			list, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				reason := err.Error()
+				if apierrors.IsForbidden(err) {
+					reason = "forbidden"
+				}
+				result.Errors = append(result.Errors, NamespaceError{Namespace: namespace, Reason: reason})
+				return
+			}
+			for i := range list.Items {
+				_, _ = helpers.StripUnusedFields(&list.Items[i])
+			}
+			result.Items = append(result.Items, list.Items...)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		if result.Items[i].GetNamespace() != result.Items[j].GetNamespace() {
+			return result.Items[i].GetNamespace() < result.Items[j].GetNamespace()
+		}
+		return result.Items[i].GetName() < result.Items[j].GetName()
+	})
+	sort.Slice(result.Errors, func(i, j int) bool { return result.Errors[i].Namespace < result.Errors[j].Namespace })
+	return result
+}
@@ -0,0 +1,57 @@
+package namespacedlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func configMap(namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	return u
+}
+
+func TestNamespacesParam(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, namespacesParam([]string{"a,b", " c ", "a", ""}))
+	assert.Empty(t, namespacesParam(nil))
+}
+
+func TestListNamespaces(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMaps: "ConfigMapList"},
+		configMap("team-b", "settings"),
+		configMap("team-a", "b"),
+		configMap("team-a", "a"),
+		configMap("other", "c"),
+	)
+	client.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "secret-team" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "", nil)
+		}
+		return false, nil, nil
+	})
+
+	got := listNamespaces(context.Background(), client, configMaps, []string{"team-b", "secret-team", "team-a"}, metav1.ListOptions{})
+
+	var names []string
+	for _, item := range got.Items {
+		names = append(names, item.GetNamespace()+"/"+item.GetName())
+		assert.Empty(t, item.GetManagedFields())
+	}
+	assert.Equal(t, []string{"team-a/a", "team-a/b", "team-b/settings"}, names)
+	assert.Equal(t, []NamespaceError{{Namespace: "secret-team", Reason: "forbidden"}}, got.Errors)
+}
@@ -0,0 +1,151 @@
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+// contentsConcurrency bounds the number of list calls in flight at once.
+const contentsConcurrency = 8
+
+type NamespaceContents struct {
+	Namespace string         `json:"namespace"`
+	Total     int            `json:"total"`
+	Kinds     []KindContents `json:"kinds"`
+	// Skipped lists the resource types that could not be listed, e.g.
+	// because the user is not allowed to. Objects of those types may exist.
+	Skipped []SkippedResource `json:"skipped"`
+}
+
+type KindContents struct {
+	Group    string        `json:"group"`
+	Version  string        `json:"version"`
+	Kind     string        `json:"kind"`
+	Resource string        `json:"resource"`
+	Items    []ContentItem `json:"items"`
+}
+
+type ContentItem struct {
+	UID  types.UID `json:"uid"`
+	Name string    `json:"name"`
+	Age  time.Time `json:"age"`
+}
+
+type SkippedResource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	Reason   string `json:"reason"`
+}
+
+type namespacedResource struct {
+	gvr  schema.GroupVersionResource
+	kind string
+}
+
+// GetNamespaceContents lists every object in the namespace, grouped by kind,
+// as a preview of what deleting the namespace would remove.
+func (h *NamespacesHandler) GetNamespaceContents(c echo.Context) error {
+	name := c.Param("name")
+	if _, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(name); err != nil || !exists {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("namespace %s not found", name))
+	}
+
+	discoveryClient := h.BaseHandler.Container.DiscoveryClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	lists, err := discovery.ServerPreferredNamespacedResources(discoveryClient)
+	// Partial discovery failures, e.g. an unavailable aggregated API, still
+	// leave the other groups usable.
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	// Resources the server configuration does not allow are left out, not
+	// reported as skipped.
+	resources := slices.DeleteFunc(listableResources(lists), func(r namespacedResource) bool {
+		return !h.BaseHandler.Container.Config().ResourceAllowed(r.gvr.GroupResource())
+	})
+	dynamicClient := h.BaseHandler.Container.DynamicClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	return c.JSON(http.StatusOK, namespaceContents(c.Request().Context(), dynamicClient, resources, name))
+}
+
+func listableResources(lists []*metav1.APIResourceList) []namespacedResource {
+	var resources []namespacedResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if !r.Namespaced || !slices.Contains(r.Verbs, "list") {
+				continue
+			}
+			resources = append(resources, namespacedResource{gvr: gv.WithResource(r.Name), kind: r.Kind})
+		}
+	}
+	return resources
+}
+
+func namespaceContents(ctx context.Context, dynamicClient dynamic.Interface, resources []namespacedResource, namespace string) NamespaceContents {
+	contents := NamespaceContents{Namespace: namespace, Kinds: make([]KindContents, 0), Skipped: make([]SkippedResource, 0)}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, contentsConcurrency)
+	for _, r := range resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			list, err := dynamicClient.Resource(r.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				reason := err.Error()
+				if apierrors.IsForbidden(err) {
+					reason = "forbidden"
+				}
+				contents.Skipped = append(contents.Skipped, SkippedResource{Group: r.gvr.Group, Version: r.gvr.Version, Resource: r.gvr.Resource, Reason: reason})
+				return
+			}
+			if len(list.Items) == 0 {
+				return
+			}
+
+			kind := KindContents{Group: r.gvr.Group, Version: r.gvr.Version, Kind: r.kind, Resource: r.gvr.Resource, Items: make([]ContentItem, 0, len(list.Items))}
+			for _, item := range list.Items {
+				kind.Items = append(kind.Items, ContentItem{UID: item.GetUID(), Name: item.GetName(), Age: item.GetCreationTimestamp().Time})
+			}
+			sort.Slice(kind.Items, func(i, j int) bool { return kind.Items[i].Name < kind.Items[j].Name })
+			contents.Kinds = append(contents.Kinds, kind)
+			contents.Total += len(kind.Items)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(contents.Kinds, func(i, j int) bool {
+		if contents.Kinds[i].Kind != contents.Kinds[j].Kind {
+			return contents.Kinds[i].Kind < contents.Kinds[j].Kind
+		}
+		return contents.Kinds[i].Group < contents.Kinds[j].Group
+	})
+	sort.Slice(contents.Skipped, func(i, j int) bool {
+		return contents.Skipped[i].Group+"/"+contents.Skipped[i].Resource < contents.Skipped[j].Group+"/"+contents.Skipped[j].Resource
+	})
+	return contents
+}
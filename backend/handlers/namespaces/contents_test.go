@@ -0,0 +1,71 @@
+package namespaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func object(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestListableResources(t *testing.T) {
+	lists := []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: []string{"get", "list"}},
+			{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+			{Name: "nodes", Kind: "Node", Verbs: []string{"list"}},
+		},
+	}}
+	got := listableResources(lists)
+	assert.Equal(t, []namespacedResource{{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, kind: "ConfigMap"}}, got)
+}
+
+func TestNamespaceContents(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			configMaps:  "ConfigMapList",
+			secrets:     "SecretList",
+			deployments: "DeploymentList",
+		},
+		object("v1", "ConfigMap", "team", "b"),
+		object("v1", "ConfigMap", "team", "a"),
+		object("v1", "ConfigMap", "other", "c"),
+		object("v1", "Secret", "team", "token"),
+	)
+	client.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil)
+	})
+
+	got := namespaceContents(context.Background(), client, []namespacedResource{
+		{gvr: configMaps, kind: "ConfigMap"},
+		{gvr: secrets, kind: "Secret"},
+		{gvr: deployments, kind: "Deployment"},
+	}, "team")
+
+	assert.Equal(t, 2, got.Total)
+	assert.Len(t, got.Kinds, 1)
+	assert.Equal(t, "ConfigMap", got.Kinds[0].Kind)
+	assert.Equal(t, "a", got.Kinds[0].Items[0].Name)
+	assert.Equal(t, "b", got.Kinds[0].Items[1].Name)
+	assert.Equal(t, []SkippedResource{{Version: "v1", Resource: "secrets", Reason: "forbidden"}}, got.Skipped)
+}
@@ -13,6 +13,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
+const GetNamespaceContents base.RouteType = 12
+
 type NamespacesHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -32,6 +34,8 @@ func NewNamespacesRouteHandler(container container.Container, routeType base.Rou
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetNamespaceContents:
+			return handler.GetNamespaceContents(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
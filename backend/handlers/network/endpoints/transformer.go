@@ -24,6 +24,26 @@ type Endpoint struct {
 type Subsets struct {
 	Addresses []string `json:"addresses"`
 	Ports     []string `json:"ports"`
+	// ReadyAddresses and NotReadyAddresses split Addresses by the ready
+	// condition, only ready ones receive service traffic.
+	ReadyAddresses    []string  `json:"readyAddresses"`
+	NotReadyAddresses []string  `json:"notReadyAddresses"`
+	Endpoints         []Backend `json:"endpoints"`
+}
+
+// Backend is one endpoint of the slice. Conditions left unset by the
+// controller are reported as the API defines them: ready and serving unless
+// said otherwise, not terminating.
+type Backend struct {
+	Addresses   []string `json:"addresses"`
+	Ready       bool     `json:"ready"`
+	Serving     bool     `json:"serving"`
+	Terminating bool     `json:"terminating"`
+	NodeName    string   `json:"nodeName,omitempty"`
+	Zone        string   `json:"zone,omitempty"`
+	// TargetRef is the backing object, e.g. "Pod/web-0", empty for endpoints
+	// managed outside of a selector.
+	TargetRef string `json:"targetRef,omitempty"`
 }
 
 func TransformEndpoint(pvs []discoveryv1.EndpointSlice) []Endpoint {
@@ -43,9 +63,19 @@ func TransformEndpoint(pvs []discoveryv1.EndpointSlice) []Endpoint {
 func TransformEndpointSliceItem(item discoveryv1.EndpointSlice) Endpoint {
 	ports := make([]string, 0)
 	ips := make([]string, 0)
+	readyIPs := make([]string, 0)
+	notReadyIPs := make([]string, 0)
+	backends := make([]Backend, 0, len(item.Endpoints))
 
 	for _, ep := range item.Endpoints {
 		ips = append(ips, ep.Addresses...)
+		backend := toBackend(ep)
+		if backend.Ready {
+			readyIPs = append(readyIPs, ep.Addresses...)
+		} else {
+			notReadyIPs = append(notReadyIPs, ep.Addresses...)
+		}
+		backends = append(backends, backend)
 	}
 
 	for _, p := range item.Ports {
@@ -70,9 +100,35 @@ func TransformEndpointSliceItem(item discoveryv1.EndpointSlice) Endpoint {
 		Namespace: item.GetNamespace(),
 		Name:      item.GetName(),
 		Subsets: Subsets{
-			Addresses: ips,
-			Ports:     ports,
+			Addresses:         ips,
+			Ports:             ports,
+			ReadyAddresses:    readyIPs,
+			NotReadyAddresses: notReadyIPs,
+			Endpoints:         backends,
 		},
 		Age: item.CreationTimestamp.Time,
 	}
 }
+
+func toBackend(ep discoveryv1.Endpoint) Backend {
+	ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+	backend := Backend{
+		Addresses:   ep.Addresses,
+		Ready:       ready,
+		Serving:     ready,
+		Terminating: ep.Conditions.Terminating != nil && *ep.Conditions.Terminating,
+	}
+	if ep.Conditions.Serving != nil {
+		backend.Serving = *ep.Conditions.Serving
+	}
+	if ep.NodeName != nil {
+		backend.NodeName = *ep.NodeName
+	}
+	if ep.Zone != nil {
+		backend.Zone = *ep.Zone
+	}
+	if ep.TargetRef != nil {
+		backend.TargetRef = ep.TargetRef.Kind + "/" + ep.TargetRef.Name
+	}
+	return backend
+}
@@ -0,0 +1,41 @@
+package endpoints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformEndpointSliceItem(t *testing.T) {
+	yes, no := true, false
+	node := "node-1"
+	got := TransformEndpointSliceItem(discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abcde", Namespace: "apps"},
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1.EndpointConditions{Ready: &yes},
+				NodeName:   &node,
+				TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "web-0"},
+			},
+			{
+				Addresses:  []string{"10.0.0.2"},
+				Conditions: discoveryv1.EndpointConditions{Ready: &no, Serving: &yes, Terminating: &yes},
+				TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: "web-1"},
+			},
+			{Addresses: []string{"192.168.1.10"}},
+		},
+	})
+
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "192.168.1.10"}, got.Subsets.Addresses)
+	assert.Equal(t, []string{"10.0.0.1", "192.168.1.10"}, got.Subsets.ReadyAddresses)
+	assert.Equal(t, []string{"10.0.0.2"}, got.Subsets.NotReadyAddresses)
+	assert.Equal(t, []Backend{
+		{Addresses: []string{"10.0.0.1"}, Ready: true, Serving: true, NodeName: "node-1", TargetRef: "Pod/web-0"},
+		{Addresses: []string{"10.0.0.2"}, Serving: true, Terminating: true, TargetRef: "Pod/web-1"},
+		{Addresses: []string{"192.168.1.10"}, Ready: true, Serving: true},
+	}, got.Subsets.Endpoints)
+}
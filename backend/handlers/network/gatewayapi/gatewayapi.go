@@ -0,0 +1,111 @@
+package gatewayapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+const group = "gateway.networking.k8s.io"
+
+// versions are tried in order, v1beta1 is what installs older than the
+// v1.0 release of the CRDs serve.
+var versions = []string{"v1", "v1beta1"}
+
+// List is the response of the Gateway API views. Installed is false, with no
+// items, on clusters without the Gateway API CRDs.
+type List[T any] struct {
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+	Items     []T    `json:"items"`
+}
+
+type GatewayAPIHandler struct {
+	container container.Container
+}
+
+func NewGatewayAPIHandler(container container.Container) *GatewayAPIHandler {
+	return &GatewayAPIHandler{container: container}
+}
+
+// GetGateways lists the Gateways in ?namespace=, or all namespaces, with
+// their listeners and addresses.
+func (h *GatewayAPIHandler) GetGateways(c echo.Context) error {
+	items, version, err := h.list(c, "gateways")
+	if err != nil {
+		return err
+	}
+	list := List[Gateway]{Installed: version != "", Version: version, Items: TransformGateways(items)}
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetHTTPRoutes lists the HTTPRoutes in ?namespace=, or all namespaces, with
+// the gateways they attach to and the backends they route to.
+func (h *GatewayAPIHandler) GetHTTPRoutes(c echo.Context) error {
+	items, version, err := h.list(c, "httproutes")
+	if err != nil {
+		return err
+	}
+	list := List[HTTPRoute]{Installed: version != "", Version: version, Items: TransformHTTPRoutes(items)}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *GatewayAPIHandler) list(c echo.Context, resource string) ([]unstructured.Unstructured, string, error) {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	clientSet := h.container.ClientSet(config, cluster)
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if clientSet == nil || dynamicClient == nil {
+		return nil, "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+
+	version, err := servedVersion(clientSet.Discovery(), resource)
+	if err != nil {
+		return nil, "", echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if version == "" {
+		return []unstructured.Unstructured{}, "", nil
+	}
+	items, err := listResource(c.Request().Context(), dynamicClient, schema.GroupVersionResource{Group: group, Version: version, Resource: resource}, c.QueryParam("namespace"))
+	if err != nil {
+		return nil, "", echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return items, version, nil
+}
+
+// servedVersion returns the first version serving resource, or "" when the
+// CRDs are not installed.
+func servedVersion(disc discovery.DiscoveryInterface, resource string) (string, error) {
+	for _, version := range versions {
+		resources, err := disc.ServerResourcesForGroupVersion(group + "/" + version)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == resource {
+				return version, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func listResource(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
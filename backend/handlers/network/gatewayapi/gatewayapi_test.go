@@ -0,0 +1,93 @@
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServedVersion(t *testing.T) {
+	clientSet := fake.NewClientset()
+	version, err := servedVersion(clientSet.Discovery(), "gateways")
+	require.NoError(t, err)
+	assert.Empty(t, version, "no CRDs installed")
+
+	clientSet.Resources = []*metav1.APIResourceList{{
+		GroupVersion: group + "/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "gateways"}, {Name: "httproutes"}},
+	}}
+	version, err = servedVersion(clientSet.Discovery(), "httproutes")
+	require.NoError(t, err)
+	assert.Equal(t, "v1beta1", version)
+	version, err = servedVersion(clientSet.Discovery(), "grpcroutes")
+	require.NoError(t, err)
+	assert.Empty(t, version)
+}
+
+func TestTransformGateways(t *testing.T) {
+	got := TransformGateways([]unstructured.Unstructured{{Object: map[string]any{
+		"metadata": map[string]any{"name": "public", "namespace": "infra"},
+		"spec": map[string]any{
+			"gatewayClassName": "istio",
+			"listeners": []any{
+				map[string]any{"name": "https", "protocol": "HTTPS", "port": int64(443), "hostname": "*.example.com"},
+				map[string]any{"name": "http", "protocol": "HTTP", "port": int64(80)},
+			},
+		},
+		"status": map[string]any{
+			"addresses":  []any{map[string]any{"type": "IPAddress", "value": "203.0.113.10"}},
+			"conditions": []any{map[string]any{"type": "Programmed", "status": "False", "reason": "Pending", "message": "waiting for load balancer", "lastTransitionTime": "2026-01-01T00:00:00Z"}},
+			"listeners":  []any{map[string]any{"name": "https", "attachedRoutes": int64(3)}},
+		},
+	}}})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "istio", got[0].GatewayClass)
+	assert.Equal(t, []Listener{
+		{Name: "https", Protocol: "HTTPS", Port: 443, Hostname: "*.example.com", AttachedRoutes: 3},
+		{Name: "http", Protocol: "HTTP", Port: 80},
+	}, got[0].Listeners)
+	assert.Equal(t, []string{"203.0.113.10"}, got[0].Addresses)
+	assert.Equal(t, "False", got[0].Programmed)
+	assert.Equal(t, "waiting for load balancer", got[0].Message)
+}
+
+func TestTransformHTTPRoutes(t *testing.T) {
+	got := TransformHTTPRoutes([]unstructured.Unstructured{{Object: map[string]any{
+		"metadata": map[string]any{"name": "shop", "namespace": "apps"},
+		"spec": map[string]any{
+			"hostnames": []any{"shop.example.com"},
+			"parentRefs": []any{
+				map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"},
+				map[string]any{"name": "internal"},
+			},
+			"rules": []any{map[string]any{
+				"matches": []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/api"}}},
+				"backendRefs": []any{
+					map[string]any{"name": "api", "port": int64(8080), "weight": int64(90)},
+					map[string]any{"name": "api-canary", "namespace": "canary", "port": int64(8080), "weight": int64(10)},
+				},
+			}},
+		},
+		"status": map[string]any{"parents": []any{map[string]any{
+			"parentRef":  map[string]any{"name": "public", "namespace": "infra", "sectionName": "https"},
+			"conditions": []any{map[string]any{"type": "Accepted", "status": "True", "reason": "Accepted", "lastTransitionTime": "2026-01-01T00:00:00Z"}},
+		}}},
+	}}})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"shop.example.com"}, got[0].Hostnames)
+	assert.Equal(t, []RouteParent{
+		{Ref: "infra/public/https", Accepted: "True"},
+		{Ref: "apps/internal", Accepted: "Unknown"},
+	}, got[0].Parents)
+	require.Len(t, got[0].Backends, 2)
+	assert.Equal(t, "apps/api", got[0].Backends[0].Ref)
+	assert.Equal(t, []string{"PathPrefix /api"}, got[0].Backends[0].Paths)
+	assert.Equal(t, "canary/api-canary", got[0].Backends[1].Ref)
+	assert.Equal(t, int32(10), *got[0].Backends[1].Weight)
+}
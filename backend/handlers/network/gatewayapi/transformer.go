@@ -0,0 +1,265 @@
+package gatewayapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maruel/natural"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type Gateway struct {
+	UID          types.UID  `json:"uid"`
+	Namespace    string     `json:"namespace"`
+	Name         string     `json:"name"`
+	Age          time.Time  `json:"age"`
+	GatewayClass string     `json:"gatewayClass"`
+	Listeners    []Listener `json:"listeners"`
+	Addresses    []string   `json:"addresses"`
+	// Programmed is the status of the Programmed condition, "Unknown" until
+	// the controller reports it.
+	Programmed string `json:"programmed"`
+	Message    string `json:"message,omitempty"`
+}
+
+type Listener struct {
+	Name           string `json:"name"`
+	Protocol       string `json:"protocol"`
+	Port           int32  `json:"port"`
+	Hostname       string `json:"hostname,omitempty"`
+	AttachedRoutes int32  `json:"attachedRoutes"`
+}
+
+type HTTPRoute struct {
+	UID       types.UID      `json:"uid"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Age       time.Time      `json:"age"`
+	Hostnames []string       `json:"hostnames"`
+	Parents   []RouteParent  `json:"parents"`
+	Backends  []RouteBackend `json:"backends"`
+}
+
+// RouteParent is a gateway the route attaches to, Accepted is the status of
+// the Accepted condition the gateway's controller reported for it.
+type RouteParent struct {
+	Ref      string `json:"ref"`
+	Accepted string `json:"accepted"`
+	Message  string `json:"message,omitempty"`
+}
+
+type RouteBackend struct {
+	// Paths are the path matches of the rule, empty when it matches all.
+	Paths []string `json:"paths"`
+	// Ref is "namespace/name" for services, "Kind/namespace/name" otherwise.
+	Ref    string `json:"ref"`
+	Port   *int32 `json:"port,omitempty"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// The Gateway API types are not vendored, these hold the fields the views
+// read and are filled from the unstructured objects.
+
+type gatewayObject struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		GatewayClassName string `json:"gatewayClassName"`
+		Listeners        []struct {
+			Name     string `json:"name"`
+			Hostname string `json:"hostname"`
+			Port     int32  `json:"port"`
+			Protocol string `json:"protocol"`
+		} `json:"listeners"`
+	} `json:"spec"`
+	Status struct {
+		Addresses []struct {
+			Value string `json:"value"`
+		} `json:"addresses"`
+		Conditions []metav1.Condition `json:"conditions"`
+		Listeners  []struct {
+			Name           string `json:"name"`
+			AttachedRoutes int32  `json:"attachedRoutes"`
+		} `json:"listeners"`
+	} `json:"status"`
+}
+
+type parentRef struct {
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	SectionName string `json:"sectionName"`
+}
+
+type httpRouteObject struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		ParentRefs []parentRef `json:"parentRefs"`
+		Hostnames  []string    `json:"hostnames"`
+		Rules      []struct {
+			Matches []struct {
+				Path *struct {
+					Type  string `json:"type"`
+					Value string `json:"value"`
+				} `json:"path"`
+			} `json:"matches"`
+			BackendRefs []struct {
+				Kind      string `json:"kind"`
+				Namespace string `json:"namespace"`
+				Name      string `json:"name"`
+				Port      *int32 `json:"port"`
+				Weight    *int32 `json:"weight"`
+			} `json:"backendRefs"`
+		} `json:"rules"`
+	} `json:"spec"`
+	Status struct {
+		Parents []struct {
+			ParentRef  parentRef          `json:"parentRef"`
+			Conditions []metav1.Condition `json:"conditions"`
+		} `json:"parents"`
+	} `json:"status"`
+}
+
+func TransformGateways(items []unstructured.Unstructured) []Gateway {
+	list := make([]Gateway, 0)
+	for _, item := range items {
+		var obj gatewayObject
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &obj); err != nil {
+			continue
+		}
+		list = append(list, TransformGatewayItem(obj))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return natural.Less(fmt.Sprintf("%s-%s", list[i].Name, list[i].Namespace), fmt.Sprintf("%s-%s", list[j].Name, list[j].Namespace))
+	})
+	return list
+}
+
+func TransformGatewayItem(obj gatewayObject) Gateway {
+	attached := make(map[string]int32)
+	for _, l := range obj.Status.Listeners {
+		attached[l.Name] = l.AttachedRoutes
+	}
+	listeners := make([]Listener, 0, len(obj.Spec.Listeners))
+	for _, l := range obj.Spec.Listeners {
+		listeners = append(listeners, Listener{
+			Name:           l.Name,
+			Protocol:       l.Protocol,
+			Port:           l.Port,
+			Hostname:       l.Hostname,
+			AttachedRoutes: attached[l.Name],
+		})
+	}
+	addresses := make([]string, 0, len(obj.Status.Addresses))
+	for _, a := range obj.Status.Addresses {
+		addresses = append(addresses, a.Value)
+	}
+	programmed, message := conditionStatus(obj.Status.Conditions, "Programmed")
+
+	return Gateway{
+		UID:          obj.UID,
+		Namespace:    obj.Namespace,
+		Name:         obj.Name,
+		Age:          obj.CreationTimestamp.Time,
+		GatewayClass: obj.Spec.GatewayClassName,
+		Listeners:    listeners,
+		Addresses:    addresses,
+		Programmed:   programmed,
+		Message:      message,
+	}
+}
+
+func TransformHTTPRoutes(items []unstructured.Unstructured) []HTTPRoute {
+	list := make([]HTTPRoute, 0)
+	for _, item := range items {
+		var obj httpRouteObject
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &obj); err != nil {
+			continue
+		}
+		list = append(list, TransformHTTPRouteItem(obj))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return natural.Less(fmt.Sprintf("%s-%s", list[i].Name, list[i].Namespace), fmt.Sprintf("%s-%s", list[j].Name, list[j].Namespace))
+	})
+	return list
+}
+
+func TransformHTTPRouteItem(obj httpRouteObject) HTTPRoute {
+	hostnames := obj.Spec.Hostnames
+	if hostnames == nil {
+		hostnames = []string{}
+	}
+
+	// Parents without status have not been picked up by a controller yet.
+	parents := make([]RouteParent, 0, len(obj.Spec.ParentRefs))
+	for _, ref := range obj.Spec.ParentRefs {
+		parent := RouteParent{Ref: parentRefString(ref, obj.Namespace), Accepted: string(metav1.ConditionUnknown)}
+		for _, status := range obj.Status.Parents {
+			if parentRefString(status.ParentRef, obj.Namespace) == parent.Ref {
+				parent.Accepted, parent.Message = conditionStatus(status.Conditions, "Accepted")
+			}
+		}
+		parents = append(parents, parent)
+	}
+
+	backends := make([]RouteBackend, 0)
+	for _, rule := range obj.Spec.Rules {
+		paths := make([]string, 0, len(rule.Matches))
+		for _, match := range rule.Matches {
+			if match.Path != nil {
+				paths = append(paths, fmt.Sprintf("%s %s", match.Path.Type, match.Path.Value))
+			}
+		}
+		for _, ref := range rule.BackendRefs {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = obj.Namespace
+			}
+			target := namespace + "/" + ref.Name
+			if ref.Kind != "" && ref.Kind != "Service" {
+				target = ref.Kind + "/" + target
+			}
+			backends = append(backends, RouteBackend{Paths: paths, Ref: target, Port: ref.Port, Weight: ref.Weight})
+		}
+	}
+
+	return HTTPRoute{
+		UID:       obj.UID,
+		Namespace: obj.Namespace,
+		Name:      obj.Name,
+		Age:       obj.CreationTimestamp.Time,
+		Hostnames: hostnames,
+		Parents:   parents,
+		Backends:  backends,
+	}
+}
+
+// parentRefString is "namespace/name[/section]", prefixed by the kind when it
+// is not a Gateway.
+func parentRefString(ref parentRef, routeNamespace string) string {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = routeNamespace
+	}
+	parts := []string{namespace, ref.Name}
+	if ref.SectionName != "" {
+		parts = append(parts, ref.SectionName)
+	}
+	if ref.Kind != "" && ref.Kind != "Gateway" {
+		parts = append([]string{ref.Kind}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
+
+func conditionStatus(conditions []metav1.Condition, conditionType string) (string, string) {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		return string(metav1.ConditionUnknown), ""
+	}
+	return string(condition.Status), condition.Message
+}
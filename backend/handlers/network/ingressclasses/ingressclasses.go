@@ -0,0 +1,82 @@
+package ingressclasses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	networkingV1 "k8s.io/api/networking/v1"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+)
+
+type IngressClassesHandler struct {
+	BaseHandler base.BaseHandler
+}
+
+func NewIngressClassRouteHandler(container container.Container, routeType base.RouteType) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		handler := NewIngressClassesHandler(c.Request().Context(), c.QueryParam("config"), c.QueryParam("cluster"), container)
+
+		switch routeType {
+		case base.GetList:
+			return handler.BaseHandler.GetList(c)
+		case base.GetDetails:
+			return handler.BaseHandler.GetDetails(c)
+		case base.GetEvents:
+			return handler.BaseHandler.GetEvents(c)
+		case base.GetYaml:
+			return handler.BaseHandler.GetYaml(c)
+		case base.Delete:
+			return handler.BaseHandler.Delete(c)
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
+		}
+	}
+}
+
+func NewIngressClassesHandler(ctx context.Context, config, cluster string, container container.Container) *IngressClassesHandler {
+	cacheKey := fmt.Sprintf("%s-%s-handlers/network/ingressclasses.NewIngressClassesHandler", config, cluster)
+	return base.GetOrCreateHandler(cacheKey, func() *IngressClassesHandler {
+		return newIngressClassesHandler(ctx, config, cluster, container)
+	})
+}
+
+func newIngressClassesHandler(ctx context.Context, config, cluster string, container container.Container) *IngressClassesHandler {
+	informer := container.SharedInformerFactory(config, cluster).Networking().V1().IngressClasses().Informer()
+	informer.SetTransform(helpers.StripUnusedFields)
+
+	handler := &IngressClassesHandler{
+		BaseHandler: base.BaseHandler{
+			Kind:             "IngressClass",
+			Container:        container,
+			Informer:         informer,
+			RestClient:       container.ClientSet(config, cluster).NetworkingV1().RESTClient(),
+			QueryConfig:      config,
+			QueryCluster:     cluster,
+			InformerCacheKey: fmt.Sprintf("%s-%s-ingressClassInformer", config, cluster),
+			TransformFunc:    transformItems,
+		},
+	}
+	cache := base.ResourceEventHandler[*networkingV1.IngressClass](&handler.BaseHandler)
+	handler.BaseHandler.StartInformer(cache)
+	handler.BaseHandler.WaitForSync(ctx)
+	return handler
+}
+
+func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
+	var list []networkingV1.IngressClass
+
+	for _, obj := range items {
+		if item, ok := obj.(*networkingV1.IngressClass); ok {
+			list = append(list, *item)
+		}
+	}
+	t := TransformIngressClass(list)
+
+	return json.Marshal(t)
+}
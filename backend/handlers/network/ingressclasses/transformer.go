@@ -0,0 +1,60 @@
+package ingressclasses
+
+import (
+	"sort"
+	"time"
+
+	"github.com/maruel/natural"
+	networkingV1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// isDefaultClassAnnotation marks the class used for ingresses without an
+// ingressClassName.
+const isDefaultClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+
+type IngressClass struct {
+	UID        types.UID `json:"uid"`
+	Name       string    `json:"name"`
+	Age        time.Time `json:"age"`
+	Controller string    `json:"controller"`
+	IsDefault  bool      `json:"isDefault"`
+	// Parameters references the controller specific configuration, as
+	// "Kind/name" or "Kind/namespace/name", empty when there is none.
+	Parameters string `json:"parameters"`
+}
+
+func TransformIngressClass(items []networkingV1.IngressClass) []IngressClass {
+	list := make([]IngressClass, 0)
+
+	for _, d := range items {
+		list = append(list, TransformIngressClassItem(d))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return natural.Less(list[i].Name, list[j].Name)
+	})
+
+	return list
+}
+
+func TransformIngressClassItem(item networkingV1.IngressClass) IngressClass {
+	return IngressClass{
+		UID:        item.GetUID(),
+		Name:       item.GetName(),
+		Age:        item.CreationTimestamp.Time,
+		Controller: item.Spec.Controller,
+		IsDefault:  item.Annotations[isDefaultClassAnnotation] == "true",
+		Parameters: parametersRef(item.Spec.Parameters),
+	}
+}
+
+func parametersRef(ref *networkingV1.IngressClassParametersReference) string {
+	if ref == nil {
+		return ""
+	}
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		return ref.Kind + "/" + *ref.Namespace + "/" + ref.Name
+	}
+	return ref.Kind + "/" + ref.Name
+}
@@ -0,0 +1,47 @@
+package ingressclasses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformIngressClassItem(t *testing.T) {
+	namespace := "ingress"
+	tests := []struct {
+		name       string
+		item       networkingV1.IngressClass
+		isDefault  bool
+		parameters string
+	}{
+		{
+			name: "default without parameters",
+			item: networkingV1.IngressClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "nginx", Annotations: map[string]string{isDefaultClassAnnotation: "true"}},
+				Spec:       networkingV1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+			},
+			isDefault: true,
+		},
+		{
+			name: "namespaced parameters",
+			item: networkingV1.IngressClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "alb"},
+				Spec: networkingV1.IngressClassSpec{
+					Controller: "ingress.k8s.aws/alb",
+					Parameters: &networkingV1.IngressClassParametersReference{Kind: "IngressClassParams", Name: "alb", Namespace: &namespace},
+				},
+			},
+			parameters: "IngressClassParams/ingress/alb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TransformIngressClassItem(tt.item)
+			assert.Equal(t, tt.item.Spec.Controller, got.Controller)
+			assert.Equal(t, tt.isDefault, got.IsDefault)
+			assert.Equal(t, tt.parameters, got.Parameters)
+		})
+	}
+}
@@ -22,8 +22,35 @@ type Endpoint struct {
 
 type Spec struct {
 	Rules []string `json:"rules"`
+	// IngressClass is spec.ingressClassName, or the deprecated
+	// kubernetes.io/ingress.class annotation when it is not set.
+	IngressClass string    `json:"ingressClass"`
+	Backends     []Backend `json:"backends"`
+	TLS          []TLS     `json:"tls"`
 }
 
+// TLS is a certificate secret and the hosts it terminates. An empty
+// SecretName leaves the certificate to the controller's default.
+type TLS struct {
+	Hosts      []string `json:"hosts"`
+	SecretName string   `json:"secretName"`
+}
+
+// Backend maps a host and path to the service serving it. The default
+// backend has neither host nor path.
+type Backend struct {
+	Host     string `json:"host"`
+	Path     string `json:"path"`
+	PathType string `json:"pathType,omitempty"`
+	Service  string `json:"service,omitempty"`
+	// Port is the service port number or name.
+	Port string `json:"port,omitempty"`
+	// Resource is "Kind/name" for backends that are not a service.
+	Resource string `json:"resource,omitempty"`
+}
+
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
 func TransformIngress(pvs []networkingV1.Ingress) []Endpoint {
 	list := make([]Endpoint, 0)
 
@@ -40,24 +67,66 @@ func TransformIngress(pvs []networkingV1.Ingress) []Endpoint {
 func TransformIngressItem(item networkingV1.Ingress) Endpoint {
 	// This will give empty array instead of null
 	rules := make([]string, 0)
+	backends := make([]Backend, 0)
+	if item.Spec.DefaultBackend != nil {
+		backends = append(backends, toBackend("", "", nil, *item.Spec.DefaultBackend))
+	}
 
 	for _, i := range item.Spec.Rules {
 		if i.HTTP != nil {
 			for _, p := range i.HTTP.Paths {
+				backend := toBackend(i.Host, p.Path, p.PathType, p.Backend)
+				backends = append(backends, backend)
 				if p.Backend.Service != nil {
-					number := int(p.Backend.Service.Port.Number)
-					rules = append(rules, fmt.Sprintf("%s --> %s:%s", p.Path, p.Backend.Service.Name, strconv.Itoa(number)))
+					rules = append(rules, fmt.Sprintf("%s --> %s:%s", p.Path, backend.Service, backend.Port))
 				}
 			}
 		}
 	}
+	tls := make([]TLS, 0, len(item.Spec.TLS))
+	for _, t := range item.Spec.TLS {
+		hosts := t.Hosts
+		if hosts == nil {
+			hosts = []string{}
+		}
+		tls = append(tls, TLS{Hosts: hosts, SecretName: t.SecretName})
+	}
 	return Endpoint{
 		UID:       item.GetUID(),
 		Namespace: item.GetNamespace(),
 		Name:      item.GetName(),
 		Spec: Spec{
-			Rules: rules,
+			Rules:        rules,
+			IngressClass: ingressClass(item),
+			Backends:     backends,
+			TLS:          tls,
 		},
 		Age: item.CreationTimestamp.Time,
 	}
 }
+
+func ingressClass(item networkingV1.Ingress) string {
+	if item.Spec.IngressClassName != nil {
+		return *item.Spec.IngressClassName
+	}
+	return item.Annotations[ingressClassAnnotation]
+}
+
+func toBackend(host, path string, pathType *networkingV1.PathType, backend networkingV1.IngressBackend) Backend {
+	b := Backend{Host: host, Path: path}
+	if pathType != nil {
+		b.PathType = string(*pathType)
+	}
+	switch {
+	case backend.Service != nil:
+		b.Service = backend.Service.Name
+		if backend.Service.Port.Name != "" {
+			b.Port = backend.Service.Port.Name
+		} else {
+			b.Port = strconv.Itoa(int(backend.Service.Port.Number))
+		}
+	case backend.Resource != nil:
+		b.Resource = backend.Resource.Kind + "/" + backend.Resource.Name
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+package ingresses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformIngressItem(t *testing.T) {
+	className := "nginx"
+	prefix := networkingV1.PathTypePrefix
+	servicePath := func(path, service string, port networkingV1.ServiceBackendPort) networkingV1.HTTPIngressPath {
+		return networkingV1.HTTPIngressPath{Path: path, PathType: &prefix, Backend: networkingV1.IngressBackend{
+			Service: &networkingV1.IngressServiceBackend{Name: service, Port: port},
+		}}
+	}
+	rule := func(host string, paths ...networkingV1.HTTPIngressPath) networkingV1.IngressRule {
+		return networkingV1.IngressRule{Host: host, IngressRuleValue: networkingV1.IngressRuleValue{HTTP: &networkingV1.HTTPIngressRuleValue{Paths: paths}}}
+	}
+
+	tests := []struct {
+		name     string
+		item     networkingV1.Ingress
+		class    string
+		rules    []string
+		backends []Backend
+		tls      []TLS
+	}{
+		{
+			name: "multi-path",
+			item: networkingV1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+				Spec: networkingV1.IngressSpec{
+					IngressClassName: &className,
+					Rules: []networkingV1.IngressRule{rule("shop.example.com",
+						servicePath("/api", "api", networkingV1.ServiceBackendPort{Number: 8080}),
+						servicePath("/", "web", networkingV1.ServiceBackendPort{Name: "http"}),
+					)},
+				},
+			},
+			class: "nginx",
+			rules: []string{"/api --> api:8080", "/ --> web:http"},
+			backends: []Backend{
+				{Host: "shop.example.com", Path: "/api", PathType: "Prefix", Service: "api", Port: "8080"},
+				{Host: "shop.example.com", Path: "/", PathType: "Prefix", Service: "web", Port: "http"},
+			},
+			tls: []TLS{},
+		},
+		{
+			name: "default backend with annotation class",
+			item: networkingV1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "static", Annotations: map[string]string{ingressClassAnnotation: "legacy"}},
+				Spec: networkingV1.IngressSpec{
+					DefaultBackend: &networkingV1.IngressBackend{Resource: &v1.TypedLocalObjectReference{Kind: "StorageBucket", Name: "static"}},
+				},
+			},
+			class:    "legacy",
+			rules:    []string{},
+			backends: []Backend{{Resource: "StorageBucket/static"}},
+			tls:      []TLS{},
+		},
+		{
+			name: "tls",
+			item: networkingV1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "secure"},
+				Spec: networkingV1.IngressSpec{
+					TLS: []networkingV1.IngressTLS{
+						{Hosts: []string{"a.example.com", "b.example.com"}, SecretName: "example-tls"},
+						{SecretName: "wildcard-tls"},
+					},
+					Rules: []networkingV1.IngressRule{rule("a.example.com", servicePath("/", "a", networkingV1.ServiceBackendPort{Number: 80}))},
+				},
+			},
+			rules:    []string{"/ --> a:80"},
+			backends: []Backend{{Host: "a.example.com", Path: "/", PathType: "Prefix", Service: "a", Port: "80"}},
+			tls: []TLS{
+				{Hosts: []string{"a.example.com", "b.example.com"}, SecretName: "example-tls"},
+				{Hosts: []string{}, SecretName: "wildcard-tls"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TransformIngressItem(tt.item)
+			assert.Equal(t, tt.class, got.Spec.IngressClass)
+			assert.Equal(t, tt.rules, got.Spec.Rules)
+			assert.Equal(t, tt.backends, got.Spec.Backends)
+			assert.Equal(t, tt.tls, got.Spec.TLS)
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const GetServiceTopology base.RouteType = 12
+
 type ServicesHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -33,6 +35,8 @@ func NewServicesRouteHandler(container container.Container, routeType base.Route
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case GetServiceTopology:
+			return handler.GetServiceTopology(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
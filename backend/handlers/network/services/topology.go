@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/kubewall/kubewall/backend/handlers/network/endpoints"
+	"github.com/kubewall/kubewall/backend/handlers/workloads/pods"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ServiceTopology struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Spec      Spec              `json:"spec"`
+	Selector  map[string]string `json:"selector"`
+	// EndpointSlices are the slices of the service, with per-address
+	// readiness and the pod behind each address.
+	EndpointSlices []endpoints.Endpoint `json:"endpointSlices"`
+	Pods           []TopologyPod        `json:"pods"`
+	Summary        TopologySummary      `json:"summary"`
+	// Issues are likely reasons the service is not reachable.
+	Issues []string `json:"issues"`
+}
+
+// TopologyPod is a pod matching the service selector. Serving is set when
+// one of its addresses is a ready endpoint, i.e. it receives traffic.
+type TopologyPod struct {
+	pods.PodList
+	InEndpoints bool `json:"inEndpoints"`
+	Serving     bool `json:"serving"`
+}
+
+type TopologySummary struct {
+	ReadyEndpoints    int `json:"readyEndpoints"`
+	NotReadyEndpoints int `json:"notReadyEndpoints"`
+	MatchingPods      int `json:"matchingPods"`
+	ReadyPods         int `json:"readyPods"`
+}
+
+// GetServiceTopology returns a service with its endpoint slices and the pods
+// its selector matches, to show why it has no healthy backends. The pod and
+// endpoint informers are loaded concurrently.
+func (h *ServicesHandler) GetServiceTopology(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	service, ok := obj.(*v1.Service)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("service %s not found", key))
+	}
+
+	ctx, config, cluster, container := c.Request().Context(), h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, h.BaseHandler.Container
+	var podItems, sliceItems []any
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		podItems, _ = pods.NewPodsHandler(ctx, config, cluster, container).BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, service.Namespace)
+	}()
+	go func() {
+		defer wg.Done()
+		sliceItems, _ = endpoints.NewEndpointsHandler(ctx, config, cluster, container).BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, service.Namespace)
+	}()
+	wg.Wait()
+
+	podList := make([]*v1.Pod, 0, len(podItems))
+	for _, item := range podItems {
+		if pod, ok := item.(*v1.Pod); ok {
+			podList = append(podList, pod)
+		}
+	}
+	slices := make([]*discoveryv1.EndpointSlice, 0, len(sliceItems))
+	for _, item := range sliceItems {
+		if slice, ok := item.(*discoveryv1.EndpointSlice); ok {
+			slices = append(slices, slice)
+		}
+	}
+	return c.JSON(http.StatusOK, serviceTopology(service, slices, podList))
+}
+
+func serviceTopology(service *v1.Service, slices []*discoveryv1.EndpointSlice, podList []*v1.Pod) ServiceTopology {
+	selector := service.Spec.Selector
+	if selector == nil {
+		selector = map[string]string{}
+	}
+	topology := ServiceTopology{
+		Name:           service.Name,
+		Namespace:      service.Namespace,
+		Spec:           TransformServiceItem(*service).Spec,
+		Selector:       selector,
+		EndpointSlices: make([]endpoints.Endpoint, 0),
+		Pods:           make([]TopologyPod, 0),
+		Issues:         make([]string, 0),
+	}
+
+	// Endpoints point at pods by name, an address may be in several slices
+	// while they are being updated.
+	inEndpoints := make(map[string]bool)
+	serving := make(map[string]bool)
+	for _, slice := range slices {
+		if slice.Namespace != service.Namespace || slice.Labels[discoveryv1.LabelServiceName] != service.Name {
+			continue
+		}
+		endpoint := endpoints.TransformEndpointSliceItem(*slice)
+		for _, backend := range endpoint.Subsets.Endpoints {
+			if backend.Ready {
+				topology.Summary.ReadyEndpoints += len(backend.Addresses)
+			} else {
+				topology.Summary.NotReadyEndpoints += len(backend.Addresses)
+			}
+			inEndpoints[backend.TargetRef] = true
+			serving[backend.TargetRef] = serving[backend.TargetRef] || backend.Ready
+		}
+		topology.EndpointSlices = append(topology.EndpointSlices, endpoint)
+	}
+	sort.Slice(topology.EndpointSlices, func(i, j int) bool {
+		return topology.EndpointSlices[i].Name < topology.EndpointSlices[j].Name
+	})
+
+	var matched []*v1.Pod
+	if len(selector) > 0 {
+		podSelector := labels.SelectorFromSet(selector)
+		for _, pod := range podList {
+			if pod.Namespace != service.Namespace || !podSelector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			matched = append(matched, pod)
+			ref := "Pod/" + pod.Name
+			topology.Pods = append(topology.Pods, TopologyPod{
+				PodList:     pods.TransformPodListItem(*pod),
+				InEndpoints: inEndpoints[ref],
+				Serving:     serving[ref],
+			})
+			if isPodReady(pod) {
+				topology.Summary.ReadyPods++
+			}
+		}
+	}
+	topology.Summary.MatchingPods = len(matched)
+	sort.Slice(topology.Pods, func(i, j int) bool { return topology.Pods[i].Name < topology.Pods[j].Name })
+
+	topology.Issues = topologyIssues(service, matched, topology)
+	return topology
+}
+
+func topologyIssues(service *v1.Service, matched []*v1.Pod, topology ServiceTopology) []string {
+	issues := make([]string, 0)
+	if service.Spec.Type == v1.ServiceTypeExternalName {
+		return issues
+	}
+	summary := topology.Summary
+	switch {
+	case len(topology.Selector) == 0:
+		if len(topology.EndpointSlices) == 0 {
+			issues = append(issues, "service has no selector and no endpoint slices, its endpoints have to be created manually")
+		}
+	case summary.MatchingPods == 0:
+		issues = append(issues, fmt.Sprintf("selector %s matches no pods in %s", labels.SelectorFromSet(topology.Selector), service.Namespace))
+	case summary.ReadyPods < summary.MatchingPods:
+		issues = append(issues, fmt.Sprintf("%d of %d matching pods are not ready", summary.MatchingPods-summary.ReadyPods, summary.MatchingPods))
+	}
+	if summary.ReadyEndpoints == 0 && (len(topology.Selector) > 0 || len(topology.EndpointSlices) > 0) {
+		issues = append(issues, "service has no ready endpoints, connections to it will fail")
+	}
+
+	for _, port := range service.Spec.Ports {
+		if port.TargetPort.StrVal == "" || len(matched) == 0 {
+			continue
+		}
+		declared := false
+		for _, pod := range matched {
+			declared = declared || declaresPort(pod, port.TargetPort.StrVal)
+		}
+		if !declared {
+			issues = append(issues, fmt.Sprintf("target port %q is not a named container port of any matching pod", port.TargetPort.StrVal))
+		}
+	}
+	return issues
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func declaresPort(pod *v1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestServiceTopology(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports:    []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+	pod := func(name string, ready bool, labels map[string]string) *v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "apps", Labels: labels},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "web", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}}},
+		}
+	}
+	endpoint := func(ip, podName string, ready bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses:  []string{ip},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: podName},
+		}
+	}
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abcde", Namespace: "apps", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Endpoints:  []discoveryv1.Endpoint{endpoint("10.0.0.1", "web-0", true), endpoint("10.0.0.2", "web-1", false)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-abcde", Namespace: "apps", Labels: map[string]string{discoveryv1.LabelServiceName: "api"}},
+			Endpoints:  []discoveryv1.Endpoint{endpoint("10.0.0.9", "api-0", true)},
+		},
+	}
+	podList := []*v1.Pod{
+		pod("web-1", false, map[string]string{"app": "web"}),
+		pod("web-0", true, map[string]string{"app": "web"}),
+		pod("api-0", true, map[string]string{"app": "api"}),
+	}
+
+	got := serviceTopology(service, slices, podList)
+	assert.Len(t, got.EndpointSlices, 1)
+	assert.Equal(t, TopologySummary{ReadyEndpoints: 1, NotReadyEndpoints: 1, MatchingPods: 2, ReadyPods: 1}, got.Summary)
+	assert.Equal(t, "web-0", got.Pods[0].Name)
+	assert.True(t, got.Pods[0].Serving)
+	assert.True(t, got.Pods[1].InEndpoints)
+	assert.False(t, got.Pods[1].Serving)
+	assert.Equal(t, []string{"1 of 2 matching pods are not ready"}, got.Issues)
+
+	t.Run("no matching pods", func(t *testing.T) {
+		got := serviceTopology(service, nil, podList[2:])
+		assert.Equal(t, []string{
+			"selector app=web matches no pods in apps",
+			"service has no ready endpoints, connections to it will fail",
+		}, got.Issues)
+	})
+
+	t.Run("undeclared target port", func(t *testing.T) {
+		other := service.DeepCopy()
+		other.Spec.Ports[0].TargetPort = intstr.FromString("metrics")
+		got := serviceTopology(other, slices, podList)
+		assert.Contains(t, got.Issues, `target port "metrics" is not a named container port of any matching pod`)
+	})
+}
@@ -0,0 +1,272 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DebugPodPending = "pending"
+	DebugPodReady   = "ready"
+	DebugPodEnded   = "ended"
+	DebugPodError   = "error"
+)
+
+const (
+	defaultDebugImage  = "busybox:1.36"
+	debugContainerName = "debugger"
+	// debugPodDeadline removes debug pods whose session cleanup never ran,
+	// e.g. because kubewall was stopped.
+	debugPodDeadline = time.Hour
+	// debugPodCleanupTimeout bounds the delete after the session ended.
+	debugPodCleanupTimeout = 30 * time.Second
+	debugNodeLabel         = "kubewall.io/debug-node"
+)
+
+// DebugNodeEvent is returned when a debug pod is created and sent on its
+// stream. "ready" means the debugger container runs, "ended" that the pod
+// stopped on its own.
+type DebugNodeEvent struct {
+	Type      string `json:"type"`
+	Pod       string `json:"pod,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Container string `json:"container,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// DebugNode is kubectl debug node/<name>: it starts a privileged pod in the
+// host namespaces of a Ready node, with the node's root filesystem at /host,
+// and returns it. Follow it with WatchDebugNode, which deletes the pod when
+// the client disconnects; a pod never followed ends at its deadline. The
+// image is busybox unless ?image= names one of --node-debug-images.
+// Disabled unless kubewall runs with --enable-node-debug.
+func (h *NodeHandler) DebugNode(c echo.Context) error {
+	cfg := h.BaseHandler.Container.Config()
+	if !cfg.NodeDebugEnabled {
+		return echo.NewHTTPError(http.StatusForbidden, "node debugging is disabled, start kubewall with --enable-node-debug")
+	}
+	image := c.QueryParam("image")
+	if image == "" {
+		image = defaultDebugImage
+	}
+	if !debugImageAllowed(image, cfg.NodeDebugImages) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("image %s is not allowed for node debugging, allow it with --node-debug-images", image))
+	}
+
+	name := c.Param("name")
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	node, ok := obj.(*coreV1.Node)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("node %s not found", name))
+	}
+	if !nodeReady(node) {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("node %s is not Ready", name))
+	}
+
+	namespace := c.QueryParam("namespace")
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	pod, err := clientSet.CoreV1().Pods(namespace).Create(c.Request().Context(), debugPod(node.Name, namespace, image), metav1.CreateOptions{})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	log.Info("audit: node debug pod created",
+		"config", h.BaseHandler.QueryConfig, "cluster", h.BaseHandler.QueryCluster,
+		"node", node.Name, "namespace", namespace, "pod", pod.Name, "image", image, "remoteAddr", c.RealIP())
+	return c.JSON(http.StatusCreated, DebugNodeEvent{Type: DebugPodPending, Pod: pod.Name, Namespace: namespace, Container: debugContainerName})
+}
+
+// WatchDebugNode streams the status of a debug pod created by DebugNode and
+// deletes the pod when the client disconnects.
+func (h *NodeHandler) WatchDebugNode(c echo.Context) error {
+	if !h.BaseHandler.Container.Config().NodeDebugEnabled {
+		return echo.NewHTTPError(http.StatusForbidden, "node debugging is disabled, start kubewall with --enable-node-debug")
+	}
+	node := c.Param("name")
+	namespace := c.QueryParam("namespace")
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	pod, err := clientSet.CoreV1().Pods(namespace).Get(c.Request().Context(), c.Param("pod"), metav1.GetOptions{})
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	// Only debug pods are followed, the stream deletes the pod it follows.
+	if pod.Labels[debugNodeLabel] != node {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("debug pod %s/%s of node %s not found", namespace, pod.Name, node))
+	}
+	audit := log.With("config", h.BaseHandler.QueryConfig, "cluster", h.BaseHandler.QueryCluster,
+		"node", node, "namespace", namespace, "pod", pod.Name, "remoteAddr", c.RealIP())
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-%s-%s-debug", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, namespace, pod.Name)
+	sseServer.CreateStream(streamKey)
+
+	go func() {
+		debugSession(c.Request().Context(), clientSet, pod, func(e DebugNodeEvent) {
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Error("failed to marshal debug node event", "err", err)
+				return
+			}
+			sseServer.Publish(streamKey, &sse.Event{Data: data})
+		})
+		audit.Info("audit: node debug pod deleted")
+	}()
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+// debugImageAllowed reports whether image is the default debug image or one
+// of the allowed ones.
+func debugImageAllowed(image string, allowed []string) bool {
+	return image == defaultDebugImage || slices.Contains(allowed, image)
+}
+
+func nodeReady(node *coreV1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == coreV1.NodeReady {
+			return condition.Status == coreV1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// debugPod is the pod kubectl debug node creates with the sysadmin profile:
+// host PID, IPC and network namespaces, privileged, and tolerating every
+// taint so it lands on cordoned or tainted nodes too.
+func debugPod(node, namespace, image string) *coreV1.Pod {
+	return &coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("node-debugger-%s-", node),
+			Namespace:    namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kubewall",
+				debugNodeLabel:                 node,
+			},
+		},
+		Spec: coreV1.PodSpec{
+			NodeName:                      node,
+			HostPID:                       true,
+			HostIPC:                       true,
+			HostNetwork:                   true,
+			RestartPolicy:                 coreV1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         ptr.To(int64(debugPodDeadline.Seconds())),
+			TerminationGracePeriodSeconds: ptr.To[int64](0),
+			Tolerations:                   []coreV1.Toleration{{Operator: coreV1.TolerationOpExists}},
+			Containers: []coreV1.Container{{
+				Name:            debugContainerName,
+				Image:           image,
+				Stdin:           true,
+				TTY:             true,
+				SecurityContext: &coreV1.SecurityContext{Privileged: ptr.To(true)},
+				VolumeMounts:    []coreV1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+			}},
+			Volumes: []coreV1.Volume{{
+				Name:         "host-root",
+				VolumeSource: coreV1.VolumeSource{HostPath: &coreV1.HostPathVolumeSource{Path: "/"}},
+			}},
+		},
+	}
+}
+
+// debugSession reports the status of the debug pod until ctx is done, then
+// deletes it.
+func debugSession(ctx context.Context, clientSet kubernetes.Interface, pod *coreV1.Pod, publish func(DebugNodeEvent)) {
+	pods := clientSet.CoreV1().Pods(pod.Namespace)
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), debugPodCleanupTimeout)
+		defer cancel()
+		if err := pods.Delete(cleanupCtx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)}); err != nil {
+			log.Warn("failed to delete node debug pod", "namespace", pod.Namespace, "pod", pod.Name, "err", err)
+		}
+	}()
+
+	event := DebugNodeEvent{Type: DebugPodPending, Pod: pod.Name, Namespace: pod.Namespace, Container: debugContainerName, Phase: string(pod.Status.Phase)}
+	publish(event)
+
+	watcher, err := pods.Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+		ResourceVersion: pod.ResourceVersion,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			publish(DebugNodeEvent{Type: DebugPodError, Pod: pod.Name, Namespace: pod.Namespace, Message: err.Error()})
+		}
+		return
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				// The session lives on, the client is only told about it.
+				<-ctx.Done()
+				return
+			}
+			current, isPod := e.Object.(*coreV1.Pod)
+			if !isPod {
+				continue
+			}
+			next := debugPodEvent(current)
+			if e.Type == watch.Deleted {
+				next.Type, next.Message = DebugPodEnded, "the debug pod was deleted"
+			}
+			if next != event {
+				event = next
+				publish(event)
+			}
+			if event.Type == DebugPodEnded {
+				<-ctx.Done()
+				return
+			}
+		}
+	}
+}
+
+func debugPodEvent(pod *coreV1.Pod) DebugNodeEvent {
+	event := DebugNodeEvent{Type: DebugPodPending, Pod: pod.Name, Namespace: pod.Namespace, Container: debugContainerName, Phase: string(pod.Status.Phase)}
+	switch pod.Status.Phase {
+	case coreV1.PodRunning:
+		event.Type = DebugPodReady
+	case coreV1.PodSucceeded, coreV1.PodFailed:
+		event.Type, event.Message = DebugPodEnded, pod.Status.Message
+	default:
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil {
+				event.Message = status.State.Waiting.Reason
+			}
+		}
+	}
+	return event
+}
@@ -0,0 +1,133 @@
+package nodes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeReady(t *testing.T) {
+	withReady := func(status coreV1.ConditionStatus) *coreV1.Node {
+		return &coreV1.Node{Status: coreV1.NodeStatus{Conditions: []coreV1.NodeCondition{
+			{Type: coreV1.NodeMemoryPressure, Status: coreV1.ConditionFalse},
+			{Type: coreV1.NodeReady, Status: status},
+		}}}
+	}
+	assert.True(t, nodeReady(withReady(coreV1.ConditionTrue)))
+	assert.False(t, nodeReady(withReady(coreV1.ConditionFalse)))
+	assert.False(t, nodeReady(withReady(coreV1.ConditionUnknown)))
+	assert.False(t, nodeReady(&coreV1.Node{}))
+}
+
+func TestDebugPod(t *testing.T) {
+	pod := debugPod("worker-1", "tools", "alpine")
+
+	assert.Equal(t, "node-debugger-worker-1-", pod.GenerateName)
+	assert.Equal(t, "tools", pod.Namespace)
+	assert.Equal(t, "worker-1", pod.Labels[debugNodeLabel])
+	assert.Equal(t, "worker-1", pod.Spec.NodeName)
+	assert.True(t, pod.Spec.HostPID)
+	assert.True(t, pod.Spec.HostIPC)
+	assert.True(t, pod.Spec.HostNetwork)
+	assert.Equal(t, coreV1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Equal(t, int64(3600), *pod.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, []coreV1.Toleration{{Operator: coreV1.TolerationOpExists}}, pod.Spec.Tolerations)
+
+	require.Len(t, pod.Spec.Containers, 1)
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, debugContainerName, container.Name)
+	assert.Equal(t, "alpine", container.Image)
+	assert.True(t, container.Stdin)
+	assert.True(t, container.TTY)
+	assert.True(t, *container.SecurityContext.Privileged)
+	assert.Equal(t, "/host", container.VolumeMounts[0].MountPath)
+	assert.Equal(t, "/", pod.Spec.Volumes[0].HostPath.Path)
+}
+
+func TestDebugImageAllowed(t *testing.T) {
+	assert.True(t, debugImageAllowed(defaultDebugImage, nil))
+	assert.False(t, debugImageAllowed("attacker/miner:latest", nil))
+	assert.True(t, debugImageAllowed("nicolaka/netshoot:v0.13", []string{"nicolaka/netshoot:v0.13"}))
+	assert.False(t, debugImageAllowed("nicolaka/netshoot:latest", []string{"nicolaka/netshoot:v0.13"}))
+}
+
+func TestDebugSession(t *testing.T) {
+	pod := debugPod("worker-1", "default", defaultDebugImage)
+	pod.Name = "node-debugger-worker-1-abcde"
+	clientSet := fake.NewClientset(pod)
+
+	events := make(chan DebugNodeEvent, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		debugSession(ctx, clientSet, pod, func(e DebugNodeEvent) { events <- e })
+		close(done)
+	}()
+
+	next := func() DebugNodeEvent {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a debug session event")
+			return DebugNodeEvent{}
+		}
+	}
+	assert.Equal(t, DebugPodPending, next().Type)
+
+	// The watch may start after the update, keep updating until it is seen.
+	running := pod.DeepCopy()
+	running.Status.Phase = coreV1.PodRunning
+	var ready DebugNodeEvent
+	assert.Eventually(t, func() bool {
+		if _, err := clientSet.CoreV1().Pods("default").UpdateStatus(context.Background(), running, metav1.UpdateOptions{}); err != nil {
+			return false
+		}
+		select {
+		case ready = <-events:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, DebugNodeEvent{
+		Type:      DebugPodReady,
+		Pod:       pod.Name,
+		Namespace: "default",
+		Container: debugContainerName,
+		Phase:     string(coreV1.PodRunning),
+	}, ready)
+
+	cancel()
+	<-done
+	_, err := clientSet.CoreV1().Pods("default").Get(context.Background(), pod.Name, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestDebugPodEvent(t *testing.T) {
+	pod := &coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "debugger", Namespace: "default"},
+		Status: coreV1.PodStatus{
+			Phase: coreV1.PodPending,
+			ContainerStatuses: []coreV1.ContainerStatus{{
+				State: coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}},
+		},
+	}
+	got := debugPodEvent(pod)
+	assert.Equal(t, DebugPodPending, got.Type)
+	assert.Equal(t, "ImagePullBackOff", got.Message)
+
+	pod.Status.Phase = coreV1.PodFailed
+	pod.Status.Message = "Pod was active on the node longer than the specified deadline"
+	got = debugPodEvent(pod)
+	assert.Equal(t, DebugPodEnded, got.Type)
+	assert.Equal(t, pod.Status.Message, got.Message)
+}
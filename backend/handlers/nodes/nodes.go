@@ -15,7 +15,10 @@ import (
 )
 
 const (
-	GetPods = 12
+	GetPods        = 12
+	GetTopNodes    = 13
+	DebugNode      = 14
+	WatchDebugNode = 15
 )
 
 type NodeHandler struct {
@@ -37,6 +40,12 @@ func NewNodeRouteHandler(container container.Container, routeType base.RouteType
 			return handler.BaseHandler.GetYaml(c)
 		case GetPods:
 			return handler.GetPods(c)
+		case GetTopNodes:
+			return handler.GetTopNodes(c)
+		case DebugNode:
+			return handler.DebugNode(c)
+		case WatchDebugNode:
+			return handler.WatchDebugNode(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -0,0 +1,85 @@
+package nodes
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+type TopNode struct {
+	Name          string  `json:"name"`
+	CPUMillicores int64   `json:"cpuMillicores"`
+	CPUPercent    float64 `json:"cpuPercent"`
+	MemoryBytes   int64   `json:"memoryBytes"`
+	MemoryPercent float64 `json:"memoryPercent"`
+	MetricsStale  bool    `json:"metricsStale,omitempty"`
+}
+
+// GetTopNodes returns the nodes using the most CPU or memory, like kubectl top
+// nodes. Percentages are of allocatable capacity. It takes ?sortBy=cpu|memory
+// and ?limit=.
+func (h *NodeHandler) GetTopNodes(c echo.Context) error {
+	q, err := helpers.ParseTopQuery(c)
+	if err != nil {
+		return err
+	}
+	if !helpers.IsMetricServerAvailable(h.BaseHandler.Container, h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster) {
+		return echo.NewHTTPError(http.StatusNotImplemented, "metrics unavailable: metrics-server is not installed on this cluster")
+	}
+
+	nodeMetrics, err := h.BaseHandler.Container.
+		MetricClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster).
+		MetricsV1beta1().
+		NodeMetricses().
+		List(c.Request().Context(), metav1.ListOptions{})
+	fresh := make(map[string]v1beta1.NodeMetrics)
+	if err == nil {
+		for _, m := range nodeMetrics.Items {
+			fresh[m.Name] = m
+		}
+	}
+	// Nodes missing from the response, or all of them when the call failed,
+	// fall back to their last-known metrics, unless they were removed.
+	lastKnown := helpers.LastKnownNodeMetrics(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	lastKnown.Prune(h.BaseHandler.Informer.GetStore())
+	merged, stale := lastKnown.Merge(fresh, err)
+	if err != nil && len(merged) == 0 {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	top := make([]TopNode, 0, len(merged))
+	for _, m := range merged {
+		n := TopNode{
+			Name:          m.Name,
+			CPUMillicores: m.Usage.Cpu().MilliValue(),
+			MemoryBytes:   m.Usage.Memory().Value(),
+			MetricsStale:  stale[m.Name],
+		}
+		if obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(m.Name); err == nil && exists {
+			if node, ok := obj.(*coreV1.Node); ok {
+				n.CPUPercent = percent(n.CPUMillicores, node.Status.Allocatable.Cpu().MilliValue())
+				n.MemoryPercent = percent(n.MemoryBytes, node.Status.Allocatable.Memory().Value())
+			}
+		}
+		top = append(top, n)
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Name < top[j].Name })
+
+	top = helpers.TopN(top, q,
+		func(n TopNode) int64 { return n.CPUMillicores },
+		func(n TopNode) int64 { return n.MemoryBytes },
+	)
+	return c.JSON(http.StatusOK, top)
+}
+
+func percent(used, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) * 100 / float64(total)
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	maxPathLength = 1024
+	// maxResults and maxResultBytes bound the response, a range over a large
+	// list or {.} would otherwise send the whole object back.
+	maxResults     = 1000
+	maxResultBytes = 1 << 20 // 1MiB
+)
+
+type QueryResult struct {
+	Path   string `json:"path"`
+	Values []any  `json:"values"`
+	// Truncated is set when values were dropped to stay within the limits.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type QueryHandler struct {
+	container container.Container
+}
+
+func NewQueryHandler(container container.Container) *QueryHandler {
+	return &QueryHandler{container: container}
+}
+
+// QueryResource evaluates the JSONPath expression of ?path= against an object
+// of any resource and returns the values it selects, e.g.
+// path={.status.containerStatuses[*].restartCount}. The braces may be left
+// out. Missing fields select nothing rather than failing.
+func (h *QueryHandler) QueryResource(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	if c.QueryParam("version") == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "version query param is required")
+	}
+	expr, path, err := parsePath(c.QueryParam("path"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	dynamicClient := h.container.DynamicClient(config, cluster)
+	if dynamicClient == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: c.QueryParam("version"), Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	var resource dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resource = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	obj, err := resource.Get(c.Request().Context(), c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	result, err := evaluate(obj, expr, path)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// parsePath parses a JSONPath expression, wrapping it in braces like kubectl
+// does when they are left out. Only the expression language of the jsonpath
+// package is supported, which reads the object and nothing else.
+func parsePath(expr string) (string, *jsonpath.JSONPath, error) {
+	expr = strings.TrimSpace(expr)
+	switch {
+	case expr == "":
+		return "", nil, fmt.Errorf("path query param is required")
+	case len(expr) > maxPathLength:
+		return "", nil, fmt.Errorf("path is longer than %d characters", maxPathLength)
+	}
+	if !strings.HasPrefix(expr, "{") {
+		if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "[") {
+			expr = "." + expr
+		}
+		expr = "{" + expr + "}"
+	}
+	path := jsonpath.New("query").AllowMissingKeys(true)
+	if err := path.Parse(expr); err != nil {
+		return "", nil, fmt.Errorf("invalid path %q: %w", expr, err)
+	}
+	return expr, path, nil
+}
+
+// evaluate returns the values path selects in obj, without managed fields,
+// up to maxResults values and maxResultBytes of JSON.
+func evaluate(obj *unstructured.Unstructured, expr string, path *jsonpath.JSONPath) (QueryResult, error) {
+	obj.SetManagedFields(nil)
+	results, err := path.FindResults(obj.Object)
+	if err != nil {
+		return QueryResult{}, err
+	}
+
+	result := QueryResult{Path: expr, Values: make([]any, 0)}
+	size := 0
+	for _, values := range results {
+		for _, value := range values {
+			if len(result.Values) == maxResults {
+				result.Truncated = true
+				return result, nil
+			}
+			v := value.Interface()
+			data, err := json.Marshal(v)
+			if err != nil {
+				return QueryResult{}, err
+			}
+			if size += len(data); size > maxResultBytes {
+				result.Truncated = true
+				return result, nil
+			}
+			result.Values = append(result.Values, v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, expr := range []string{"", "{.status[}", strings.Repeat("a", maxPathLength+1)} {
+		_, _, err := parsePath(expr)
+		assert.Error(t, err, expr)
+	}
+	for input, want := range map[string]string{
+		"{.status.phase}": "{.status.phase}",
+		".status.phase":   "{.status.phase}",
+		"status.phase":    "{.status.phase}",
+	} {
+		expr, _, err := parsePath(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, expr)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":          "web",
+			"managedFields": []any{map[string]any{"manager": "kubectl"}},
+		},
+		"status": map[string]any{
+			"containerStatuses": []any{
+				map[string]any{"name": "app", "restartCount": int64(2)},
+				map[string]any{"name": "sidecar", "restartCount": int64(0)},
+			},
+		},
+	}}
+	query := func(s string) QueryResult {
+		expr, path, err := parsePath(s)
+		require.NoError(t, err)
+		result, err := evaluate(obj, expr, path)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := query("{.status.containerStatuses[*].restartCount}")
+	assert.Equal(t, []any{int64(2), int64(0)}, result.Values)
+	assert.False(t, result.Truncated)
+	assert.Equal(t, []any{"app"}, query(`{.status.containerStatuses[?(@.restartCount>0)].name}`).Values)
+	assert.Empty(t, query(".status.missing").Values)
+	assert.Empty(t, query(".metadata.managedFields").Values)
+
+	items := make([]any, maxResults+1)
+	for i := range items {
+		items[i] = int64(i)
+	}
+	obj.Object["items"] = items
+	result = query("{.items[*]}")
+	assert.Len(t, result.Values, maxResults)
+	assert.True(t, result.Truncated)
+
+	obj.Object["items"] = []any{strings.Repeat("x", maxResultBytes)}
+	result = query("{.items[*]}")
+	assert.Empty(t, result.Values)
+	assert.True(t, result.Truncated)
+}
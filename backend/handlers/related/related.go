@@ -0,0 +1,213 @@
+package related
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// References says how the object refers to the requested one, e.g.
+	// "volume:config" or "envFrom:app".
+	References []string `json:"references,omitempty"`
+}
+
+type RelatedHandler struct {
+	container container.Container
+}
+
+func NewRelatedHandler(container container.Container) *RelatedHandler {
+	return &RelatedHandler{container: container}
+}
+
+// GetRelatedResources returns the objects related to a Service, ConfigMap,
+// Secret or PersistentVolumeClaim, grouped by kind.
+func (h *RelatedHandler) GetRelatedResources(c echo.Context) error {
+	clientSet := h.container.ClientSet(c.QueryParam("config"), c.QueryParam("cluster"))
+	ctx := c.Request().Context()
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+
+	var related map[string][]ResourceRef
+	var err error
+	switch strings.ToLower(c.Param("kind")) {
+	case "service", "services":
+		related, err = serviceRelated(ctx, clientSet, namespace, name)
+	case "configmap", "configmaps":
+		related, err = consumersRelated(ctx, clientSet, "ConfigMap", namespace, name)
+	case "secret", "secrets":
+		related, err = consumersRelated(ctx, clientSet, "Secret", namespace, name)
+	case "persistentvolumeclaim", "persistentvolumeclaims":
+		related, err = pvcRelated(ctx, clientSet, namespace, name)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("related resources are not supported for kind %s", c.Param("kind")))
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, related)
+}
+
+func serviceRelated(ctx context.Context, clientSet kubernetes.Interface, namespace, name string) (map[string][]ResourceRef, error) {
+	svc, err := clientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	related := map[string][]ResourceRef{
+		"endpoints":      {},
+		"endpointSlices": {},
+		"pods":           {},
+	}
+	if ep, err := clientSet.CoreV1().Endpoints(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		related["endpoints"] = append(related["endpoints"], ResourceRef{Kind: "Endpoints", Namespace: ep.Namespace, Name: ep.Name})
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	slices, err := clientSet.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, slice := range slices.Items {
+		related["endpointSlices"] = append(related["endpointSlices"], ResourceRef{Kind: "EndpointSlice", Namespace: slice.Namespace, Name: slice.Name})
+	}
+
+	// A Service without a selector has manually managed endpoints and no pods.
+	if len(svc.Spec.Selector) == 0 {
+		return related, nil
+	}
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		related["pods"] = append(related["pods"], ResourceRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name})
+	}
+	return related, nil
+}
+
+// consumersRelated returns the pods and workloads whose pod spec references a
+// ConfigMap or Secret.
+func consumersRelated(ctx context.Context, clientSet kubernetes.Interface, kind, namespace, name string) (map[string][]ResourceRef, error) {
+	var err error
+	if kind == "ConfigMap" {
+		_, err = clientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		_, err = clientSet.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	related := map[string][]ResourceRef{
+		"pods":         {},
+		"deployments":  {},
+		"statefulSets": {},
+		"daemonSets":   {},
+		"jobs":         {},
+		"cronJobs":     {},
+	}
+	add := func(group, refKind string, meta metav1.ObjectMeta, spec *v1.PodSpec) {
+		if refs := helpers.PodSpecReferences(spec, kind, name); len(refs) > 0 {
+			related[group] = append(related[group], ResourceRef{Kind: refKind, Namespace: meta.Namespace, Name: meta.Name, References: refs})
+		}
+	}
+
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		add("pods", "Pod", pods.Items[i].ObjectMeta, &pods.Items[i].Spec)
+	}
+
+	deployments, err := clientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		add("deployments", "Deployment", deployments.Items[i].ObjectMeta, &deployments.Items[i].Spec.Template.Spec)
+	}
+
+	statefulSets, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		add("statefulSets", "StatefulSet", statefulSets.Items[i].ObjectMeta, &statefulSets.Items[i].Spec.Template.Spec)
+	}
+
+	daemonSets, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		add("daemonSets", "DaemonSet", daemonSets.Items[i].ObjectMeta, &daemonSets.Items[i].Spec.Template.Spec)
+	}
+
+	jobs, err := clientSet.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		add("jobs", "Job", jobs.Items[i].ObjectMeta, &jobs.Items[i].Spec.Template.Spec)
+	}
+
+	cronJobs, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range cronJobs.Items {
+		add("cronJobs", "CronJob", cronJobs.Items[i].ObjectMeta, &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+	}
+
+	return related, nil
+}
+
+func pvcRelated(ctx context.Context, clientSet kubernetes.Interface, namespace, name string) (map[string][]ResourceRef, error) {
+	pvc, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	related := map[string][]ResourceRef{
+		"persistentVolumes": {},
+		"pods":              {},
+	}
+	if pvc.Spec.VolumeName != "" {
+		related["persistentVolumes"] = append(related["persistentVolumes"], ResourceRef{Kind: "PersistentVolume", Name: pvc.Spec.VolumeName})
+	}
+
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if refs := helpers.PodSpecReferences(&pod.Spec, "PersistentVolumeClaim", name); len(refs) > 0 {
+			related["pods"] = append(related["pods"], ResourceRef{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, References: refs})
+		}
+	}
+	return related, nil
+}
@@ -0,0 +1,36 @@
+package related
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServiceRelated(t *testing.T) {
+	clientSet := fake.NewClientset(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       v1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+		},
+		&discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-abc", Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "web"},
+		}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: map[string]string{"app": "api"}}},
+	)
+
+	related, err := serviceRelated(context.Background(), clientSet, "default", "web")
+	assert.NoError(t, err)
+	assert.Empty(t, related["endpoints"])
+	assert.Equal(t, []ResourceRef{{Kind: "EndpointSlice", Namespace: "default", Name: "web-abc"}}, related["endpointSlices"])
+	assert.Equal(t, []ResourceRef{{Kind: "Pod", Namespace: "default", Name: "web-1"}}, related["pods"])
+
+	_, err = serviceRelated(context.Background(), clientSet, "default", "missing")
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -0,0 +1,73 @@
+package csidrivers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/maruel/natural"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CSIDriver summarizes how Kubernetes attaches and mounts the driver's
+// volumes. Unset fields are reported with the API server's defaults.
+type CSIDriver struct {
+	UID  types.UID `json:"uid"`
+	Name string    `json:"name"`
+	Age  time.Time `json:"age"`
+	// AttachRequired means volumes go through a VolumeAttachment, and so
+	// the external-attacher, before they can be mounted.
+	AttachRequired       bool                            `json:"attachRequired"`
+	PodInfoOnMount       bool                            `json:"podInfoOnMount"`
+	StorageCapacity      bool                            `json:"storageCapacity"`
+	RequiresRepublish    bool                            `json:"requiresRepublish"`
+	SELinuxMount         bool                            `json:"seLinuxMount"`
+	FSGroupPolicy        storageV1.FSGroupPolicy         `json:"fsGroupPolicy"`
+	VolumeLifecycleModes []storageV1.VolumeLifecycleMode `json:"volumeLifecycleModes"`
+	TokenAudiences       []string                        `json:"tokenAudiences"`
+}
+
+func TransformCSIDriver(items []storageV1.CSIDriver) []CSIDriver {
+	list := make([]CSIDriver, 0)
+
+	for _, d := range items {
+		list = append(list, TransformCSIDriverItem(d))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return natural.Less(list[i].Name, list[j].Name)
+	})
+
+	return list
+}
+
+func TransformCSIDriverItem(item storageV1.CSIDriver) CSIDriver {
+	spec := item.Spec
+	fsGroupPolicy := storageV1.ReadWriteOnceWithFSTypeFSGroupPolicy
+	if spec.FSGroupPolicy != nil {
+		fsGroupPolicy = *spec.FSGroupPolicy
+	}
+	modes := spec.VolumeLifecycleModes
+	if len(modes) == 0 {
+		modes = []storageV1.VolumeLifecycleMode{storageV1.VolumeLifecyclePersistent}
+	}
+	audiences := make([]string, 0, len(spec.TokenRequests))
+	for _, request := range spec.TokenRequests {
+		audiences = append(audiences, request.Audience)
+	}
+
+	return CSIDriver{
+		UID:  item.GetUID(),
+		Name: item.GetName(),
+		Age:  item.CreationTimestamp.Time,
+
+		AttachRequired:       spec.AttachRequired == nil || *spec.AttachRequired,
+		PodInfoOnMount:       spec.PodInfoOnMount != nil && *spec.PodInfoOnMount,
+		StorageCapacity:      spec.StorageCapacity != nil && *spec.StorageCapacity,
+		RequiresRepublish:    spec.RequiresRepublish != nil && *spec.RequiresRepublish,
+		SELinuxMount:         spec.SELinuxMount != nil && *spec.SELinuxMount,
+		FSGroupPolicy:        fsGroupPolicy,
+		VolumeLifecycleModes: modes,
+		TokenAudiences:       audiences,
+	}
+}
@@ -0,0 +1,42 @@
+package csidrivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	storageV1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformCSIDriverItem(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		got := TransformCSIDriverItem(storageV1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}})
+		assert.True(t, got.AttachRequired)
+		assert.False(t, got.PodInfoOnMount)
+		assert.Equal(t, storageV1.ReadWriteOnceWithFSTypeFSGroupPolicy, got.FSGroupPolicy)
+		assert.Equal(t, []storageV1.VolumeLifecycleMode{storageV1.VolumeLifecyclePersistent}, got.VolumeLifecycleModes)
+		assert.Empty(t, got.TokenAudiences)
+	})
+
+	t.Run("ephemeral secrets driver", func(t *testing.T) {
+		attach, podInfo, republish := false, true, true
+		policy := storageV1.FileFSGroupPolicy
+		got := TransformCSIDriverItem(storageV1.CSIDriver{
+			ObjectMeta: metav1.ObjectMeta{Name: "secrets-store.csi.k8s.io"},
+			Spec: storageV1.CSIDriverSpec{
+				AttachRequired:       &attach,
+				PodInfoOnMount:       &podInfo,
+				RequiresRepublish:    &republish,
+				FSGroupPolicy:        &policy,
+				VolumeLifecycleModes: []storageV1.VolumeLifecycleMode{storageV1.VolumeLifecycleEphemeral},
+				TokenRequests:        []storageV1.TokenRequest{{Audience: "vault"}},
+			},
+		})
+		assert.False(t, got.AttachRequired)
+		assert.True(t, got.PodInfoOnMount)
+		assert.True(t, got.RequiresRepublish)
+		assert.Equal(t, storageV1.FileFSGroupPolicy, got.FSGroupPolicy)
+		assert.Equal(t, []storageV1.VolumeLifecycleMode{storageV1.VolumeLifecycleEphemeral}, got.VolumeLifecycleModes)
+		assert.Equal(t, []string{"vault"}, got.TokenAudiences)
+	})
+}
@@ -0,0 +1,64 @@
+package csinodes
+
+import (
+	"sort"
+	"time"
+
+	"github.com/maruel/natural"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CSINode lists the CSI drivers installed on a node, it is named after it.
+type CSINode struct {
+	UID     types.UID `json:"uid"`
+	Name    string    `json:"name"`
+	Age     time.Time `json:"age"`
+	Drivers []Driver  `json:"drivers"`
+}
+
+type Driver struct {
+	Name         string   `json:"name"`
+	NodeID       string   `json:"nodeID"`
+	TopologyKeys []string `json:"topologyKeys"`
+	// AllocatableCount is the maximum number of the driver's volumes on the
+	// node, nil when the driver sets no limit.
+	AllocatableCount *int32 `json:"allocatableCount"`
+}
+
+func TransformCSINode(items []storageV1.CSINode) []CSINode {
+	list := make([]CSINode, 0)
+
+	for _, d := range items {
+		list = append(list, TransformCSINodeItem(d))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return natural.Less(list[i].Name, list[j].Name)
+	})
+
+	return list
+}
+
+func TransformCSINodeItem(item storageV1.CSINode) CSINode {
+	drivers := make([]Driver, 0, len(item.Spec.Drivers))
+	for _, d := range item.Spec.Drivers {
+		topologyKeys := d.TopologyKeys
+		if topologyKeys == nil {
+			topologyKeys = []string{}
+		}
+		driver := Driver{Name: d.Name, NodeID: d.NodeID, TopologyKeys: topologyKeys}
+		if d.Allocatable != nil {
+			driver.AllocatableCount = d.Allocatable.Count
+		}
+		drivers = append(drivers, driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].Name < drivers[j].Name })
+
+	return CSINode{
+		UID:     item.GetUID(),
+		Name:    item.GetName(),
+		Age:     item.CreationTimestamp.Time,
+		Drivers: drivers,
+	}
+}
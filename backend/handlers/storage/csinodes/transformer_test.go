@@ -0,0 +1,27 @@
+package csinodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	storageV1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformCSINodeItem(t *testing.T) {
+	count := int32(25)
+	got := TransformCSINodeItem(storageV1.CSINode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec: storageV1.CSINodeSpec{Drivers: []storageV1.CSINodeDriver{
+			{Name: "pd.csi.storage.gke.io", NodeID: "projects/p/zones/z/instances/node-1", TopologyKeys: []string{"topology.gke.io/zone"}, Allocatable: &storageV1.VolumeNodeResources{Count: &count}},
+			{Name: "efs.csi.aws.com", NodeID: "i-123"},
+		}},
+	})
+
+	assert.Equal(t, "node-1", got.Name)
+	assert.Len(t, got.Drivers, 2)
+	assert.Equal(t, "efs.csi.aws.com", got.Drivers[0].Name)
+	assert.Equal(t, []string{}, got.Drivers[0].TopologyKeys)
+	assert.Nil(t, got.Drivers[0].AllocatableCount)
+	assert.Equal(t, int32(25), *got.Drivers[1].AllocatableCount)
+}
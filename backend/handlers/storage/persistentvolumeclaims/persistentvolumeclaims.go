@@ -13,6 +13,8 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 )
 
+const ResizePVC base.RouteType = 12
+
 type PersistentVolumeClaimsHandler struct {
 	BaseHandler base.BaseHandler
 }
@@ -32,6 +34,8 @@ func NewPersistentVolumeClaimsRouteHandler(container container.Container, routeT
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case ResizePVC:
+			return handler.ResizePVC(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
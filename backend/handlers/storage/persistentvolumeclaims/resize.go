@@ -0,0 +1,110 @@
+package persistentvolumeclaims
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ResizeRequest struct {
+	Storage string `json:"storage"`
+}
+
+type ResizeResponse struct {
+	PVC *coreV1.PersistentVolumeClaim `json:"pvc"`
+	// Conditions are the resize related conditions of the claim, e.g.
+	// Resizing or FileSystemResizePending, which tell how far the expansion got.
+	Conditions []coreV1.PersistentVolumeClaimCondition `json:"conditions"`
+}
+
+var resizeConditionTypes = map[coreV1.PersistentVolumeClaimConditionType]bool{
+	coreV1.PersistentVolumeClaimResizing:                true,
+	coreV1.PersistentVolumeClaimFileSystemResizePending: true,
+	coreV1.PersistentVolumeClaimControllerResizeError:   true,
+	coreV1.PersistentVolumeClaimNodeResizeError:         true,
+}
+
+// ResizePVC expands a claim to the requested storage size. Only increases are
+// allowed, and only when the claim's StorageClass allows volume expansion.
+func (h *PersistentVolumeClaimsHandler) ResizePVC(c echo.Context) error {
+	r := new(ResizeRequest)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	pvc, err := resizePVC(c.Request().Context(), clientSet, c.QueryParam("namespace"), c.Param("name"), r.Storage, helpers.ResourceVersion(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ResizeResponse{PVC: pvc, Conditions: resizeConditions(pvc)})
+}
+
+func resizePVC(ctx context.Context, clientSet kubernetes.Interface, namespace, name, storage, resourceVersion string) (*coreV1.PersistentVolumeClaim, error) {
+	size, err := resource.ParseQuantity(storage)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid storage size %q: %s", storage, err))
+	}
+
+	pvc, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if err := helpers.CheckResourceVersion(pvc, coreV1.Resource("persistentvolumeclaims"), resourceVersion); err != nil {
+		return nil, apiError(err)
+	}
+	if current, ok := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]; ok && size.Cmp(current) <= 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("new size %s must be larger than the current size %s", size.String(), current.String()))
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "claim has no storage class, volume expansion is not supported")
+	}
+	storageClass, err := clientSet.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("storage class %s does not allow volume expansion", storageClass.Name))
+	}
+
+	patch, err := json.Marshal(helpers.PreconditionPatch(map[string]any{
+		"spec": map[string]any{
+			"resources": map[string]any{
+				"requests": map[string]string{string(coreV1.ResourceStorage): size.String()},
+			},
+		},
+	}, resourceVersion))
+	if err != nil {
+		return nil, err
+	}
+	pvc, err = clientSet.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return pvc, nil
+}
+
+func resizeConditions(pvc *coreV1.PersistentVolumeClaim) []coreV1.PersistentVolumeClaimCondition {
+	conditions := make([]coreV1.PersistentVolumeClaimCondition, 0)
+	for _, condition := range pvc.Status.Conditions {
+		if resizeConditionTypes[condition.Type] {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+func apiError(err error) error {
+	return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+}
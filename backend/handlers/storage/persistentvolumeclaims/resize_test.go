@@ -0,0 +1,79 @@
+package persistentvolumeclaims
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newClaim(name, class, size string) *coreV1.PersistentVolumeClaim {
+	return &coreV1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", ResourceVersion: "3"},
+		Spec: coreV1.PersistentVolumeClaimSpec{
+			StorageClassName: &class,
+			Resources: coreV1.VolumeResourceRequirements{
+				Requests: coreV1.ResourceList{coreV1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func TestResizePVC(t *testing.T) {
+	allow, deny := true, false
+	clientSet := fake.NewClientset(
+		&storageV1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "expandable"}, AllowVolumeExpansion: &allow},
+		&storageV1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}, AllowVolumeExpansion: &deny},
+		newClaim("data", "expandable", "1Gi"),
+		newClaim("logs", "fixed", "1Gi"),
+	)
+
+	tests := []struct {
+		name     string
+		claim    string
+		storage  string
+		version  string
+		wantCode int
+	}{
+		{name: "stale resourceVersion", claim: "data", storage: "2Gi", version: "2", wantCode: http.StatusConflict},
+		{name: "expand", claim: "data", storage: "2Gi", version: "3"},
+		{name: "shrink", claim: "data", storage: "500Mi", wantCode: http.StatusBadRequest},
+		{name: "same size", claim: "data", storage: "2Gi", wantCode: http.StatusBadRequest},
+		{name: "invalid size", claim: "data", storage: "big", wantCode: http.StatusBadRequest},
+		{name: "expansion not allowed", claim: "logs", storage: "2Gi", wantCode: http.StatusBadRequest},
+		{name: "missing claim", claim: "nope", storage: "2Gi", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc, err := resizePVC(context.Background(), clientSet, "default", tt.claim, tt.storage, tt.version)
+			if tt.wantCode != 0 {
+				var httpErr *echo.HTTPError
+				assert.True(t, errors.As(err, &httpErr))
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "2Gi", pvc.Spec.Resources.Requests.Storage().String())
+		})
+	}
+}
+
+func TestResizeConditions(t *testing.T) {
+	pvc := &coreV1.PersistentVolumeClaim{Status: coreV1.PersistentVolumeClaimStatus{Conditions: []coreV1.PersistentVolumeClaimCondition{
+		{Type: coreV1.PersistentVolumeClaimResizing, Status: coreV1.ConditionTrue},
+		{Type: "ModifyingVolume", Status: coreV1.ConditionTrue},
+		{Type: coreV1.PersistentVolumeClaimFileSystemResizePending, Status: coreV1.ConditionTrue},
+	}}}
+	got := resizeConditions(pvc)
+	assert.Len(t, got, 2)
+	assert.Equal(t, coreV1.PersistentVolumeClaimResizing, got[0].Type)
+	assert.Equal(t, coreV1.PersistentVolumeClaimFileSystemResizePending, got[1].Type)
+}
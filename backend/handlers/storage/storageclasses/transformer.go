@@ -19,8 +19,18 @@ type StorageClass struct {
 	Provisioner       string                            `json:"provisioner"`
 	ReclaimPolicy     *v1.PersistentVolumeReclaimPolicy `json:"reclaimPolicy"`
 	VolumeBindingMode *storageV1.VolumeBindingMode      `json:"VolumeBindingMode"`
+	// IsDefault is set when the class is annotated as the cluster default,
+	// which is used for claims without a storageClassName.
+	IsDefault            bool              `json:"isDefault"`
+	AllowVolumeExpansion bool              `json:"allowVolumeExpansion"`
+	Parameters           map[string]string `json:"parameters"`
 }
 
+const (
+	isDefaultClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaIsDefaultClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
 func TransformStorageClass(items []storageV1.StorageClass) []StorageClass {
 	list := make([]StorageClass, 0)
 
@@ -36,6 +46,10 @@ func TransformStorageClass(items []storageV1.StorageClass) []StorageClass {
 }
 
 func TransformStorageClassItem(item storageV1.StorageClass) StorageClass {
+	parameters := item.Parameters
+	if parameters == nil {
+		parameters = map[string]string{}
+	}
 	return StorageClass{
 		UID:               item.GetUID(),
 		Namespace:         item.GetNamespace(),
@@ -44,5 +58,15 @@ func TransformStorageClassItem(item storageV1.StorageClass) StorageClass {
 		ReclaimPolicy:     item.ReclaimPolicy,
 		VolumeBindingMode: item.VolumeBindingMode,
 		Age:               item.CreationTimestamp.Time,
+
+		IsDefault:            isDefaultClass(item),
+		AllowVolumeExpansion: item.AllowVolumeExpansion != nil && *item.AllowVolumeExpansion,
+		Parameters:           parameters,
 	}
 }
+
+// isDefaultClass follows the API server, which still honours the beta annotation.
+func isDefaultClass(item storageV1.StorageClass) bool {
+	return item.Annotations[isDefaultClassAnnotation] == "true" ||
+		item.Annotations[betaIsDefaultClassAnnotation] == "true"
+}
@@ -0,0 +1,55 @@
+package storageclasses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	storageV1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformStorageClassItem(t *testing.T) {
+	allow := true
+	tests := []struct {
+		name       string
+		item       storageV1.StorageClass
+		isDefault  bool
+		expansion  bool
+		parameters map[string]string
+	}{
+		{
+			name: "default with expansion",
+			item: storageV1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "gp3", Annotations: map[string]string{isDefaultClassAnnotation: "true"}},
+				Parameters:           map[string]string{"type": "gp3"},
+				AllowVolumeExpansion: &allow,
+			},
+			isDefault:  true,
+			expansion:  true,
+			parameters: map[string]string{"type": "gp3"},
+		},
+		{
+			name: "beta default annotation",
+			item: storageV1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{betaIsDefaultClassAnnotation: "true"}},
+			},
+			isDefault:  true,
+			parameters: map[string]string{},
+		},
+		{
+			name: "not default",
+			item: storageV1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "slow", Annotations: map[string]string{isDefaultClassAnnotation: "false"}},
+			},
+			parameters: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TransformStorageClassItem(tt.item)
+			assert.Equal(t, tt.isDefault, got.IsDefault)
+			assert.Equal(t, tt.expansion, got.AllowVolumeExpansion)
+			assert.Equal(t, tt.parameters, got.Parameters)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package tablewatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// EventTable carries the columns and every row. It is sent first and
+	// again whenever the watch has to be restarted from a fresh list.
+	EventTable = "TABLE"
+	EventError = "ERROR"
+)
+
+// tableAccept asks the API server for the server-side printed table, the
+// columns kubectl get shows, including those of CRDs.
+const tableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// rewatchDelay paces restarts of watches that keep failing.
+const rewatchDelay = time.Second
+
+type TableRow struct {
+	UID       string `json:"uid"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Cells     []any  `json:"cells"`
+}
+
+// TableEvent is sent on the stream. A "TABLE" event replaces the whole table,
+// "ADDED", "MODIFIED" and "DELETED" events update a single row by uid.
+type TableEvent struct {
+	Type            string                         `json:"type"`
+	Columns         []metav1.TableColumnDefinition `json:"columns,omitempty"`
+	Rows            []TableRow                     `json:"rows,omitempty"`
+	Row             *TableRow                      `json:"row,omitempty"`
+	ResourceVersion string                         `json:"resourceVersion,omitempty"`
+	Message         string                         `json:"message,omitempty"`
+}
+
+// tableSource lists a resource as a table and watches it from a
+// resourceVersion, returning the raw JSON watch stream.
+type tableSource interface {
+	list(ctx context.Context) ([]byte, error)
+	watch(ctx context.Context, resourceVersion string) (io.ReadCloser, error)
+}
+
+type TableWatchHandler struct {
+	container container.Container
+}
+
+func NewTableWatchHandler(container container.Container) *TableWatchHandler {
+	return &TableWatchHandler{container: container}
+}
+
+// WatchResourceTable is the kubectl get --watch equivalent for any resource:
+// it streams the server-computed table, then row updates as objects change.
+// MODIFIED events are only sent when a cell of the row changed.
+func (h *TableWatchHandler) WatchResourceTable(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+
+	clientSet := h.container.ClientSet(config, cluster)
+	if clientSet == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	version := c.QueryParam("version")
+	if version == "" {
+		version = "v1"
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: version, Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+	source := &restTableSource{client: clientSet.CoreV1().RESTClient(), path: resourcePath(gvr, namespace)}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-%s-%s-%s-table", config, cluster, gvr.Group, gvr.Resource, namespace)
+	sseServer.CreateStream(streamKey)
+
+	go watchTable(c.Request().Context(), source, func(e TableEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error("failed to marshal table event", "err", err)
+			return
+		}
+		sseServer.Publish(streamKey, &sse.Event{Data: data})
+	})
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func resourcePath(gvr schema.GroupVersionResource, namespace string) string {
+	path := "/apis/" + gvr.Group + "/" + gvr.Version
+	if gvr.Group == "" {
+		path = "/api/" + gvr.Version
+	}
+	if namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	return path + "/" + gvr.Resource
+}
+
+type restTableSource struct {
+	client rest.Interface
+	path   string
+}
+
+func (s *restTableSource) list(ctx context.Context) ([]byte, error) {
+	return s.client.Get().AbsPath(s.path).
+		SetHeader("Accept", tableAccept).
+		Param("includeObject", string(metav1.IncludeMetadata)).
+		DoRaw(ctx)
+}
+
+func (s *restTableSource) watch(ctx context.Context, resourceVersion string) (io.ReadCloser, error) {
+	return s.client.Get().AbsPath(s.path).
+		SetHeader("Accept", tableAccept).
+		Param("includeObject", string(metav1.IncludeMetadata)).
+		Param("watch", "true").
+		Param("allowWatchBookmarks", "true").
+		Param("resourceVersion", resourceVersion).
+		Stream(ctx)
+}
+
+// watchTable lists and watches until ctx is done. A watch that ends is resumed
+// from the last seen resourceVersion, an expired one starts over from a list.
+func watchTable(ctx context.Context, source tableSource, publish func(TableEvent)) {
+	sent := make(map[string][]any)
+	resourceVersion := ""
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			table, err := listTable(ctx, source)
+			if err != nil {
+				if ctx.Err() == nil {
+					publish(TableEvent{Type: EventError, Message: err.Error()})
+				}
+				return
+			}
+			sent = make(map[string][]any, len(table.Rows))
+			for _, row := range table.Rows {
+				sent[row.UID] = row.Cells
+			}
+			publish(table)
+			resourceVersion = table.ResourceVersion
+		}
+
+		var err error
+		resourceVersion, err = followWatch(ctx, source, resourceVersion, sent, publish)
+		if err != nil && ctx.Err() == nil {
+			log.Warn("table watch ended", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(rewatchDelay):
+		}
+	}
+}
+
+func listTable(ctx context.Context, source tableSource) (TableEvent, error) {
+	body, err := source.list(ctx)
+	if err != nil {
+		return TableEvent{}, err
+	}
+	table := new(metav1.Table)
+	if err := json.Unmarshal(body, table); err != nil {
+		return TableEvent{}, err
+	}
+	if table.Kind != "Table" {
+		return TableEvent{}, errors.New("the API server did not return a table for this resource")
+	}
+
+	event := TableEvent{
+		Type:            EventTable,
+		Columns:         table.ColumnDefinitions,
+		Rows:            make([]TableRow, 0, len(table.Rows)),
+		ResourceVersion: table.ResourceVersion,
+	}
+	for _, row := range table.Rows {
+		event.Rows = append(event.Rows, tableRow(row))
+	}
+	return event, nil
+}
+
+type watchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// followWatch publishes the row changes of one watch request and returns the
+// resourceVersion to resume from, empty when a new list is needed.
+func followWatch(ctx context.Context, source tableSource, resourceVersion string, sent map[string][]any, publish func(TableEvent)) (string, error) {
+	stream, err := source.watch(ctx, resourceVersion)
+	if err != nil {
+		return resourceVersion, err
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var event watchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return resourceVersion, nil
+			}
+			return resourceVersion, err
+		}
+
+		if event.Type == watch.Error {
+			status := new(metav1.Status)
+			if err := json.Unmarshal(event.Object, status); err == nil && status.Code == http.StatusGone {
+				return "", nil
+			}
+			return resourceVersion, fmt.Errorf("watch error: %s", event.Object)
+		}
+
+		table := new(metav1.Table)
+		if err := json.Unmarshal(event.Object, table); err != nil {
+			return resourceVersion, err
+		}
+		if table.ResourceVersion != "" {
+			resourceVersion = table.ResourceVersion
+		}
+		if event.Type == watch.Bookmark {
+			continue
+		}
+
+		for _, r := range table.Rows {
+			row := tableRow(r)
+			if row.UID == "" {
+				continue
+			}
+			if v := rowResourceVersion(r); v != "" {
+				resourceVersion = v
+			}
+
+			switch event.Type {
+			case watch.Deleted:
+				delete(sent, row.UID)
+				publish(TableEvent{Type: string(event.Type), Row: &TableRow{UID: row.UID, Name: row.Name, Namespace: row.Namespace}})
+			case watch.Added, watch.Modified:
+				if previous, ok := sent[row.UID]; ok && reflect.DeepEqual(previous, row.Cells) {
+					continue
+				}
+				eventType := watch.Modified
+				if _, ok := sent[row.UID]; !ok {
+					eventType = watch.Added
+				}
+				sent[row.UID] = row.Cells
+				publish(TableEvent{Type: string(eventType), Row: &row})
+			}
+		}
+	}
+}
+
+func tableRow(row metav1.TableRow) TableRow {
+	tableRow := TableRow{Cells: row.Cells}
+	if meta := rowMetadata(row); meta != nil {
+		tableRow.UID = string(meta.UID)
+		tableRow.Name = meta.Name
+		tableRow.Namespace = meta.Namespace
+	}
+	return tableRow
+}
+
+func rowResourceVersion(row metav1.TableRow) string {
+	if meta := rowMetadata(row); meta != nil {
+		return meta.ResourceVersion
+	}
+	return ""
+}
+
+// rowMetadata decodes the PartialObjectMetadata requested with
+// includeObject=Metadata.
+func rowMetadata(row metav1.TableRow) *metav1.PartialObjectMetadata {
+	if len(row.Object.Raw) == 0 {
+		return nil
+	}
+	meta := new(metav1.PartialObjectMetadata)
+	if err := json.Unmarshal(row.Object.Raw, meta); err != nil {
+		return nil
+	}
+	return meta
+}
@@ -0,0 +1,113 @@
+package tablewatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func row(uid, name, status, rv string) string {
+	return fmt.Sprintf(`{"cells":[%q,%q],"object":{"kind":"PartialObjectMetadata","apiVersion":"meta.k8s.io/v1","metadata":{"name":%q,"namespace":"default","uid":%q,"resourceVersion":%q}}}`, name, status, name, uid, rv)
+}
+
+func watchLine(eventType, rows string) string {
+	return fmt.Sprintf(`{"type":%q,"object":{"kind":"Table","apiVersion":"meta.k8s.io/v1","metadata":{},"rows":[%s]}}`+"\n", eventType, rows)
+}
+
+type fakeSource struct {
+	mu      sync.Mutex
+	lists   []string
+	watches []string
+	rvs     []string
+}
+
+func (s *fakeSource) list(context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.lists) == 0 {
+		return nil, errors.New("no more lists")
+	}
+	body := s.lists[0]
+	s.lists = s.lists[1:]
+	return []byte(body), nil
+}
+
+func (s *fakeSource) watch(ctx context.Context, resourceVersion string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rvs = append(s.rvs, resourceVersion)
+	if len(s.watches) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	body := s.watches[0]
+	s.watches = s.watches[1:]
+	return io.NopCloser(strings.NewReader(body)), nil
+}
+
+func TestWatchTable(t *testing.T) {
+	list := `{"kind":"Table","apiVersion":"meta.k8s.io/v1","metadata":{"resourceVersion":"10"},` +
+		`"columnDefinitions":[{"name":"Name","type":"string","format":"name"},{"name":"Status","type":"string"}],` +
+		`"rows":[` + row("a", "web-a", "Running", "9") + `]}`
+	source := &fakeSource{
+		lists: []string{list, list},
+		watches: []string{
+			watchLine("ADDED", row("a", "web-a", "Running", "9")) +
+				watchLine("MODIFIED", row("a", "web-a", "Running", "11")) +
+				watchLine("ADDED", row("b", "web-b", "Pending", "12")) +
+				watchLine("MODIFIED", row("b", "web-b", "Running", "13")),
+			watchLine("DELETED", row("b", "web-b", "Running", "14")) +
+				`{"type":"ERROR","object":{"kind":"Status","apiVersion":"v1","status":"Failure","code":410,"reason":"Expired"}}` + "\n",
+		},
+	}
+
+	var mu sync.Mutex
+	var events []TableEvent
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchTable(ctx, source, func(e TableEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, EventTable, events[0].Type)
+	assert.Len(t, events[0].Columns, 2)
+	assert.Equal(t, []TableRow{{UID: "a", Name: "web-a", Namespace: "default", Cells: []any{"web-a", "Running"}}}, events[0].Rows)
+
+	// Events that do not change the cells of web-a are dropped.
+	assert.Equal(t, "ADDED", events[1].Type)
+	assert.Equal(t, "b", events[1].Row.UID)
+	assert.Equal(t, "MODIFIED", events[2].Type)
+	assert.Equal(t, []any{"web-b", "Running"}, events[2].Row.Cells)
+	assert.Equal(t, "DELETED", events[3].Type)
+	assert.Equal(t, "web-b", events[3].Row.Name)
+
+	// The expired watch starts over with a fresh table.
+	assert.Equal(t, EventTable, events[4].Type)
+	assert.Equal(t, []string{"10", "13", "10"}, source.rvs[:3])
+}
+
+func TestResourcePath(t *testing.T) {
+	assert.Equal(t, "/api/v1/namespaces/default/pods", resourcePath(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, "default"))
+	assert.Equal(t, "/apis/cert-manager.io/v1/certificates", resourcePath(schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}, ""))
+}
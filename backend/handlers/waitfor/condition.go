@@ -0,0 +1,108 @@
+package waitfor
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	kindCondition = "condition"
+	kindJSONPath  = "jsonpath"
+	kindDelete    = "delete"
+)
+
+// condition is a parsed "for" query parameter.
+type condition struct {
+	kind string
+	// name is the condition type or the JSONPath expression.
+	name string
+	// value is the expected condition status or JSONPath value. An empty
+	// value on a JSONPath condition only requires the path to exist.
+	value string
+	path  *jsonpath.JSONPath
+}
+
+func parseFor(s string) (condition, error) {
+	kind, rest, _ := strings.Cut(s, "=")
+	switch strings.ToLower(kind) {
+	case kindDelete:
+		if rest != "" {
+			return condition{}, fmt.Errorf("delete takes no arguments")
+		}
+		return condition{kind: kindDelete}, nil
+	case kindCondition:
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			value = "True"
+		}
+		if name == "" || value == "" {
+			return condition{}, fmt.Errorf("invalid condition %q", s)
+		}
+		return condition{kind: kindCondition, name: name, value: value}, nil
+	case kindJSONPath:
+		end := strings.LastIndex(rest, "}")
+		if !strings.HasPrefix(rest, "{") || end < 0 {
+			return condition{}, fmt.Errorf("jsonpath expression must be wrapped in {}: %q", rest)
+		}
+		expr, value := rest[:end+1], rest[end+1:]
+		if value != "" {
+			if !strings.HasPrefix(value, "=") {
+				return condition{}, fmt.Errorf("invalid jsonpath condition %q", s)
+			}
+			value = value[1:]
+		}
+		path := jsonpath.New("wait").AllowMissingKeys(true)
+		if err := path.Parse(expr); err != nil {
+			return condition{}, fmt.Errorf("invalid jsonpath expression %q: %w", expr, err)
+		}
+		return condition{kind: kindJSONPath, name: expr, value: value, path: path}, nil
+	default:
+		return condition{}, fmt.Errorf("for must be one of condition=<type>[=<status>], jsonpath={<expr>}[=<value>] or delete")
+	}
+}
+
+// check reports whether obj meets the condition and what was observed.
+func (c condition) check(obj *unstructured.Unstructured) (bool, string, error) {
+	switch c.kind {
+	case kindCondition:
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, item := range conditions {
+			cond, ok := item.(map[string]any)
+			if !ok || !strings.EqualFold(fmt.Sprint(cond["type"]), c.name) {
+				continue
+			}
+			status := fmt.Sprint(cond["status"])
+			return strings.EqualFold(status, c.value), status, nil
+		}
+		return false, "", nil
+	case kindJSONPath:
+		results, err := c.path.FindResults(obj.Object)
+		if err != nil {
+			return false, "", err
+		}
+		if len(results) == 0 || len(results[0]) == 0 {
+			return false, "", nil
+		}
+		observed := fmt.Sprint(results[0][0].Interface())
+		return c.value == "" || observed == c.value, observed, nil
+	default:
+		return false, "exists", nil
+	}
+}
+
+func (c condition) String() string {
+	switch c.kind {
+	case kindDelete:
+		return "deletion"
+	case kindJSONPath:
+		if c.value == "" {
+			return c.name
+		}
+		return fmt.Sprintf("%s=%s", c.name, c.value)
+	default:
+		return fmt.Sprintf("condition %s=%s", c.name, c.value)
+	}
+}
@@ -0,0 +1,61 @@
+package waitfor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseFor(t *testing.T) {
+	invalid := []string{"", "ready", "delete=now", "condition=", "condition=Ready=", "jsonpath=.status", "jsonpath={.status}Running", "jsonpath={.status[}"}
+	for _, s := range invalid {
+		_, err := parseFor(s)
+		assert.Error(t, err, s)
+	}
+
+	cond, err := parseFor("condition=Available")
+	assert.NoError(t, err)
+	assert.Equal(t, "True", cond.value)
+
+	cond, err = parseFor("jsonpath={.status.phase}=Succeeded")
+	assert.NoError(t, err)
+	assert.Equal(t, "{.status.phase}", cond.name)
+	assert.Equal(t, "Succeeded", cond.value)
+}
+
+func TestConditionCheck(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"phase": "Running",
+			"conditions": []any{
+				map[string]any{"type": "Available", "status": "True"},
+				map[string]any{"type": "Progressing", "status": "False"},
+			},
+		},
+	}}
+
+	tests := []struct {
+		for_     string
+		met      bool
+		observed string
+	}{
+		{"condition=available", true, "True"},
+		{"condition=Progressing", false, "False"},
+		{"condition=Progressing=false", true, "False"},
+		{"condition=Complete", false, ""},
+		{"jsonpath={.status.phase}=Running", true, "Running"},
+		{"jsonpath={.status.phase}=Succeeded", false, "Running"},
+		{"jsonpath={.status.phase}", true, "Running"},
+		{"jsonpath={.status.missing}", false, ""},
+		{"delete", false, "exists"},
+	}
+	for _, tt := range tests {
+		cond, err := parseFor(tt.for_)
+		assert.NoError(t, err, tt.for_)
+		met, observed, err := cond.check(obj)
+		assert.NoError(t, err, tt.for_)
+		assert.Equal(t, tt.met, met, tt.for_)
+		assert.Equal(t, tt.observed, observed, tt.for_)
+	}
+}
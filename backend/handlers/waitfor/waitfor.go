@@ -0,0 +1,186 @@
+package waitfor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+const (
+	defaultTimeout = time.Minute
+	maxTimeout     = 30 * time.Minute
+)
+
+const (
+	EventProgress = "progress"
+	EventDone     = "done"
+)
+
+// WaitEvent is sent on the stream for every observed change of the resource,
+// followed by a single "done" event once the wait ends.
+type WaitEvent struct {
+	Type string `json:"type"`
+	// Observed is the current condition status, JSONPath value, or
+	// "notFound"/"deleted" when the resource does not exist.
+	Observed string `json:"observed,omitempty"`
+	Met      bool   `json:"met"`
+	// Reason is set on the done event: "met", "timeout" or "error".
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Retryable and RetryAfterMs tell whether waiting again can help after
+	// an "error", see helpers.StreamError.
+	Retryable    bool  `json:"retryable,omitempty"`
+	RetryAfterMs int64 `json:"retryAfterMs,omitempty"`
+}
+
+type WaitHandler struct {
+	container container.Container
+}
+
+func NewWaitHandler(container container.Container) *WaitHandler {
+	return &WaitHandler{container: container}
+}
+
+// WaitForCondition is the kubectl wait equivalent. It streams the state of a
+// resource until the "for" query condition is met or the timeout elapses.
+//
+// Supported conditions are "condition=<type>[=<status>]",
+// "jsonpath={<expr>}[=<value>]" and "delete".
+func (h *WaitHandler) WaitForCondition(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace := c.QueryParam("namespace")
+	name := c.Param("name")
+
+	cond, err := parseFor(c.QueryParam("for"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	timeout, err := parseTimeout(c.QueryParam("timeout"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	client := h.container.DynamicClient(config, cluster)
+	if client == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	version := c.QueryParam("version")
+	if version == "" {
+		version = "v1"
+	}
+	gvr := schema.GroupVersionResource{Group: c.QueryParam("group"), Version: version, Resource: c.Param("resource")}
+	if err := helpers.CheckResourceAllowed(h.container, gvr.GroupResource()); err != nil {
+		return err
+	}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-%s-%s-%s-wait", config, cluster, gvr.Resource, namespace, name)
+	sseServer.CreateStream(streamKey)
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	defer cancel()
+
+	go waitFor(ctx, client.Resource(gvr).Namespace(namespace), name, cond, func(e WaitEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error("failed to marshal wait event", "err", err)
+			return
+		}
+		sseServer.Publish(streamKey, &sse.Event{Data: data})
+	})
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func waitFor(ctx context.Context, ri dynamic.ResourceInterface, name string, cond condition, publish func(WaitEvent)) {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return ri.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return ri.Watch(ctx, options)
+		},
+	}
+
+	precondition := func(store cache.Store) (bool, error) {
+		if len(store.List()) > 0 {
+			return false, nil
+		}
+		met := cond.kind == kindDelete
+		publish(WaitEvent{Type: EventProgress, Observed: "notFound", Met: met})
+		return met, nil
+	}
+
+	_, err := watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, precondition, func(e watch.Event) (bool, error) {
+		switch e.Type {
+		case watch.Deleted:
+			met := cond.kind == kindDelete
+			publish(WaitEvent{Type: EventProgress, Observed: "deleted", Met: met})
+			return met, nil
+		case watch.Added, watch.Modified:
+			obj, ok := e.Object.(*unstructured.Unstructured)
+			if !ok {
+				return false, nil
+			}
+			met, observed, err := cond.check(obj)
+			if err != nil {
+				return false, err
+			}
+			publish(WaitEvent{Type: EventProgress, Observed: observed, Met: met})
+			return met, nil
+		default:
+			return false, nil
+		}
+	})
+
+	switch {
+	case err == nil:
+		publish(WaitEvent{Type: EventDone, Met: true, Reason: "met"})
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		publish(WaitEvent{Type: EventDone, Reason: "timeout", Message: fmt.Sprintf("timed out waiting for %s", cond)})
+	case ctx.Err() != nil:
+		// The client went away, there is nobody left to tell.
+	default:
+		streamError := helpers.NewStreamError(err)
+		publish(WaitEvent{Type: EventDone, Reason: "error", Message: err.Error(), Retryable: streamError.Retryable, RetryAfterMs: streamError.RetryAfterMs})
+	}
+}
+
+func parseTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", s, err)
+	}
+	if timeout <= 0 || timeout > maxTimeout {
+		return 0, fmt.Errorf("timeout must be between 0 and %s", maxTimeout)
+	}
+	return timeout, nil
+}
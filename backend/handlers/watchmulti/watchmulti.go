@@ -0,0 +1,201 @@
+package watchmulti
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	maxTargets = 20
+	// syncTimeout bounds the wait for an informer, one that cannot list,
+	// e.g. when forbidden, never syncs.
+	syncTimeout = 15 * time.Second
+)
+
+// Target is one object to watch.
+type Target struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (t Target) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: t.Group, Version: t.Version, Resource: t.Resource}
+}
+
+func (t Target) key() string {
+	if t.Namespace == "" {
+		return t.Name
+	}
+	return t.Namespace + "/" + t.Name
+}
+
+// TargetEvent is the state of one target, sent once the informers synced and
+// on every change after.
+type TargetEvent struct {
+	Target          Target         `json:"target"`
+	Exists          bool           `json:"exists"`
+	ResourceVersion string         `json:"resourceVersion,omitempty"`
+	Generation      int64          `json:"generation,omitempty"`
+	Status          map[string]any `json:"status,omitempty"`
+	Error           string         `json:"error,omitempty"`
+}
+
+type WatchMultiHandler struct {
+	container container.Container
+}
+
+func NewWatchMultiHandler(container container.Container) *WatchMultiHandler {
+	return &WatchMultiHandler{container: container}
+}
+
+// WatchMulti streams the status of the objects in ?targets=, a JSON array of
+// {group, version, resource, namespace, name}, over one connection. Targets
+// of the same resource share its dynamic informer.
+func (h *WatchMultiHandler) WatchMulti(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	targets, err := parseTargets(c.QueryParam("targets"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	for _, t := range targets {
+		if err := helpers.CheckResourceAllowed(h.container, t.gvr().GroupResource()); err != nil {
+			return err
+		}
+	}
+	factory := h.container.DynamicSharedInformerFactory(config, cluster)
+	if factory == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-watch-multi", config, cluster)
+	sseServer.CreateStream(streamKey)
+
+	go watchTargets(c.Request().Context(), factory, h.container.InformerStopCh(config, cluster), targets, func(e TargetEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error("failed to marshal watch event", "err", err)
+			return
+		}
+		sseServer.Publish(streamKey, &sse.Event{Data: data})
+	})
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func parseTargets(value string) ([]Target, error) {
+	if value == "" {
+		return nil, fmt.Errorf("targets query param is required")
+	}
+	var targets []Target
+	if err := json.Unmarshal([]byte(value), &targets); err != nil {
+		return nil, fmt.Errorf("invalid targets: %w", err)
+	}
+	if len(targets) == 0 || len(targets) > maxTargets {
+		return nil, fmt.Errorf("between 1 and %d targets are allowed", maxTargets)
+	}
+	for _, t := range targets {
+		if t.Version == "" || t.Resource == "" || t.Name == "" {
+			return nil, fmt.Errorf("every target needs a version, resource and name")
+		}
+	}
+	return targets, nil
+}
+
+// watchTargets publishes the state of every target until ctx is done. Each
+// target gets its own filtered handler on the shared informer of its
+// resource, removed again when the stream ends. The factory is started with
+// stopCh, the informers outlive the stream.
+func watchTargets(ctx context.Context, factory dynamicinformer.DynamicSharedInformerFactory, stopCh <-chan struct{}, targets []Target, publish func(TargetEvent)) {
+	informers := make(map[schema.GroupVersionResource]cache.SharedIndexInformer)
+	for _, t := range targets {
+		if _, ok := informers[t.gvr()]; !ok {
+			informer := factory.ForResource(t.gvr()).Informer()
+			// Fails once the informer runs, informers started by the custom
+			// resources handler set the same transform.
+			_ = informer.SetTransform(helpers.StripUnusedFields)
+			informers[t.gvr()] = informer
+		}
+	}
+	go factory.Start(stopCh)
+
+	synced := make(map[schema.GroupVersionResource]bool)
+	for gvr, informer := range informers {
+		syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+		synced[gvr] = cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced)
+		cancel()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	for _, t := range targets {
+		if !synced[t.gvr()] {
+			publish(TargetEvent{Target: t, Error: fmt.Sprintf("failed to watch %s, the resource may not exist or not be listable", t.gvr().GroupResource())})
+			continue
+		}
+		informer := informers[t.gvr()]
+		// The handler replays the store as adds, which sends the initial state
+		// of an existing target without missing changes in between.
+		registration, err := informer.AddEventHandler(targetHandler(t, publish))
+		if err != nil {
+			publish(TargetEvent{Target: t, Error: err.Error()})
+			continue
+		}
+		defer func() { _ = informer.RemoveEventHandler(registration) }()
+		cache.WaitForCacheSync(ctx.Done(), registration.HasSynced)
+		if _, exists, _ := informer.GetStore().GetByKey(t.key()); !exists {
+			publish(TargetEvent{Target: t})
+		}
+	}
+	<-ctx.Done()
+}
+
+func targetHandler(t Target, publish func(TargetEvent)) cache.ResourceEventHandler {
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj any) bool {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			return err == nil && key == t.key()
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj any) { publish(targetEvent(t, obj)) },
+			UpdateFunc: func(_, obj any) { publish(targetEvent(t, obj)) },
+			DeleteFunc: func(any) { publish(TargetEvent{Target: t}) },
+		},
+	}
+}
+
+func targetEvent(t Target, obj any) TargetEvent {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return TargetEvent{Target: t, Error: fmt.Sprintf("unexpected object %T", obj)}
+	}
+	status, _, _ := unstructured.NestedMap(u.Object, "status")
+	return TargetEvent{
+		Target:          t,
+		Exists:          true,
+		ResourceVersion: u.GetResourceVersion(),
+		Generation:      u.GetGeneration(),
+		Status:          status,
+	}
+}
@@ -0,0 +1,95 @@
+package watchmulti
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func object(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestParseTargets(t *testing.T) {
+	targets, err := parseTargets(`[{"group":"apps","version":"v1","resource":"deployments","namespace":"default","name":"web"}]`)
+	require.NoError(t, err)
+	assert.Equal(t, "default/web", targets[0].key())
+
+	for _, value := range []string{"", "[]", "{", `[{"version":"v1","resource":"services"}]`} {
+		_, err := parseTargets(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestWatchTargets(t *testing.T) {
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	services := schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	deployment := object("apps/v1", "Deployment", "default", "web")
+	require.NoError(t, unstructured.SetNestedField(deployment.Object, int64(1), "status", "readyReplicas"))
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{deployments: "DeploymentList", services: "ServiceList"},
+		deployment,
+		object("apps/v1", "Deployment", "default", "other"),
+		object("v1", "Service", "default", "web"),
+	)
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+
+	var mu sync.Mutex
+	var events []TargetEvent
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchTargets(ctx, factory, ctx.Done(), []Target{
+		{Group: "apps", Version: "v1", Resource: "deployments", Namespace: "default", Name: "web"},
+		{Version: "v1", Resource: "services", Namespace: "default", Name: "web"},
+		{Version: "v1", Resource: "services", Namespace: "default", Name: "missing"},
+	}, func(e TargetEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	snapshot := func() []TargetEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]TargetEvent(nil), events...)
+	}
+
+	require.Eventually(t, func() bool { return len(snapshot()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	initial := snapshot()
+	assert.Equal(t, "web", initial[0].Target.Name)
+	assert.True(t, initial[0].Exists)
+	assert.Equal(t, map[string]any{"readyReplicas": int64(1)}, initial[0].Status)
+	assert.True(t, initial[1].Exists)
+	assert.Equal(t, TargetEvent{Target: Target{Version: "v1", Resource: "services", Namespace: "default", Name: "missing"}}, initial[2])
+
+	// Changes to other objects of a watched resource are not sent.
+	other := object("apps/v1", "Deployment", "default", "other")
+	other.SetLabels(map[string]string{"changed": "true"})
+	_, err := client.Resource(deployments).Namespace("default").Update(ctx, other, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(deployment.Object, int64(2), "status", "readyReplicas"))
+	_, err = client.Resource(deployments).Namespace("default").Update(ctx, deployment, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(snapshot()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]any{"readyReplicas": int64(2)}, snapshot()[3].Status)
+
+	require.NoError(t, client.Resource(services).Namespace("default").Delete(ctx, "web", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool { return len(snapshot()) == 5 }, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, snapshot()[4].Exists)
+	assert.Equal(t, "services", snapshot()[4].Target.Resource)
+}
@@ -0,0 +1,231 @@
+package whoami
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// SourceSelfSubjectReview is an identity the API server reported.
+	SourceSelfSubjectReview = "selfSubjectReview"
+	// SourceKubeConfig is an identity read from the context's credentials,
+	// for clusters without the SelfSubjectReview API. It is what the
+	// credentials claim, authenticating webhooks and proxies may map it.
+	SourceKubeConfig = "kubeconfig"
+)
+
+const (
+	AuthClientCertificate = "clientCertificate"
+	AuthToken             = "token"
+	AuthBasic             = "basic"
+	AuthExec              = "exec"
+	AuthProvider          = "authProvider"
+)
+
+// scopeExtras are the user extra keys that hold scopes.
+var scopeExtras = []string{"scopes.authorization.openshift.io"}
+
+type Impersonation struct {
+	UserName string   `json:"userName,omitempty"`
+	UID      string   `json:"uid,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
+
+type Identity struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups"`
+	Scopes   []string            `json:"scopes,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+	Source   string              `json:"source"`
+	// AuthInfo is the kubeconfig user of the context, AuthMethod how its
+	// credentials authenticate.
+	AuthInfo   string `json:"authInfo,omitempty"`
+	AuthMethod string `json:"authMethod,omitempty"`
+	// Impersonation is set when the context impersonates another user, the
+	// identity is then the impersonated one.
+	Impersonation *Impersonation `json:"impersonation,omitempty"`
+}
+
+type WhoAmIHandler struct {
+	container container.Container
+}
+
+func NewWhoAmIHandler(container container.Container) *WhoAmIHandler {
+	return &WhoAmIHandler{container: container}
+}
+
+// WhoAmI is kubectl auth whoami: the user and groups the selected cluster's
+// credentials authenticate as.
+func (h *WhoAmIHandler) WhoAmI(c echo.Context) error {
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+
+	clientSet := h.container.ClientSet(config, cluster)
+	restConfig := h.container.RestConfig(config, cluster)
+	if clientSet == nil || restConfig == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("cluster %s not found in config %s", cluster, config))
+	}
+	authInfo := ""
+	if kubeConfig, ok := h.container.Config().GetKubeConfigInfo(config); ok {
+		if cfg, ok := kubeConfig.Clusters[cluster]; ok {
+			authInfo = cfg.AuthInfo
+		}
+	}
+
+	identity, err := whoAmI(c.Request().Context(), clientSet, restConfig)
+	if err != nil {
+		if apierrors.IsUnauthorized(err) {
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	identity.AuthInfo = authInfo
+	return c.JSON(http.StatusOK, identity)
+}
+
+func whoAmI(ctx context.Context, clientSet kubernetes.Interface, restConfig *rest.Config) (Identity, error) {
+	var identity Identity
+	review, err := clientSet.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationV1.SelfSubjectReview{}, metav1.CreateOptions{})
+	switch {
+	case err == nil:
+		user := review.Status.UserInfo
+		identity = Identity{Username: user.Username, UID: user.UID, Groups: user.Groups, Source: SourceSelfSubjectReview}
+		if len(user.Extra) > 0 {
+			identity.Extra = make(map[string][]string, len(user.Extra))
+			for key, values := range user.Extra {
+				identity.Extra[key] = values
+			}
+			for _, key := range scopeExtras {
+				identity.Scopes = append(identity.Scopes, user.Extra[key]...)
+			}
+		}
+		identity.AuthMethod = authMethod(restConfig)
+	case apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) || apierrors.IsForbidden(err):
+		// Not served before Kubernetes 1.28, or the review is not allowed.
+		if identity, err = kubeConfigIdentity(restConfig); err != nil {
+			return Identity{}, err
+		}
+	default:
+		return Identity{}, err
+	}
+
+	if impersonate := restConfig.Impersonate; impersonate.UserName != "" || impersonate.UID != "" || len(impersonate.Groups) > 0 {
+		identity.Impersonation = &Impersonation{UserName: impersonate.UserName, UID: impersonate.UID, Groups: impersonate.Groups}
+	}
+	if identity.Groups == nil {
+		identity.Groups = []string{}
+	}
+	return identity, nil
+}
+
+func authMethod(restConfig *rest.Config) string {
+	switch {
+	case len(restConfig.CertData) > 0 || restConfig.CertFile != "":
+		return AuthClientCertificate
+	case restConfig.BearerToken != "" || restConfig.BearerTokenFile != "":
+		return AuthToken
+	case restConfig.Username != "":
+		return AuthBasic
+	case restConfig.ExecProvider != nil:
+		return AuthExec
+	case restConfig.AuthProvider != nil:
+		return AuthProvider
+	default:
+		return ""
+	}
+}
+
+// kubeConfigIdentity reads the identity from the credentials: the subject of
+// a client certificate, the claims of a JWT bearer token or the basic auth
+// user. Exec and auth provider credentials are opaque, only the method is
+// known. An impersonated user replaces whatever the credentials say.
+func kubeConfigIdentity(restConfig *rest.Config) (Identity, error) {
+	identity := Identity{Source: SourceKubeConfig, AuthMethod: authMethod(restConfig)}
+	switch identity.AuthMethod {
+	case AuthClientCertificate:
+		cert, err := clientCertificate(restConfig)
+		if err != nil {
+			return Identity{}, err
+		}
+		identity.Username, identity.Groups = cert.Subject.CommonName, cert.Subject.Organization
+	case AuthToken:
+		token := restConfig.BearerToken
+		if token == "" {
+			data, err := os.ReadFile(restConfig.BearerTokenFile)
+			if err != nil {
+				return Identity{}, err
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		identity.Username, identity.Groups, identity.Scopes = tokenClaims(token)
+	case AuthBasic:
+		identity.Username = restConfig.Username
+	}
+
+	if impersonate := restConfig.Impersonate; impersonate.UserName != "" {
+		identity.Username, identity.UID, identity.Groups = impersonate.UserName, impersonate.UID, impersonate.Groups
+	}
+	return identity, nil
+}
+
+func clientCertificate(restConfig *rest.Config) (*x509.Certificate, error) {
+	data := restConfig.CertData
+	if len(data) == 0 {
+		var err error
+		if data, err = os.ReadFile(restConfig.CertFile); err != nil {
+			return nil, err
+		}
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("client certificate is not PEM encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// tokenClaims reads the user of a JWT bearer token. The signature is not
+// checked, only the API server can tell whether the token is valid. Opaque
+// tokens yield nothing.
+func tokenClaims(token string) (string, []string, []string) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", nil, nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil
+	}
+	var claims struct {
+		Subject string   `json:"sub"`
+		Groups  []string `json:"groups"`
+		Scope   string   `json:"scope"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", nil, nil
+	}
+
+	groups := claims.Groups
+	// Service account tokens carry no groups, they are implied by the subject
+	// "system:serviceaccount:<namespace>:<name>".
+	if serviceAccount, ok := strings.CutPrefix(claims.Subject, "system:serviceaccount:"); ok {
+		namespace, _, _ := strings.Cut(serviceAccount, ":")
+		groups = append(groups, "system:serviceaccounts", "system:serviceaccounts:"+namespace)
+	}
+	return claims.Subject, groups, strings.Fields(claims.Scope)
+}
@@ -0,0 +1,129 @@
+package whoami
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func reviewClient(user authenticationV1.UserInfo, err error) *fake.Clientset {
+	clientSet := fake.NewClientset()
+	clientSet.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if err != nil {
+			return true, nil, err
+		}
+		return true, &authenticationV1.SelfSubjectReview{Status: authenticationV1.SelfSubjectReviewStatus{UserInfo: user}}, nil
+	})
+	return clientSet
+}
+
+func clientCert(t *testing.T, commonName string, organization ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName, Organization: organization},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func jwt(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(payload)) + ".signature"
+}
+
+func TestWhoAmI(t *testing.T) {
+	notServed := apierrors.NewNotFound(schema.GroupResource{Group: "authentication.k8s.io", Resource: "selfsubjectreviews"}, "")
+
+	t.Run("self subject review", func(t *testing.T) {
+		clientSet := reviewClient(authenticationV1.UserInfo{
+			Username: "jane@example.com",
+			UID:      "42",
+			Groups:   []string{"developers", "system:authenticated"},
+			Extra:    map[string]authenticationV1.ExtraValue{"scopes.authorization.openshift.io": {"user:info", "user:check-access"}},
+		}, nil)
+
+		got, err := whoAmI(context.Background(), clientSet, &rest.Config{BearerToken: "opaque"})
+		require.NoError(t, err)
+		assert.Equal(t, Identity{
+			Username:   "jane@example.com",
+			UID:        "42",
+			Groups:     []string{"developers", "system:authenticated"},
+			Scopes:     []string{"user:info", "user:check-access"},
+			Extra:      map[string][]string{"scopes.authorization.openshift.io": {"user:info", "user:check-access"}},
+			Source:     SourceSelfSubjectReview,
+			AuthMethod: AuthToken,
+		}, got)
+	})
+
+	t.Run("impersonating", func(t *testing.T) {
+		clientSet := reviewClient(authenticationV1.UserInfo{Username: "ops", Groups: []string{"system:authenticated"}}, nil)
+		restConfig := &rest.Config{Impersonate: rest.ImpersonationConfig{UserName: "ops"}}
+
+		got, err := whoAmI(context.Background(), clientSet, restConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "ops", got.Username)
+		assert.Equal(t, &Impersonation{UserName: "ops"}, got.Impersonation)
+	})
+
+	t.Run("client certificate without the review API", func(t *testing.T) {
+		restConfig := &rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: clientCert(t, "kubernetes-admin", "system:masters")}}
+
+		got, err := whoAmI(context.Background(), reviewClient(authenticationV1.UserInfo{}, notServed), restConfig)
+		require.NoError(t, err)
+		assert.Equal(t, Identity{
+			Username:   "kubernetes-admin",
+			Groups:     []string{"system:masters"},
+			Source:     SourceKubeConfig,
+			AuthMethod: AuthClientCertificate,
+		}, got)
+	})
+
+	t.Run("service account token without the review API", func(t *testing.T) {
+		restConfig := &rest.Config{BearerToken: jwt(`{"sub":"system:serviceaccount:monitoring:prometheus","scope":"read write"}`)}
+
+		got, err := whoAmI(context.Background(), reviewClient(authenticationV1.UserInfo{}, notServed), restConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "system:serviceaccount:monitoring:prometheus", got.Username)
+		assert.Equal(t, []string{"system:serviceaccounts", "system:serviceaccounts:monitoring"}, got.Groups)
+		assert.Equal(t, []string{"read", "write"}, got.Scopes)
+	})
+
+	t.Run("exec credentials without the review API", func(t *testing.T) {
+		restConfig := &rest.Config{ExecProvider: &api.ExecConfig{Command: "aws"}}
+
+		got, err := whoAmI(context.Background(), reviewClient(authenticationV1.UserInfo{}, notServed), restConfig)
+		require.NoError(t, err)
+		assert.Equal(t, Identity{Groups: []string{}, Source: SourceKubeConfig, AuthMethod: AuthExec}, got)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		clientSet := reviewClient(authenticationV1.UserInfo{}, apierrors.NewUnauthorized("Unauthorized"))
+
+		_, err := whoAmI(context.Background(), clientSet, &rest.Config{BearerToken: "expired"})
+		assert.True(t, apierrors.IsUnauthorized(err))
+	})
+}
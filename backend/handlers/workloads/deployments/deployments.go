@@ -17,8 +17,10 @@ import (
 )
 
 const (
-	GetPods     = 12
-	UpdateScale = 13
+	GetPods          = 12
+	UpdateScale      = 13
+	PauseDeployment  = 14
+	ResumeDeployment = 15
 )
 
 type DeploymentsHandler struct {
@@ -43,10 +45,16 @@ func NewDeploymentRouteHandler(container container.Container, routeType base.Rou
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case base.SetImage:
+			return handler.BaseHandler.SetWorkloadImage(c)
 		case GetPods:
 			return handler.GetPods(c)
 		case UpdateScale:
 			return handler.UpdateScale(c)
+		case PauseDeployment:
+			return handler.PauseDeployment(c)
+		case ResumeDeployment:
+			return handler.ResumeDeployment(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -125,8 +133,9 @@ func (h *DeploymentsHandler) UpdateScale(c echo.Context) error {
 
 	scale := &autoscalingv1.Scale{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      c.Param("name"),
-			Namespace: c.QueryParam("namespace"),
+			Name:            c.Param("name"),
+			Namespace:       c.QueryParam("namespace"),
+			ResourceVersion: helpers.ResourceVersion(c),
 		},
 		Spec: autoscalingv1.ScaleSpec{
 			Replicas: r.Replicas,
@@ -139,7 +148,7 @@ func (h *DeploymentsHandler) UpdateScale(c echo.Context) error {
 		UpdateScale(c.Request().Context(), c.Param("name"), scale, metav1.UpdateOptions{})
 
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"message": err.Error()})
+		return c.JSON(helpers.WriteErrorStatus(err), echo.Map{"message": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, echo.Map{"success": true})
@@ -0,0 +1,57 @@
+package deployments
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PauseDeployment pauses the rollouts of a deployment, so changes to its pod
+// template can be staged and rolled out together once it is resumed.
+func (h *DeploymentsHandler) PauseDeployment(c echo.Context) error {
+	return h.setPaused(c, true)
+}
+
+// ResumeDeployment resumes a paused deployment, which rolls out the changes
+// made to its pod template while it was paused.
+func (h *DeploymentsHandler) ResumeDeployment(c echo.Context) error {
+	return h.setPaused(c, false)
+}
+
+func (h *DeploymentsHandler) setPaused(c echo.Context, paused bool) error {
+	clientSet := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	deployment, err := setDeploymentPaused(c.Request().Context(), clientSet, c.QueryParam("namespace"), c.Param("name"), paused, helpers.ResourceVersion(c))
+	if err != nil {
+		return echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return c.JSON(http.StatusOK, deployment)
+}
+
+func setDeploymentPaused(ctx context.Context, clientSet kubernetes.Interface, namespace, name string, paused bool, resourceVersion string) (*v1.Deployment, error) {
+	deployments := clientSet.AppsV1().Deployments(namespace)
+	deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := helpers.CheckResourceVersion(deployment, v1.Resource("deployments"), resourceVersion); err != nil {
+		return nil, err
+	}
+	if deployment.Spec.Paused == paused {
+		return deployment, nil
+	}
+
+	patch, err := json.Marshal(helpers.PreconditionPatch(map[string]any{
+		"spec": map[string]any{"paused": paused},
+	}, resourceVersion))
+	if err != nil {
+		return nil, err
+	}
+	return deployments.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
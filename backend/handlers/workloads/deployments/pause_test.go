@@ -0,0 +1,38 @@
+package deployments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSetDeploymentPaused(t *testing.T) {
+	clientSet := fake.NewClientset(&v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "5"},
+	})
+	ctx := context.Background()
+
+	deployment, err := setDeploymentPaused(ctx, clientSet, "default", "web", true, "5")
+	assert.NoError(t, err)
+	assert.True(t, deployment.Spec.Paused)
+
+	deployment, err = setDeploymentPaused(ctx, clientSet, "default", "web", false, "")
+	assert.NoError(t, err)
+	assert.False(t, deployment.Spec.Paused)
+
+	_, err = setDeploymentPaused(ctx, clientSet, "default", "web", true, "4")
+	assert.True(t, apierrors.IsConflict(err))
+
+	_, err = setDeploymentPaused(ctx, clientSet, "default", "missing", true, "")
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestTransformDeploymentItemPaused(t *testing.T) {
+	d := v1.Deployment{Spec: v1.DeploymentSpec{Paused: true}}
+	assert.True(t, TransformDeploymentItem(d).Spec.Paused)
+}
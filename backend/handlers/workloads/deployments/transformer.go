@@ -22,7 +22,8 @@ type DeploymentList struct {
 }
 
 type Spec struct {
-	Replicas int `json:"replicas"`
+	Replicas int  `json:"replicas"`
+	Paused   bool `json:"paused"`
 }
 
 type Status struct {
@@ -76,6 +77,7 @@ func TransformDeploymentItem(d appV1.Deployment) DeploymentList {
 		Name:      d.GetName(),
 		Spec: Spec{
 			Replicas: specReplicas,
+			Paused:   d.Spec.Paused,
 		},
 		Status: Status{
 			ObservedGeneration: d.Status.ObservedGeneration,
@@ -0,0 +1,113 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+)
+
+type ContainerHistory struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	Image        string          `json:"image"`
+	Ready        bool            `json:"ready"`
+	RestartCount int32           `json:"restartCount"`
+	State        ContainerRecord `json:"state"`
+	// LastState is the previous termination, the only earlier run the API
+	// server retains; nil when the container never restarted.
+	LastState *ContainerRecord `json:"lastState,omitempty"`
+}
+
+type ContainerRecord struct {
+	State      string     `json:"state"`
+	Reason     string     `json:"reason,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	ExitCode   *int32     `json:"exitCode,omitempty"`
+	Signal     *int32     `json:"signal,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// GetPodContainerHistory returns the current and last state of every init,
+// app and ephemeral container of a pod.
+func (h *PodsHandler) GetPodContainerHistory(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	return c.JSON(http.StatusOK, TransformContainerHistory(pod))
+}
+
+func TransformContainerHistory(pod *v1.Pod) []ContainerHistory {
+	history := make([]ContainerHistory, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses)+len(pod.Status.EphemeralContainerStatuses))
+	for _, s := range pod.Status.InitContainerStatuses {
+		history = append(history, transformContainerStatus(s, "init"))
+	}
+	for _, s := range pod.Status.ContainerStatuses {
+		history = append(history, transformContainerStatus(s, "container"))
+	}
+	for _, s := range pod.Status.EphemeralContainerStatuses {
+		history = append(history, transformContainerStatus(s, "ephemeral"))
+	}
+	return history
+}
+
+func transformContainerStatus(status v1.ContainerStatus, containerType string) ContainerHistory {
+	h := ContainerHistory{
+		Name:         status.Name,
+		Type:         containerType,
+		Image:        status.Image,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+		State:        transformContainerState(status.State),
+	}
+	if last := transformContainerState(status.LastTerminationState); last.State != "" {
+		h.LastState = &last
+	}
+	return h
+}
+
+func transformContainerState(state v1.ContainerState) ContainerRecord {
+	switch {
+	case state.Terminated != nil:
+		t := state.Terminated
+		return ContainerRecord{
+			State:      "terminated",
+			Reason:     t.Reason,
+			Message:    t.Message,
+			ExitCode:   &t.ExitCode,
+			Signal:     nonZero(t.Signal),
+			StartedAt:  timeOrNil(t.StartedAt.Time),
+			FinishedAt: timeOrNil(t.FinishedAt.Time),
+		}
+	case state.Running != nil:
+		return ContainerRecord{State: "running", StartedAt: timeOrNil(state.Running.StartedAt.Time)}
+	case state.Waiting != nil:
+		return ContainerRecord{State: "waiting", Reason: state.Waiting.Reason, Message: state.Waiting.Message}
+	default:
+		return ContainerRecord{}
+	}
+}
+
+func nonZero(v int32) *int32 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
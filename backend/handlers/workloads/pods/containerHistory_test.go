@@ -0,0 +1,101 @@
+package pods
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTransformContainerHistory(t *testing.T) {
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Minute)
+	exitCode, signal, zero := int32(137), int32(9), int32(0)
+
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want []ContainerHistory
+	}{
+		{
+			name: "restarted without a last state",
+			pod: &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "app",
+				Image:        "nginx:1",
+				Ready:        true,
+				RestartCount: 3,
+				State:        v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+			}}}},
+			want: []ContainerHistory{{
+				Name:         "app",
+				Type:         "container",
+				Image:        "nginx:1",
+				Ready:        true,
+				RestartCount: 3,
+				State:        ContainerRecord{State: "running", StartedAt: &started},
+			}},
+		},
+		{
+			name: "init container before app container",
+			pod: &v1.Pod{Status: v1.PodStatus{
+				InitContainerStatuses: []v1.ContainerStatus{{
+					Name: "migrate",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+						Reason:     "Completed",
+						StartedAt:  metav1.NewTime(started),
+						FinishedAt: metav1.NewTime(finished),
+					}},
+				}},
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  "app",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}},
+				}},
+			}},
+			want: []ContainerHistory{
+				{
+					Name:  "migrate",
+					Type:  "init",
+					State: ContainerRecord{State: "terminated", Reason: "Completed", ExitCode: &zero, StartedAt: &started, FinishedAt: &finished},
+				},
+				{
+					Name:  "app",
+					Type:  "container",
+					State: ContainerRecord{State: "waiting", Reason: "PodInitializing"},
+				},
+			},
+		},
+		{
+			name: "waiting after a crash",
+			pod: &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 5,
+				State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					ExitCode:   137,
+					Signal:     9,
+					Reason:     "OOMKilled",
+					FinishedAt: metav1.NewTime(finished),
+				}},
+			}}}},
+			want: []ContainerHistory{{
+				Name:         "app",
+				Type:         "container",
+				RestartCount: 5,
+				State:        ContainerRecord{State: "waiting", Reason: "CrashLoopBackOff", Message: "back-off 5m0s"},
+				LastState:    &ContainerRecord{State: "terminated", Reason: "OOMKilled", ExitCode: &exitCode, Signal: &signal, FinishedAt: &finished},
+			}},
+		},
+		{
+			name: "no statuses yet",
+			pod:  &v1.Pod{},
+			want: []ContainerHistory{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TransformContainerHistory(tt.pod))
+		})
+	}
+}
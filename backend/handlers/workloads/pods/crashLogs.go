@@ -0,0 +1,127 @@
+package pods
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultCrashLogLines = 500
+	maxCrashLogLines     = 5000
+	// crashLogLimitBytes bounds the logs read per container, tailLines alone
+	// does not when lines are long.
+	crashLogLimitBytes = 2 * 1024 * 1024
+)
+
+type CrashLog struct {
+	ExitCode   int32        `json:"exitCode"`
+	Reason     string       `json:"reason,omitempty"`
+	FinishedAt *time.Time   `json:"finishedAt,omitempty"`
+	Logs       []LogMessage `json:"logs"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// GetPodCrashLogs returns the previous-instance logs of every container whose
+// last termination had a non-zero exit code, keyed by container name.
+func (h *PodsHandler) GetPodCrashLogs(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	tailLines := crashLogTailLines(c.QueryParam("tailLines"))
+	crashes := make(map[string]CrashLog)
+	for _, status := range crashedContainers(pod) {
+		t := status.LastTerminationState.Terminated
+		crash := CrashLog{
+			ExitCode:   t.ExitCode,
+			Reason:     t.Reason,
+			FinishedAt: timeOrNil(t.FinishedAt.Time),
+		}
+		crash.Logs, err = h.fetchPreviousLogs(c.Request().Context(), namespace, name, status.Name, tailLines)
+		if err != nil {
+			crash.Error = err.Error()
+		}
+		crashes[status.Name] = crash
+	}
+
+	return c.JSON(http.StatusOK, crashes)
+}
+
+// crashedContainers returns the init and app container statuses whose last
+// termination exited with a non-zero code.
+func crashedContainers(pod *v1.Pod) []v1.ContainerStatus {
+	var crashed []v1.ContainerStatus
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			if t := s.LastTerminationState.Terminated; t != nil && t.ExitCode != 0 {
+				crashed = append(crashed, s)
+			}
+		}
+	}
+	return crashed
+}
+
+// crashLogTailLines parses ?tailLines=, falling back to the default when it is
+// missing or invalid and clamping it to maxCrashLogLines.
+func crashLogTailLines(v string) int64 {
+	parsed, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || parsed <= 0 {
+		return defaultCrashLogLines
+	}
+	return min(parsed, maxCrashLogLines)
+}
+
+func previousLogOptions(containerName string, tailLines int64) *v1.PodLogOptions {
+	limitBytes := int64(crashLogLimitBytes)
+	return &v1.PodLogOptions{
+		Container:  containerName,
+		Timestamps: true,
+		Previous:   true,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}
+}
+
+func (h *PodsHandler) fetchPreviousLogs(ctx context.Context, namespace, podName, containerName string, tailLines int64) ([]LogMessage, error) {
+	podLogs, err := h.clientSet.CoreV1().Pods(namespace).GetLogs(podName, previousLogOptions(containerName, tailLines)).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer podLogs.Close()
+
+	result := make([]LogMessage, 0)
+	scanner := bufio.NewScanner(podLogs)
+	scanner.Buffer(make([]byte, 0, maxLogLineSize), maxLogLineSize)
+
+	for scanner.Scan() {
+		timestamp, message, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		parseTime, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			continue
+		}
+		result = append(result, LogMessage{
+			ContainerName: containerName,
+			Timestamp:     parseTime.Format(timestampLayout),
+			Log:           message,
+		})
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,37 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrashLogTailLines(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"", defaultCrashLogLines},
+		{"100", 100},
+		{"5000", maxCrashLogLines},
+		{"1000000", maxCrashLogLines},
+		{"0", defaultCrashLogLines},
+		{"-5", defaultCrashLogLines},
+		{"many", defaultCrashLogLines},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, crashLogTailLines(tt.value), "tailLines=%q", tt.value)
+	}
+}
+
+func TestPreviousLogOptions(t *testing.T) {
+	opts := previousLogOptions("app", 100)
+
+	assert.Equal(t, "app", opts.Container)
+	assert.True(t, opts.Previous)
+	require.NotNil(t, opts.TailLines)
+	assert.Equal(t, int64(100), *opts.TailLines)
+	require.NotNil(t, opts.LimitBytes)
+	assert.Equal(t, int64(crashLogLimitBytes), *opts.LimitBytes)
+}
@@ -37,7 +37,7 @@ func (h *PodsHandler) DeploymentsPods() {
 		podsByDeployment[deployment] = append(podsByDeployment[deployment], pod)
 	}
 
-	podsMetricsList := GetPodsMetricsList(&h.BaseHandler)
+	podsMetricsList, staleMetrics := GetPodsMetricsList(&h.BaseHandler)
 
 	deployments := make([]string, 0, len(podsByDeployment))
 	for d := range podsByDeployment {
@@ -47,7 +47,7 @@ func (h *PodsHandler) DeploymentsPods() {
 
 	for _, dep := range deployments {
 		depPods := podsByDeployment[dep]
-		transformed := TransformPodList(depPods, podsMetricsList)
+		transformed := TransformPodList(depPods, podsMetricsList, staleMetrics)
 
 		data, err := json.Marshal(transformed)
 		if err != nil {
@@ -0,0 +1,244 @@
+package pods
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const diskUsageExecTimeout = 15 * time.Second
+
+// Filesystem is one line of df, sizes in bytes.
+type Filesystem struct {
+	Filesystem string `json:"filesystem"`
+	MountPoint string `json:"mountPoint"`
+	Size       int64  `json:"size"`
+	Used       int64  `json:"used"`
+	Available  int64  `json:"available"`
+	Percent    int    `json:"percent"`
+}
+
+// PathUsage is what du reports for a path, in bytes.
+type PathUsage struct {
+	Path string `json:"path"`
+	Used int64  `json:"used"`
+}
+
+type PodDiskUsage struct {
+	Container string `json:"container"`
+	// Supported is false when the container has no df, e.g. distroless
+	// images, Message then says why.
+	Supported   bool         `json:"supported"`
+	Message     string       `json:"message,omitempty"`
+	Filesystems []Filesystem `json:"filesystems"`
+	Path        *PathUsage   `json:"path,omitempty"`
+}
+
+// execFunc runs command in the container and returns its stdout and stderr.
+type execFunc func(ctx context.Context, command []string) (string, string, error)
+
+// GetPodDiskUsage runs df, and du for ?path=, in a container of the pod. It
+// shows the ephemeral storage use the metrics API does not report.
+func (h *PodsHandler) GetPodDiskUsage(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	container := c.QueryParam("container")
+	if container == "" {
+		container = defaultContainer(pod)
+	}
+	if !containerRunning(pod, container) {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("container %s of pod %s is not running", container, key))
+	}
+	path := c.QueryParam("path")
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return echo.NewHTTPError(http.StatusBadRequest, "path must be absolute")
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), diskUsageExecTimeout)
+	defer cancel()
+	usage, err := podDiskUsage(ctx, h.auditedExecIn(c, namespace, name, container), path)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	usage.Container = container
+	return c.JSON(http.StatusOK, usage)
+}
+
+// execIn returns an execFunc for the container, over WebSocket with a
+// fallback to SPDY for API servers that do not support it, as kubectl does.
+func (h *PodsHandler) execIn(namespace, pod, container string) execFunc {
+	return func(ctx context.Context, command []string) (string, string, error) {
+		req := h.clientSet.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("exec").
+			VersionedParams(&v1.PodExecOptions{
+				Container: container,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+
+		websocketExec, err := remotecommand.NewWebSocketExecutor(h.restConfig, http.MethodGet, req.URL().String())
+		if err != nil {
+			return "", "", err
+		}
+		spdyExec, err := remotecommand.NewSPDYExecutor(h.restConfig, http.MethodPost, req.URL())
+		if err != nil {
+			return "", "", err
+		}
+		executor, err := remotecommand.NewFallbackExecutor(websocketExec, spdyExec, func(err error) bool {
+			return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+		})
+		if err != nil {
+			return "", "", err
+		}
+
+		var stdout, stderr bytes.Buffer
+		err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+		return stdout.String(), stderr.String(), err
+	}
+}
+
+func podDiskUsage(ctx context.Context, exec execFunc, path string) (PodDiskUsage, error) {
+	usage := PodDiskUsage{Supported: true, Filesystems: make([]Filesystem, 0)}
+
+	// -kP is POSIX output in KiB, unlike -h it parses the same on GNU and
+	// busybox.
+	stdout, stderr, err := exec(ctx, []string{"df", "-kP"})
+	if err != nil {
+		if commandNotFound(err, stderr) {
+			usage.Supported, usage.Message = false, "the container has no df command, disk usage is not supported"
+			return usage, nil
+		}
+		// df fails when some mounts cannot be read but still lists the others.
+		if strings.TrimSpace(stdout) == "" {
+			return PodDiskUsage{}, execError("df", err, stderr)
+		}
+	}
+	usage.Filesystems = parseDF(stdout)
+
+	if path == "" {
+		return usage, nil
+	}
+	stdout, stderr, err = exec(ctx, []string{"du", "-sk", path})
+	if err != nil && strings.TrimSpace(stdout) == "" {
+		if commandNotFound(err, stderr) {
+			usage.Message = "the container has no du command, path usage is not supported"
+			return usage, nil
+		}
+		return PodDiskUsage{}, execError("du", err, stderr)
+	}
+	if used, ok := parseDU(stdout); ok {
+		usage.Path = &PathUsage{Path: path, Used: used}
+	}
+	return usage, nil
+}
+
+// commandNotFound tells a missing binary from a failing one. The runtime
+// reports it as an exec error or, through a shell, as exit code 126 or 127.
+func commandNotFound(err error, stderr string) bool {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && (exitErr.ExitStatus() == 126 || exitErr.ExitStatus() == 127) {
+		return true
+	}
+	for _, msg := range []string{err.Error(), stderr} {
+		if strings.Contains(msg, "executable file not found") || strings.Contains(msg, "no such file or directory") {
+			return true
+		}
+	}
+	return false
+}
+
+func execError(command string, err error, stderr string) error {
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("%s failed: %s", command, stderr)
+	}
+	return fmt.Errorf("%s failed: %w", command, err)
+}
+
+// parseDF parses df -kP output. Mount points may contain spaces, they are
+// the rest of the line after the fifth column.
+func parseDF(output string) []Filesystem {
+	filesystems := make([]Filesystem, 0)
+	for i, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 6 {
+			continue
+		}
+		size, err1 := strconv.ParseInt(fields[1], 10, 64)
+		used, err2 := strconv.ParseInt(fields[2], 10, 64)
+		available, err3 := strconv.ParseInt(fields[3], 10, 64)
+		percent, err4 := strconv.Atoi(strings.TrimSuffix(fields[4], "%"))
+		if err := errors.Join(err1, err2, err3, err4); err != nil {
+			continue
+		}
+		filesystems = append(filesystems, Filesystem{
+			Filesystem: fields[0],
+			MountPoint: strings.Join(fields[5:], " "),
+			Size:       size * 1024,
+			Used:       used * 1024,
+			Available:  available * 1024,
+			Percent:    percent,
+		})
+	}
+	return filesystems
+}
+
+// parseDU reads the total of du -sk, the first column of its last line.
+func parseDU(output string) (int64, bool) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) == 0 {
+		return 0, false
+	}
+	used, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return used * 1024, true
+}
+
+// defaultContainer is the container kubectl picks when none is named: the one
+// the kubectl.kubernetes.io/default-container annotation names if the pod has
+// it, else the first.
+func defaultContainer(pod *v1.Pod) string {
+	if name := pod.Annotations["kubectl.kubernetes.io/default-container"]; name != "" && findContainer(pod, name) != nil {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+func containerRunning(pod *v1.Pod, container string) bool {
+	for _, status := range append(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses...) {
+		if status.Name == container {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
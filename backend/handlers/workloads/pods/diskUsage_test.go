@@ -0,0 +1,113 @@
+package pods
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const dfOutput = `Filesystem     1024-blocks     Used Available Capacity Mounted on
+overlay          102687672 51343836  46089540      53% /
+tmpfs                65536        0     65536       0% /dev
+/dev/sda1        102687672 51343836  46089540      53% /var/lib/my data
+`
+
+func TestParseDF(t *testing.T) {
+	assert.Equal(t, []Filesystem{
+		{Filesystem: "overlay", MountPoint: "/", Size: 102687672 * 1024, Used: 51343836 * 1024, Available: 46089540 * 1024, Percent: 53},
+		{Filesystem: "tmpfs", MountPoint: "/dev", Size: 65536 * 1024, Available: 65536 * 1024},
+		{Filesystem: "/dev/sda1", MountPoint: "/var/lib/my data", Size: 102687672 * 1024, Used: 51343836 * 1024, Available: 46089540 * 1024, Percent: 53},
+	}, parseDF(dfOutput))
+	assert.Empty(t, parseDF(""))
+}
+
+func TestParseDU(t *testing.T) {
+	used, ok := parseDU("2048\t/var/cache\n")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2048*1024), used)
+
+	_, ok = parseDU("du: /missing: No such file or directory\n")
+	assert.False(t, ok)
+}
+
+// fakeExec answers every command by its name.
+func fakeExec(results map[string]func() (string, string, error)) execFunc {
+	return func(_ context.Context, command []string) (string, string, error) {
+		if result, ok := results[command[0]]; ok {
+			return result()
+		}
+		return "", "", errors.New(`OCI runtime exec failed: exec failed: unable to start container process: exec: "` + command[0] + `": executable file not found in $PATH: unknown`)
+	}
+}
+
+func TestPodDiskUsage(t *testing.T) {
+	df := func() (string, string, error) { return dfOutput, "", nil }
+
+	t.Run("df and du", func(t *testing.T) {
+		usage, err := podDiskUsage(context.Background(), fakeExec(map[string]func() (string, string, error){
+			"df": df,
+			"du": func() (string, string, error) { return "512\t/tmp\n", "", nil },
+		}), "/tmp")
+		require.NoError(t, err)
+		assert.True(t, usage.Supported)
+		assert.Len(t, usage.Filesystems, 3)
+		assert.Equal(t, &PathUsage{Path: "/tmp", Used: 512 * 1024}, usage.Path)
+	})
+
+	t.Run("no df in the image", func(t *testing.T) {
+		usage, err := podDiskUsage(context.Background(), fakeExec(nil), "")
+		require.NoError(t, err)
+		assert.False(t, usage.Supported)
+		assert.Contains(t, usage.Message, "no df command")
+		assert.Empty(t, usage.Filesystems)
+	})
+
+	t.Run("no du in the image", func(t *testing.T) {
+		usage, err := podDiskUsage(context.Background(), fakeExec(map[string]func() (string, string, error){"df": df}), "/tmp")
+		require.NoError(t, err)
+		assert.True(t, usage.Supported)
+		assert.Contains(t, usage.Message, "no du command")
+		assert.Nil(t, usage.Path)
+	})
+
+	t.Run("df lists readable mounts despite failing", func(t *testing.T) {
+		usage, err := podDiskUsage(context.Background(), fakeExec(map[string]func() (string, string, error){
+			"df": func() (string, string, error) {
+				return dfOutput, "df: /proc/keys: Permission denied", utilexec.CodeExitError{Err: errors.New("exit 1"), Code: 1}
+			},
+		}), "")
+		require.NoError(t, err)
+		assert.Len(t, usage.Filesystems, 3)
+	})
+
+	t.Run("du on a missing path", func(t *testing.T) {
+		_, err := podDiskUsage(context.Background(), fakeExec(map[string]func() (string, string, error){
+			"df": df,
+			"du": func() (string, string, error) {
+				return "", "du: cannot access '/missing': No such file or directory", utilexec.CodeExitError{Err: errors.New("exit 1"), Code: 1}
+			},
+		}), "/missing")
+		// The message names the path, not the du binary, so it is an error.
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "du failed"))
+	})
+}
+
+func TestDefaultContainer(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}, {Name: "sidecar"}}}}
+	assert.Equal(t, "app", defaultContainer(pod))
+
+	pod.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{"kubectl.kubernetes.io/default-container": "sidecar"}}
+	assert.Equal(t, "sidecar", defaultContainer(pod))
+
+	// Like kubectl, an annotation naming no container of the pod is ignored.
+	pod.Annotations["kubectl.kubernetes.io/default-container"] = "removed"
+	assert.Equal(t, "app", defaultContainer(pod))
+}
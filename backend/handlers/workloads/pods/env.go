@@ -0,0 +1,339 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const maskedValue = "********"
+
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Source is one of "value", "configMapKeyRef", "secretKeyRef", "fieldRef",
+	// "resourceFieldRef", "configMap" or "secret", the last two for envFrom.
+	Source string `json:"source"`
+	// From names the ConfigMap, Secret or field the value came from.
+	From   string `json:"from,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Masked bool   `json:"masked,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// GetPodEnv returns the effective environment of a container with the source
+// of every variable. Secret values are masked unless ?reveal=true.
+func (h *PodsHandler) GetPodEnv(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	container := findContainer(pod, c.QueryParam("container"))
+	if container == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("container %q not found in pod %s", c.QueryParam("container"), key))
+	}
+
+	reveal, _ := strconv.ParseBool(c.QueryParam("reveal"))
+	if reveal {
+		log.Info("audit: secret environment values revealed",
+			"config", h.BaseHandler.QueryConfig, "cluster", h.BaseHandler.QueryCluster,
+			"pod", key, "container", container.Name, "remoteAddr", c.RealIP())
+	}
+
+	return c.JSON(http.StatusOK, resolveEnv(c.Request().Context(), h.clientSet, pod, container, reveal))
+}
+
+// findContainer returns the named container, init containers included. An
+// empty name selects the default container, see defaultContainer.
+func findContainer(pod *v1.Pod, name string) *v1.Container {
+	if name == "" {
+		if name = defaultContainer(pod); name == "" {
+			return nil
+		}
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == name {
+			return &pod.Spec.InitContainers[i]
+		}
+	}
+	return nil
+}
+
+// resolveEnv merges envFrom and env the way the kubelet does: envFrom sources
+// in order, then env, with later definitions replacing earlier ones. $(VAR)
+// references are returned unexpanded.
+func resolveEnv(ctx context.Context, clientSet kubernetes.Interface, pod *v1.Pod, container *v1.Container, reveal bool) []EnvVar {
+	r := &envResolver{
+		ctx:        ctx,
+		clientSet:  clientSet,
+		namespace:  pod.Namespace,
+		configMaps: make(map[string]*v1.ConfigMap),
+		secrets:    make(map[string]*v1.Secret),
+	}
+
+	vars := make([]EnvVar, 0)
+	index := make(map[string]int)
+	set := func(v EnvVar) {
+		if (v.Source == "secretKeyRef" || v.Source == "secret") && !reveal && v.Value != "" {
+			v.Value, v.Masked = maskedValue, true
+		}
+		if i, ok := index[v.Name]; ok {
+			vars[i] = v
+			return
+		}
+		index[v.Name] = len(vars)
+		vars = append(vars, v)
+	}
+
+	for _, from := range container.EnvFrom {
+		for _, v := range r.envFrom(from) {
+			set(v)
+		}
+	}
+	for _, env := range container.Env {
+		set(r.env(pod, container, env))
+	}
+	return vars
+}
+
+type envResolver struct {
+	ctx        context.Context
+	clientSet  kubernetes.Interface
+	namespace  string
+	configMaps map[string]*v1.ConfigMap
+	secrets    map[string]*v1.Secret
+}
+
+func (r *envResolver) envFrom(from v1.EnvFromSource) []EnvVar {
+	var vars []EnvVar
+	switch {
+	case from.ConfigMapRef != nil:
+		cm, err := r.configMap(from.ConfigMapRef.Name)
+		if err != nil {
+			if !isOptionalMissing(err, from.ConfigMapRef.Optional) {
+				vars = append(vars, EnvVar{Name: from.Prefix, Source: "configMap", From: from.ConfigMapRef.Name, Error: err.Error()})
+			}
+			return vars
+		}
+		for k, v := range cm.Data {
+			vars = append(vars, EnvVar{Name: from.Prefix + k, Value: v, Source: "configMap", From: cm.Name, Key: k})
+		}
+	case from.SecretRef != nil:
+		secret, err := r.secret(from.SecretRef.Name)
+		if err != nil {
+			if !isOptionalMissing(err, from.SecretRef.Optional) {
+				vars = append(vars, EnvVar{Name: from.Prefix, Source: "secret", From: from.SecretRef.Name, Error: err.Error()})
+			}
+			return vars
+		}
+		for k, v := range secret.Data {
+			vars = append(vars, EnvVar{Name: from.Prefix + k, Value: string(v), Source: "secret", From: secret.Name, Key: k})
+		}
+	}
+	// Map iteration order is random, keep the output stable.
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+func (r *envResolver) env(pod *v1.Pod, container *v1.Container, env v1.EnvVar) EnvVar {
+	v := EnvVar{Name: env.Name, Value: env.Value, Source: "value"}
+	src := env.ValueFrom
+	switch {
+	case src == nil:
+	case src.ConfigMapKeyRef != nil:
+		ref := src.ConfigMapKeyRef
+		v.Source, v.From, v.Key = "configMapKeyRef", ref.Name, ref.Key
+		cm, err := r.configMap(ref.Name)
+		if err != nil {
+			v.Error = optionalError(err, ref.Optional)
+			break
+		}
+		value, ok := cm.Data[ref.Key]
+		if !ok {
+			if b, binary := cm.BinaryData[ref.Key]; binary {
+				value, ok = string(b), true
+			}
+		}
+		if !ok {
+			v.Error = missingKeyError(ref.Key, ref.Optional)
+		}
+		v.Value = value
+	case src.SecretKeyRef != nil:
+		ref := src.SecretKeyRef
+		v.Source, v.From, v.Key = "secretKeyRef", ref.Name, ref.Key
+		secret, err := r.secret(ref.Name)
+		if err != nil {
+			v.Error = optionalError(err, ref.Optional)
+			break
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			v.Error = missingKeyError(ref.Key, ref.Optional)
+		}
+		v.Value = string(value)
+	case src.FieldRef != nil:
+		v.Source, v.From = "fieldRef", src.FieldRef.FieldPath
+		value, err := podFieldValue(pod, src.FieldRef.FieldPath)
+		if err != nil {
+			v.Error = err.Error()
+		}
+		v.Value = value
+	case src.ResourceFieldRef != nil:
+		v.Source, v.From = "resourceFieldRef", src.ResourceFieldRef.Resource
+		value, err := containerResourceValue(container, src.ResourceFieldRef)
+		if err != nil {
+			v.Error = err.Error()
+		}
+		v.Value = value
+	}
+	return v
+}
+
+func (r *envResolver) configMap(name string) (*v1.ConfigMap, error) {
+	if cm, ok := r.configMaps[name]; ok {
+		return cm, nil
+	}
+	cm, err := r.clientSet.CoreV1().ConfigMaps(r.namespace).Get(r.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	r.configMaps[name] = cm
+	return cm, nil
+}
+
+func (r *envResolver) secret(name string) (*v1.Secret, error) {
+	if secret, ok := r.secrets[name]; ok {
+		return secret, nil
+	}
+	secret, err := r.clientSet.CoreV1().Secrets(r.namespace).Get(r.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	r.secrets[name] = secret
+	return secret, nil
+}
+
+func isOptionalMissing(err error, optional *bool) bool {
+	return apierrors.IsNotFound(err) && optional != nil && *optional
+}
+
+func optionalError(err error, optional *bool) string {
+	if isOptionalMissing(err, optional) {
+		return ""
+	}
+	return err.Error()
+}
+
+func missingKeyError(key string, optional *bool) string {
+	if optional != nil && *optional {
+		return ""
+	}
+	return fmt.Sprintf("key %s not found", key)
+}
+
+// podFieldValue resolves the downward API fields supported in env.
+func podFieldValue(pod *v1.Pod, fieldPath string) (string, error) {
+	if path, subscript, ok := strings.Cut(fieldPath, "["); ok {
+		key := strings.Trim(strings.TrimSuffix(subscript, "]"), `'"`)
+		switch path {
+		case "metadata.labels":
+			return pod.Labels[key], nil
+		case "metadata.annotations":
+			return pod.Annotations[key], nil
+		}
+		return "", fmt.Errorf("unsupported field path %s", fieldPath)
+	}
+
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name, nil
+	case "metadata.namespace":
+		return pod.Namespace, nil
+	case "metadata.uid":
+		return string(pod.UID), nil
+	case "spec.nodeName":
+		return pod.Spec.NodeName, nil
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, nil
+	case "status.hostIP":
+		return pod.Status.HostIP, nil
+	case "status.hostIPs":
+		ips := make([]string, 0, len(pod.Status.HostIPs))
+		for _, ip := range pod.Status.HostIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), nil
+	case "status.podIP":
+		return pod.Status.PodIP, nil
+	case "status.podIPs":
+		ips := make([]string, 0, len(pod.Status.PodIPs))
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), nil
+	}
+	return "", fmt.Errorf("unsupported field path %s", fieldPath)
+}
+
+// containerResourceValue mirrors the kubelet: the quantity is divided by the
+// divisor and rounded up. Unset limits fall back to node allocatable, which
+// is not known here.
+func containerResourceValue(container *v1.Container, ref *v1.ResourceFieldSelector) (string, error) {
+	if ref.ContainerName != "" && ref.ContainerName != container.Name {
+		return "", fmt.Errorf("resource of container %s cannot be resolved from container %s", ref.ContainerName, container.Name)
+	}
+
+	kind, name, ok := strings.Cut(ref.Resource, ".")
+	if !ok {
+		return "", fmt.Errorf("unsupported resource %s", ref.Resource)
+	}
+	var list v1.ResourceList
+	switch kind {
+	case "limits":
+		list = container.Resources.Limits
+	case "requests":
+		list = container.Resources.Requests
+	default:
+		return "", fmt.Errorf("unsupported resource %s", ref.Resource)
+	}
+	quantity, ok := list[v1.ResourceName(name)]
+	if !ok {
+		if kind == "limits" {
+			return "", fmt.Errorf("%s is not set, defaults to node allocatable", ref.Resource)
+		}
+		return "0", nil
+	}
+
+	divisor := ref.Divisor
+	if divisor.IsZero() {
+		divisor = resource.MustParse("1")
+	}
+	if name == string(v1.ResourceCPU) {
+		return strconv.FormatInt(int64(math.Ceil(float64(quantity.MilliValue())/float64(divisor.MilliValue()))), 10), nil
+	}
+	return strconv.FormatInt(int64(math.Ceil(float64(quantity.Value())/float64(divisor.Value()))), 10), nil
+}
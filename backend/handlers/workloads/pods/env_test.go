@@ -0,0 +1,85 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveEnv(t *testing.T) {
+	optional := true
+	clientSet := fake.NewClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Data:       map[string]string{"LOG_LEVEL": "info", "MODE": "from-configmap"},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name: "web",
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m")},
+				},
+				EnvFrom: []v1.EnvFromSource{
+					{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "app"}}},
+					{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "missing"}, Optional: &optional}},
+				},
+				Env: []v1.EnvVar{
+					{Name: "MODE", Value: "literal"},
+					{Name: "DB_PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "password",
+					}}},
+					{Name: "APP", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
+					{Name: "CPU", ValueFrom: &v1.EnvVarSource{ResourceFieldRef: &v1.ResourceFieldSelector{Resource: "limits.cpu"}}},
+					{Name: "MISSING", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "app"}, Key: "nope",
+					}}},
+				},
+			}},
+		},
+	}
+
+	vars := resolveEnv(context.Background(), clientSet, pod, &pod.Spec.Containers[0], false)
+	assert.Equal(t, []EnvVar{
+		{Name: "LOG_LEVEL", Value: "info", Source: "configMap", From: "app", Key: "LOG_LEVEL"},
+		{Name: "MODE", Value: "literal", Source: "value"},
+		{Name: "DB_PASSWORD", Value: maskedValue, Source: "secretKeyRef", From: "db", Key: "password", Masked: true},
+		{Name: "APP", Value: "web", Source: "fieldRef", From: "metadata.labels['app']"},
+		{Name: "CPU", Value: "2", Source: "resourceFieldRef", From: "limits.cpu"},
+		{Name: "MISSING", Source: "configMapKeyRef", From: "app", Key: "nope", Error: "key nope not found"},
+	}, vars)
+
+	vars = resolveEnv(context.Background(), clientSet, pod, &pod.Spec.Containers[0], true)
+	assert.Equal(t, "hunter2", vars[2].Value)
+	assert.False(t, vars[2].Masked)
+}
+
+func TestFindContainer(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"kubectl.kubernetes.io/default-container": "app"}},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate"}},
+			Containers:     []v1.Container{{Name: "istio-proxy"}, {Name: "app"}},
+		},
+	}
+
+	assert.Equal(t, "app", findContainer(pod, "").Name, "empty name selects the default container")
+	assert.Equal(t, "istio-proxy", findContainer(pod, "istio-proxy").Name)
+	assert.Equal(t, "migrate", findContainer(pod, "migrate").Name)
+	assert.Nil(t, findContainer(pod, "missing"))
+
+	delete(pod.Annotations, "kubectl.kubernetes.io/default-container")
+	assert.Equal(t, "istio-proxy", findContainer(pod, "").Name, "first container without the annotation")
+	assert.Nil(t, findContainer(&v1.Pod{}, ""))
+}
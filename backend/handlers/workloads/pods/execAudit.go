@@ -0,0 +1,70 @@
+package pods
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+)
+
+// maxTranscriptBytes bounds each of stdout and stderr in the audit log.
+const maxTranscriptBytes = 4096
+
+// execSession identifies an exec for the audit log.
+type execSession struct {
+	config     string
+	cluster    string
+	namespace  string
+	pod        string
+	container  string
+	remoteAddr string
+	// transcript adds the truncated output to the end entry.
+	transcript bool
+}
+
+// auditedExecIn is execIn with every command audit logged when it starts and
+// ends, and with --audit-exec-transcript its truncated output as well.
+func (h *PodsHandler) auditedExecIn(c echo.Context, namespace, pod, container string) execFunc {
+	return auditExec(h.execIn(namespace, pod, container), execSession{
+		config:     h.BaseHandler.QueryConfig,
+		cluster:    h.BaseHandler.QueryCluster,
+		namespace:  namespace,
+		pod:        pod,
+		container:  container,
+		remoteAddr: c.RealIP(),
+		transcript: h.BaseHandler.Container.Config().ExecAuditTranscript,
+	})
+}
+
+func auditExec(exec execFunc, session execSession) execFunc {
+	return func(ctx context.Context, command []string) (string, string, error) {
+		fields := []any{
+			"config", session.config, "cluster", session.cluster, "namespace", session.namespace,
+			"pod", session.pod, "container", session.container, "command", command, "remoteAddr", session.remoteAddr,
+		}
+		log.Info("audit: exec session started", fields...)
+
+		start := time.Now()
+		stdout, stderr, err := exec(ctx, command)
+		fields = append(fields, "duration", time.Since(start).Round(time.Millisecond).String())
+		if err != nil {
+			fields = append(fields, "err", err)
+		}
+		if session.transcript {
+			fields = append(fields, "stdout", truncateTranscript(stdout), "stderr", truncateTranscript(stderr))
+		}
+		log.Info("audit: exec session ended", fields...)
+		return stdout, stderr, err
+	}
+}
+
+func truncateTranscript(output string) string {
+	if len(output) <= maxTranscriptBytes {
+		return output
+	}
+	// Cut at a rune boundary so the log line stays valid UTF-8.
+	cut := strings.ToValidUTF8(output[:maxTranscriptBytes], "")
+	return cut + "...(truncated)"
+}
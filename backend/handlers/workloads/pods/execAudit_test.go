@@ -0,0 +1,54 @@
+package pods
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditExec(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	failing := func(ctx context.Context, command []string) (string, string, error) {
+		return "partial", "du: permission denied", errors.New("command terminated with exit code 1")
+	}
+	session := execSession{namespace: "apps", pod: "web-0", container: "app", remoteAddr: "10.1.1.1"}
+
+	t.Run("without transcript", func(t *testing.T) {
+		buf.Reset()
+		stdout, stderr, err := auditExec(failing, session)(context.Background(), []string{"du", "-s", "/data"})
+		assert.Equal(t, "partial", stdout)
+		assert.Equal(t, "du: permission denied", stderr)
+		assert.Error(t, err)
+
+		logged := buf.String()
+		assert.Contains(t, logged, "audit: exec session started")
+		assert.Contains(t, logged, "audit: exec session ended")
+		assert.Contains(t, logged, "web-0")
+		assert.Contains(t, logged, "exit code 1")
+		assert.NotContains(t, logged, "permission denied")
+	})
+
+	t.Run("with transcript", func(t *testing.T) {
+		buf.Reset()
+		session.transcript = true
+		_, _, _ = auditExec(failing, session)(context.Background(), []string{"du", "-s", "/data"})
+		assert.Contains(t, buf.String(), "permission denied")
+	})
+}
+
+func TestTruncateTranscript(t *testing.T) {
+	assert.Equal(t, "ok", truncateTranscript("ok"))
+
+	long := strings.Repeat("a", maxTranscriptBytes-1) + "é" + "tail"
+	got := truncateTranscript(long)
+	assert.Equal(t, strings.Repeat("a", maxTranscriptBytes-1)+"...(truncated)", got)
+}
@@ -0,0 +1,186 @@
+package pods
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	PullSecretOK      = "ok"
+	PullSecretMissing = "missing"
+	PullSecretInvalid = "invalid"
+	PullSecretError   = "error"
+)
+
+// RegistryCredential is one registry of a pull secret. The password is never
+// returned.
+type RegistryCredential struct {
+	Server   string `json:"server"`
+	Username string `json:"username,omitempty"`
+}
+
+type ImagePullSecret struct {
+	Name string `json:"name"`
+	// Sources are "pod" and "serviceAccount", the service account's secrets
+	// are only copied into pods created without their own.
+	Sources    []string             `json:"sources"`
+	Type       string               `json:"type,omitempty"`
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty"`
+	Registries []RegistryCredential `json:"registries"`
+}
+
+type PodImagePullSecrets struct {
+	ServiceAccount      string            `json:"serviceAccount"`
+	ServiceAccountError string            `json:"serviceAccountError,omitempty"`
+	Secrets             []ImagePullSecret `json:"secrets"`
+}
+
+// dockerConfigEntry is an entry of a .dockerconfigjson or .dockercfg. Only
+// the fields needed for the username are read.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Auth     string `json:"auth"`
+}
+
+// GetPodImagePullSecrets lists the image pull secrets of a pod and its service
+// account with the registries and usernames they hold, flagging missing and
+// malformed ones.
+func (h *PodsHandler) GetPodImagePullSecrets(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	secrets := resolvePullSecrets(c.Request().Context(), h.clientSet, pod)
+	for _, secret := range secrets.Secrets {
+		if secret.Type != "" {
+			log.Info("audit: image pull secret decoded",
+				"config", h.BaseHandler.QueryConfig, "cluster", h.BaseHandler.QueryCluster,
+				"pod", key, "secret", secret.Name, "remoteAddr", c.RealIP())
+		}
+	}
+	return c.JSON(http.StatusOK, secrets)
+}
+
+func resolvePullSecrets(ctx context.Context, clientSet kubernetes.Interface, pod *v1.Pod) PodImagePullSecrets {
+	result := PodImagePullSecrets{ServiceAccount: pod.Spec.ServiceAccountName, Secrets: make([]ImagePullSecret, 0)}
+	if result.ServiceAccount == "" {
+		result.ServiceAccount = "default"
+	}
+
+	var names []string
+	sources := make(map[string][]string)
+	add := func(refs []v1.LocalObjectReference, source string) {
+		for _, ref := range refs {
+			if ref.Name == "" {
+				continue
+			}
+			if _, ok := sources[ref.Name]; !ok {
+				names = append(names, ref.Name)
+			}
+			sources[ref.Name] = append(sources[ref.Name], source)
+		}
+	}
+	add(pod.Spec.ImagePullSecrets, "pod")
+	serviceAccount, err := clientSet.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, result.ServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		result.ServiceAccountError = err.Error()
+	} else {
+		add(serviceAccount.ImagePullSecrets, "serviceAccount")
+	}
+
+	for _, name := range names {
+		pullSecret := ImagePullSecret{Name: name, Sources: sources[name], Registries: make([]RegistryCredential, 0)}
+		secret, err := clientSet.CoreV1().Secrets(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			pullSecret.Status, pullSecret.Error = PullSecretMissing, fmt.Sprintf("secret %s not found", name)
+		case err != nil:
+			pullSecret.Status, pullSecret.Error = PullSecretError, err.Error()
+		default:
+			pullSecret.Type = string(secret.Type)
+			registries, err := pullSecretRegistries(secret)
+			if err != nil {
+				pullSecret.Status, pullSecret.Error = PullSecretInvalid, err.Error()
+			} else {
+				pullSecret.Status, pullSecret.Registries = PullSecretOK, registries
+			}
+		}
+		result.Secrets = append(result.Secrets, pullSecret)
+	}
+	return result
+}
+
+// pullSecretRegistries reads the registries and usernames of a
+// kubernetes.io/dockerconfigjson or the legacy kubernetes.io/dockercfg secret.
+func pullSecretRegistries(secret *v1.Secret) ([]RegistryCredential, error) {
+	var entries map[string]dockerConfigEntry
+	switch secret.Type {
+	case v1.SecretTypeDockerConfigJson:
+		data, ok := secret.Data[v1.DockerConfigJsonKey]
+		if !ok {
+			return nil, fmt.Errorf("secret has no %s key", v1.DockerConfigJsonKey)
+		}
+		var config struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", v1.DockerConfigJsonKey, err)
+		}
+		entries = config.Auths
+	case v1.SecretTypeDockercfg:
+		data, ok := secret.Data[v1.DockerConfigKey]
+		if !ok {
+			return nil, fmt.Errorf("secret has no %s key", v1.DockerConfigKey)
+		}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", v1.DockerConfigKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("secret type %s is not %s", secret.Type, v1.SecretTypeDockerConfigJson)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("secret has no registries")
+	}
+
+	registries := make([]RegistryCredential, 0, len(entries))
+	for server, entry := range entries {
+		registries = append(registries, RegistryCredential{Server: server, Username: entryUsername(entry)})
+	}
+	sort.Slice(registries, func(i, j int) bool { return registries[i].Server < registries[j].Server })
+	return registries, nil
+}
+
+// entryUsername takes the username field or, without one, the part of the
+// base64 user:password auth before the colon.
+func entryUsername(entry dockerConfigEntry) string {
+	if entry.Username != "" {
+		return entry.Username
+	}
+	auth, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return ""
+	}
+	username, _, _ := strings.Cut(string(auth), ":")
+	return username
+}
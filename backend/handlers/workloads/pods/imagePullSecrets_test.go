@@ -0,0 +1,82 @@
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolvePullSecrets(t *testing.T) {
+	meta := func(name string) metav1.ObjectMeta { return metav1.ObjectMeta{Namespace: "apps", Name: name} }
+	clientSet := fake.NewClientset(
+		&v1.ServiceAccount{
+			ObjectMeta:       meta("builder"),
+			ImagePullSecrets: []v1.LocalObjectReference{{Name: "ghcr"}, {Name: "legacy"}},
+		},
+		&v1.Secret{
+			ObjectMeta: meta("ghcr"),
+			Type:       v1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{v1.DockerConfigJsonKey: []byte(`{"auths":{
+				"ghcr.io":{"username":"bot","password":"s3cret"},
+				"registry.example.com":{"auth":"` + "ZGVwbG95OnMzY3JldA==" + `"}}}`)},
+		},
+		&v1.Secret{
+			ObjectMeta: meta("legacy"),
+			Type:       v1.SecretTypeDockercfg,
+			Data:       map[string][]byte{v1.DockerConfigKey: []byte(`{"quay.io":{"username":"robot","password":"s3cret"}}`)},
+		},
+		&v1.Secret{
+			ObjectMeta: meta("opaque"),
+			Type:       v1.SecretTypeOpaque,
+			Data:       map[string][]byte{"token": []byte("s3cret")},
+		},
+	)
+	pod := &v1.Pod{
+		ObjectMeta: meta("web"),
+		Spec: v1.PodSpec{
+			ServiceAccountName: "builder",
+			ImagePullSecrets:   []v1.LocalObjectReference{{Name: "ghcr"}, {Name: "gone"}, {Name: "opaque"}},
+		},
+	}
+
+	result := resolvePullSecrets(context.Background(), clientSet, pod)
+	assert.Equal(t, "builder", result.ServiceAccount)
+	assert.Empty(t, result.ServiceAccountError)
+	assert.Equal(t, []ImagePullSecret{
+		{
+			Name: "ghcr", Sources: []string{"pod", "serviceAccount"}, Type: string(v1.SecretTypeDockerConfigJson), Status: PullSecretOK,
+			Registries: []RegistryCredential{{Server: "ghcr.io", Username: "bot"}, {Server: "registry.example.com", Username: "deploy"}},
+		},
+		{Name: "gone", Sources: []string{"pod"}, Status: PullSecretMissing, Error: "secret gone not found", Registries: []RegistryCredential{}},
+		{
+			Name: "opaque", Sources: []string{"pod"}, Type: string(v1.SecretTypeOpaque), Status: PullSecretInvalid,
+			Error: "secret type Opaque is not kubernetes.io/dockerconfigjson", Registries: []RegistryCredential{},
+		},
+		{
+			Name: "legacy", Sources: []string{"serviceAccount"}, Type: string(v1.SecretTypeDockercfg), Status: PullSecretOK,
+			Registries: []RegistryCredential{{Server: "quay.io", Username: "robot"}},
+		},
+	}, result.Secrets)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cret")
+}
+
+func TestPullSecretRegistries(t *testing.T) {
+	secret := func(data string) *v1.Secret {
+		return &v1.Secret{Type: v1.SecretTypeDockerConfigJson, Data: map[string][]byte{v1.DockerConfigJsonKey: []byte(data)}}
+	}
+	_, err := pullSecretRegistries(secret(`not json`))
+	assert.ErrorContains(t, err, "invalid .dockerconfigjson")
+	_, err = pullSecretRegistries(secret(`{"auths":{}}`))
+	assert.EqualError(t, err, "secret has no registries")
+	_, err = pullSecretRegistries(&v1.Secret{Type: v1.SecretTypeDockerConfigJson})
+	assert.EqualError(t, err, "secret has no .dockerconfigjson key")
+}
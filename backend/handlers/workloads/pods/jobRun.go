@@ -0,0 +1,331 @@
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+const (
+	JobRunLog  = "log"
+	JobRunPod  = "pod"
+	JobRunDone = "done"
+)
+
+// jobLogDrainTimeout bounds how long the done event waits for the log
+// streams of finished pods to be read to the end.
+const jobLogDrainTimeout = 10 * time.Second
+
+// JobRunEvent is sent on the job run stream: "log" for every log line, "pod"
+// when a pod of the job starts, finishes or disappears, and a single "done"
+// once the job completes or fails.
+type JobRunEvent struct {
+	Type      string `json:"type"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Log       string `json:"log,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	// Result is "succeeded" or "failed" on the done event.
+	Result    string `json:"result,omitempty"`
+	Succeeded int32  `json:"succeeded,omitempty"`
+	Failed    int32  `json:"failed,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	// Failures describes the terminated containers of failed pods, e.g.
+	// "pi-x2k/pi: Error (exit code 1)".
+	Failures []string `json:"failures,omitempty"`
+	// Retryable and RetryAfterMs tell whether following the job again can
+	// help after an "error", see helpers.StreamError.
+	Retryable    bool  `json:"retryable,omitempty"`
+	RetryAfterMs int64 `json:"retryAfterMs,omitempty"`
+}
+
+// StreamJobRun follows a Job until it completes or fails, streaming the logs
+// of every pod it creates, parallel ones included.
+func (h *PodsHandler) StreamJobRun(c echo.Context) error {
+	ctx := c.Request().Context()
+	config := c.QueryParam("config")
+	cluster := c.QueryParam("cluster")
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+
+	job, err := h.clientSet.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	sseServer := sse.New()
+	sseServer.AutoStream = true
+	sseServer.EventTTL = 0
+	streamKey := fmt.Sprintf("%s-%s-%s-%s-job-run", config, cluster, namespace, name)
+	sseServer.CreateStream(streamKey)
+
+	go watchJobRun(ctx, h.clientSet, job, selector, func(e JobRunEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			log.Error("failed to marshal job run event", "err", err)
+			return
+		}
+		sseServer.Publish(streamKey, &sse.Event{Data: data})
+	})
+
+	sseServer.ServeHTTP(streamKey, c.Response(), c.Request())
+	return nil
+}
+
+func watchJobRun(ctx context.Context, clientSet kubernetes.Interface, job *batchV1.Job, selector labels.Selector, publish func(JobRunEvent)) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tailer := newJobLogTailer(ctx, clientSet, job.Namespace, publish)
+	pods := clientSet.CoreV1().Pods(job.Namespace)
+	podInformer := cache.NewSharedIndexInformer(cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector.String()
+			return pods.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector.String()
+			return pods.Watch(ctx, options)
+		},
+	}, clientSet), &v1.Pod{}, 0, cache.Indexers{})
+	registration, err := podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    tailer.observe,
+		UpdateFunc: func(_, obj any) { tailer.observe(obj) },
+		DeleteFunc: tailer.deleted,
+	})
+	if err != nil {
+		publish(JobRunEvent{Type: JobRunDone, Reason: "error", Message: err.Error()})
+		return
+	}
+	go podInformer.RunWithContext(ctx)
+	// Pods of an already finished job must be seen before it is reported done.
+	if !cache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		return
+	}
+
+	finished, err := waitForJob(ctx, clientSet, job)
+	if err != nil {
+		if ctx.Err() == nil {
+			streamError := helpers.NewStreamError(err)
+			publish(JobRunEvent{Type: JobRunDone, Reason: "error", Message: err.Error(), Retryable: streamError.Retryable, RetryAfterMs: streamError.RetryAfterMs})
+		}
+		return
+	}
+
+	tailer.drain(jobLogDrainTimeout)
+	publish(jobDoneEvent(finished, podInformer.GetStore().List(), tailer.tailedCount()))
+}
+
+// waitForJob returns the job once it has a Complete or Failed condition.
+func waitForJob(ctx context.Context, clientSet kubernetes.Interface, job *batchV1.Job) (*batchV1.Job, error) {
+	jobs := clientSet.BatchV1().Jobs(job.Namespace)
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", job.Name).String()
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.Watch(ctx, options)
+		},
+	}
+
+	var finished *batchV1.Job
+	_, err := watchtools.UntilWithSync(ctx, cache.ToListWatcherWithWatchListSemantics(lw, clientSet), &batchV1.Job{}, nil, func(e watch.Event) (bool, error) {
+		switch e.Type {
+		case watch.Deleted:
+			return false, errors.New("job was deleted")
+		case watch.Added, watch.Modified:
+			j, ok := e.Object.(*batchV1.Job)
+			if !ok || jobFinishedCondition(j) == nil {
+				return false, nil
+			}
+			finished = j
+			return true, nil
+		default:
+			return false, nil
+		}
+	})
+	return finished, err
+}
+
+func jobFinishedCondition(job *batchV1.Job) *batchV1.JobCondition {
+	for i, c := range job.Status.Conditions {
+		if (c.Type == batchV1.JobComplete || c.Type == batchV1.JobFailed) && c.Status == v1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func jobDoneEvent(job *batchV1.Job, pods []any, tailed int) JobRunEvent {
+	condition := jobFinishedCondition(job)
+	e := JobRunEvent{
+		Type:      JobRunDone,
+		Result:    "succeeded",
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Reason:    condition.Reason,
+		Message:   condition.Message,
+	}
+	if condition.Type == batchV1.JobFailed {
+		e.Result = "failed"
+	}
+
+	for _, obj := range pods {
+		pod, ok := obj.(*v1.Pod)
+		if !ok || pod.Status.Phase != v1.PodFailed {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if t := status.State.Terminated; t != nil && t.ExitCode != 0 {
+				e.Failures = append(e.Failures, fmt.Sprintf("%s/%s: %s (exit code %d)", pod.Name, status.Name, t.Reason, t.ExitCode))
+			}
+		}
+	}
+	sort.Strings(e.Failures)
+
+	if tailed == 0 && e.Message == "" {
+		e.Message = "no pods of the job were found, they may have been garbage-collected"
+	}
+	return e
+}
+
+// jobLogTailer starts following the logs of a pod as soon as its containers
+// run, so logs are read before the pod can be garbage-collected.
+type jobLogTailer struct {
+	ctx       context.Context
+	clientSet kubernetes.Interface
+	namespace string
+	publish   func(JobRunEvent)
+
+	mu       sync.Mutex
+	started  map[string]bool
+	draining bool
+	wg       sync.WaitGroup
+}
+
+func newJobLogTailer(ctx context.Context, clientSet kubernetes.Interface, namespace string, publish func(JobRunEvent)) *jobLogTailer {
+	return &jobLogTailer{ctx: ctx, clientSet: clientSet, namespace: namespace, publish: publish, started: make(map[string]bool)}
+}
+
+func (t *jobLogTailer) observe(obj any) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Status.Phase == v1.PodPending || pod.Status.Phase == "" {
+		return
+	}
+
+	containers := make([]string, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, c := range pod.Spec.InitContainers {
+		containers = append(containers, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+
+	t.mu.Lock()
+	if t.started[pod.Name] || t.draining {
+		t.mu.Unlock()
+		return
+	}
+	t.started[pod.Name] = true
+	t.wg.Add(len(containers))
+	t.mu.Unlock()
+
+	t.publish(JobRunEvent{Type: JobRunPod, Pod: pod.Name, Phase: string(pod.Status.Phase)})
+	for _, container := range containers {
+		go func() {
+			defer t.wg.Done()
+			t.follow(pod.Name, container)
+		}()
+	}
+}
+
+func (t *jobLogTailer) deleted(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	tailed := t.started[pod.Name]
+	t.mu.Unlock()
+
+	e := JobRunEvent{Type: JobRunPod, Pod: pod.Name, Phase: "Deleted"}
+	if !tailed {
+		e.Message = "pod was deleted before its logs could be read"
+	}
+	t.publish(e)
+}
+
+func (t *jobLogTailer) follow(podName, container string) {
+	logsChannel := make(chan LogMessage, 100)
+	go func() {
+		defer close(logsChannel)
+		streamLogs(t.ctx, t.clientSet, t.namespace, podName, &v1.PodLogOptions{
+			Container:  container,
+			Timestamps: true,
+			Follow:     true,
+		}, nil, logsChannel)
+	}()
+	for msg := range logsChannel {
+		t.publish(JobRunEvent{Type: JobRunLog, Pod: podName, Container: msg.ContainerName, Timestamp: msg.Timestamp, Log: msg.Log})
+	}
+}
+
+// drain waits for the log streams to end, which they do once the containers
+// have terminated, or until timeout.
+func (t *jobLogTailer) drain(timeout time.Duration) {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *jobLogTailer) tailedCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.started)
+}
@@ -0,0 +1,130 @@
+package pods
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func jobPod(name string, phase v1.PodPhase, exitCode int32) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"job-name": "pi"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "pi"}}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	if exitCode != 0 {
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{{
+			Name:  "pi",
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: exitCode}},
+		}}
+	}
+	return pod
+}
+
+type jobRunRecorder struct {
+	mu     sync.Mutex
+	events []JobRunEvent
+}
+
+func (r *jobRunRecorder) publish(e JobRunEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *jobRunRecorder) done() (JobRunEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e.Type == JobRunDone {
+			return e, true
+		}
+	}
+	return JobRunEvent{}, false
+}
+
+func (r *jobRunRecorder) pods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pods []string
+	for _, e := range r.events {
+		if e.Type == JobRunPod {
+			pods = append(pods, e.Pod)
+		}
+	}
+	return pods
+}
+
+func TestWatchJobRun(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"job-name": "pi"})
+	job := &batchV1.Job{ObjectMeta: metav1.ObjectMeta{Name: "pi", Namespace: "default"}}
+
+	t.Run("parallel job fails", func(t *testing.T) {
+		failed := job.DeepCopy()
+		failed.Status = batchV1.JobStatus{Succeeded: 1, Failed: 1, Conditions: []batchV1.JobCondition{
+			{Type: batchV1.JobFailed, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+		}}
+		other := jobPod("other", v1.PodRunning, 0)
+		other.Labels = map[string]string{"job-name": "other"}
+		clientSet := fake.NewClientset(failed, jobPod("pi-a", v1.PodSucceeded, 0), jobPod("pi-b", v1.PodFailed, 1), other)
+
+		r := &jobRunRecorder{}
+		watchJobRun(context.Background(), clientSet, failed, selector, r.publish)
+
+		done, ok := r.done()
+		assert.True(t, ok)
+		assert.Equal(t, "failed", done.Result)
+		assert.Equal(t, "BackoffLimitExceeded", done.Reason)
+		assert.Equal(t, []string{"pi-b/pi: Error (exit code 1)"}, done.Failures)
+		assert.ElementsMatch(t, []string{"pi-a", "pi-b"}, r.pods())
+	})
+
+	t.Run("waits for completion", func(t *testing.T) {
+		clientSet := fake.NewClientset(job.DeepCopy())
+		r := &jobRunRecorder{}
+		finished := make(chan struct{})
+		go func() {
+			watchJobRun(context.Background(), clientSet, job, selector, r.publish)
+			close(finished)
+		}()
+
+		_, err := clientSet.CoreV1().Pods("default").Create(context.Background(), jobPod("pi-a", v1.PodRunning, 0), metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(r.pods()) == 1 }, 5*time.Second, 10*time.Millisecond)
+		_, ok := r.done()
+		assert.False(t, ok)
+
+		complete := job.DeepCopy()
+		complete.Status = batchV1.JobStatus{Succeeded: 1, Conditions: []batchV1.JobCondition{{Type: batchV1.JobComplete, Status: v1.ConditionTrue}}}
+		_, err = clientSet.BatchV1().Jobs("default").UpdateStatus(context.Background(), complete, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatal("job run did not finish")
+		}
+		done, _ := r.done()
+		assert.Equal(t, "succeeded", done.Result)
+		assert.Equal(t, int32(1), done.Succeeded)
+	})
+
+	t.Run("pods garbage-collected", func(t *testing.T) {
+		complete := job.DeepCopy()
+		complete.Status = batchV1.JobStatus{Succeeded: 1, Conditions: []batchV1.JobCondition{{Type: batchV1.JobComplete, Status: v1.ConditionTrue}}}
+		r := &jobRunRecorder{}
+		watchJobRun(context.Background(), fake.NewClientset(complete), complete, selector, r.publish)
+
+		done, _ := r.done()
+		assert.Equal(t, "succeeded", done.Result)
+		assert.Contains(t, done.Message, "garbage-collected")
+	})
+}
@@ -0,0 +1,240 @@
+package pods
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultLogRangeBytes = 256 * 1024
+	maxLogRangeBytes     = 4 * 1024 * 1024
+	defaultLogRangeLines = 1000
+	maxLogRangeLines     = 10000
+)
+
+type LogRangeResponse struct {
+	Logs []LogMessage `json:"logs"`
+	// Cursor is passed back as ?cursor= to get the next chunk, it is set
+	// whenever HasMore is.
+	Cursor  string `json:"cursor,omitempty"`
+	HasMore bool   `json:"hasMore"`
+}
+
+// logCursor is the position after the last line returned: its timestamp and
+// how many lines of that timestamp were returned. SinceTime only has second
+// precision, so the next chunk starts at the second and skips those lines.
+type logCursor struct {
+	time time.Time
+	skip int
+}
+
+type logRangeLine struct {
+	time    time.Time
+	message string
+}
+
+func (c logCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.time.Format(time.RFC3339Nano) + "|" + strconv.Itoa(c.skip)))
+}
+
+func decodeLogCursor(value string) (logCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return logCursor{}, fmt.Errorf("invalid cursor")
+	}
+	timestamp, skip, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return logCursor{}, fmt.Errorf("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return logCursor{}, fmt.Errorf("invalid cursor")
+	}
+	n, err := strconv.Atoi(skip)
+	if err != nil || n < 0 {
+		return logCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return logCursor{time: t, skip: n}, nil
+}
+
+// GetLogRange returns the logs of ?container= between ?sinceTime= and the
+// optional ?untilTime= (RFC3339) in chunks of at most ?limitBytes= read and
+// ?limit= lines. A response with hasMore has a cursor to get the next chunk,
+// the grep params filter the lines of every chunk.
+func (h *PodsHandler) GetLogRange(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var from logCursor
+	if value := c.QueryParam("cursor"); value != "" {
+		if from, err = decodeLogCursor(value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		since, err := time.Parse(time.RFC3339Nano, c.QueryParam("sinceTime"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "sinceTime is required as an RFC3339 timestamp")
+		}
+		from = logCursor{time: since}
+	}
+	var until time.Time
+	if value := c.QueryParam("untilTime"); value != "" {
+		if until, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid untilTime, expected an RFC3339 timestamp")
+		}
+	}
+	limitBytes, err := logRangeLimit(c.QueryParam("limitBytes"), defaultLogRangeBytes, maxLogRangeBytes)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limitBytes: %s", err))
+	}
+	limitLines, err := logRangeLimit(c.QueryParam("limit"), defaultLogRangeLines, maxLogRangeLines)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit: %s", err))
+	}
+
+	containerNames, err := h.getContainerNames(namespace, name, c.QueryParam("container"), "")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	containerName := containerNames[0]
+	req := h.clientSet.CoreV1().Pods(namespace).GetLogs(name, &v1.PodLogOptions{
+		Container:  containerName,
+		Timestamps: true,
+		SinceTime:  &metav1.Time{Time: from.time.Truncate(time.Second)},
+		LimitBytes: &limitBytes,
+	})
+	podLogs, err := req.Stream(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	defer podLogs.Close()
+
+	response, err := readLogRange(podLogs, containerName, from, until, filter, int(limitLines), limitBytes)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func logRangeLimit(value string, def, max int64) (int64, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%q is not a positive number", value)
+	}
+	return min(n, max), nil
+}
+
+// readLogRange reads the timestamped lines of one log chunk read with
+// LimitBytes set to limitBytes. Lines before from, or within its skip count,
+// were returned by an earlier chunk. When the read hit limitBytes the last
+// line may be cut off, it is left to the next chunk unless it is the only one.
+func readLogRange(r io.Reader, containerName string, from logCursor, until time.Time, filter *logFilter, limitLines int, limitBytes int64) (LogRangeResponse, error) {
+	response := LogRangeResponse{Logs: make([]LogMessage, 0)}
+	counted := &countingReader{r: r}
+	scanner := bufio.NewScanner(counted)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	var lines []logRangeLine
+	skip := from.skip
+	for scanner.Scan() {
+		timestamp, message, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil || t.Before(from.time) {
+			continue
+		}
+		if t.Equal(from.time) && skip > 0 {
+			skip--
+			continue
+		}
+		if !until.IsZero() && t.After(until) {
+			return toLogRange(response, containerName, lines, filter, false), nil
+		}
+		lines = append(lines, logRangeLine{time: t, message: message})
+		// One line past the limit tells whether there is more.
+		if len(lines) > limitLines {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return response, echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	hasMore := len(lines) > limitLines
+	if hasMore {
+		lines = lines[:limitLines]
+	} else if counted.n >= limitBytes {
+		hasMore = true
+		if len(lines) == 0 {
+			// The cursor would not move, the lines skipped alone fill limitBytes.
+			return response, echo.NewHTTPError(http.StatusUnprocessableEntity, "limitBytes is too small to read past the cursor")
+		}
+		if len(lines) > 1 {
+			lines = lines[:len(lines)-1]
+		}
+	}
+	if !hasMore {
+		return toLogRange(response, containerName, lines, filter, false), nil
+	}
+
+	// The cursor counts every line of its timestamp read so far, including
+	// those of earlier chunks when this one did not move past it.
+	next := logCursor{time: from.time, skip: from.skip}
+	if len(lines) > 0 {
+		last := lines[len(lines)-1].time
+		if !last.Equal(from.time) {
+			next = logCursor{time: last}
+		}
+		for _, line := range lines {
+			if line.time.Equal(last) {
+				next.skip++
+			}
+		}
+	}
+	response.Cursor = next.encode()
+	return toLogRange(response, containerName, lines, filter, true), nil
+}
+
+func toLogRange(response LogRangeResponse, containerName string, lines []logRangeLine, filter *logFilter, hasMore bool) LogRangeResponse {
+	for _, line := range lines {
+		if !filter.keep(line.message) {
+			continue
+		}
+		response.Logs = append(response.Logs, LogMessage{
+			ContainerName: containerName,
+			Timestamp:     line.time.Format(timestampLayout),
+			Log:           line.message,
+		})
+	}
+	response.HasMore = hasMore
+	return response
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
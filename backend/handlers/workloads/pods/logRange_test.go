@@ -0,0 +1,83 @@
+package pods
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLogRange(t *testing.T) {
+	logs := strings.Join([]string{
+		"2024-05-01T10:00:00.100000000Z before",
+		"2024-05-01T10:00:01.000000000Z one",
+		"2024-05-01T10:00:01.000000000Z two",
+		"2024-05-01T10:00:01.000000000Z three",
+		"2024-05-01T10:00:02.500000000Z four",
+		"2024-05-01T10:00:05.000000000Z after",
+	}, "\n") + "\n"
+	since := time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)
+	until := time.Date(2024, 5, 1, 10, 0, 3, 0, time.UTC)
+	messages := func(r LogRangeResponse) []string {
+		var m []string
+		for _, l := range r.Logs {
+			m = append(m, l.Log)
+		}
+		return m
+	}
+
+	// Pages of two lines, the cursor skips lines of the same timestamp
+	// already returned.
+	r, err := readLogRange(strings.NewReader(logs), "app", logCursor{time: since}, until, nil, 2, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, messages(r))
+	assert.Equal(t, "2024-05-01 10:00:01.000Z", r.Logs[0].Timestamp)
+	require.True(t, r.HasMore)
+	cursor, err := decodeLogCursor(r.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, logCursor{time: since, skip: 2}, cursor)
+
+	r, err = readLogRange(strings.NewReader(logs), "app", cursor, until, nil, 2, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "four"}, messages(r))
+	// The next line is past untilTime, ending the range.
+	assert.False(t, r.HasMore)
+	assert.Empty(t, r.Cursor)
+
+	// A chunk cut at limitBytes leaves its partial last line to the next one.
+	cut := logs[:strings.Index(logs, "three")+2]
+	r, err = readLogRange(strings.NewReader(cut), "app", logCursor{time: since}, time.Time{}, nil, 10, int64(len(cut)))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, messages(r))
+	assert.True(t, r.HasMore)
+	cursor, err = decodeLogCursor(r.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, logCursor{time: since, skip: 2}, cursor)
+
+	// Filtered lines still move the cursor.
+	filter := &logFilter{re: regexp.MustCompile("^t")}
+	r, err = readLogRange(strings.NewReader(logs), "app", logCursor{time: since}, until, filter, 10, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "three"}, messages(r))
+	assert.False(t, r.HasMore)
+
+	// Skipped lines alone filling limitBytes would never advance.
+	_, err = readLogRange(strings.NewReader(cut), "app", logCursor{time: since, skip: 3}, time.Time{}, nil, 10, int64(len(cut)))
+	assert.Error(t, err)
+}
+
+func TestDecodeLogCursor(t *testing.T) {
+	c := logCursor{time: time.Date(2024, 5, 1, 10, 0, 1, 123456789, time.UTC), skip: 4}
+	decoded, err := decodeLogCursor(c.encode())
+	require.NoError(t, err)
+	assert.True(t, c.time.Equal(decoded.time))
+	assert.Equal(t, 4, decoded.skip)
+
+	for _, value := range []string{"!!", "bm90LWEtY3Vyc29y", c.encode()[:10]} {
+		_, err := decodeLogCursor(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,17 +17,69 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/r3labs/sse/v2"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const maxLogLineSize = 1024 * 1024
 
+// logMetaEvent names the SSE event that lists the streams of a log request
+// before any line is sent.
+const logMetaEvent = "meta"
+
 type LogMessage struct {
 	ContainerName string `json:"containerName"`
 	Timestamp     string `json:"timestamp"`
 	Log           string `json:"log"`
+	// StreamID identifies the container within the request, in the order the
+	// meta event lists them. Seq numbers the lines of one stream in the order
+	// they were read, lines of different streams may arrive interleaved.
+	StreamID int    `json:"streamId"`
+	Seq      uint64 `json:"seq,omitempty"`
+}
+
+type LogStream struct {
+	StreamID      int    `json:"streamId"`
+	ContainerName string `json:"containerName"`
+}
+
+// LogStreamMeta is sent once as the "meta" event so clients can set up a
+// lane per container before logs arrive.
+type LogStreamMeta struct {
+	Streams []LogStream `json:"streams"`
+}
+
+// logFilter keeps the log lines matching a regular expression, or with
+// invert the lines that do not match.
+type logFilter struct {
+	re     *regexp.Regexp
+	invert bool
+}
+
+// parseLogFilter compiles the grep, invert and caseInsensitive params once
+// per request. It returns nil, keeping every line, when grep is empty.
+func parseLogFilter(c echo.Context) (*logFilter, error) {
+	pattern := c.QueryParam("grep")
+	if pattern == "" {
+		return nil, nil
+	}
+	if c.QueryParam("caseInsensitive") == "true" {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep expression: %w", err)
+	}
+	return &logFilter{re: re, invert: c.QueryParam("invert") == "true"}, nil
+}
+
+func (f *logFilter) keep(line string) bool {
+	if f == nil {
+		return true
+	}
+	return f.re.MatchString(line) != f.invert
 }
 
-func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, containerName string, logsChannel chan<- LogMessage) {
+func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, containerName string, filter *logFilter, logsChannel chan<- LogMessage) {
 	i := int64(100)
 	podLogOptions := &v1.PodLogOptions{
 		Container:  containerName,
@@ -34,7 +87,15 @@ func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, contain
 		Follow:     true,
 		TailLines:  &i,
 	}
-	req := h.clientSet.CoreV1().Pods(namespace).GetLogs(podName, podLogOptions)
+	streamLogs(ctx, h.clientSet, namespace, podName, podLogOptions, filter, logsChannel)
+}
+
+// streamLogs sends the log lines of a container that pass filter to
+// logsChannel until the stream ends or ctx is done. podLogOptions must
+// request timestamps.
+func streamLogs(ctx context.Context, clientSet kubernetes.Interface, namespace, podName string, podLogOptions *v1.PodLogOptions, filter *logFilter, logsChannel chan<- LogMessage) {
+	containerName := podLogOptions.Container
+	req := clientSet.CoreV1().Pods(namespace).GetLogs(podName, podLogOptions)
 	podLogs, err := req.Stream(ctx)
 	if err != nil {
 		log.Error("failed to open log stream", "pod", podName, "container", containerName, "err", err)
@@ -50,6 +111,7 @@ func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, contain
 	scanner := bufio.NewScanner(podLogs)
 	scanner.Buffer(make([]byte, 0, maxLogLineSize), maxLogLineSize)
 
+	var seq uint64
 	for scanner.Scan() {
 		select {
 		case <-ctx.Done():
@@ -62,15 +124,20 @@ func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, contain
 			log.Warn("malformed log line received", "pod", podName, "container", containerName)
 			continue
 		}
+		if !filter.keep(message) {
+			continue
+		}
 		parseTime, err := time.Parse(time.RFC3339Nano, timestamp)
 		if err != nil {
 			log.Error("failed to parse log timestamp", "pod", podName, "container", containerName, "raw", timestamp, "err", err)
 			continue
 		}
+		seq++
 		msg := LogMessage{
 			ContainerName: containerName,
 			Timestamp:     parseTime.Format("2006-01-02 15:04:05.000Z"),
 			Log:           message,
+			Seq:           seq,
 		}
 		select {
 		case logsChannel <- msg:
@@ -83,12 +150,20 @@ func (h *PodsHandler) fetchLogs(ctx context.Context, namespace, podName, contain
 	}
 }
 
-func (h *PodsHandler) publishLogsToSSE(ctx context.Context, name, namespace, container, allContainers, streamKey string, sseServer *sse.Server) (error, bool) {
+func (h *PodsHandler) publishLogsToSSE(ctx context.Context, name, namespace, container, allContainers string, filter *logFilter, streamKey string, sseServer *sse.Server) (error, bool) {
 	containerNames, err := h.getContainerNames(namespace, name, container, allContainers)
 	if err != nil {
 		return err, true
 	}
 
+	meta, streamIDs := logStreams(containerNames)
+	if j, err := json.Marshal(meta); err == nil {
+		sseServer.Publish(streamKey, &sse.Event{
+			Event: []byte(logMetaEvent),
+			Data:  j,
+		})
+	}
+
 	logsChannel := make(chan LogMessage, 100)
 
 	var wg sync.WaitGroup
@@ -96,7 +171,7 @@ func (h *PodsHandler) publishLogsToSSE(ctx context.Context, name, namespace, con
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			h.fetchLogs(ctx, namespace, name, containerName, logsChannel)
+			h.fetchLogs(ctx, namespace, name, containerName, filter, logsChannel)
 		}()
 	}
 	go func() {
@@ -105,6 +180,7 @@ func (h *PodsHandler) publishLogsToSSE(ctx context.Context, name, namespace, con
 	}()
 
 	for logMsg := range logsChannel {
+		logMsg.StreamID = streamIDs[logMsg.ContainerName]
 		j, err := json.Marshal(logMsg)
 		if err != nil {
 			log.Error("failed to marshal log message", "err", err)
@@ -118,6 +194,18 @@ func (h *PodsHandler) publishLogsToSSE(ctx context.Context, name, namespace, con
 	return nil, false
 }
 
+// logStreams assigns each container a stream ID by its position in
+// containerNames, init containers first, so IDs are stable across reconnects.
+func logStreams(containerNames []string) (LogStreamMeta, map[string]int) {
+	meta := LogStreamMeta{Streams: make([]LogStream, 0, len(containerNames))}
+	streamIDs := make(map[string]int, len(containerNames))
+	for i, name := range containerNames {
+		meta.Streams = append(meta.Streams, LogStream{StreamID: i, ContainerName: name})
+		streamIDs[name] = i
+	}
+	return meta, streamIDs
+}
+
 const timestampLayout = "2006-01-02 15:04:05.000Z"
 
 type HistoryResponse struct {
@@ -125,8 +213,11 @@ type HistoryResponse struct {
 	HasMore bool         `json:"hasMore"`
 }
 
+// getContainerNames returns the containers to read logs from: every one with
+// allContainers, else the named one or the pod's default container.
 func (h *PodsHandler) getContainerNames(namespace, name, container, allContainers string) ([]string, error) {
-	if !strings.EqualFold(allContainers, "true") {
+	all := strings.EqualFold(allContainers, "true")
+	if !all && container != "" {
 		return []string{container}, nil
 	}
 	podObj, _, err := h.BaseHandler.Informer.GetStore().GetByKey(fmt.Sprintf("%s/%s", namespace, name))
@@ -140,6 +231,9 @@ func (h *PodsHandler) getContainerNames(namespace, name, container, allContainer
 	if !ok {
 		return nil, fmt.Errorf("failed to type assert pod object %s/%s", namespace, name)
 	}
+	if !all {
+		return []string{defaultContainer(pod)}, nil
+	}
 	var names []string
 	for _, c := range pod.Spec.InitContainers {
 		names = append(names, c.Name)
@@ -197,6 +291,10 @@ func (h *PodsHandler) GetLogHistory(c echo.Context) error {
 	beforeStr := c.QueryParam("before")
 	batchSizeStr := c.QueryParam("batchSize")
 
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	if beforeStr == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "before parameter is required")
 	}
@@ -238,7 +336,7 @@ func (h *PodsHandler) GetLogHistory(c echo.Context) error {
 			if err != nil {
 				continue
 			}
-			if t.Before(beforeTime) {
+			if t.Before(beforeTime) && filter.keep(l.Log) {
 				filtered = append(filtered, l)
 			}
 		}
@@ -0,0 +1,76 @@
+package pods
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestParseLogFilter(t *testing.T) {
+	parse := func(query string) (*logFilter, error) {
+		req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		return parseLogFilter(echo.New().NewContext(req, httptest.NewRecorder()))
+	}
+
+	filter, err := parse("")
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.keep("anything"))
+
+	filter, err = parse("grep=error%7Cwarn")
+	require.NoError(t, err)
+	assert.True(t, filter.keep("level=error msg=boom"))
+	assert.False(t, filter.keep("level=ERROR msg=boom"))
+	assert.False(t, filter.keep("level=info"))
+
+	filter, err = parse("grep=error&caseInsensitive=true&invert=true")
+	require.NoError(t, err)
+	assert.False(t, filter.keep("level=ERROR msg=boom"))
+	assert.True(t, filter.keep("level=info"))
+
+	_, err = parse("grep=%28unclosed")
+	assert.Error(t, err)
+}
+
+func TestLogStreams(t *testing.T) {
+	meta, streamIDs := logStreams([]string{"migrate", "app", "sidecar"})
+	assert.Equal(t, LogStreamMeta{Streams: []LogStream{
+		{StreamID: 0, ContainerName: "migrate"},
+		{StreamID: 1, ContainerName: "app"},
+		{StreamID: 2, ContainerName: "sidecar"},
+	}}, meta)
+	assert.Equal(t, 2, streamIDs["sidecar"])
+}
+
+func TestGetContainerNames(t *testing.T) {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &v1.Pod{}, 0, cache.Indexers{})
+	h := &PodsHandler{BaseHandler: base.BaseHandler{Informer: informer}}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{"kubectl.kubernetes.io/default-container": "app"}},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate"}},
+			Containers:     []v1.Container{{Name: "proxy"}, {Name: "app"}},
+		},
+	}
+	require.NoError(t, informer.GetStore().Add(pod))
+
+	names, err := h.getContainerNames("shop", "web", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app"}, names)
+	names, err = h.getContainerNames("shop", "web", "proxy", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"proxy"}, names)
+	names, err = h.getContainerNames("shop", "web", "", "true")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"migrate", "proxy", "app"}, names)
+	_, err = h.getContainerNames("shop", "missing", "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package pods
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func podMetrics(name, cpu string) v1beta1.PodMetrics {
+	return v1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Containers: []v1beta1.ContainerMetrics{{
+			Name:  "app",
+			Usage: coreV1.ResourceList{coreV1.ResourceCPU: resource.MustParse(cpu), coreV1.ResourceMemory: resource.MustParse("1Mi")},
+		}},
+	}
+}
+
+func TestIntermittentPodMetrics(t *testing.T) {
+	cache := helpers.NewMetricsCache[v1beta1.PodMetrics](time.Minute)
+	pods := []coreV1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+	}
+	byName := func(list []PodList) map[string]PodList {
+		m := make(map[string]PodList)
+		for _, p := range list {
+			m[p.Name] = p
+		}
+		return m
+	}
+
+	list, stale := mergePodMetrics(cache, &v1beta1.PodMetricsList{Items: []v1beta1.PodMetrics{podMetrics("api", "100m"), podMetrics("web", "200m")}}, nil)
+	got := byName(TransformPodList(pods, list, stale))
+	assert.Equal(t, "0.100000", got["api"].CPU)
+	assert.False(t, got["api"].MetricsStale)
+
+	// metrics-server answers without one of the pods.
+	list, stale = mergePodMetrics(cache, &v1beta1.PodMetricsList{Items: []v1beta1.PodMetrics{podMetrics("api", "300m")}}, nil)
+	got = byName(TransformPodList(pods, list, stale))
+	assert.Equal(t, "0.300000", got["api"].CPU)
+	assert.False(t, got["api"].MetricsStale)
+	assert.Equal(t, "0.200000", got["web"].CPU)
+	assert.True(t, got["web"].MetricsStale)
+
+	// The metrics API is down, nothing drops to zero.
+	list, stale = mergePodMetrics(cache, &v1beta1.PodMetricsList{}, errors.New("service unavailable"))
+	got = byName(TransformPodList(pods, list, stale))
+	assert.Equal(t, "0.300000", got["api"].CPU)
+	assert.True(t, got["api"].MetricsStale)
+	assert.Equal(t, "0.200000", got["web"].CPU)
+	assert.True(t, got["web"].MetricsStale)
+}
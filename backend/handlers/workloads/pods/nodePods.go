@@ -34,7 +34,7 @@ func (h *PodsHandler) NodePods() {
 	}
 
 	// fetch metrics once
-	podsMetricsList := GetPodsMetricsList(&h.BaseHandler)
+	podsMetricsList, staleMetrics := GetPodsMetricsList(&h.BaseHandler)
 
 	nodes := make([]string, 0, len(podsByNode))
 	for n := range podsByNode {
@@ -44,7 +44,7 @@ func (h *PodsHandler) NodePods() {
 
 	for _, node := range nodes {
 		perNodePods := podsByNode[node]
-		transformed := TransformPodList(perNodePods, podsMetricsList)
+		transformed := TransformPodList(perNodePods, podsMetricsList, staleMetrics)
 
 		data, err := json.Marshal(transformed)
 		if err != nil {
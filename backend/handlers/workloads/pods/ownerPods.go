@@ -0,0 +1,171 @@
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/r3labs/sse/v2"
+	appV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// ownerFilter selects the pods pushed on an owner pods stream. A non-empty uid
+// takes precedence over kind and name.
+type ownerFilter struct {
+	namespace string
+	kind      string
+	name      string
+	uid       types.UID
+}
+
+func (f ownerFilter) matches(owners []metav1.OwnerReference) bool {
+	for _, owner := range owners {
+		if f.uid != "" {
+			if owner.UID == f.uid {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(owner.Kind, f.kind) && owner.Name == f.name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOwnerPods streams the pods whose owner chain includes the requested
+// owner. ReplicaSets are followed to their Deployment and Jobs to their
+// CronJob; other controllers match on their direct ownerReferences, by
+// ?uid= when given.
+func (h *PodsHandler) GetOwnerPods(c echo.Context) error {
+	filter := ownerFilter{
+		namespace: c.QueryParam("namespace"),
+		kind:      c.Param("kind"),
+		name:      c.Param("name"),
+		uid:       types.UID(c.QueryParam("uid")),
+	}
+	if filter.uid == "" && (filter.kind == "" || filter.name == "") {
+		return echo.NewHTTPError(http.StatusBadRequest, "owner kind and name, or uid, are required")
+	}
+	if filter.uid == "" {
+		if err := checkOwner(c.Request().Context(), h.clientSet, filter); err != nil {
+			return err
+		}
+	}
+
+	streamID := fmt.Sprintf("%s-%s-%s-%s-%s-%s-owner-pods", h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster, filter.namespace, strings.ToLower(filter.kind), filter.name, filter.uid)
+	release, err := h.ownerStreams.Acquire(streamID, filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
+	}
+	defer release()
+	go func() {
+		podsMetricsList, staleMetrics := GetPodsMetricsList(&h.BaseHandler)
+		h.publishOwnerPods(streamID, filter, podsMetricsList, staleMetrics)
+	}()
+
+	h.BaseHandler.Container.SSE().ServeHTTP(streamID, c.Response(), c.Request())
+	return nil
+}
+
+// OwnerPods refreshes every open owner pods stream. Metrics are fetched once
+// for all of them.
+func (h *PodsHandler) OwnerPods() {
+	if h.ownerStreams.Len() == 0 {
+		return
+	}
+	podsMetricsList, staleMetrics := GetPodsMetricsList(&h.BaseHandler)
+	h.ownerStreams.Range(func(streamID string, filter ownerFilter) {
+		h.publishOwnerPods(streamID, filter, podsMetricsList, staleMetrics)
+	})
+}
+
+func (h *PodsHandler) publishOwnerPods(streamID string, filter ownerFilter, podsMetricsList *v1beta1.PodMetricsList, staleMetrics map[string]bool) {
+	var items []any
+	if filter.namespace != "" {
+		items, _ = h.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, filter.namespace)
+	} else {
+		items = h.BaseHandler.Informer.GetStore().List()
+	}
+
+	var pods []v1.Pod
+	for _, obj := range items {
+		if pod, ok := obj.(*v1.Pod); ok && filter.matches(h.ownerChain(pod)) {
+			pods = append(pods, *pod)
+		}
+	}
+
+	data, err := json.Marshal(TransformPodList(pods, podsMetricsList, staleMetrics))
+	if err != nil {
+		data = []byte("[]")
+	}
+	h.BaseHandler.Container.SSE().Publish(streamID, &sse.Event{Data: data})
+}
+
+// checkOwner verifies that a built-in workload owner exists so that a
+// mistyped name is reported instead of streaming an empty list. Owners of
+// other kinds are matched on ownerReferences only.
+func checkOwner(ctx context.Context, clientSet kubernetes.Interface, filter ownerFilter) *echo.HTTPError {
+	ns, name, opts := filter.namespace, filter.name, metav1.GetOptions{}
+	var get func() error
+	switch strings.ToLower(filter.kind) {
+	case "deployment":
+		get = func() error { _, err := clientSet.AppsV1().Deployments(ns).Get(ctx, name, opts); return err }
+	case "replicaset":
+		get = func() error { _, err := clientSet.AppsV1().ReplicaSets(ns).Get(ctx, name, opts); return err }
+	case "daemonset":
+		get = func() error { _, err := clientSet.AppsV1().DaemonSets(ns).Get(ctx, name, opts); return err }
+	case "statefulset":
+		get = func() error { _, err := clientSet.AppsV1().StatefulSets(ns).Get(ctx, name, opts); return err }
+	case "job":
+		get = func() error { _, err := clientSet.BatchV1().Jobs(ns).Get(ctx, name, opts); return err }
+	case "cronjob":
+		get = func() error { _, err := clientSet.BatchV1().CronJobs(ns).Get(ctx, name, opts); return err }
+	default:
+		return nil
+	}
+
+	if ns == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("namespace is required for owner kind %s", filter.kind))
+	}
+	if err := get(); err != nil {
+		if apierrors.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("%s %s/%s not found", filter.kind, ns, name))
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+// ownerChain returns the pod's owners together with the owners of any
+// ReplicaSet or Job among them.
+func (h *PodsHandler) ownerChain(pod *v1.Pod) []metav1.OwnerReference {
+	chain := append([]metav1.OwnerReference{}, pod.OwnerReferences...)
+	for _, owner := range pod.OwnerReferences {
+		key := fmt.Sprintf("%s/%s", pod.GetNamespace(), owner.Name)
+		switch owner.Kind {
+		case "ReplicaSet":
+			item, exists, err := h.replicasetHandler.BaseHandler.Informer.GetStore().GetByKey(key)
+			if rs, ok := item.(*appV1.ReplicaSet); err == nil && exists && ok {
+				chain = append(chain, rs.GetOwnerReferences()...)
+			}
+		case "Job":
+			item, exists, err := h.jobsHandler.BaseHandler.Informer.GetStore().GetByKey(key)
+			if job, ok := item.(*batchV1.Job); err == nil && exists && ok {
+				chain = append(chain, job.GetOwnerReferences()...)
+			}
+		}
+	}
+	return chain
+}
@@ -0,0 +1,50 @@
+package pods
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckOwner(t *testing.T) {
+	clientSet := fake.NewClientset(&appV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+	})
+
+	tests := []struct {
+		name   string
+		filter ownerFilter
+		code   int
+	}{
+		{name: "existing owner", filter: ownerFilter{namespace: "default", kind: "Deployment", name: "web"}},
+		{name: "nonexistent owner", filter: ownerFilter{namespace: "default", kind: "Deployment", name: "wbe"}, code: http.StatusNotFound},
+		{name: "missing namespace", filter: ownerFilter{kind: "StatefulSet", name: "db"}, code: http.StatusBadRequest},
+		{name: "custom controller is not checked", filter: ownerFilter{namespace: "default", kind: "Rollout", name: "web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOwner(context.Background(), clientSet, tt.filter)
+			if tt.code == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, tt.code, err.Code)
+			}
+		})
+	}
+}
+
+func TestOwnerFilterMatches(t *testing.T) {
+	owners := []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-6d4f", UID: "rs-uid"}, {Kind: "Deployment", Name: "web", UID: "dep-uid"}}
+
+	assert.True(t, ownerFilter{kind: "deployment", name: "web"}.matches(owners))
+	assert.False(t, ownerFilter{kind: "deployment", name: "api"}.matches(owners))
+	assert.True(t, ownerFilter{uid: "rs-uid"}.matches(owners))
+	assert.False(t, ownerFilter{kind: "Deployment", name: "web", uid: "other"}.matches(owners))
+}
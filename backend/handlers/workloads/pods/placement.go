@@ -0,0 +1,467 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+)
+
+// PlacementRule is one scheduling rule of a pod in selector syntax.
+type PlacementRule struct {
+	Description string `json:"description"`
+	// Required rules must hold for the pod to be scheduled, the others are
+	// preferences, weighted by Weight for affinities.
+	Required bool  `json:"required"`
+	Weight   int32 `json:"weight,omitempty"`
+	// Satisfied is whether the rule holds on the pod's node. It is unset for
+	// pods not scheduled yet and rules that could not be checked.
+	Satisfied *bool  `json:"satisfied,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// PodPlacement is what decides where a pod may run, and whether it holds on
+// the node the pod landed on.
+type PodPlacement struct {
+	Node            string          `json:"node,omitempty"`
+	NodeSelector    []PlacementRule `json:"nodeSelector"`
+	NodeAffinity    []PlacementRule `json:"nodeAffinity"`
+	PodAffinity     []PlacementRule `json:"podAffinity"`
+	PodAntiAffinity []PlacementRule `json:"podAntiAffinity"`
+	TopologySpread  []PlacementRule `json:"topologySpread"`
+	Tolerations     []string        `json:"tolerations"`
+	// Taints are the taints of the node, satisfied when the pod tolerates
+	// them.
+	Taints []PlacementRule `json:"taints"`
+}
+
+// placementCluster is what the pod's rules are checked against. namespaces
+// is nil when they were not listed, rules with a namespace selector are then
+// not checked.
+type placementCluster struct {
+	nodes      map[string]*v1.Node
+	pods       []*v1.Pod
+	namespaces []v1.Namespace
+}
+
+// GetPodPlacement returns a pod's node selector, node and pod affinities,
+// topology spread constraints and tolerations as readable rules, each checked
+// against the node the pod runs on.
+func (h *PodsHandler) GetPodPlacement(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	ctx := c.Request().Context()
+	nodes, err := h.clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	cluster := placementCluster{nodes: make(map[string]*v1.Node, len(nodes.Items))}
+	for i := range nodes.Items {
+		cluster.nodes[nodes.Items[i].Name] = &nodes.Items[i]
+	}
+	for _, obj := range h.BaseHandler.Informer.GetStore().List() {
+		if p, ok := obj.(*v1.Pod); ok {
+			cluster.pods = append(cluster.pods, p)
+		}
+	}
+	if usesNamespaceSelector(pod) {
+		// Users not allowed to list namespaces still get the other rules.
+		namespaces, err := h.clientSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil && !apierrors.IsForbidden(err) {
+			return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+		}
+		if err == nil {
+			cluster.namespaces = namespaces.Items
+		}
+	}
+	return c.JSON(http.StatusOK, podPlacement(pod, cluster))
+}
+
+func podPlacement(pod *v1.Pod, cluster placementCluster) PodPlacement {
+	node := cluster.nodes[pod.Spec.NodeName]
+	placement := PodPlacement{
+		Node:            pod.Spec.NodeName,
+		NodeSelector:    make([]PlacementRule, 0),
+		NodeAffinity:    make([]PlacementRule, 0),
+		PodAffinity:     make([]PlacementRule, 0),
+		PodAntiAffinity: make([]PlacementRule, 0),
+		TopologySpread:  make([]PlacementRule, 0),
+		Tolerations:     make([]string, 0),
+		Taints:          make([]PlacementRule, 0),
+	}
+	check := func(f func() bool) *bool {
+		if node == nil {
+			return nil
+		}
+		satisfied := f()
+		return &satisfied
+	}
+
+	keys := make([]string, 0, len(pod.Spec.NodeSelector))
+	for key := range pod.Spec.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := pod.Spec.NodeSelector[key]
+		placement.NodeSelector = append(placement.NodeSelector, PlacementRule{
+			Description: fmt.Sprintf("%s=%s", key, value),
+			Required:    true,
+			Satisfied:   check(func() bool { return node.Labels[key] == value }),
+		})
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil {
+		if na := affinity.NodeAffinity; na != nil {
+			if required := na.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+				terms := required.NodeSelectorTerms
+				for _, term := range terms {
+					rule := PlacementRule{
+						Description: nodeSelectorTermString(term),
+						Required:    true,
+						Satisfied:   check(func() bool { return nodeSelectorTermMatches(term, node) }),
+					}
+					if len(terms) > 1 {
+						rule.Detail = "one of the required terms has to match"
+					}
+					placement.NodeAffinity = append(placement.NodeAffinity, rule)
+				}
+			}
+			for _, preferred := range na.PreferredDuringSchedulingIgnoredDuringExecution {
+				placement.NodeAffinity = append(placement.NodeAffinity, PlacementRule{
+					Description: nodeSelectorTermString(preferred.Preference),
+					Weight:      preferred.Weight,
+					Satisfied:   check(func() bool { return nodeSelectorTermMatches(preferred.Preference, node) }),
+				})
+			}
+		}
+		if pa := affinity.PodAffinity; pa != nil {
+			placement.PodAffinity = podAffinityRules(pod, node, cluster, pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution, false)
+		}
+		if paa := affinity.PodAntiAffinity; paa != nil {
+			placement.PodAntiAffinity = podAffinityRules(pod, node, cluster, paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution, true)
+		}
+	}
+
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		placement.TopologySpread = append(placement.TopologySpread, topologySpreadRule(pod, node, cluster, constraint))
+	}
+
+	for _, toleration := range pod.Spec.Tolerations {
+		placement.Tolerations = append(placement.Tolerations, tolerationString(toleration))
+	}
+	if node != nil {
+		for _, taint := range node.Spec.Taints {
+			tolerated := slices.ContainsFunc(pod.Spec.Tolerations, func(t v1.Toleration) bool {
+				return t.ToleratesTaint(klog.Background(), &taint, false)
+			})
+			placement.Taints = append(placement.Taints, PlacementRule{
+				Description: taint.ToString(),
+				Required:    taint.Effect != v1.TaintEffectPreferNoSchedule,
+				Satisfied:   &tolerated,
+			})
+		}
+	}
+	return placement
+}
+
+// nodeRequirement converts a node selector requirement into a label
+// requirement, which supports the same operators and prints them.
+func nodeRequirement(r v1.NodeSelectorRequirement) (*labels.Requirement, error) {
+	ops := map[v1.NodeSelectorOperator]selection.Operator{
+		v1.NodeSelectorOpIn:           selection.In,
+		v1.NodeSelectorOpNotIn:        selection.NotIn,
+		v1.NodeSelectorOpExists:       selection.Exists,
+		v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		v1.NodeSelectorOpGt:           selection.GreaterThan,
+		v1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	op, ok := ops[r.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %s", r.Operator)
+	}
+	return labels.NewRequirement(r.Key, op, r.Values)
+}
+
+func nodeSelectorTermString(term v1.NodeSelectorTerm) string {
+	parts := make([]string, 0, len(term.MatchExpressions)+len(term.MatchFields))
+	for _, r := range slices.Concat(term.MatchExpressions, term.MatchFields) {
+		if requirement, err := nodeRequirement(r); err == nil {
+			parts = append(parts, requirement.String())
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %s %s", r.Key, r.Operator, strings.Join(r.Values, ",")))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nodeSelectorTermMatches applies the term's label expressions to the node's
+// labels and its field expressions, only metadata.name exists, to its name.
+func nodeSelectorTermMatches(term v1.NodeSelectorTerm, node *v1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	matches := func(requirements []v1.NodeSelectorRequirement, set labels.Set) bool {
+		for _, r := range requirements {
+			requirement, err := nodeRequirement(r)
+			if err != nil || !requirement.Matches(set) {
+				return false
+			}
+		}
+		return true
+	}
+	return matches(term.MatchExpressions, node.Labels) && matches(term.MatchFields, labels.Set{"metadata.name": node.Name})
+}
+
+func podAffinityRules(pod *v1.Pod, node *v1.Node, cluster placementCluster, required []v1.PodAffinityTerm, preferred []v1.WeightedPodAffinityTerm, anti bool) []PlacementRule {
+	rules := make([]PlacementRule, 0, len(required)+len(preferred))
+	rule := func(term v1.PodAffinityTerm) PlacementRule {
+		rule := PlacementRule{Description: podAffinityTermString(pod, term)}
+		if node == nil {
+			return rule
+		}
+		count, ok := podsInDomain(pod, node, cluster, term)
+		if !ok {
+			rule.Detail = "namespaces could not be listed to resolve the namespace selector"
+			return rule
+		}
+		satisfied := count > 0
+		if anti {
+			satisfied = count == 0
+		}
+		rule.Satisfied = &satisfied
+		rule.Detail = fmt.Sprintf("%d matching pods in the node's %s domain", count, term.TopologyKey)
+		return rule
+	}
+	for _, term := range required {
+		r := rule(term)
+		r.Required = true
+		rules = append(rules, r)
+	}
+	for _, weighted := range preferred {
+		r := rule(weighted.PodAffinityTerm)
+		r.Weight = weighted.Weight
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+func podAffinityTermString(pod *v1.Pod, term v1.PodAffinityTerm) string {
+	namespaces := pod.Namespace
+	switch {
+	case term.NamespaceSelector != nil && len(term.NamespaceSelector.MatchLabels) == 0 && len(term.NamespaceSelector.MatchExpressions) == 0:
+		namespaces = "all namespaces"
+	case term.NamespaceSelector != nil:
+		namespaces = "namespaces matching " + metav1.FormatLabelSelector(term.NamespaceSelector)
+		if len(term.Namespaces) > 0 {
+			namespaces = strings.Join(term.Namespaces, ", ") + " and " + namespaces
+		}
+	case len(term.Namespaces) > 0:
+		namespaces = strings.Join(term.Namespaces, ", ")
+	}
+	return fmt.Sprintf("pods matching %s in %s, per %s", metav1.FormatLabelSelector(term.LabelSelector), namespaces, term.TopologyKey)
+}
+
+// podsInDomain counts the other pods matching term that run in the same
+// topology domain as node. ok is false when the term's namespaces could not
+// be resolved.
+func podsInDomain(pod *v1.Pod, node *v1.Node, cluster placementCluster, term v1.PodAffinityTerm) (int, bool) {
+	inNamespace, ok := termNamespaces(pod, term, cluster.namespaces)
+	if !ok {
+		return 0, false
+	}
+	domain, ok := node.Labels[term.TopologyKey]
+	if !ok {
+		return 0, true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil || term.LabelSelector == nil {
+		return 0, true
+	}
+	count := 0
+	for _, p := range cluster.pods {
+		if p.UID == pod.UID || isTerminated(p) || !inNamespace(p.Namespace) || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		if n := cluster.nodes[p.Spec.NodeName]; n != nil && n.Labels[term.TopologyKey] == domain {
+			count++
+		}
+	}
+	return count, true
+}
+
+func termNamespaces(pod *v1.Pod, term v1.PodAffinityTerm, namespaces []v1.Namespace) (func(string) bool, bool) {
+	if term.NamespaceSelector == nil {
+		names := term.Namespaces
+		if len(names) == 0 {
+			names = []string{pod.Namespace}
+		}
+		return func(ns string) bool { return slices.Contains(names, ns) }, true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(term.NamespaceSelector)
+	if err != nil {
+		return nil, false
+	}
+	if selector.Empty() {
+		return func(string) bool { return true }, true
+	}
+	if namespaces == nil {
+		return nil, false
+	}
+	names := slices.Clone(term.Namespaces)
+	for _, ns := range namespaces {
+		if selector.Matches(labels.Set(ns.Labels)) {
+			names = append(names, ns.Name)
+		}
+	}
+	return func(ns string) bool { return slices.Contains(names, ns) }, true
+}
+
+func usesNamespaceSelector(pod *v1.Pod) bool {
+	affinity := pod.Spec.Affinity
+	if affinity == nil {
+		return false
+	}
+	var terms []v1.PodAffinityTerm
+	if affinity.PodAffinity != nil {
+		terms = append(terms, affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, w := range affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, w.PodAffinityTerm)
+		}
+	}
+	if affinity.PodAntiAffinity != nil {
+		terms = append(terms, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, w := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, w.PodAffinityTerm)
+		}
+	}
+	return slices.ContainsFunc(terms, func(t v1.PodAffinityTerm) bool { return t.NamespaceSelector != nil })
+}
+
+// topologySpreadRule counts the pods matching the constraint per domain, on
+// the nodes the pod could be scheduled to unless the constraint ignores node
+// affinity, and checks the skew between the fullest and emptiest domain.
+func topologySpreadRule(pod *v1.Pod, node *v1.Node, cluster placementCluster, constraint v1.TopologySpreadConstraint) PlacementRule {
+	selectorString := metav1.FormatLabelSelector(constraint.LabelSelector)
+	if len(constraint.MatchLabelKeys) > 0 {
+		selectorString += fmt.Sprintf(" with the pod's %s", strings.Join(constraint.MatchLabelKeys, ", "))
+	}
+	rule := PlacementRule{
+		Description: fmt.Sprintf("max skew %d across %s for pods matching %s", constraint.MaxSkew, constraint.TopologyKey, selectorString),
+		Required:    constraint.WhenUnsatisfiable == v1.DoNotSchedule,
+	}
+	if node == nil {
+		return rule
+	}
+	selector, err := metav1.LabelSelectorAsSelector(constraint.LabelSelector)
+	if err != nil || constraint.LabelSelector == nil {
+		return rule
+	}
+	for _, key := range constraint.MatchLabelKeys {
+		if value, ok := pod.Labels[key]; ok {
+			if r, err := labels.NewRequirement(key, selection.Equals, []string{value}); err == nil {
+				selector = selector.Add(*r)
+			}
+		}
+	}
+
+	honorAffinity := constraint.NodeAffinityPolicy == nil || *constraint.NodeAffinityPolicy == v1.NodeInclusionPolicyHonor
+	counts := make(map[string]int)
+	for _, n := range cluster.nodes {
+		domain, ok := n.Labels[constraint.TopologyKey]
+		if !ok || (honorAffinity && !nodeAllowedByAffinity(pod, n)) {
+			continue
+		}
+		if _, ok := counts[domain]; !ok {
+			counts[domain] = 0
+		}
+	}
+	for _, p := range cluster.pods {
+		if p.Namespace != pod.Namespace || isTerminated(p) || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		if n := cluster.nodes[p.Spec.NodeName]; n != nil {
+			if domain, ok := n.Labels[constraint.TopologyKey]; ok {
+				if _, counted := counts[domain]; counted {
+					counts[domain]++
+				}
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return rule
+	}
+
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	parts := make([]string, 0, len(domains))
+	lowest, highest := counts[domains[0]], counts[domains[0]]
+	for _, domain := range domains {
+		parts = append(parts, fmt.Sprintf("%s: %d", domain, counts[domain]))
+		lowest, highest = min(lowest, counts[domain]), max(highest, counts[domain])
+	}
+	// Below minDomains the scheduler counts the missing domains as empty.
+	if constraint.MinDomains != nil && int32(len(domains)) < *constraint.MinDomains {
+		lowest = 0
+	}
+	satisfied := int32(highest-lowest) <= constraint.MaxSkew
+	rule.Satisfied = &satisfied
+	rule.Detail = fmt.Sprintf("skew %d (%s)", highest-lowest, strings.Join(parts, ", "))
+	return rule
+}
+
+// nodeAllowedByAffinity reports whether the pod's node selector and required
+// node affinity allow node.
+func nodeAllowedByAffinity(pod *v1.Pod, node *v1.Node) bool {
+	for key, value := range pod.Spec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	return slices.ContainsFunc(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, func(term v1.NodeSelectorTerm) bool {
+		return nodeSelectorTermMatches(term, node)
+	})
+}
+
+func tolerationString(t v1.Toleration) string {
+	var b strings.Builder
+	switch {
+	case t.Key == "" && t.Operator == v1.TolerationOpExists:
+		b.WriteString("every taint")
+	case t.Operator == v1.TolerationOpExists:
+		b.WriteString(t.Key)
+	default:
+		fmt.Fprintf(&b, "%s=%s", t.Key, t.Value)
+	}
+	if t.Effect != "" {
+		fmt.Fprintf(&b, ":%s", t.Effect)
+	}
+	if t.TolerationSeconds != nil {
+		fmt.Fprintf(&b, " for %ds", *t.TolerationSeconds)
+	}
+	return b.String()
+}
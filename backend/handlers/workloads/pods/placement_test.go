@@ -0,0 +1,143 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPodPlacement(t *testing.T) {
+	node := func(name, zone string, taints ...v1.Taint) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+				"kubernetes.io/hostname":      name,
+				"topology.kubernetes.io/zone": zone,
+				"disktype":                    "ssd",
+			}},
+			Spec: v1.NodeSpec{Taints: taints},
+		}
+	}
+	pod := func(name, nodeName, app string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", UID: types.UID(name), Labels: map[string]string{"app": app}},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+		}
+	}
+	ignore := v1.NodeInclusionPolicyIgnore
+	web := pod("web-1", "n1", "web")
+	web.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	web.Spec.Affinity = &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: []v1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}},
+			}}},
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{
+				Weight:     10,
+				Preference: v1.NodeSelectorTerm{MatchFields: []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"n2"}}}},
+			}},
+		},
+		PodAffinity: &v1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cache"}},
+				TopologyKey:   "topology.kubernetes.io/zone",
+			}},
+		},
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{{
+				Weight: 50,
+				PodAffinityTerm: v1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					TopologyKey:   "kubernetes.io/hostname",
+				},
+			}},
+		},
+	}
+	web.Spec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.DoNotSchedule, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, NodeAffinityPolicy: &ignore},
+	}
+	web.Spec.Tolerations = []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+
+	cluster := placementCluster{
+		nodes: map[string]*v1.Node{
+			"n1": node("n1", "a", v1.Taint{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}, v1.Taint{Key: "spot", Effect: v1.TaintEffectPreferNoSchedule}),
+			"n2": node("n2", "b"),
+		},
+		pods: []*v1.Pod{web, pod("web-2", "n1", "web"), pod("cache-1", "n1", "cache")},
+	}
+	placement := podPlacement(web, cluster)
+	satisfied := func(r PlacementRule) bool {
+		require.NotNil(t, r.Satisfied, r.Description)
+		return *r.Satisfied
+	}
+
+	assert.Equal(t, "n1", placement.Node)
+	require.Len(t, placement.NodeSelector, 1)
+	assert.Equal(t, "disktype=ssd", placement.NodeSelector[0].Description)
+	assert.True(t, satisfied(placement.NodeSelector[0]))
+
+	require.Len(t, placement.NodeAffinity, 2)
+	assert.Equal(t, "topology.kubernetes.io/zone in (a)", placement.NodeAffinity[0].Description)
+	assert.True(t, placement.NodeAffinity[0].Required)
+	assert.True(t, satisfied(placement.NodeAffinity[0]))
+	assert.Equal(t, int32(10), placement.NodeAffinity[1].Weight)
+	assert.False(t, satisfied(placement.NodeAffinity[1]))
+
+	require.Len(t, placement.PodAffinity, 1)
+	assert.Equal(t, "pods matching app=cache in shop, per topology.kubernetes.io/zone", placement.PodAffinity[0].Description)
+	assert.True(t, satisfied(placement.PodAffinity[0]))
+	require.Len(t, placement.PodAntiAffinity, 1)
+	assert.False(t, satisfied(placement.PodAntiAffinity[0]))
+	assert.Equal(t, "1 matching pods in the node's kubernetes.io/hostname domain", placement.PodAntiAffinity[0].Detail)
+
+	// Honoring the required zone leaves only zone a to spread across.
+	require.Len(t, placement.TopologySpread, 2)
+	assert.True(t, placement.TopologySpread[0].Required)
+	assert.True(t, satisfied(placement.TopologySpread[0]))
+	assert.Equal(t, "skew 0 (a: 2)", placement.TopologySpread[0].Detail)
+	assert.False(t, satisfied(placement.TopologySpread[1]))
+	assert.Equal(t, "skew 2 (a: 2, b: 0)", placement.TopologySpread[1].Detail)
+
+	assert.Equal(t, []string{"dedicated=gpu:NoSchedule"}, placement.Tolerations)
+	require.Len(t, placement.Taints, 2)
+	assert.True(t, placement.Taints[0].Required)
+	assert.True(t, satisfied(placement.Taints[0]))
+	assert.False(t, placement.Taints[1].Required)
+	assert.False(t, satisfied(placement.Taints[1]))
+
+	pending := pod("web-3", "", "web")
+	pending.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	placement = podPlacement(pending, cluster)
+	assert.Empty(t, placement.Node)
+	assert.Nil(t, placement.NodeSelector[0].Satisfied)
+	assert.Empty(t, placement.Taints)
+}
+
+func TestTermNamespaces(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop"}}
+	namespaces := []v1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "billing", Labels: map[string]string{"team": "b"}}},
+	}
+
+	in, ok := termNamespaces(pod, v1.PodAffinityTerm{}, nil)
+	require.True(t, ok)
+	assert.True(t, in("shop"))
+	assert.False(t, in("billing"))
+
+	in, ok = termNamespaces(pod, v1.PodAffinityTerm{NamespaceSelector: &metav1.LabelSelector{}}, nil)
+	require.True(t, ok)
+	assert.True(t, in("billing"))
+
+	term := v1.PodAffinityTerm{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}}
+	_, ok = termNamespaces(pod, term, nil)
+	assert.False(t, ok)
+	in, ok = termNamespaces(pod, term, namespaces)
+	require.True(t, ok)
+	assert.True(t, in("billing"))
+	assert.False(t, in("shop"))
+}
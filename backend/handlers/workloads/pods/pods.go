@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/workloads/jobs"
 	"github.com/kubewall/kubewall/backend/handlers/workloads/replicaset"
 	"github.com/r3labs/sse/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,7 +26,25 @@ import (
 )
 
 const (
-	GetLogHistory base.RouteType = 14
+	GetLogHistory          base.RouteType = 14
+	GetPodContainerHistory base.RouteType = 15
+	GetPodCrashLogs        base.RouteType = 16
+	GetOwnerPods           base.RouteType = 17
+	GetTopPods             base.RouteType = 18
+	GetPodEnv              base.RouteType = 19
+	GetPodScheduling       base.RouteType = 20
+	StreamJobRun           base.RouteType = 21
+	RestartPodsBySelector  base.RouteType = 22
+	GetPodProbes           base.RouteType = 23
+	GetPodDiskUsage        base.RouteType = 24
+	GetPodImagePullSecrets base.RouteType = 25
+	GetStuckPods           base.RouteType = 26
+	ForceDeletePod         base.RouteType = 27
+	GetPodTimeline         base.RouteType = 28
+	GetLogRange            base.RouteType = 29
+	GetPodPlacement        base.RouteType = 30
+	GetNamespaceRestarts   base.RouteType = 31
+	GetReplicaSetPods      base.RouteType = 32
 )
 
 type PodsHandler struct {
@@ -33,6 +52,9 @@ type PodsHandler struct {
 	clientSet         *kubernetes.Clientset
 	restConfig        *rest.Config
 	replicasetHandler *replicaset.ReplicaSetHandler
+	jobsHandler       *jobs.JobsHandler
+	// ownerStreams holds the filter of every subscribed owner pods stream
+	ownerStreams *helpers.StreamViews[ownerFilter]
 }
 
 func NewPodsRouteHandler(container container.Container, routeType base.RouteType) echo.HandlerFunc {
@@ -54,6 +76,42 @@ func NewPodsRouteHandler(container container.Container, routeType base.RouteType
 			return handler.GetLogs(c)
 		case GetLogHistory:
 			return handler.GetLogHistory(c)
+		case GetPodContainerHistory:
+			return handler.GetPodContainerHistory(c)
+		case GetPodCrashLogs:
+			return handler.GetPodCrashLogs(c)
+		case GetOwnerPods:
+			return handler.GetOwnerPods(c)
+		case GetTopPods:
+			return handler.GetTopPods(c)
+		case GetPodEnv:
+			return handler.GetPodEnv(c)
+		case GetPodScheduling:
+			return handler.GetPodScheduling(c)
+		case StreamJobRun:
+			return handler.StreamJobRun(c)
+		case RestartPodsBySelector:
+			return handler.RestartPodsBySelector(c)
+		case GetPodProbes:
+			return handler.GetPodProbes(c)
+		case GetPodDiskUsage:
+			return handler.GetPodDiskUsage(c)
+		case GetPodImagePullSecrets:
+			return handler.GetPodImagePullSecrets(c)
+		case GetStuckPods:
+			return handler.GetStuckPods(c)
+		case ForceDeletePod:
+			return handler.ForceDeletePod(c)
+		case GetPodTimeline:
+			return handler.GetPodTimeline(c)
+		case GetLogRange:
+			return handler.GetLogRange(c)
+		case GetPodPlacement:
+			return handler.GetPodPlacement(c)
+		case GetNamespaceRestarts:
+			return handler.GetNamespaceRestartReport(c)
+		case GetReplicaSetPods:
+			return handler.GetReplicaSetPods(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
@@ -86,6 +144,8 @@ func newPodsHandler(ctx context.Context, config, cluster string, container conta
 		restConfig:        container.RestConfig(config, cluster),
 		clientSet:         clientSet,
 		replicasetHandler: replicaset.NewReplicaSetHandler(ctx, config, cluster, container),
+		jobsHandler:       jobs.NewJobsHandler(ctx, config, cluster, container),
+		ownerStreams:      helpers.NewStreamViews[ownerFilter](0, container.SSE().RemoveStream),
 	}
 
 	additionalEvents := []map[string]func(){
@@ -93,6 +153,7 @@ func newPodsHandler(ctx context.Context, config, cluster string, container conta
 			"pods-deployments": func() {
 				go handler.DeploymentsPods()
 				go handler.NodePods()
+				go handler.OwnerPods()
 			},
 		},
 	}
@@ -111,17 +172,18 @@ func transformItems(items []any, b *base.BaseHandler) ([]byte, error) {
 			list = append(list, *item)
 		}
 	}
-	podMetricsList := GetPodsMetricsList(b)
-	t := TransformPodList(list, podMetricsList)
+	podMetricsList, stale := GetPodsMetricsList(b)
+	t := TransformPodList(list, podMetricsList, stale)
 
 	return json.Marshal(t)
 }
 
-func GetPodsMetricsList(b *base.BaseHandler) *v1beta1.PodMetricsList {
-	cacheKey := fmt.Sprintf(helpers.IsMetricServerAvailableCacheKeyFormat, b.QueryConfig, b.QueryCluster)
-	value, exists := b.Container.Cache().GetIfPresent(cacheKey)
-	if value == nil || value == false || !exists {
-		return nil
+// GetPodsMetricsList lists the metrics of all pods. Pods the metrics API did
+// not return, or all of them when the call failed, get their last-known metrics
+// and are reported in the stale set, keyed by namespace/name.
+func GetPodsMetricsList(b *base.BaseHandler) (*v1beta1.PodMetricsList, map[string]bool) {
+	if !helpers.IsMetricServerAvailable(b.Container, b.QueryConfig, b.QueryCluster) {
+		return nil, nil
 	}
 	podMetrics, err := b.Container.
 		MetricClient(b.QueryConfig, b.QueryCluster).
@@ -131,10 +193,31 @@ func GetPodsMetricsList(b *base.BaseHandler) *v1beta1.PodMetricsList {
 	if err != nil {
 		log.Info("failed to get pod metrics", "err", err)
 	}
-	return podMetrics
+	return mergePodMetrics(helpers.LastKnownPodMetrics(b.QueryConfig, b.QueryCluster), podMetrics, err)
+}
+
+func mergePodMetrics(cache *helpers.MetricsCache[v1beta1.PodMetrics], podMetrics *v1beta1.PodMetricsList, err error) (*v1beta1.PodMetricsList, map[string]bool) {
+	fresh := make(map[string]v1beta1.PodMetrics)
+	if err == nil && podMetrics != nil {
+		for _, m := range podMetrics.Items {
+			fresh[fmt.Sprintf("%s/%s", m.Namespace, m.Name)] = m
+		}
+	}
+	items, stale := cache.Merge(fresh, err)
+
+	merged := &v1beta1.PodMetricsList{Items: make([]v1beta1.PodMetrics, 0, len(items))}
+	for _, m := range items {
+		merged.Items = append(merged.Items, m)
+	}
+	return merged, stale
 }
 
 func (h *PodsHandler) GetLogs(c echo.Context) error {
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	sseServer := sse.New()
 	sseServer.AutoStream = true
 	sseServer.EventTTL = 0
@@ -152,7 +235,10 @@ func (h *PodsHandler) GetLogs(c echo.Context) error {
 	} else {
 		key = fmt.Sprintf("%s-%s-%s-%s-logs", config, cluster, name, namespace)
 	}
-	go h.publishLogsToSSE(ctx, name, namespace, containerName, allContainers, key, sseServer)
+	// Created up front so the meta event is replayed to the subscriber even
+	// when it is published before ServeHTTP subscribes.
+	sseServer.CreateStream(key)
+	go h.publishLogsToSSE(ctx, name, namespace, containerName, allContainers, filter, key, sseServer)
 
 	sseServer.ServeHTTP(key, c.Response(), c.Request())
 
@@ -0,0 +1,156 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type ProbeSummary struct {
+	// Type is one of "httpGet", "tcpSocket", "grpc" or "exec".
+	Type string `json:"type"`
+	// Port is the probed port, with the number a named port resolves to,
+	// e.g. "http (8080)".
+	Port    string   `json:"port,omitempty"`
+	Path    string   `json:"path,omitempty"`
+	Scheme  string   `json:"scheme,omitempty"`
+	Host    string   `json:"host,omitempty"`
+	Command []string `json:"command,omitempty"`
+	Service string   `json:"service,omitempty"`
+
+	InitialDelaySeconds           int32  `json:"initialDelaySeconds"`
+	PeriodSeconds                 int32  `json:"periodSeconds"`
+	TimeoutSeconds                int32  `json:"timeoutSeconds"`
+	SuccessThreshold              int32  `json:"successThreshold"`
+	FailureThreshold              int32  `json:"failureThreshold"`
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+}
+
+type ContainerProbes struct {
+	Name string `json:"name"`
+	// Sidecar is set for init containers that keep running next to the
+	// app containers, the only init containers that may have probes.
+	Sidecar      bool          `json:"sidecar,omitempty"`
+	Ready        bool          `json:"ready"`
+	Started      *bool         `json:"started,omitempty"`
+	RestartCount int32         `json:"restartCount"`
+	Liveness     *ProbeSummary `json:"liveness,omitempty"`
+	Readiness    *ProbeSummary `json:"readiness,omitempty"`
+	Startup      *ProbeSummary `json:"startup,omitempty"`
+}
+
+type PodProbes struct {
+	Containers []ContainerProbes `json:"containers"`
+	// Conditions are the Ready and ContainersReady conditions of the pod,
+	// which readiness probes drive.
+	Conditions []v1.PodCondition `json:"conditions"`
+}
+
+// GetPodProbes summarizes the liveness, readiness and startup probes of every
+// container of a pod together with the readiness it currently reports.
+func (h *PodsHandler) GetPodProbes(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	return c.JSON(http.StatusOK, podProbes(pod))
+}
+
+func podProbes(pod *v1.Pod) PodProbes {
+	statuses := make(map[string]v1.ContainerStatus)
+	for _, s := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		statuses[s.Name] = s
+	}
+
+	probes := PodProbes{Containers: make([]ContainerProbes, 0), Conditions: make([]v1.PodCondition, 0)}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			cp := containerProbes(container, statuses[container.Name])
+			cp.Sidecar = true
+			probes.Containers = append(probes.Containers, cp)
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		probes.Containers = append(probes.Containers, containerProbes(container, statuses[container.Name]))
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady || condition.Type == v1.ContainersReady {
+			probes.Conditions = append(probes.Conditions, condition)
+		}
+	}
+	return probes
+}
+
+func containerProbes(container v1.Container, status v1.ContainerStatus) ContainerProbes {
+	return ContainerProbes{
+		Name:         container.Name,
+		Ready:        status.Ready,
+		Started:      status.Started,
+		RestartCount: status.RestartCount,
+		Liveness:     probeSummary(container, container.LivenessProbe),
+		Readiness:    probeSummary(container, container.ReadinessProbe),
+		Startup:      probeSummary(container, container.StartupProbe),
+	}
+}
+
+func probeSummary(container v1.Container, probe *v1.Probe) *ProbeSummary {
+	if probe == nil {
+		return nil
+	}
+	summary := &ProbeSummary{
+		InitialDelaySeconds:           probe.InitialDelaySeconds,
+		PeriodSeconds:                 probe.PeriodSeconds,
+		TimeoutSeconds:                probe.TimeoutSeconds,
+		SuccessThreshold:              probe.SuccessThreshold,
+		FailureThreshold:              probe.FailureThreshold,
+		TerminationGracePeriodSeconds: probe.TerminationGracePeriodSeconds,
+	}
+
+	handler := probe.ProbeHandler
+	switch {
+	case handler.HTTPGet != nil:
+		summary.Type = "httpGet"
+		summary.Port = probePort(container, handler.HTTPGet.Port)
+		summary.Path = handler.HTTPGet.Path
+		summary.Scheme = string(handler.HTTPGet.Scheme)
+		summary.Host = handler.HTTPGet.Host
+	case handler.TCPSocket != nil:
+		summary.Type = "tcpSocket"
+		summary.Port = probePort(container, handler.TCPSocket.Port)
+		summary.Host = handler.TCPSocket.Host
+	case handler.GRPC != nil:
+		summary.Type = "grpc"
+		summary.Port = fmt.Sprint(handler.GRPC.Port)
+		if handler.GRPC.Service != nil {
+			summary.Service = *handler.GRPC.Service
+		}
+	case handler.Exec != nil:
+		summary.Type = "exec"
+		summary.Command = handler.Exec.Command
+	}
+	return summary
+}
+
+// probePort resolves a named port against the ports of the container, a name
+// the container does not declare makes the probe fail.
+func probePort(container v1.Container, port intstr.IntOrString) string {
+	if port.Type == intstr.Int {
+		return port.String()
+	}
+	for _, p := range container.Ports {
+		if p.Name == port.StrVal {
+			return fmt.Sprintf("%s (%d)", port.StrVal, p.ContainerPort)
+		}
+	}
+	return fmt.Sprintf("%s (not declared)", port.StrVal)
+}
@@ -0,0 +1,76 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestPodProbes(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "migrate"},
+				{
+					Name:          "proxy",
+					RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways),
+					StartupProbe: &v1.Probe{
+						ProbeHandler:  v1.ProbeHandler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt32(15021)}},
+						PeriodSeconds: 1, FailureThreshold: 30,
+					},
+				},
+			},
+			Containers: []v1.Container{{
+				Name:  "app",
+				Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				LivenessProbe: &v1.Probe{
+					ProbeHandler:        v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http"), Scheme: v1.URISchemeHTTP}},
+					InitialDelaySeconds: 5, PeriodSeconds: 10, TimeoutSeconds: 1, SuccessThreshold: 1, FailureThreshold: 3,
+				},
+				ReadinessProbe: &v1.Probe{
+					ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("metrics")}},
+				},
+			}, {
+				Name: "worker",
+				LivenessProbe: &v1.Probe{
+					ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{Command: []string{"cat", "/tmp/healthy"}}},
+				},
+			}},
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue},
+				{Type: v1.PodReady, Status: v1.ConditionFalse, Reason: "ContainersNotReady"},
+			},
+			InitContainerStatuses: []v1.ContainerStatus{{Name: "proxy", Ready: true, Started: ptr.To(true)}},
+			ContainerStatuses:     []v1.ContainerStatus{{Name: "app", RestartCount: 4}, {Name: "worker", Ready: true}},
+		},
+	}
+
+	got := podProbes(pod)
+	assert.Len(t, got.Containers, 3)
+
+	proxy := got.Containers[0]
+	assert.Equal(t, "proxy", proxy.Name)
+	assert.True(t, proxy.Sidecar)
+	assert.True(t, *proxy.Started)
+	assert.Equal(t, &ProbeSummary{Type: "tcpSocket", Port: "15021", PeriodSeconds: 1, FailureThreshold: 30}, proxy.Startup)
+
+	app := got.Containers[1]
+	assert.Equal(t, int32(4), app.RestartCount)
+	assert.False(t, app.Ready)
+	assert.Equal(t, &ProbeSummary{
+		Type: "httpGet", Port: "http (8080)", Path: "/healthz", Scheme: "HTTP",
+		InitialDelaySeconds: 5, PeriodSeconds: 10, TimeoutSeconds: 1, SuccessThreshold: 1, FailureThreshold: 3,
+	}, app.Liveness)
+	assert.Equal(t, "metrics (not declared)", app.Readiness.Port)
+	assert.Nil(t, app.Startup)
+
+	assert.Equal(t, []string{"cat", "/tmp/healthy"}, got.Containers[2].Liveness.Command)
+
+	assert.Len(t, got.Conditions, 1)
+	assert.Equal(t, v1.PodReady, got.Conditions[0].Type)
+}
@@ -0,0 +1,118 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	appV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// RevisionPods are the pods of one ReplicaSet. Revision is the Deployment
+// revision the ReplicaSet was created for, zero when it has none.
+type RevisionPods struct {
+	ReplicaSet string    `json:"replicaSet"`
+	Revision   int64     `json:"revision"`
+	Pods       []PodList `json:"pods"`
+}
+
+type ReplicaSetPods struct {
+	// Deployment is set with ?includeDeployment=true when a Deployment
+	// controls the ReplicaSet.
+	Deployment  string         `json:"deployment,omitempty"`
+	ReplicaSets []RevisionPods `json:"replicaSets"`
+}
+
+// GetReplicaSetPods returns the pods of a ReplicaSet. With
+// ?includeDeployment=true it returns the pods of every ReplicaSet of its
+// Deployment instead, newest revision first, so old and new pods can be
+// compared during a rollout.
+func (h *PodsHandler) GetReplicaSetPods(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.replicasetHandler.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !exists {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("replicaset %s not found", key))
+	}
+	rs, ok := obj.(*appV1.ReplicaSet)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("unexpected object for replicaset %s", key))
+	}
+	includeDeployment, _ := strconv.ParseBool(c.QueryParam("includeDeployment"))
+
+	var response ReplicaSetPods
+	replicaSets := []*appV1.ReplicaSet{rs}
+	if owner := metav1.GetControllerOf(rs); includeDeployment && owner != nil && owner.Kind == "Deployment" {
+		response.Deployment = owner.Name
+		items, err := h.replicasetHandler.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		replicaSets = nil
+		for _, item := range items {
+			if sibling, ok := item.(*appV1.ReplicaSet); ok {
+				if controller := metav1.GetControllerOf(sibling); controller != nil && controller.UID == owner.UID {
+					replicaSets = append(replicaSets, sibling)
+				}
+			}
+		}
+	}
+
+	items, err := h.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pods := make([]*v1.Pod, 0, len(items))
+	for _, item := range items {
+		if pod, ok := item.(*v1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	podsMetricsList, staleMetrics := GetPodsMetricsList(&h.BaseHandler)
+	response.ReplicaSets = replicaSetRevisions(replicaSets, pods, podsMetricsList, staleMetrics)
+	return c.JSON(http.StatusOK, response)
+}
+
+// replicaSetRevisions groups pods by the ReplicaSet controlling them, newest
+// revision first.
+func replicaSetRevisions(replicaSets []*appV1.ReplicaSet, pods []*v1.Pod, podsMetricsList *v1beta1.PodMetricsList, staleMetrics map[string]bool) []RevisionPods {
+	sorted := make([]*appV1.ReplicaSet, len(replicaSets))
+	copy(sorted, replicaSets)
+	revision := func(rs *appV1.ReplicaSet) int64 {
+		n, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		return n
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if a, b := revision(sorted[i]), revision(sorted[j]); a != b {
+			return a > b
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	revisions := make([]RevisionPods, 0, len(sorted))
+	for _, rs := range sorted {
+		var owned []v1.Pod
+		for _, pod := range pods {
+			if controller := metav1.GetControllerOf(pod); controller != nil && controller.UID == rs.UID {
+				owned = append(owned, *pod)
+			}
+		}
+		revisions = append(revisions, RevisionPods{
+			ReplicaSet: rs.Name,
+			Revision:   revision(rs),
+			Pods:       TransformPodList(owned, podsMetricsList, staleMetrics),
+		})
+	}
+	return revisions
+}
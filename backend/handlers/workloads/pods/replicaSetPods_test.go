@@ -0,0 +1,49 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReplicaSetRevisions(t *testing.T) {
+	controller := true
+	replicaSet := func(name, revision string) *appV1.ReplicaSet {
+		return &appV1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "shop",
+			UID:         types.UID(name),
+			Annotations: map[string]string{revisionAnnotation: revision},
+		}}
+	}
+	pod := func(name, owner string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "shop",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: owner, UID: types.UID(owner), Controller: &controller}},
+		}}
+	}
+	pods := []*v1.Pod{pod("web-old-1", "web-old"), pod("web-new-1", "web-new"), pod("web-new-2", "web-new"), pod("api-1", "api")}
+
+	revisions := replicaSetRevisions([]*appV1.ReplicaSet{replicaSet("web-old", "2"), replicaSet("web-new", "10"), replicaSet("web-older", "1")}, pods, nil, nil)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, "web-new", revisions[0].ReplicaSet)
+	assert.Equal(t, int64(10), revisions[0].Revision)
+	require.Len(t, revisions[0].Pods, 2)
+	assert.Equal(t, "web-new-1", revisions[0].Pods[0].Name)
+	assert.Equal(t, "web-old", revisions[1].ReplicaSet)
+	require.Len(t, revisions[1].Pods, 1)
+	assert.Equal(t, "web-old-1", revisions[1].Pods[0].Name)
+	assert.Equal(t, int64(1), revisions[2].Revision)
+	assert.Empty(t, revisions[2].Pods)
+
+	revisions = replicaSetRevisions([]*appV1.ReplicaSet{replicaSet("api", "")}, pods, nil, nil)
+	require.Len(t, revisions, 1)
+	assert.Zero(t, revisions[0].Revision)
+	assert.Len(t, revisions[0].Pods, 1)
+}
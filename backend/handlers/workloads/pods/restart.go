@@ -0,0 +1,92 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+type RestartRequest struct {
+	Namespace          string `json:"namespace"`
+	Selector           string `json:"selector"`
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	// DryRun only lists the pods that would be restarted.
+	DryRun bool `json:"dryRun"`
+}
+
+type RestartFailure struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+type RestartResponse struct {
+	DryRun    bool             `json:"dryRun"`
+	Restarted []string         `json:"restarted"`
+	Failures  []RestartFailure `json:"failures"`
+}
+
+// RestartPodsBySelector deletes the pods of a namespace matching a label
+// selector so their controllers recreate them.
+func (h *PodsHandler) RestartPodsBySelector(c echo.Context) error {
+	r := new(RestartRequest)
+	if err := c.Bind(r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	response, err := restartPods(c.Request().Context(), h.clientSet, *r)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func restartPods(ctx context.Context, clientSet kubernetes.Interface, r RestartRequest) (*RestartResponse, error) {
+	if r.Namespace == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "namespace is required")
+	}
+	// An empty selector matches every pod of the namespace.
+	if strings.TrimSpace(r.Selector) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "selector is required")
+	}
+	selector, err := labels.Parse(r.Selector)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid selector %q: %s", r.Selector, err))
+	}
+	if selector.Empty() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "selector is required")
+	}
+	if r.GracePeriodSeconds != nil && *r.GracePeriodSeconds < 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "gracePeriodSeconds must not be negative")
+	}
+
+	pods := clientSet.CoreV1().Pods(r.Namespace)
+	list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	response := &RestartResponse{DryRun: r.DryRun, Restarted: make([]string, 0), Failures: make([]RestartFailure, 0)}
+	for _, pod := range list.Items {
+		// Pods already being deleted are restarting anyway.
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if !r.DryRun {
+			err := pods.Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: r.GracePeriodSeconds})
+			if err != nil {
+				response.Failures = append(response.Failures, RestartFailure{Name: pod.Name, Message: err.Error()})
+				continue
+			}
+		}
+		response.Restarted = append(response.Restarted, pod.Name)
+	}
+	sort.Strings(response.Restarted)
+	return response, nil
+}
@@ -0,0 +1,182 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	defaultRestartReportLimit = 10
+	maxRestartReportLimit     = 100
+)
+
+type RestartReason struct {
+	Reason   string `json:"reason"`
+	Restarts int    `json:"restarts"`
+}
+
+// WorkloadRestarts sums the restarts of the pods of one workload. Reasons
+// are the last termination reasons of its containers, weighted by their
+// restart counts, most frequent first.
+type WorkloadRestarts struct {
+	Kind          string          `json:"kind"`
+	Name          string          `json:"name"`
+	Restarts      int             `json:"restarts"`
+	Pods          []string        `json:"pods"`
+	Reasons       []RestartReason `json:"reasons"`
+	LastRestartAt *time.Time      `json:"lastRestartAt,omitempty"`
+}
+
+type NamespaceRestartReport struct {
+	Namespace string `json:"namespace"`
+	// Since is set when only containers restarted after it are counted.
+	Since     *time.Time         `json:"since,omitempty"`
+	Restarts  int                `json:"restarts"`
+	Workloads []WorkloadRestarts `json:"workloads"`
+}
+
+// GetNamespaceRestartReport returns the workloads of a namespace whose pods
+// restarted most, with why their containers last terminated, limited to the
+// top ?limit= (default 10). With ?since= (a duration, e.g. 1h) only
+// containers whose last restart is that recent are counted. The API only
+// keeps a container's restart count and its last termination, so their whole
+// count is included.
+func (h *PodsHandler) GetNamespaceRestartReport(c echo.Context) error {
+	namespace := c.Param("name")
+	var since time.Time
+	if value := c.QueryParam("since"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid since %q", value))
+		}
+		since = time.Now().Add(-d)
+	}
+	limit := defaultRestartReportLimit
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit %q", value))
+		}
+		limit = min(n, maxRestartReportLimit)
+	}
+
+	objs, err := h.BaseHandler.Informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pods := make([]*v1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*v1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return c.JSON(http.StatusOK, restartReport(namespace, pods, h.workloadOf, since, limit))
+}
+
+// workloadOf returns the workload a pod belongs to: the Deployment or CronJob
+// above its ReplicaSet or Job, else its controller, else the pod itself.
+func (h *PodsHandler) workloadOf(pod *v1.Pod) (string, string) {
+	chain := h.ownerChain(pod)
+	for _, kind := range []string{"Deployment", "CronJob"} {
+		if i := slices.IndexFunc(chain, func(o metav1.OwnerReference) bool { return o.Kind == kind }); i >= 0 {
+			return chain[i].Kind, chain[i].Name
+		}
+	}
+	for _, owner := range chain {
+		if owner.Controller != nil && *owner.Controller {
+			return owner.Kind, owner.Name
+		}
+	}
+	return "Pod", pod.Name
+}
+
+func restartReport(namespace string, pods []*v1.Pod, workloadOf func(*v1.Pod) (string, string), since time.Time, limit int) NamespaceRestartReport {
+	report := NamespaceRestartReport{Namespace: namespace, Workloads: make([]WorkloadRestarts, 0)}
+	if !since.IsZero() {
+		report.Since = &since
+	}
+
+	type workloadKey struct{ kind, name string }
+	workloads := make(map[workloadKey]*WorkloadRestarts)
+	reasons := make(map[workloadKey]map[string]int)
+	for _, pod := range pods {
+		kind, name := workloadOf(pod)
+		key := workloadKey{kind, name}
+		for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			if status.RestartCount == 0 {
+				continue
+			}
+			terminated := status.LastTerminationState.Terminated
+			var finishedAt time.Time
+			if terminated != nil {
+				finishedAt = terminated.FinishedAt.Time
+			}
+			if !since.IsZero() && finishedAt.Before(since) {
+				continue
+			}
+
+			w := workloads[key]
+			if w == nil {
+				w = &WorkloadRestarts{Kind: kind, Name: name, Pods: make([]string, 0)}
+				workloads[key] = w
+				reasons[key] = make(map[string]int)
+			}
+			w.Restarts += int(status.RestartCount)
+			report.Restarts += int(status.RestartCount)
+			if !slices.Contains(w.Pods, pod.Name) {
+				w.Pods = append(w.Pods, pod.Name)
+			}
+			reasons[key][terminationReason(terminated)] += int(status.RestartCount)
+			if !finishedAt.IsZero() && (w.LastRestartAt == nil || finishedAt.After(*w.LastRestartAt)) {
+				w.LastRestartAt = &finishedAt
+			}
+		}
+	}
+
+	for key, w := range workloads {
+		for reason, restarts := range reasons[key] {
+			w.Reasons = append(w.Reasons, RestartReason{Reason: reason, Restarts: restarts})
+		}
+		sort.Slice(w.Reasons, func(i, j int) bool {
+			if w.Reasons[i].Restarts != w.Reasons[j].Restarts {
+				return w.Reasons[i].Restarts > w.Reasons[j].Restarts
+			}
+			return w.Reasons[i].Reason < w.Reasons[j].Reason
+		})
+		sort.Strings(w.Pods)
+		report.Workloads = append(report.Workloads, *w)
+	}
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i], report.Workloads[j]
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Kind+"/"+a.Name < b.Kind+"/"+b.Name
+	})
+	if len(report.Workloads) > limit {
+		report.Workloads = report.Workloads[:limit]
+	}
+	return report
+}
+
+// terminationReason is the reason a container last terminated, e.g.
+// OOMKilled or Error, or its exit code when the runtime gave none.
+func terminationReason(terminated *v1.ContainerStateTerminated) string {
+	switch {
+	case terminated == nil:
+		return "Unknown"
+	case terminated.Reason != "":
+		return terminated.Reason
+	default:
+		return fmt.Sprintf("ExitCode:%d", terminated.ExitCode)
+	}
+}
@@ -0,0 +1,82 @@
+package pods
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestartReport(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	restarted := func(restarts int32, reason string, exitCode int32, ago time.Duration) v1.ContainerStatus {
+		return v1.ContainerStatus{
+			RestartCount: restarts,
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				Reason:     reason,
+				ExitCode:   exitCode,
+				FinishedAt: metav1.NewTime(now.Add(-ago)),
+			}},
+		}
+	}
+	pod := func(name string, statuses ...v1.ContainerStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Status:     v1.PodStatus{ContainerStatuses: statuses},
+		}
+	}
+	api1 := pod("api-1", restarted(3, "OOMKilled", 137, time.Minute), restarted(1, "Error", 1, 2*time.Hour))
+	api2 := pod("api-2", restarted(2, "", 2, 10*time.Minute))
+	api2.Status.InitContainerStatuses = []v1.ContainerStatus{restarted(1, "Error", 1, 3*time.Hour)}
+	pods := []*v1.Pod{
+		api1,
+		api2,
+		pod("worker-1", restarted(4, "Error", 1, 5*time.Hour)),
+		pod("cron-1", restarted(1, "Completed", 0, 30*time.Minute)),
+		pod("idle-1", v1.ContainerStatus{Name: "idle"}),
+	}
+	workloadOf := func(pod *v1.Pod) (string, string) {
+		switch pod.Name {
+		case "api-1", "api-2":
+			return "Deployment", "api"
+		case "worker-1":
+			return "StatefulSet", "worker"
+		}
+		return "Pod", pod.Name
+	}
+
+	report := restartReport("shop", pods, workloadOf, time.Time{}, 10)
+	assert.Equal(t, "shop", report.Namespace)
+	assert.Nil(t, report.Since)
+	assert.Equal(t, 12, report.Restarts)
+	require.Len(t, report.Workloads, 3)
+	api := report.Workloads[0]
+	assert.Equal(t, "Deployment", api.Kind)
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, 7, api.Restarts)
+	assert.Equal(t, []string{"api-1", "api-2"}, api.Pods)
+	assert.Equal(t, []RestartReason{
+		{Reason: "OOMKilled", Restarts: 3},
+		{Reason: "Error", Restarts: 2},
+		{Reason: "ExitCode:2", Restarts: 2},
+	}, api.Reasons)
+	require.NotNil(t, api.LastRestartAt)
+	assert.Equal(t, now.Add(-time.Minute), *api.LastRestartAt)
+	assert.Equal(t, "worker", report.Workloads[1].Name)
+	assert.Equal(t, "cron-1", report.Workloads[2].Name)
+
+	report = restartReport("shop", pods, workloadOf, now.Add(-time.Hour), 1)
+	require.NotNil(t, report.Since)
+	assert.Equal(t, 6, report.Restarts)
+	require.Len(t, report.Workloads, 1)
+	assert.Equal(t, 5, report.Workloads[0].Restarts)
+	assert.Equal(t, []RestartReason{
+		{Reason: "OOMKilled", Restarts: 3},
+		{Reason: "ExitCode:2", Restarts: 2},
+	}, report.Workloads[0].Reasons)
+
+	assert.Equal(t, "Unknown", terminationReason(nil))
+}
@@ -0,0 +1,59 @@
+package pods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestartPods(t *testing.T) {
+	newPod := func(name, namespace, app string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}}}
+	}
+	newClientSet := func() *fake.Clientset {
+		return fake.NewClientset(
+			newPod("web-2", "default", "web"),
+			newPod("web-1", "default", "web"),
+			newPod("api-1", "default", "api"),
+			newPod("web-1", "other", "web"),
+		)
+	}
+	remaining := func(clientSet *fake.Clientset) int {
+		list, _ := clientSet.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		return len(list.Items)
+	}
+
+	t.Run("deletes matching pods", func(t *testing.T) {
+		clientSet := newClientSet()
+		got, err := restartPods(context.Background(), clientSet, RestartRequest{Namespace: "default", Selector: "app=web"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"web-1", "web-2"}, got.Restarted)
+		assert.Empty(t, got.Failures)
+		assert.Equal(t, 1, remaining(clientSet))
+	})
+
+	t.Run("dry run keeps pods", func(t *testing.T) {
+		clientSet := newClientSet()
+		got, err := restartPods(context.Background(), clientSet, RestartRequest{Namespace: "default", Selector: "app in (web,api)", DryRun: true})
+		assert.NoError(t, err)
+		assert.True(t, got.DryRun)
+		assert.Equal(t, []string{"api-1", "web-1", "web-2"}, got.Restarted)
+		assert.Equal(t, 3, remaining(clientSet))
+	})
+
+	t.Run("rejects an empty selector", func(t *testing.T) {
+		clientSet := newClientSet()
+		_, err := restartPods(context.Background(), clientSet, RestartRequest{Namespace: "default", Selector: " "})
+		assert.Error(t, err)
+		assert.Equal(t, 3, remaining(clientSet))
+	})
+
+	t.Run("rejects an invalid selector", func(t *testing.T) {
+		_, err := restartPods(context.Background(), newClientSet(), RestartRequest{Namespace: "default", Selector: "app in (web"})
+		assert.Error(t, err)
+	})
+}
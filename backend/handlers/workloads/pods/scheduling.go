@@ -0,0 +1,104 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SchedulingResources struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+	Pods          int64 `json:"pods"`
+}
+
+// PodScheduling compares a pod's requests with the node it runs on.
+type PodScheduling struct {
+	Node        string              `json:"node"`
+	PodRequests SchedulingResources `json:"podRequests"`
+	Allocatable SchedulingResources `json:"allocatable"`
+	// Requested sums the requests of every non-terminated pod on the node,
+	// this one included.
+	Requested SchedulingResources `json:"requested"`
+	// Headroom is what is left for new pods, negative when overcommitted.
+	Headroom SchedulingResources `json:"headroom"`
+}
+
+// GetPodScheduling returns how the requests of a scheduled pod compare to the
+// allocatable capacity of its node and the requests of its neighbours.
+func (h *PodsHandler) GetPodScheduling(c echo.Context) error {
+	key := fmt.Sprintf("%s/%s", c.QueryParam("namespace"), c.Param("name"))
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+	if pod.Spec.NodeName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("pod %s is not scheduled", key))
+	}
+
+	node, err := h.BaseHandler.Container.ClientSet(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster).
+		CoreV1().
+		Nodes().
+		Get(c.Request().Context(), pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, podScheduling(pod, node, h.BaseHandler.Informer.GetStore().List()))
+}
+
+func podScheduling(pod *v1.Pod, node *v1.Node, items []any) PodScheduling {
+	requested := v1.ResourceList{}
+	var podCount int64
+	for _, obj := range items {
+		p, ok := obj.(*v1.Pod)
+		if !ok || p.Spec.NodeName != node.Name || isTerminated(p) {
+			continue
+		}
+		for name, quantity := range helpers.PodRequests(&p.Spec) {
+			sum := requested[name]
+			sum.Add(quantity)
+			requested[name] = sum
+		}
+		podCount++
+	}
+
+	s := PodScheduling{
+		Node:        node.Name,
+		PodRequests: schedulingResources(helpers.PodRequests(&pod.Spec), 1),
+		Allocatable: schedulingResources(node.Status.Allocatable, node.Status.Allocatable.Pods().Value()),
+		Requested:   schedulingResources(requested, podCount),
+	}
+	s.Headroom = SchedulingResources{
+		CPUMillicores: s.Allocatable.CPUMillicores - s.Requested.CPUMillicores,
+		MemoryBytes:   s.Allocatable.MemoryBytes - s.Requested.MemoryBytes,
+		Pods:          s.Allocatable.Pods - s.Requested.Pods,
+	}
+	return s
+}
+
+func schedulingResources(list v1.ResourceList, pods int64) SchedulingResources {
+	return SchedulingResources{
+		CPUMillicores: list.Cpu().MilliValue(),
+		MemoryBytes:   list.Memory().Value(),
+		Pods:          pods,
+	}
+}
+
+// isTerminated reports whether the pod no longer holds its requests, which
+// the scheduler ignores when fitting new pods.
+func isTerminated(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
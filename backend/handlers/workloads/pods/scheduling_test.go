@@ -0,0 +1,45 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodScheduling(t *testing.T) {
+	newPod := func(name, node, cpu string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.PodSpec{NodeName: node, Containers: []v1.Container{{
+				Name:      "app",
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourceMemory: resource.MustParse("1Gi")}},
+			}}},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+			v1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+	pod := newPod("api", "node-a", "500m", v1.PodRunning)
+	items := []any{
+		pod,
+		newPod("web", "node-a", "1", v1.PodRunning),
+		newPod("done", "node-a", "1", v1.PodSucceeded),
+		newPod("other", "node-b", "1", v1.PodRunning),
+	}
+
+	got := podScheduling(pod, node, items)
+	assert.Equal(t, "node-a", got.Node)
+	assert.Equal(t, SchedulingResources{CPUMillicores: 500, MemoryBytes: 1 << 30, Pods: 1}, got.PodRequests)
+	assert.Equal(t, SchedulingResources{CPUMillicores: 2000, MemoryBytes: 4 << 30, Pods: 110}, got.Allocatable)
+	assert.Equal(t, SchedulingResources{CPUMillicores: 1500, MemoryBytes: 2 << 30, Pods: 2}, got.Requested)
+	assert.Equal(t, SchedulingResources{CPUMillicores: 500, MemoryBytes: 2 << 30, Pods: 108}, got.Headroom)
+}
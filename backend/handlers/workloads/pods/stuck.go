@@ -0,0 +1,151 @@
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultStuckThreshold is how long past its deletion time a pod has to be to
+// count as stuck, kubelets normally finish well within it.
+const defaultStuckThreshold = 5 * time.Minute
+
+type StuckPod struct {
+	Name       string   `json:"name"`
+	Namespace  string   `json:"namespace"`
+	Node       string   `json:"node,omitempty"`
+	Finalizers []string `json:"finalizers"`
+	// DeletionTimestamp is when the grace period ended, TerminatingFor counts
+	// from the deletion request.
+	DeletionTimestamp time.Time `json:"deletionTimestamp"`
+	TerminatingFor    string    `json:"terminatingFor"`
+	OverdueSeconds    int64     `json:"overdueSeconds"`
+}
+
+type ForceDeleteResponse struct {
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	FinalizersRemoved bool   `json:"finalizersRemoved"`
+}
+
+// GetStuckPods lists pods still terminating longer than ?olderThan= (a
+// duration, default 5m) after their grace period ended, in ?namespace= or
+// all namespaces, most overdue first.
+func (h *PodsHandler) GetStuckPods(c echo.Context) error {
+	threshold := defaultStuckThreshold
+	if value := c.QueryParam("olderThan"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid olderThan %q", value))
+		}
+		threshold = d
+	}
+
+	pods := make([]*v1.Pod, 0)
+	for _, obj := range h.BaseHandler.Informer.GetStore().List() {
+		if pod, ok := obj.(*v1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return c.JSON(http.StatusOK, stuckPods(pods, c.QueryParam("namespace"), threshold, time.Now()))
+}
+
+func stuckPods(pods []*v1.Pod, namespace string, threshold time.Duration, now time.Time) []StuckPod {
+	stuck := make([]StuckPod, 0)
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil || (namespace != "" && pod.Namespace != namespace) {
+			continue
+		}
+		overdue := now.Sub(pod.DeletionTimestamp.Time)
+		if overdue < threshold {
+			continue
+		}
+		requested := pod.DeletionTimestamp.Time
+		if pod.DeletionGracePeriodSeconds != nil {
+			requested = requested.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+		}
+		finalizers := pod.Finalizers
+		if finalizers == nil {
+			finalizers = make([]string, 0)
+		}
+		stuck = append(stuck, StuckPod{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			Node:              pod.Spec.NodeName,
+			Finalizers:        finalizers,
+			DeletionTimestamp: pod.DeletionTimestamp.Time,
+			TerminatingFor:    helpers.HumanizeAge(requested, now),
+			OverdueSeconds:    int64(overdue.Seconds()),
+		})
+	}
+	sort.Slice(stuck, func(i, j int) bool {
+		if stuck[i].OverdueSeconds != stuck[j].OverdueSeconds {
+			return stuck[i].OverdueSeconds > stuck[j].OverdueSeconds
+		}
+		return stuck[i].Namespace+"/"+stuck[i].Name < stuck[j].Namespace+"/"+stuck[j].Name
+	})
+	return stuck
+}
+
+// ForceDeletePod deletes a terminating pod with a grace period of 0, like
+// kubectl delete --force. It refuses pods that are not terminating yet, those
+// should be deleted normally first. With ?removeFinalizers=true the
+// finalizers holding the pod are removed as well.
+func (h *PodsHandler) ForceDeletePod(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	removeFinalizers, _ := strconv.ParseBool(c.QueryParam("removeFinalizers"))
+
+	response, err := forceDeletePod(c.Request().Context(), h.clientSet, namespace, name, removeFinalizers)
+	if err != nil {
+		return err
+	}
+	log.Info("audit: pod force deleted",
+		"config", h.BaseHandler.QueryConfig, "cluster", h.BaseHandler.QueryCluster,
+		"namespace", namespace, "pod", name, "finalizersRemoved", response.FinalizersRemoved, "remoteAddr", c.RealIP())
+	return c.JSON(http.StatusOK, response)
+}
+
+func forceDeletePod(ctx context.Context, clientSet kubernetes.Interface, namespace, name string, removeFinalizers bool) (*ForceDeleteResponse, error) {
+	pods := clientSet.CoreV1().Pods(namespace)
+	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	if pod.DeletionTimestamp == nil {
+		return nil, echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("pod %s/%s is not terminating, delete it normally first", namespace, name))
+	}
+
+	response := &ForceDeleteResponse{Name: name, Namespace: namespace}
+	if removeFinalizers && len(pod.Finalizers) > 0 {
+		patch, _ := json.Marshal(helpers.PreconditionPatch(map[string]any{"metadata": map[string]any{"finalizers": nil}}, pod.ResourceVersion))
+		if _, err := pods.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+		}
+		response.FinalizersRemoved = true
+	}
+
+	// The UID precondition keeps a pod recreated under the same name, e.g. by
+	// a StatefulSet, from being deleted instead.
+	err = pods.Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: new(int64),
+		Preconditions:      &metav1.Preconditions{UID: &pod.UID},
+	})
+	// Removing the last finalizer of an expired pod can complete the deletion.
+	if err != nil && !(response.FinalizersRemoved && apierrors.IsNotFound(err)) {
+		return nil, echo.NewHTTPError(helpers.WriteErrorStatus(err), err.Error())
+	}
+	return response, nil
+}
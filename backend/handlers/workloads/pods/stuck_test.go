@@ -0,0 +1,91 @@
+package pods
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func terminatingPod(namespace, name string, deleted time.Time, finalizers ...string) *v1.Pod {
+	grace := int64(30)
+	deletion := metav1.NewTime(deleted)
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: name, UID: types.UID("uid-" + name),
+			DeletionTimestamp: &deletion, DeletionGracePeriodSeconds: &grace, Finalizers: finalizers,
+		},
+		Spec: v1.PodSpec{NodeName: "node-a"},
+	}
+}
+
+func TestStuckPods(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pods := []*v1.Pod{
+		terminatingPod("apps", "web", now.Add(-10*time.Minute), "example.com/cleanup"),
+		terminatingPod("apps", "fresh", now.Add(-time.Minute)),
+		terminatingPod("jobs", "batch", now.Add(-2*time.Hour)),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "running"}},
+	}
+
+	got := stuckPods(pods, "", 5*time.Minute, now)
+	require.Len(t, got, 2)
+	assert.Equal(t, "batch", got[0].Name)
+	assert.Equal(t, StuckPod{
+		Name: "web", Namespace: "apps", Node: "node-a", Finalizers: []string{"example.com/cleanup"},
+		DeletionTimestamp: now.Add(-10 * time.Minute), TerminatingFor: "10m", OverdueSeconds: 600,
+	}, got[1])
+	assert.Equal(t, []string{}, got[0].Finalizers)
+
+	got = stuckPods(pods, "apps", 0, now)
+	assert.Len(t, got, 2)
+}
+
+func TestForceDeletePod(t *testing.T) {
+	status := func(err error) int {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr.Code
+		}
+		return 0
+	}
+
+	t.Run("refuses pods that are not terminating", func(t *testing.T) {
+		clientSet := fake.NewClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"}})
+		_, err := forceDeletePod(context.Background(), clientSet, "apps", "web", false)
+		assert.Equal(t, http.StatusConflict, status(err))
+	})
+
+	t.Run("missing pod", func(t *testing.T) {
+		_, err := forceDeletePod(context.Background(), fake.NewClientset(), "apps", "web", false)
+		assert.Equal(t, http.StatusNotFound, status(err))
+	})
+
+	t.Run("deletes with finalizers removed", func(t *testing.T) {
+		clientSet := fake.NewClientset(terminatingPod("apps", "web", time.Now(), "example.com/cleanup"))
+		got, err := forceDeletePod(context.Background(), clientSet, "apps", "web", true)
+		require.NoError(t, err)
+		assert.True(t, got.FinalizersRemoved)
+
+		_, err = clientSet.CoreV1().Pods("apps").Get(context.Background(), "web", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		var gracePeriod *int64
+		for _, action := range clientSet.Actions() {
+			if action, ok := action.(k8stesting.DeleteAction); ok {
+				gracePeriod = action.GetDeleteOptions().GracePeriodSeconds
+			}
+		}
+		require.NotNil(t, gracePeriod)
+		assert.Equal(t, int64(0), *gracePeriod)
+	})
+}
@@ -0,0 +1,157 @@
+package pods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	TimelineCreated   = "created"
+	TimelineCondition = "condition"
+	TimelineContainer = "container"
+	TimelineEvent     = "event"
+)
+
+type TimelineEntry struct {
+	Time time.Time `json:"time"`
+	// OffsetSeconds is the time since the pod was created.
+	OffsetSeconds float64 `json:"offsetSeconds"`
+	Type          string  `json:"type"`
+	// Name is the condition type, "started" or "finished" for containers, or
+	// the event reason.
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Count     int32  `json:"count,omitempty"`
+}
+
+type PodTimeline struct {
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace"`
+	Created   time.Time       `json:"created"`
+	Entries   []TimelineEntry `json:"entries"`
+	// EventsError is set when the events could not be listed, the timeline
+	// then only has what the pod records.
+	EventsError string `json:"eventsError,omitempty"`
+}
+
+// GetPodTimeline merges the pod's conditions, container start and finish
+// times and events into one chronological timeline, showing where startup
+// time went.
+func (h *PodsHandler) GetPodTimeline(c echo.Context) error {
+	namespace, name := c.QueryParam("namespace"), c.Param("name")
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	obj, exists, err := h.BaseHandler.Informer.GetStore().GetByKey(key)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pod, ok := obj.(*v1.Pod)
+	if !exists || !ok {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("pod %s not found", key))
+	}
+
+	events, err := podEvents(c.Request().Context(), h.clientSet, pod)
+	timeline := podTimeline(pod, events)
+	if err != nil {
+		timeline.EventsError = err.Error()
+	}
+	return c.JSON(http.StatusOK, timeline)
+}
+
+// podEvents lists the events of this pod, matched by UID so a previous pod
+// of the same name, e.g. of a StatefulSet, is left out.
+func podEvents(ctx context.Context, clientSet kubernetes.Interface, pod *v1.Pod) ([]v1.Event, error) {
+	list, err := clientSet.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+			fields.OneTermEqualSelector("involvedObject.name", pod.Name),
+			fields.OneTermEqualSelector("involvedObject.uid", string(pod.UID)),
+		).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func podTimeline(pod *v1.Pod, events []v1.Event) PodTimeline {
+	created := pod.CreationTimestamp.Time
+	timeline := PodTimeline{Name: pod.Name, Namespace: pod.Namespace, Created: created, Entries: make([]TimelineEntry, 0)}
+	add := func(at time.Time, entry TimelineEntry) {
+		if at.IsZero() {
+			return
+		}
+		entry.Time = at
+		entry.OffsetSeconds = at.Sub(created).Seconds()
+		timeline.Entries = append(timeline.Entries, entry)
+	}
+
+	add(created, TimelineEntry{Type: TimelineCreated, Name: TimelineCreated})
+	for _, condition := range pod.Status.Conditions {
+		add(condition.LastTransitionTime.Time, TimelineEntry{
+			Type:    TimelineCondition,
+			Name:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		for _, state := range []v1.ContainerState{status.LastTerminationState, status.State} {
+			switch {
+			case state.Running != nil:
+				add(state.Running.StartedAt.Time, TimelineEntry{Type: TimelineContainer, Name: "started", Container: status.Name})
+			case state.Terminated != nil:
+				add(state.Terminated.StartedAt.Time, TimelineEntry{Type: TimelineContainer, Name: "started", Container: status.Name})
+				add(state.Terminated.FinishedAt.Time, TimelineEntry{
+					Type:      TimelineContainer,
+					Name:      "finished",
+					Container: status.Name,
+					Status:    fmt.Sprintf("exit code %d", state.Terminated.ExitCode),
+					Reason:    state.Terminated.Reason,
+					Message:   state.Terminated.Message,
+				})
+			}
+		}
+	}
+	for _, event := range events {
+		add(timelineEventTime(event), TimelineEntry{
+			Type:    TimelineEvent,
+			Name:    event.Reason,
+			Status:  event.Type,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+
+	// Conditions and container states have second precision, the stable
+	// sort keeps the pod's own records ahead of events of the same second.
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		return timeline.Entries[i].Time.Before(timeline.Entries[j].Time)
+	})
+	return timeline
+}
+
+// timelineEventTime is when an event first happened.
+func timelineEventTime(event v1.Event) time.Time {
+	switch {
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
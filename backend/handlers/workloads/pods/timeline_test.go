@@ -0,0 +1,65 @@
+package pods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodTimeline(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) metav1.Time {
+		return metav1.NewTime(created.Add(time.Duration(seconds) * time.Second))
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web", UID: "uid-web", CreationTimestamp: metav1.NewTime(created)},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: at(40)},
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: at(1)},
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue, LastTransitionTime: at(20)},
+			},
+			InitContainerStatuses: []v1.ContainerStatus{{
+				Name:  "migrate",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{StartedAt: at(15), FinishedAt: at(20), Reason: "Completed"}},
+			}},
+			ContainerStatuses: []v1.ContainerStatus{{
+				Name:  "app",
+				State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: at(35)}},
+			}},
+		},
+	}
+	events := []v1.Event{
+		{Reason: "Pulled", Message: "Successfully pulled image \"app:1\" in 12s", FirstTimestamp: at(33), Type: v1.EventTypeNormal, Count: 1},
+		{Reason: "Pulling", Message: "Pulling image \"app:1\"", EventTime: metav1.NewMicroTime(created.Add(21 * time.Second)), Type: v1.EventTypeNormal},
+	}
+
+	got := podTimeline(pod, events)
+	var names []string
+	for _, entry := range got.Entries {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(t, []string{"created", "PodScheduled", "started", "Initialized", "finished", "Pulling", "Pulled", "started", "Ready"}, names)
+	assert.Equal(t, "migrate", got.Entries[2].Container)
+	assert.Equal(t, "exit code 0", got.Entries[4].Status)
+	assert.Equal(t, 33.0, got.Entries[6].OffsetSeconds)
+	assert.Equal(t, 40.0, got.Entries[8].OffsetSeconds)
+}
+
+func TestPodEvents(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web", UID: "uid-web"}}
+	clientSet := fake.NewClientset(&v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "apps", Name: "web.1"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web", UID: "uid-web"},
+		Reason:         "Scheduled",
+	})
+	events, err := podEvents(context.Background(), clientSet, pod)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,67 @@
+package pods
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/kubewall/kubewall/backend/handlers/helpers"
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TopPod struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	CPUMillicores int64  `json:"cpuMillicores"`
+	MemoryBytes   int64  `json:"memoryBytes"`
+	MetricsStale  bool   `json:"metricsStale,omitempty"`
+}
+
+// GetTopPods returns the pods using the most CPU or memory, like kubectl top
+// pods. It takes ?namespace=, ?sortBy=cpu|memory and ?limit=.
+func (h *PodsHandler) GetTopPods(c echo.Context) error {
+	q, err := helpers.ParseTopQuery(c)
+	if err != nil {
+		return err
+	}
+	if !helpers.IsMetricServerAvailable(h.BaseHandler.Container, h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster) {
+		return echo.NewHTTPError(http.StatusNotImplemented, "metrics unavailable: metrics-server is not installed on this cluster")
+	}
+
+	namespace := c.QueryParam("namespace")
+	podMetrics, err := h.BaseHandler.Container.
+		MetricClient(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster).
+		MetricsV1beta1().
+		PodMetricses(namespace).
+		List(c.Request().Context(), metav1.ListOptions{})
+	lastKnown := helpers.LastKnownPodMetrics(h.BaseHandler.QueryConfig, h.BaseHandler.QueryCluster)
+	lastKnown.Prune(h.BaseHandler.Informer.GetStore())
+	merged, stale := mergePodMetrics(lastKnown, podMetrics, err)
+	if err != nil && len(merged.Items) == 0 {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+
+	top := make([]TopPod, 0, len(merged.Items))
+	for _, m := range merged.Items {
+		if namespace != "" && m.Namespace != namespace {
+			continue
+		}
+		p := TopPod{Name: m.Name, Namespace: m.Namespace, MetricsStale: stale[fmt.Sprintf("%s/%s", m.Namespace, m.Name)]}
+		for _, container := range m.Containers {
+			p.CPUMillicores += container.Usage.Cpu().MilliValue()
+			p.MemoryBytes += container.Usage.Memory().Value()
+		}
+		top = append(top, p)
+	}
+	// Merged metrics come out of a map, keep ties in a stable order.
+	sort.Slice(top, func(i, j int) bool {
+		return fmt.Sprintf("%s/%s", top[i].Namespace, top[i].Name) < fmt.Sprintf("%s/%s", top[j].Namespace, top[j].Name)
+	})
+
+	top = helpers.TopN(top, q,
+		func(p TopPod) int64 { return p.CPUMillicores },
+		func(p TopPod) int64 { return p.MemoryBytes },
+	)
+	return c.JSON(http.StatusOK, top)
+}
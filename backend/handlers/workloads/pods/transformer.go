@@ -29,9 +29,12 @@ type PodList struct {
 	Qos           string    `json:"qos"`
 	Age           time.Time `json:"age"`
 	HasUpdated    bool      `json:"hasUpdated"`
+	// MetricsStale is set when CPU and Memory are last-known values because
+	// the metrics API did not return this pod.
+	MetricsStale bool `json:"metricsStale,omitempty"`
 }
 
-func TransformPodList(pods []coreV1.Pod, podMetricsList *v1beta1.PodMetricsList) []PodList {
+func TransformPodList(pods []coreV1.Pod, podMetricsList *v1beta1.PodMetricsList, staleMetrics map[string]bool) []PodList {
 	list := make([]PodList, 0)
 	podsMetricsMap := GetPodsMetrics(podMetricsList)
 
@@ -40,6 +43,7 @@ func TransformPodList(pods []coreV1.Pod, podMetricsList *v1beta1.PodMetricsList)
 		if metrics, exists := podsMetricsMap[item.Name]; exists {
 			item.CPU = metrics["cpu"]
 			item.Memory = metrics["memory"]
+			item.MetricsStale = staleMetrics[fmt.Sprintf("%s/%s", item.Namespace, item.Name)]
 		}
 
 		list = append(list, item)
@@ -33,6 +33,8 @@ func NewStatefulSetRouteHandler(container container.Container, routeType base.Ro
 			return handler.BaseHandler.GetYaml(c)
 		case base.Delete:
 			return handler.BaseHandler.Delete(c)
+		case base.SetImage:
+			return handler.BaseHandler.SetWorkloadImage(c)
 		default:
 			return echo.NewHTTPError(http.StatusInternalServerError, "Unknown route type")
 		}
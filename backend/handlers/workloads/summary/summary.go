@@ -0,0 +1,201 @@
+package summary
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RolloutSummary counts the workloads that have all their replicas available.
+type RolloutSummary struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+type JobSummary struct {
+	Total     int `json:"total"`
+	Active    int `json:"active"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+type CronJobSummary struct {
+	Total     int `json:"total"`
+	Suspended int `json:"suspended"`
+	// Active counts the cron jobs with at least one running job.
+	Active int `json:"active"`
+}
+
+type WorkloadsSummary struct {
+	Namespace    string         `json:"namespace,omitempty"`
+	Deployments  RolloutSummary `json:"deployments"`
+	StatefulSets RolloutSummary `json:"statefulSets"`
+	DaemonSets   RolloutSummary `json:"daemonSets"`
+	Jobs         JobSummary     `json:"jobs"`
+	CronJobs     CronJobSummary `json:"cronJobs"`
+}
+
+type SummaryHandler struct {
+	container container.Container
+}
+
+func NewSummaryHandler(container container.Container) *SummaryHandler {
+	return &SummaryHandler{container: container}
+}
+
+// GetWorkloadsSummary returns workload counts and health rollups for a
+// namespace, or the whole cluster when ?namespace= is empty.
+func (h *SummaryHandler) GetWorkloadsSummary(c echo.Context) error {
+	clientSet := h.container.ClientSet(c.QueryParam("config"), c.QueryParam("cluster"))
+	summary, err := workloadsSummary(c.Request().Context(), clientSet, c.QueryParam("namespace"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+func workloadsSummary(ctx context.Context, clientSet kubernetes.Interface, namespace string) (WorkloadsSummary, error) {
+	summary := WorkloadsSummary{Namespace: namespace}
+	apps, batch := clientSet.AppsV1(), clientSet.BatchV1()
+
+	// Every rollup writes its own field, only the first error is kept.
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	run := func(f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f(); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}()
+	}
+
+	run(func() error {
+		list, err := apps.Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		summary.Deployments = deploymentsRollup(list.Items)
+		return nil
+	})
+	run(func() error {
+		list, err := apps.StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		summary.StatefulSets = statefulSetsRollup(list.Items)
+		return nil
+	})
+	run(func() error {
+		list, err := apps.DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		summary.DaemonSets = daemonSetsRollup(list.Items)
+		return nil
+	})
+	run(func() error {
+		list, err := batch.Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		summary.Jobs = jobsRollup(list.Items)
+		return nil
+	})
+	run(func() error {
+		list, err := batch.CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		summary.CronJobs = cronJobsRollup(list.Items)
+		return nil
+	})
+	wg.Wait()
+
+	return summary, firstErr
+}
+
+func deploymentsRollup(items []appsV1.Deployment) RolloutSummary {
+	r := RolloutSummary{Total: len(items)}
+	for _, d := range items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.AvailableReplicas >= desired {
+			r.Available++
+		}
+	}
+	return r
+}
+
+func statefulSetsRollup(items []appsV1.StatefulSet) RolloutSummary {
+	r := RolloutSummary{Total: len(items)}
+	for _, s := range items {
+		desired := int32(1)
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		if s.Status.AvailableReplicas >= desired {
+			r.Available++
+		}
+	}
+	return r
+}
+
+func daemonSetsRollup(items []appsV1.DaemonSet) RolloutSummary {
+	r := RolloutSummary{Total: len(items)}
+	for _, d := range items {
+		if d.Status.NumberAvailable >= d.Status.DesiredNumberScheduled {
+			r.Available++
+		}
+	}
+	return r
+}
+
+func jobsRollup(items []batchV1.Job) JobSummary {
+	r := JobSummary{Total: len(items)}
+	for _, j := range items {
+		switch {
+		case hasJobCondition(j, batchV1.JobComplete):
+			r.Succeeded++
+		case hasJobCondition(j, batchV1.JobFailed):
+			r.Failed++
+		default:
+			r.Active++
+		}
+	}
+	return r
+}
+
+func hasJobCondition(job batchV1.Job, conditionType batchV1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == coreV1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func cronJobsRollup(items []batchV1.CronJob) CronJobSummary {
+	r := CronJobSummary{Total: len(items)}
+	for _, cj := range items {
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			r.Suspended++
+		}
+		if len(cj.Status.Active) > 0 {
+			r.Active++
+		}
+	}
+	return r
+}
@@ -0,0 +1,49 @@
+package summary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWorkloadsSummary(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	suspend := true
+	meta := func(namespace, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name}
+	}
+
+	clientSet := fake.NewClientset(
+		&appsV1.Deployment{ObjectMeta: meta("team", "api"), Spec: appsV1.DeploymentSpec{Replicas: replicas(2)}, Status: appsV1.DeploymentStatus{AvailableReplicas: 2}},
+		&appsV1.Deployment{ObjectMeta: meta("team", "web"), Spec: appsV1.DeploymentSpec{Replicas: replicas(3)}, Status: appsV1.DeploymentStatus{AvailableReplicas: 1}},
+		&appsV1.Deployment{ObjectMeta: meta("other", "db"), Spec: appsV1.DeploymentSpec{Replicas: replicas(1)}, Status: appsV1.DeploymentStatus{AvailableReplicas: 1}},
+		&appsV1.StatefulSet{ObjectMeta: meta("team", "cache"), Spec: appsV1.StatefulSetSpec{Replicas: replicas(1)}, Status: appsV1.StatefulSetStatus{AvailableReplicas: 1}},
+		&appsV1.DaemonSet{ObjectMeta: meta("team", "agent"), Status: appsV1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 2}},
+		&batchV1.Job{ObjectMeta: meta("team", "done"), Status: batchV1.JobStatus{Conditions: []batchV1.JobCondition{{Type: batchV1.JobComplete, Status: coreV1.ConditionTrue}}}},
+		&batchV1.Job{ObjectMeta: meta("team", "broken"), Status: batchV1.JobStatus{Conditions: []batchV1.JobCondition{{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue}}}},
+		&batchV1.Job{ObjectMeta: meta("team", "running"), Status: batchV1.JobStatus{Active: 1}},
+		&batchV1.CronJob{ObjectMeta: meta("team", "nightly"), Spec: batchV1.CronJobSpec{Suspend: &suspend}},
+		&batchV1.CronJob{ObjectMeta: meta("team", "hourly"), Status: batchV1.CronJobStatus{Active: []coreV1.ObjectReference{{Name: "hourly-1"}}}},
+	)
+
+	got, err := workloadsSummary(context.Background(), clientSet, "team")
+	assert.NoError(t, err)
+	assert.Equal(t, WorkloadsSummary{
+		Namespace:    "team",
+		Deployments:  RolloutSummary{Total: 2, Available: 1},
+		StatefulSets: RolloutSummary{Total: 1, Available: 1},
+		DaemonSets:   RolloutSummary{Total: 1},
+		Jobs:         JobSummary{Total: 3, Active: 1, Succeeded: 1, Failed: 1},
+		CronJobs:     CronJobSummary{Total: 2, Suspended: 1, Active: 1},
+	}, got)
+
+	all, err := workloadsSummary(context.Background(), clientSet, "")
+	assert.NoError(t, err)
+	assert.Equal(t, RolloutSummary{Total: 3, Available: 2}, all.Deployments)
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/maypok86/otter/v2/stats"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+)
+
+// Registry holds kubewall's own metrics. It is kept apart from the default
+// registry so nothing client-go or a dependency registers ends up on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// SSEConnections is maintained by the stream limit middleware.
+	SSEConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kubewall_sse_active_connections",
+		Help: "Number of open server-sent event connections.",
+	})
+
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubewall_http_requests_total",
+		Help: "HTTP requests served, by route, method and status code.",
+	}, []string{"path", "method", "code"})
+
+	upstreamLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kubewall_upstream_request_duration_seconds",
+		Help:    "Latency of requests to Kubernetes API servers, by verb and host.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"verb", "host"})
+)
+
+var registerOnce sync.Once
+
+func init() {
+	Registry.MustRegister(SSEConnections, requests, upstreamLatency)
+}
+
+// RegisterClientMetrics routes client-go request latencies into
+// kubewall_upstream_request_duration_seconds. client-go only honours the
+// first registration, so this is safe to call more than once.
+func RegisterClientMetrics() {
+	registerOnce.Do(func() {
+		clientmetrics.Register(clientmetrics.RegisterOpts{
+			RequestLatency: latencyAdapter{},
+		})
+	})
+}
+
+// RegisterCacheStats exposes hit and miss counters of an otter cache created
+// with a stats recorder.
+func RegisterCacheStats(name string, snapshot func() stats.Stats) {
+	labels := prometheus.Labels{"cache": name}
+	Registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "kubewall_cache_hits_total",
+			Help:        "Cache lookups that returned a cached value.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(snapshot().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "kubewall_cache_misses_total",
+			Help:        "Cache lookups that did not find a cached value.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(snapshot().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "kubewall_cache_hit_ratio",
+			Help:        "Ratio of cache lookups that returned a cached value.",
+			ConstLabels: labels,
+		}, func() float64 { return snapshot().HitRatio() }),
+	)
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware counts requests per route, method and status code. The route is
+// the registered path, not the request URL, so the label values stay bounded.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			code := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok && !c.Response().Committed {
+				code = he.Code
+			} else if err != nil && !c.Response().Committed {
+				code = http.StatusInternalServerError
+			}
+			path := c.Path()
+			if path == "" {
+				path = "unmatched"
+			}
+			requests.WithLabelValues(path, c.Request().Method, strconv.Itoa(code)).Inc()
+
+			return err
+		}
+	}
+}
+
+type latencyAdapter struct{}
+
+func (latencyAdapter) Observe(_ context.Context, verb string, u url.URL, latency time.Duration) {
+	upstreamLatency.WithLabelValues(verb, u.Host).Observe(latency.Seconds())
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/maypok86/otter/v2/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/metrics", nil), rec)
+	assert.NoError(t, Handler()(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+
+	t.Run("counts requests per route, method and status", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/pods/web?config=test-config", nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetPath("/api/v1/pods/:name")
+		err := Middleware()(func(c echo.Context) error {
+			return c.NoContent(http.StatusAccepted)
+		})(c)
+		assert.NoError(t, err)
+
+		body := scrape(t)
+		assert.Contains(t, body, `kubewall_http_requests_total{code="202",method="GET",path="/api/v1/pods/:name"} 1`)
+		assert.NotContains(t, body, "test-config")
+	})
+
+	t.Run("labels unmatched routes", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/nope", nil), httptest.NewRecorder())
+		err := Middleware()(func(c echo.Context) error {
+			return echo.ErrNotFound
+		})(c)
+		assert.Error(t, err)
+
+		assert.Contains(t, scrape(t), `kubewall_http_requests_total{code="404",method="POST",path="unmatched"} 1`)
+	})
+
+}
+
+func TestRegisterCacheStats(t *testing.T) {
+	RegisterCacheStats("test", func() stats.Stats {
+		return stats.Stats{Hits: 3, Misses: 1}
+	})
+
+	body := scrape(t)
+	assert.Contains(t, body, `kubewall_cache_hits_total{cache="test"} 3`)
+	assert.Contains(t, body, `kubewall_cache_misses_total{cache="test"} 1`)
+	assert.Contains(t, body, `kubewall_cache_hit_ratio{cache="test"} 0.75`)
+}
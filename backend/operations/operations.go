@@ -0,0 +1,82 @@
+// Package operations tracks long-running requests by a client-generated ID so
+// the client can cancel them, e.g. when the user navigates away mid drain or
+// wait.
+package operations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operation describes an in-flight cancellable request.
+type Operation struct {
+	ID      string    `json:"id"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Config  string    `json:"config,omitempty"`
+	Cluster string    `json:"cluster,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+type entry struct {
+	operation Operation
+	cancel    context.CancelFunc
+}
+
+var (
+	mu       sync.Mutex
+	inFlight = make(map[string]entry)
+)
+
+// Register records op under op.ID. The returned context is cancelled by
+// Cancel; release must be called once the request ends. An ID already in
+// flight is rejected, so one operation cannot cancel another.
+func Register(ctx context.Context, op Operation) (context.Context, func(), error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := inFlight[op.ID]; ok {
+		return nil, nil, fmt.Errorf("operation %s is already in flight", op.ID)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	inFlight[op.ID] = entry{operation: op, cancel: cancel}
+	return ctx, func() {
+		mu.Lock()
+		delete(inFlight, op.ID)
+		mu.Unlock()
+		cancel()
+	}, nil
+}
+
+// Cancel cancels the operation with id and reports whether it was in flight.
+func Cancel(id string) (Operation, bool) {
+	mu.Lock()
+	e, ok := inFlight[id]
+	mu.Unlock()
+	if !ok {
+		return Operation{}, false
+	}
+	e.cancel()
+	return e.operation, true
+}
+
+// List returns the operations in flight, oldest first.
+func List() []Operation {
+	mu.Lock()
+	list := make([]Operation, 0, len(inFlight))
+	for _, e := range inFlight {
+		list = append(list, e.operation)
+	}
+	mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Started.Equal(list[j].Started) {
+			return list[i].Started.Before(list[j].Started)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,42 @@
+package operations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperations(t *testing.T) {
+	started := time.Now()
+	ctx1, release1, err := Register(context.Background(), Operation{ID: "drain-1", Started: started})
+	require.NoError(t, err)
+	ctx2, release2, err := Register(context.Background(), Operation{ID: "wait-1", Started: started.Add(time.Second)})
+	require.NoError(t, err)
+	defer release2()
+
+	_, _, err = Register(context.Background(), Operation{ID: "drain-1"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"drain-1", "wait-1"}, ids(List()))
+
+	op, ok := Cancel("drain-1")
+	assert.True(t, ok)
+	assert.Equal(t, "drain-1", op.ID)
+	assert.Error(t, ctx1.Err())
+	assert.NoError(t, ctx2.Err())
+
+	release1()
+	assert.Equal(t, []string{"wait-1"}, ids(List()))
+	_, ok = Cancel("drain-1")
+	assert.False(t, ok)
+}
+
+func ids(list []Operation) []string {
+	ids := make([]string, 0, len(list))
+	for _, op := range list {
+		ids = append(ids, op.ID)
+	}
+	return ids
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartMemory is how much of a multipart form is held in memory, files
+// beyond it are spooled to temporary files.
+const multipartMemory = 4 << 20
+
+// BodyLimitMiddleware rejects request bodies larger than
+// Config().MaxRequestBodySize with 413. Multipart forms are parsed up front,
+// so uploads are spooled to disk rather than read into memory, and the
+// temporary files are removed once the request is handled.
+func BodyLimitMiddleware(container container.Container) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			limit := container.Config().MaxRequestBodySize
+			req := c.Request()
+			if limit <= 0 || req.Body == nil || req.Body == http.NoBody {
+				return next(c)
+			}
+			if req.ContentLength > limit {
+				return tooLarge(limit)
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+			// Form values are read with errors discarded, a truncated form
+			// would look like missing fields.
+			switch contentType := req.Header.Get(echo.HeaderContentType); {
+			case strings.HasPrefix(contentType, echo.MIMEMultipartForm):
+				if err := req.ParseMultipartForm(multipartMemory); err != nil {
+					return formError(err, limit)
+				}
+				defer req.MultipartForm.RemoveAll()
+			case strings.HasPrefix(contentType, echo.MIMEApplicationForm):
+				if err := req.ParseForm(); err != nil {
+					return formError(err, limit)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+func formError(err error, limit int64) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return tooLarge(limit)
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid form: %v", err))
+}
+
+func tooLarge(limit int64) error {
+	return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body too large (max %d bytes)", limit))
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	cfg := config.NewAppConfig("test", ":0", 10, 10, false)
+	cfg.MaxRequestBodySize = 1024
+	limit := BodyLimitMiddleware(container.NewContainer(&config.Env{}, cfg))
+	e := echo.New()
+
+	serve := func(req *http.Request, next echo.HandlerFunc) error {
+		return limit(next)(e.NewContext(req, httptest.NewRecorder()))
+	}
+	status := func(err error) int {
+		if httpErr, ok := err.(*echo.HTTPError); ok {
+			return httpErr.Code
+		}
+		return http.StatusOK
+	}
+	multipartBody := func(size int) (*bytes.Buffer, string) {
+		body := new(bytes.Buffer)
+		w := multipart.NewWriter(body)
+		part, _ := w.CreateFormFile("file", "manifest.yaml")
+		part.Write(bytes.Repeat([]byte("a"), size))
+		w.Close()
+		return body, w.FormDataContentType()
+	}
+
+	t.Run("rejects a declared length over the limit", func(t *testing.T) {
+		called := false
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/app/apply", strings.NewReader(strings.Repeat("a", 2048)))
+		err := serve(req, func(echo.Context) error { called = true; return nil })
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status(err))
+		assert.False(t, called)
+	})
+
+	t.Run("caps bodies of unknown length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", io.NopCloser(strings.NewReader(strings.Repeat("a", 2048))))
+		req.ContentLength = -1
+		err := serve(req, func(c echo.Context) error {
+			_, err := io.ReadAll(c.Request().Body)
+			var maxBytesErr *http.MaxBytesError
+			assert.ErrorAs(t, err, &maxBytesErr)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects multipart forms over the limit", func(t *testing.T) {
+		body, contentType := multipartBody(2048)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", io.NopCloser(body))
+		req.ContentLength = -1
+		req.Header.Set(echo.HeaderContentType, contentType)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status(serve(req, func(echo.Context) error { return nil })))
+	})
+
+	t.Run("rejects url-encoded forms over the limit", func(t *testing.T) {
+		form := url.Values{"yaml": {strings.Repeat("a", 2048)}}.Encode()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/app/apply", io.NopCloser(strings.NewReader(form)))
+		req.ContentLength = -1
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status(serve(req, func(echo.Context) error { return nil })))
+	})
+
+	t.Run("removes spooled files after the request", func(t *testing.T) {
+		cfg.MaxRequestBodySize = multipartMemory * 2
+		defer func() { cfg.MaxRequestBodySize = 1024 }()
+
+		body, contentType := multipartBody(multipartMemory + 1024)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/configs", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		var spooled string
+		err := serve(req, func(c echo.Context) error {
+			fh, err := c.FormFile("file")
+			require.NoError(t, err)
+			f, err := fh.Open()
+			require.NoError(t, err)
+			defer f.Close()
+			file, ok := f.(*os.File)
+			require.True(t, ok, "upload over the memory limit should be spooled to disk")
+			spooled = file.Name()
+			return nil
+		})
+		assert.NoError(t, err)
+		_, err = os.Stat(spooled)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		cfg.MaxRequestBodySize = 0
+		defer func() { cfg.MaxRequestBodySize = 1024 }()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/app/apply", strings.NewReader(strings.Repeat("a", 2048)))
+		assert.NoError(t, serve(req, func(echo.Context) error { return nil }))
+	})
+}
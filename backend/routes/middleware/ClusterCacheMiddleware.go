@@ -3,8 +3,10 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
 
+	cfg "github.com/kubewall/kubewall/backend/config"
 	"github.com/kubewall/kubewall/backend/container"
 	"github.com/kubewall/kubewall/backend/handlers/config/secrets"
 	"github.com/kubewall/kubewall/backend/handlers/helpers"
@@ -66,6 +68,10 @@ func ClusterCacheMiddleware(container container.Container) echo.MiddlewareFunc {
 				return c.JSON(400, "cluster not found in config")
 			}
 
+			if err := conn.CheckExecAuth(cfg.ExecAuthTimeout); err != nil {
+				return c.JSON(http.StatusUnauthorized, err.Error())
+			}
+
 			if !conn.IsConnected() {
 				conn.MarkAsConnected()
 			}
@@ -73,7 +79,10 @@ func ClusterCacheMiddleware(container container.Container) echo.MiddlewareFunc {
 			clusterKey := fmt.Sprintf("%s-%s", config, cluster)
 			once, _ := clusterInitOnce.LoadOrStore(clusterKey, &sync.Once{})
 			once.(*sync.Once).Do(func() {
-				helpers.CacheAllResources(container, config, cluster)
+				// Already cached when the clients were warmed up.
+				if _, err := helpers.GetAllResourcesFromCache(container, config, cluster); err != nil {
+					helpers.CacheAllResources(container, config, cluster)
+				}
 				loadAllInformerOfCluster(config, cluster, container)
 			})
 
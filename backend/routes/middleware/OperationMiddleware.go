@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/operations"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderOperationID carries the client-generated ID of a cancellable request.
+// Event streams, which cannot set headers, pass ?operationId= instead.
+const HeaderOperationID = "X-Operation-ID"
+
+var operationIDRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// OperationMiddleware registers requests that carry an operation ID, so
+// DELETE api/v1/app/operations/:id can cancel their context. Requests
+// without one are passed through untouched.
+func OperationMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(HeaderOperationID)
+			if id == "" {
+				id = c.QueryParam("operationId")
+			}
+			if id == "" {
+				return next(c)
+			}
+			if !operationIDRegex.MatchString(id) {
+				return c.JSON(http.StatusBadRequest, echo.Map{"message": "invalid operation id, use up to 128 letters, digits, '.', '_' or '-'"})
+			}
+
+			ctx, release, err := operations.Register(c.Request().Context(), operations.Operation{
+				ID:      id,
+				Method:  c.Request().Method,
+				Path:    c.Request().URL.Path,
+				Config:  c.QueryParam("config"),
+				Cluster: c.QueryParam("cluster"),
+				Started: time.Now(),
+			})
+			if err != nil {
+				return c.JSON(http.StatusConflict, echo.Map{"message": err.Error()})
+			}
+			defer release()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/operations"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationMiddleware(t *testing.T) {
+	mw := OperationMiddleware()
+	e := echo.New()
+
+	request := func(target, id string, next echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		if id != "" {
+			req.Header.Set(HeaderOperationID, id)
+		}
+		rec := httptest.NewRecorder()
+		assert.NoError(t, mw(next)(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("cancel ends the request context", func(t *testing.T) {
+		rec := request("/api/v1/wait/pods/web", "op-1", func(c echo.Context) error {
+			_, ok := operations.Cancel("op-1")
+			assert.True(t, ok)
+			<-c.Request().Context().Done()
+			return c.NoContent(http.StatusOK)
+		})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, operations.List())
+	})
+
+	t.Run("query param for event streams", func(t *testing.T) {
+		request("/api/v1/wait/pods/web?operationId=op-2", "", func(c echo.Context) error {
+			assert.Len(t, operations.List(), 1)
+			return c.NoContent(http.StatusOK)
+		})
+	})
+
+	t.Run("rejects an id in flight", func(t *testing.T) {
+		var inner *httptest.ResponseRecorder
+		request("/", "op-3", func(c echo.Context) error {
+			inner = request("/", "op-3", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+			return c.NoContent(http.StatusOK)
+		})
+		assert.Equal(t, http.StatusConflict, inner.Code)
+	})
+
+	t.Run("rejects invalid ids", func(t *testing.T) {
+		rec := request("/", "a b", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+// ProbeMiddleware answers /healthz and /readyz before routing so that probes
+// never pass through addon, auth or cluster middleware. It must be installed
+// with e.Pre.
+//
+// /healthz reports process liveness. /readyz additionally requires the app
+// config to be loaded and the server to be listening.
+func ProbeMiddleware(e *echo.Echo, container container.Container) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if method != http.MethodGet && method != http.MethodHead {
+				return next(c)
+			}
+
+			switch c.Request().URL.Path {
+			case "/healthz":
+				return c.String(http.StatusOK, "OK")
+			case "/readyz":
+				if !container.Config().Loaded() {
+					return c.String(http.StatusServiceUnavailable, "config not loaded")
+				}
+				if e.ListenerAddr() == nil && e.TLSListenerAddr() == nil {
+					return c.String(http.StatusServiceUnavailable, "server not listening")
+				}
+				return c.String(http.StatusOK, "OK")
+			default:
+				return next(c)
+			}
+		}
+	}
+}
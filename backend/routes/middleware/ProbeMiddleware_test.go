@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func runProbe(t *testing.T, e *echo.Echo, appContainer container.Container, path string) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	nextCalled := false
+	next := func(c echo.Context) error {
+		nextCalled = true
+		return c.String(http.StatusUnauthorized, "next handler")
+	}
+
+	err := ProbeMiddleware(e, appContainer)(next)(c)
+	assert.NoError(t, err)
+	return rec, nextCalled
+}
+
+func TestProbeMiddleware(t *testing.T) {
+	cfg := config.NewAppConfig("test", ":0", 10, 10, false)
+	appContainer := container.NewContainer(&config.Env{}, cfg)
+	e := echo.New()
+
+	t.Run("healthz answers without calling next", func(t *testing.T) {
+		rec, nextCalled := runProbe(t, e, appContainer, "/healthz")
+		assert.False(t, nextCalled)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("readyz is unavailable before config is loaded", func(t *testing.T) {
+		rec, nextCalled := runProbe(t, e, appContainer, "/readyz")
+		assert.False(t, nextCalled)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("readyz is unavailable before the server listens", func(t *testing.T) {
+		cfg.LoadAppConfig()
+		rec, _ := runProbe(t, e, appContainer, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("readyz is ok once listening", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer l.Close()
+		e.Listener = l
+
+		rec, _ := runProbe(t, e, appContainer, "/readyz")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("other paths fall through", func(t *testing.T) {
+		_, nextCalled := runProbe(t, e, appContainer, "/api/v1/pods")
+		assert.True(t, nextCalled)
+	})
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+)
+
+// heartbeat is an SSE comment line. Clients ignore it, proxies see traffic.
+var heartbeat = []byte(":\n\n")
+
+// SSEHeartbeatMiddleware writes a comment line on idle event streams every
+// Config().SSEHeartbeatInterval, independently of how often data is
+// published, so proxies with short idle timeouts keep the connection open.
+func SSEHeartbeatMiddleware(container container.Container) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			interval := container.Config().SSEHeartbeatInterval
+			if interval <= 0 || !isEventStream(c) {
+				return next(c)
+			}
+
+			w := &heartbeatWriter{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = w
+			defer w.close()
+
+			go func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-c.Request().Context().Done():
+						return
+					case <-ticker.C:
+						if !w.beat() {
+							return
+						}
+					}
+				}
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+func isEventStream(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "text/event-stream")
+}
+
+// heartbeatWriter serializes heartbeats with the handler's writes. A
+// heartbeat is only written between events, i.e. when everything written so
+// far has been flushed, so it never splits an event.
+type heartbeatWriter struct {
+	http.ResponseWriter
+	mu        sync.Mutex
+	streaming bool
+	pending   bool
+	closed    bool
+}
+
+func (w *heartbeatWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.streaming = code == http.StatusOK && strings.HasPrefix(w.Header().Get(echo.HeaderContentType), "text/event-stream")
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *heartbeatWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *heartbeatWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+	w.pending = false
+}
+
+func (w *heartbeatWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// beat writes a heartbeat if the stream is idle. It returns false once the
+// handler has returned.
+func (w *heartbeatWriter) beat() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return false
+	}
+	if !w.streaming || w.pending {
+		return true
+	}
+	if _, err := w.ResponseWriter.Write(heartbeat); err != nil {
+		return false
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+	return true
+}
+
+func (w *heartbeatWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+}
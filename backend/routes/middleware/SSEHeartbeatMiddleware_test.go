@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &heartbeatWriter{ResponseWriter: rec}
+
+	assert.True(t, w.beat())
+	assert.Empty(t, rec.Body.String(), "no heartbeat before the stream started")
+
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	w.beat()
+	assert.Equal(t, ":\n\n", rec.Body.String())
+
+	w.Write([]byte("id: 1\n"))
+	w.beat()
+	assert.Equal(t, ":\n\nid: 1\n", rec.Body.String(), "no heartbeat inside an event")
+
+	w.Write([]byte("data: {}\n\n"))
+	w.Flush()
+	w.beat()
+	assert.Equal(t, ":\n\nid: 1\ndata: {}\n\n:\n\n", rec.Body.String())
+
+	w.close()
+	assert.False(t, w.beat())
+}
+
+func TestSSEHeartbeatMiddleware(t *testing.T) {
+	cfg := config.NewAppConfig("test", ":0", 10, 10, false)
+	cfg.SSEHeartbeatInterval = 10 * time.Millisecond
+	heartbeats := SSEHeartbeatMiddleware(container.NewContainer(&config.Env{}, cfg))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+	req.Header.Set(echo.HeaderAccept, "text/event-stream")
+	rec := httptest.NewRecorder()
+	err := heartbeats(func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Flush()
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})(echo.New().NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, strings.Count(rec.Body.String(), ":\n\n"), 2)
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/kubewall/kubewall/backend/metrics"
+	"github.com/kubewall/kubewall/backend/streams"
+	"github.com/labstack/echo/v4"
+)
+
+// sseRetryAfter is the Retry-After hint, in seconds, sent with rejected streams.
+const sseRetryAfter = "5"
+
+// SSELimitMiddleware bounds the number of concurrent event streams to
+// Config().MaxSSEConnections. Streams over the limit are rejected with 503 so
+// that reconnect storms cannot exhaust goroutines or file descriptors.
+//
+// WebSocket streams count against the same limit. Accepted streams are
+// registered per config so they can be closed when the config is removed or
+// the server shuts down.
+func SSELimitMiddleware(container container.Container) echo.MiddlewareFunc {
+	var active atomic.Int64
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !isEventStream(c) && !websocket.IsWebSocketUpgrade(c.Request()) {
+				return next(c)
+			}
+
+			limit := int64(container.Config().MaxSSEConnections)
+			if n := active.Add(1); limit > 0 && n > limit {
+				active.Add(-1)
+				c.Response().Header().Set("Retry-After", sseRetryAfter)
+				return c.JSON(http.StatusServiceUnavailable, echo.Map{"message": "too many open streams, retry later"})
+			}
+			metrics.SSEConnections.Inc()
+			ctx, release := streams.Register(c.Request().Context(), c.QueryParam("config"))
+			c.SetRequest(c.Request().WithContext(ctx))
+			defer func() {
+				release()
+				active.Add(-1)
+				metrics.SSEConnections.Dec()
+			}()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubewall/kubewall/backend/config"
+	"github.com/kubewall/kubewall/backend/container"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSELimitMiddleware(t *testing.T) {
+	cfg := config.NewAppConfig("test", ":0", 10, 10, false)
+	cfg.MaxSSEConnections = 1
+	limit := SSELimitMiddleware(container.NewContainer(&config.Env{}, cfg))
+	e := echo.New()
+
+	stream := func(accept string, next echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+		req.Header.Set(echo.HeaderAccept, accept)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, limit(next)(e.NewContext(req, rec)))
+		return rec
+	}
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+	t.Run("rejects streams over the limit", func(t *testing.T) {
+		var inner *httptest.ResponseRecorder
+		outer := stream("text/event-stream", func(c echo.Context) error {
+			inner = stream("text/event-stream", ok)
+			return c.NoContent(http.StatusOK)
+		})
+
+		assert.Equal(t, http.StatusOK, outer.Code)
+		assert.Equal(t, http.StatusServiceUnavailable, inner.Code)
+		assert.Equal(t, sseRetryAfter, inner.Header().Get("Retry-After"))
+	})
+
+	t.Run("releases the slot when a stream ends", func(t *testing.T) {
+		rec := stream("text/event-stream", ok)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("counts websocket streams", func(t *testing.T) {
+		stream("text/event-stream", func(c echo.Context) error {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			rec := httptest.NewRecorder()
+			assert.NoError(t, limit(ok)(e.NewContext(req, rec)))
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			return nil
+		})
+	})
+
+	t.Run("ignores non-stream requests", func(t *testing.T) {
+		stream("text/event-stream", func(c echo.Context) error {
+			rec := stream("application/json", ok)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			return nil
+		})
+	})
+}
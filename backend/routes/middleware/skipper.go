@@ -12,5 +12,8 @@ func shouldSkip(c echo.Context) bool {
 		addons.ShouldSkipClusterMiddleware(c) ||
 		c.Path() == "" ||
 		c.Path() == "/" ||
-		c.Path() == "/healthz"
+		c.Path() == "/metrics" ||
+		c.Path() == "/api/v1/compare" ||
+		strings.HasPrefix(c.Path(), "/api/v1/charts") ||
+		strings.HasPrefix(c.Path(), "/api/v1/configs")
 }
@@ -14,7 +14,10 @@ import (
 	"github.com/kubewall/kubewall/backend/handlers/accesscontrol/serviceaccounts"
 	"github.com/kubewall/kubewall/backend/handlers/app"
 	"github.com/kubewall/kubewall/backend/handlers/apply"
+	"github.com/kubewall/kubewall/backend/handlers/artifacthub"
 	"github.com/kubewall/kubewall/backend/handlers/base"
+	"github.com/kubewall/kubewall/backend/handlers/clusterhealth"
+	"github.com/kubewall/kubewall/backend/handlers/compare"
 	configmaps "github.com/kubewall/kubewall/backend/handlers/config/configMaps"
 	horizontalpodautoscalers "github.com/kubewall/kubewall/backend/handlers/config/horizontalPodAutoscalers"
 	"github.com/kubewall/kubewall/backend/handlers/config/leases"
@@ -26,17 +29,33 @@ import (
 	"github.com/kubewall/kubewall/backend/handlers/config/secrets"
 	"github.com/kubewall/kubewall/backend/handlers/crds/crds"
 	"github.com/kubewall/kubewall/backend/handlers/crds/resources"
+	"github.com/kubewall/kubewall/backend/handlers/deprecations"
 	"github.com/kubewall/kubewall/backend/handlers/events"
+	"github.com/kubewall/kubewall/backend/handlers/explain"
+	"github.com/kubewall/kubewall/backend/handlers/finalizers"
+	"github.com/kubewall/kubewall/backend/handlers/helmreleases"
 	"github.com/kubewall/kubewall/backend/handlers/mcp"
+	"github.com/kubewall/kubewall/backend/handlers/metadata"
+	"github.com/kubewall/kubewall/backend/handlers/namespacedlist"
 	"github.com/kubewall/kubewall/backend/handlers/namespaces"
 	"github.com/kubewall/kubewall/backend/handlers/network/endpoints"
+	"github.com/kubewall/kubewall/backend/handlers/network/gatewayapi"
+	"github.com/kubewall/kubewall/backend/handlers/network/ingressclasses"
 	"github.com/kubewall/kubewall/backend/handlers/network/ingresses"
 	"github.com/kubewall/kubewall/backend/handlers/network/services"
 	"github.com/kubewall/kubewall/backend/handlers/nodes"
 	"github.com/kubewall/kubewall/backend/handlers/portforward"
+	"github.com/kubewall/kubewall/backend/handlers/query"
+	"github.com/kubewall/kubewall/backend/handlers/related"
+	"github.com/kubewall/kubewall/backend/handlers/storage/csidrivers"
+	"github.com/kubewall/kubewall/backend/handlers/storage/csinodes"
 	"github.com/kubewall/kubewall/backend/handlers/storage/persistentvolumeclaims"
 	"github.com/kubewall/kubewall/backend/handlers/storage/persistentvolumes"
 	"github.com/kubewall/kubewall/backend/handlers/storage/storageclasses"
+	"github.com/kubewall/kubewall/backend/handlers/tablewatch"
+	"github.com/kubewall/kubewall/backend/handlers/waitfor"
+	"github.com/kubewall/kubewall/backend/handlers/watchmulti"
+	"github.com/kubewall/kubewall/backend/handlers/whoami"
 	cronjobs "github.com/kubewall/kubewall/backend/handlers/workloads/cronJobs"
 	"github.com/kubewall/kubewall/backend/handlers/workloads/daemonsets"
 	"github.com/kubewall/kubewall/backend/handlers/workloads/deployments"
@@ -44,6 +63,8 @@ import (
 	"github.com/kubewall/kubewall/backend/handlers/workloads/pods"
 	"github.com/kubewall/kubewall/backend/handlers/workloads/replicaset"
 	statefulset "github.com/kubewall/kubewall/backend/handlers/workloads/statefulsets"
+	"github.com/kubewall/kubewall/backend/handlers/workloads/summary"
+	"github.com/kubewall/kubewall/backend/metrics"
 	appmiddleware "github.com/kubewall/kubewall/backend/routes/middleware"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -56,14 +77,20 @@ func ConfigureRoutes(e *echo.Echo, appContainer container.Container) {
 	e.HideBanner = true
 	setCORSConfig(e)
 
+	e.Pre(appmiddleware.ProbeMiddleware(e, appContainer))
 	e.Pre(middleware.RemoveTrailingSlash())
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
 		Format: "[${time_rfc3339}] ${status} ${method} ${uri} (${remote_ip}) ${error} ${latency_human}\n",
 		Output: e.Logger.Output(),
 	}))
+	e.Use(metrics.Middleware())
 	e.Use(middleware.Recover())
 	e.Use(middleware.RequestID())
+	e.Use(appmiddleware.BodyLimitMiddleware(appContainer))
 	addons.RegisterMiddleware(e, appContainer)
+	e.Use(appmiddleware.SSELimitMiddleware(appContainer))
+	e.Use(appmiddleware.SSEHeartbeatMiddleware(appContainer))
+	e.Use(appmiddleware.OperationMiddleware())
 	e.Use(appmiddleware.ClusterQueryParamMiddleware(appContainer))
 	e.Use(appmiddleware.ClusterConnectivityMiddleware(appContainer))
 	e.Use(appmiddleware.ClusterCacheMiddleware(appContainer))
@@ -76,11 +103,39 @@ func ConfigureRoutes(e *echo.Echo, appContainer container.Container) {
 		Root:       "static",
 		Filesystem: http.FS(embeddedFiles),
 	}))
-	e.GET("/healthz", func(c echo.Context) error {
-		return c.String(http.StatusOK, "OK")
-	})
+	e.GET("/metrics", metrics.Handler())
 
 	e.POST("api/v1/app/apply", apply.NewApplyHandler(appContainer, apply.POSTApply))
+	e.POST("api/v1/app/apply/url", apply.NewApplyHandler(appContainer, apply.POSTApplyFromURL))
+	e.POST("api/v1/app/apply/bundle", apply.NewApplyHandler(appContainer, apply.POSTApplyBundle))
+	e.POST("api/v1/app/apply/bundle/stream", apply.NewApplyHandler(appContainer, apply.POSTApplyBundleStream))
+	e.POST("api/v1/app/validate", apply.NewApplyHandler(appContainer, apply.POSTValidate))
+
+	e.GET("api/v1/related/:kind/:name", related.NewRelatedHandler(appContainer).GetRelatedResources).Name = "relatedResources"
+	e.GET("api/v1/workloads/summary", summary.NewSummaryHandler(appContainer).GetWorkloadsSummary).Name = "workloadsSummary"
+	e.POST("api/v1/compare", compare.NewCompareHandler(appContainer).CompareResources).Name = "compareResources"
+	e.GET("api/v1/wait/:resource/:name", waitfor.NewWaitHandler(appContainer).WaitForCondition).Name = "waitForCondition"
+	e.GET("api/v1/query/:resource/:name", query.NewQueryHandler(appContainer).QueryResource).Name = "queryResource"
+	e.GET("api/v1/cluster/health", clusterhealth.NewHealthHandler(appContainer).GetClusterHealth).Name = "clusterHealth"
+	e.GET("api/v1/whoami", whoami.NewWhoAmIHandler(appContainer).WhoAmI).Name = "whoAmI"
+	e.GET("api/v1/deprecations", deprecations.NewDeprecationsHandler(appContainer).GetDeprecationWarnings).Name = "deprecationWarnings"
+	charts := artifacthub.NewArtifactHubHandler(appContainer)
+	e.GET("api/v1/charts/search", charts.SearchCharts).Name = "chartsSearch"
+	e.GET("api/v1/charts/values-schema", charts.GetChartValuesSchema).Name = "chartsValuesSchema"
+	e.GET("api/v1/explain/:resource", explain.NewExplainHandler(appContainer).ExplainResource).Name = "explainResource"
+	helmReleases := helmreleases.NewHelmReleasesHandler(appContainer)
+	e.GET("api/v1/helm/releases", helmReleases.ListReleases).Name = "helmReleases"
+	e.GET("api/v1/helm/releases/:name/diff", helmReleases.DiffReleaseRevisions).Name = "helmReleaseDiff"
+	e.POST("api/v1/helm/template", helmReleases.TemplateChart).Name = "helmTemplate"
+	e.POST("api/v1/helm/releases/:name/upgrade/diff", helmReleases.PostUpgradeDiff).Name = "helmReleaseUpgradeDiff"
+	e.DELETE("api/v1/finalizers/:resource/:name", finalizers.NewFinalizersHandler(appContainer).RemoveFinalizer).Name = "removeFinalizer"
+	metadataHandler := metadata.NewMetadataHandler(appContainer)
+	e.PATCH("api/v1/metadata/:resource/:name/labels", metadataHandler.SetLabels).Name = "setLabels"
+	e.PATCH("api/v1/metadata/:resource/:name/annotations", metadataHandler.SetAnnotations).Name = "setAnnotations"
+	e.GET("api/v1/metadata/:resource/:name/ownership", metadataHandler.GetFieldOwnership).Name = "fieldOwnership"
+	e.GET("api/v1/namespaced/:resource", namespacedlist.NewNamespacedListHandler(appContainer).GetList).Name = "namespacedList"
+	e.GET("api/v1/watch", watchmulti.NewWatchMultiHandler(appContainer).WatchMulti).Name = "watchMulti"
+	e.GET("api/v1/table/:resource", tablewatch.NewTableWatchHandler(appContainer).WatchResourceTable).Name = "resourceTableWatch"
 
 	appConfig := app.NewAppConfigHandler(appContainer)
 	e.GET("api/v1/app/config", appConfig.Get)
@@ -88,24 +143,37 @@ func ConfigureRoutes(e *echo.Echo, appContainer container.Container) {
 	e.POST("api/v1/app/config/kubeconfigs-bearer", appConfig.PostBearer)
 	e.POST("api/v1/app/config/kubeconfigs-certificate", appConfig.PostCertificate)
 	e.GET("api/v1/app/config/reload", appConfig.Reload)
+	e.POST("api/v1/configs/reload", appConfig.PostReload)
+	e.GET("api/v1/configs/contexts", appConfig.GetAllContexts)
+	e.PUT("api/v1/configs/:id/name", appConfig.RenameConfig)
 
 	e.DELETE("api/v1/app/config/kubeconfigs/:configId", appConfig.Delete)
+	e.GET("api/v1/app/operations", appConfig.GetOperations)
+	e.DELETE("api/v1/app/operations/:id", appConfig.CancelOperation)
 
 	// Namespaces
 	e.GET("api/v1/namespaces", namespaces.NewNamespacesRouteHandler(appContainer, base.GetList)).Name = "namespacesList"
 	e.GET("api/v1/namespaces/:name", namespaces.NewNamespacesRouteHandler(appContainer, base.GetDetails)).Name = "namespacesDetails"
 	e.GET("api/v1/namespaces/:name/yaml", namespaces.NewNamespacesRouteHandler(appContainer, base.GetYaml)).Name = "namespacesYaml"
 	e.GET("api/v1/namespaces/:name/events", namespaces.NewNamespacesRouteHandler(appContainer, base.GetEvents)).Name = "namespacesEvents"
+	e.GET("api/v1/namespaces/:name/events/stream", events.NewEventsRouteHandler(appContainer, events.GetNamespaceEventsStream)).Name = "namespacesEventsStream"
+	e.GET("api/v1/namespaces/:name/contents", namespaces.NewNamespacesRouteHandler(appContainer, namespaces.GetNamespaceContents)).Name = "namespacesContents"
+	e.GET("api/v1/namespaces/:name/restarts", pods.NewPodsRouteHandler(appContainer, pods.GetNamespaceRestarts)).Name = "namespacesRestarts"
 	e.DELETE("api/v1/namespaces", namespaces.NewNamespacesRouteHandler(appContainer, base.Delete)).Name = "namespacesDelete"
 
 	// Nodes
 	e.GET("api/v1/nodes", nodes.NewNodeRouteHandler(appContainer, base.GetList)).Name = "nodesList"
+	e.GET("api/v1/nodes/top", nodes.NewNodeRouteHandler(appContainer, nodes.GetTopNodes)).Name = "nodesTop"
 	e.GET("api/v1/nodes/:name", nodes.NewNodeRouteHandler(appContainer, base.GetDetails)).Name = "nodesDetails"
 	e.GET("api/v1/nodes/:name/yaml", nodes.NewNodeRouteHandler(appContainer, base.GetYaml)).Name = "nodesYaml"
 	e.GET("api/v1/nodes/:name/events", nodes.NewNodeRouteHandler(appContainer, base.GetEvents)).Name = "nodesEvents"
 	e.GET("api/v1/nodes/:name/pods", nodes.NewNodeRouteHandler(appContainer, deployments.GetPods)).Name = "nodePods"
+	e.POST("api/v1/nodes/:name/debug", nodes.NewNodeRouteHandler(appContainer, nodes.DebugNode)).Name = "nodeDebug"
+	e.GET("api/v1/nodes/:name/debug/:pod", nodes.NewNodeRouteHandler(appContainer, nodes.WatchDebugNode)).Name = "nodeDebugWatch"
 
 	e.GET("api/v1/events", events.NewEventsRouteHandler(appContainer, base.GetList)).Name = "eventsList"
+	e.GET("api/v1/events/warnings", events.NewEventsRouteHandler(appContainer, events.GetClusterWarnings)).Name = "eventsWarnings"
+	e.GET("api/v1/events/pressure", events.NewEventsRouteHandler(appContainer, events.GetResourcePressure)).Name = "eventsPressure"
 	e.DELETE("api/v1/events", events.NewEventsRouteHandler(appContainer, base.Delete)).Name = "eventsDelete"
 
 	e.GET("api/v1/portforwards", portforward.NewPortForwardingHandler(appContainer, base.GetList))
@@ -127,6 +195,7 @@ func customResources(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/customresourcedefinitions/:name", crds.NewCRDRouteHandler(appContainer, base.GetDetails))
 	e.GET("api/v1/customresourcedefinitions/:name/yaml", crds.NewCRDRouteHandler(appContainer, base.GetYaml))
 	e.GET("api/v1/customresourcedefinitions/:name/events", crds.NewCRDRouteHandler(appContainer, base.GetEvents))
+	e.GET("api/v1/customresourcedefinitions/:name/schema", crds.NewCRDRouteHandler(appContainer, crds.GetSchema))
 	e.DELETE("api/v1/customresourcedefinitions", crds.NewCRDRouteHandler(appContainer, base.Delete))
 
 	e.GET("api/v1/customresources", resources.NewUnstructuredRouteHandler(appContainer, base.GetList))
@@ -147,6 +216,7 @@ func servicesRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/services/:name", services.NewServicesRouteHandler(appContainer, base.GetDetails)).Name = "servicesDetails"
 	e.GET("api/v1/services/:name/yaml", services.NewServicesRouteHandler(appContainer, base.GetYaml)).Name = "servicesYaml"
 	e.GET("api/v1/services/:name/events", services.NewServicesRouteHandler(appContainer, base.GetEvents)).Name = "servicesEvents"
+	e.GET("api/v1/services/:name/topology", services.NewServicesRouteHandler(appContainer, services.GetServiceTopology)).Name = "servicesTopology"
 	e.DELETE("api/v1/services", services.NewServicesRouteHandler(appContainer, base.Delete)).Name = "servicesDelete"
 
 	// Endpoints
@@ -162,6 +232,18 @@ func servicesRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/ingresses/:name/yaml", ingresses.NewIngressRouteHandler(appContainer, base.GetYaml)).Name = "ingressesYaml"
 	e.GET("api/v1/ingresses/:name/events", ingresses.NewIngressRouteHandler(appContainer, base.GetEvents)).Name = "ingressesEvents"
 	e.DELETE("api/v1/ingresses", ingresses.NewIngressRouteHandler(appContainer, base.Delete)).Name = "ingressesDelete"
+
+	// IngressClasses
+	e.GET("api/v1/ingressclasses", ingressclasses.NewIngressClassRouteHandler(appContainer, base.GetList)).Name = "ingressclassesList"
+	e.GET("api/v1/ingressclasses/:name", ingressclasses.NewIngressClassRouteHandler(appContainer, base.GetDetails)).Name = "ingressclassesDetails"
+	e.GET("api/v1/ingressclasses/:name/yaml", ingressclasses.NewIngressClassRouteHandler(appContainer, base.GetYaml)).Name = "ingressclassesYaml"
+	e.GET("api/v1/ingressclasses/:name/events", ingressclasses.NewIngressClassRouteHandler(appContainer, base.GetEvents)).Name = "ingressclassesEvents"
+	e.DELETE("api/v1/ingressclasses", ingressclasses.NewIngressClassRouteHandler(appContainer, base.Delete)).Name = "ingressclassesDelete"
+
+	// Gateway API
+	gatewayAPI := gatewayapi.NewGatewayAPIHandler(appContainer)
+	e.GET("api/v1/gatewayapi/gateways", gatewayAPI.GetGateways).Name = "gatewayapiGateways"
+	e.GET("api/v1/gatewayapi/httproutes", gatewayAPI.GetHTTPRoutes).Name = "gatewayapiHTTPRoutes"
 }
 
 func storageRoutes(e *echo.Echo, appContainer container.Container) {
@@ -178,6 +260,7 @@ func storageRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/persistentvolumeclaims/:name/yaml", persistentvolumeclaims.NewPersistentVolumeClaimsRouteHandler(appContainer, base.GetYaml)).Name = "persistentvolumeclaimsYaml"
 	e.GET("api/v1/persistentvolumeclaims/:name/events", persistentvolumeclaims.NewPersistentVolumeClaimsRouteHandler(appContainer, base.GetEvents)).Name = "persistentvolumeclaimsEvents"
 	e.DELETE("api/v1/persistentvolumeclaims", persistentvolumeclaims.NewPersistentVolumeClaimsRouteHandler(appContainer, base.Delete)).Name = "persistentvolumeclaimsDelete"
+	e.POST("api/v1/persistentvolumeclaims/:name/resize", persistentvolumeclaims.NewPersistentVolumeClaimsRouteHandler(appContainer, persistentvolumeclaims.ResizePVC)).Name = "persistentvolumeclaimsResize"
 
 	// StorageClasses
 	e.GET("api/v1/storageclasses", storageclasses.NewStorageClassRouteHandler(appContainer, base.GetList)).Name = "storageclassesList"
@@ -185,6 +268,19 @@ func storageRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/storageclasses/:name/yaml", storageclasses.NewStorageClassRouteHandler(appContainer, base.GetYaml)).Name = "storageclassesYaml"
 	e.GET("api/v1/storageclasses/:name/events", storageclasses.NewStorageClassRouteHandler(appContainer, base.GetEvents)).Name = "storageclassesEvents"
 	e.DELETE("api/v1/storageclasses", storageclasses.NewStorageClassRouteHandler(appContainer, base.Delete)).Name = "storageclassesDelete"
+
+	// CSIDrivers
+	e.GET("api/v1/csidrivers", csidrivers.NewCSIDriverRouteHandler(appContainer, base.GetList)).Name = "csidriversList"
+	e.GET("api/v1/csidrivers/:name", csidrivers.NewCSIDriverRouteHandler(appContainer, base.GetDetails)).Name = "csidriversDetails"
+	e.GET("api/v1/csidrivers/:name/yaml", csidrivers.NewCSIDriverRouteHandler(appContainer, base.GetYaml)).Name = "csidriversYaml"
+	e.GET("api/v1/csidrivers/:name/events", csidrivers.NewCSIDriverRouteHandler(appContainer, base.GetEvents)).Name = "csidriversEvents"
+	e.DELETE("api/v1/csidrivers", csidrivers.NewCSIDriverRouteHandler(appContainer, base.Delete)).Name = "csidriversDelete"
+
+	// CSINodes
+	e.GET("api/v1/csinodes", csinodes.NewCSINodeRouteHandler(appContainer, base.GetList)).Name = "csinodesList"
+	e.GET("api/v1/csinodes/:name", csinodes.NewCSINodeRouteHandler(appContainer, base.GetDetails)).Name = "csinodesDetails"
+	e.GET("api/v1/csinodes/:name/yaml", csinodes.NewCSINodeRouteHandler(appContainer, base.GetYaml)).Name = "csinodesYaml"
+	e.GET("api/v1/csinodes/:name/events", csinodes.NewCSINodeRouteHandler(appContainer, base.GetEvents)).Name = "csinodesEvents"
 }
 
 func configRoutes(e *echo.Echo, appContainer container.Container) {
@@ -194,6 +290,8 @@ func configRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/configmaps/:name/yaml", configmaps.NewConfigMapsRouteHandler(appContainer, base.GetYaml)).Name = "configmapsYaml"
 	e.GET("api/v1/configmaps/:name/events", configmaps.NewConfigMapsRouteHandler(appContainer, base.GetEvents)).Name = "configmapsEvents"
 	e.DELETE("api/v1/configmaps", configmaps.NewConfigMapsRouteHandler(appContainer, base.Delete)).Name = "configmapsDelete"
+	e.GET("api/v1/configmaps/:name/consumers", configmaps.NewConfigMapsRouteHandler(appContainer, configmaps.GetConfigMapConsumers)).Name = "configmapsConsumers"
+	e.GET("api/v1/configmaps/:name/keys/:key", configmaps.NewConfigMapsRouteHandler(appContainer, configmaps.GetConfigMapKey)).Name = "configmapsKey"
 
 	// Secrets
 	e.GET("api/v1/secrets", secrets.NewSecretsRouteHandler(appContainer, base.GetList)).Name = "secretsList"
@@ -201,6 +299,8 @@ func configRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/secrets/:name/yaml", secrets.NewSecretsRouteHandler(appContainer, base.GetYaml)).Name = "secretsYaml"
 	e.GET("api/v1/secrets/:name/events", secrets.NewSecretsRouteHandler(appContainer, base.GetEvents)).Name = "secretsEvents"
 	e.DELETE("api/v1/secrets", secrets.NewSecretsRouteHandler(appContainer, base.Delete)).Name = "secretsDelete"
+	e.GET("api/v1/secrets/:name/consumers", secrets.NewSecretsRouteHandler(appContainer, secrets.GetSecretConsumers)).Name = "secretsConsumers"
+	e.GET("api/v1/secrets/:name/keys/:key", secrets.NewSecretsRouteHandler(appContainer, secrets.GetSecretKey)).Name = "secretsKey"
 
 	// ResourceQuotas
 	e.GET("api/v1/resourcequotas", resourcequotas.NewResourceQuotaRouteHandler(appContainer, base.GetList)).Name = "resourcequotasList"
@@ -215,13 +315,17 @@ func configRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/limitranges/:name/yaml", limitranges.NewLimitRangesRouteHandler(appContainer, base.GetYaml)).Name = "limitrangesYaml"
 	e.GET("api/v1/limitranges/:name/events", limitranges.NewLimitRangesRouteHandler(appContainer, base.GetEvents)).Name = "limitrangesEvents"
 	e.DELETE("api/v1/limitranges", limitranges.NewLimitRangesRouteHandler(appContainer, base.Delete)).Name = "limitrangesDelete"
+	e.PUT("api/v1/limitranges/:name", limitranges.NewLimitRangesRouteHandler(appContainer, limitranges.SaveLimitRange)).Name = "limitrangesSave"
+	e.GET("api/v1/namespaces/:name/limitdefaults", limitranges.NewLimitRangesRouteHandler(appContainer, limitranges.GetNamespaceDefaults)).Name = "namespacesLimitDefaults"
 
 	// HorizontalPodAutoscalers (HPA)
 	e.GET("api/v1/horizontalpodautoscalers", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, base.GetList)).Name = "horizontalpodautoscalersList"
 	e.GET("api/v1/horizontalpodautoscalers/:name", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, base.GetDetails)).Name = "horizontalpodautoscalersDetails"
 	e.GET("api/v1/horizontalpodautoscalers/:name/yaml", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, base.GetYaml)).Name = "horizontalpodautoscalersYaml"
 	e.GET("api/v1/horizontalpodautoscalers/:name/events", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, base.GetEvents)).Name = "horizontalpodautoscalersEvents"
+	e.GET("api/v1/horizontalpodautoscalers/:name/status", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, horizontalpodautoscalers.GetHPAStatus)).Name = "horizontalpodautoscalersStatus"
 	e.DELETE("api/v1/horizontalpodautoscalers", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, base.Delete)).Name = "horizontalpodautoscalersDelete"
+	e.POST("api/v1/horizontalpodautoscalers/:name/bounds", horizontalpodautoscalers.NewHorizontalPodAutoscalersRouteHandler(appContainer, horizontalpodautoscalers.SetHPABounds)).Name = "horizontalpodautoscalersBounds"
 
 	// PodDisruptionBudgets (PDB)
 	e.GET("api/v1/poddisruptionbudgets", poddisruptionbudgets.NewPodDisruptionBudgetRouteHandler(appContainer, base.GetList)).Name = "poddisruptionbudgetsList"
@@ -255,12 +359,27 @@ func configRoutes(e *echo.Echo, appContainer container.Container) {
 func workloadRoutes(e *echo.Echo, appContainer container.Container) {
 	// Pods
 	e.GET("api/v1/pods", pods.NewPodsRouteHandler(appContainer, base.GetList)).Name = "podsList"
+	e.GET("api/v1/pods/top", pods.NewPodsRouteHandler(appContainer, pods.GetTopPods)).Name = "podsTop"
+	e.GET("api/v1/pods/stuck", pods.NewPodsRouteHandler(appContainer, pods.GetStuckPods)).Name = "podsStuck"
 	e.GET("api/v1/pods/:name", pods.NewPodsRouteHandler(appContainer, base.GetDetails)).Name = "podsDetails"
 	e.GET("api/v1/pods/:name/yaml", pods.NewPodsRouteHandler(appContainer, base.GetYaml)).Name = "podsYaml"
 	e.GET("api/v1/pods/:name/logs", pods.NewPodsRouteHandler(appContainer, base.GetLogs)).Name = "podsLogs"
 	e.GET("api/v1/pods/:name/logs/history", pods.NewPodsRouteHandler(appContainer, pods.GetLogHistory)).Name = "podsLogsHistory"
+	e.GET("api/v1/pods/:name/logs/range", pods.NewPodsRouteHandler(appContainer, pods.GetLogRange)).Name = "podsLogsRange"
+	e.GET("api/v1/pods/:name/logs/crash", pods.NewPodsRouteHandler(appContainer, pods.GetPodCrashLogs)).Name = "podsCrashLogs"
+	e.GET("api/v1/pods/:name/env", pods.NewPodsRouteHandler(appContainer, pods.GetPodEnv)).Name = "podsEnv"
+	e.GET("api/v1/pods/:name/scheduling", pods.NewPodsRouteHandler(appContainer, pods.GetPodScheduling)).Name = "podsScheduling"
+	e.GET("api/v1/pods/:name/placement", pods.NewPodsRouteHandler(appContainer, pods.GetPodPlacement)).Name = "podsPlacement"
+	e.GET("api/v1/pods/:name/probes", pods.NewPodsRouteHandler(appContainer, pods.GetPodProbes)).Name = "podsProbes"
+	e.GET("api/v1/pods/:name/disk", pods.NewPodsRouteHandler(appContainer, pods.GetPodDiskUsage)).Name = "podsDiskUsage"
+	e.GET("api/v1/pods/:name/imagepullsecrets", pods.NewPodsRouteHandler(appContainer, pods.GetPodImagePullSecrets)).Name = "podsImagePullSecrets"
+	e.GET("api/v1/pods/:name/timeline", pods.NewPodsRouteHandler(appContainer, pods.GetPodTimeline)).Name = "podsTimeline"
 	e.GET("api/v1/pods/:name/events", pods.NewPodsRouteHandler(appContainer, base.GetEvents)).Name = "podsEvents"
+	e.GET("api/v1/pods/:name/containers/history", pods.NewPodsRouteHandler(appContainer, pods.GetPodContainerHistory)).Name = "podsContainerHistory"
+	e.GET("api/v1/pods/owner/:kind/:name", pods.NewPodsRouteHandler(appContainer, pods.GetOwnerPods)).Name = "podsByOwner"
 	e.DELETE("api/v1/pods", pods.NewPodsRouteHandler(appContainer, base.Delete)).Name = "podsDelete"
+	e.DELETE("api/v1/pods/:name/force", pods.NewPodsRouteHandler(appContainer, pods.ForceDeletePod)).Name = "podsForceDelete"
+	e.POST("api/v1/pods/restart", pods.NewPodsRouteHandler(appContainer, pods.RestartPodsBySelector)).Name = "podsRestart"
 
 	// Deployments
 	e.GET("api/v1/deployments", deployments.NewDeploymentRouteHandler(appContainer, base.GetList)).Name = "deploymentsList"
@@ -270,6 +389,9 @@ func workloadRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/deployments/:name/pods", deployments.NewDeploymentRouteHandler(appContainer, deployments.GetPods)).Name = "deploymentsPods"
 	e.DELETE("api/v1/deployments", deployments.NewDeploymentRouteHandler(appContainer, base.Delete)).Name = "deploymentsDelete"
 	e.POST("api/v1/deployments/:name/scale", deployments.NewDeploymentRouteHandler(appContainer, deployments.UpdateScale)).Name = "deploymentsScale"
+	e.POST("api/v1/deployments/:name/pause", deployments.NewDeploymentRouteHandler(appContainer, deployments.PauseDeployment)).Name = "deploymentsPause"
+	e.POST("api/v1/deployments/:name/resume", deployments.NewDeploymentRouteHandler(appContainer, deployments.ResumeDeployment)).Name = "deploymentsResume"
+	e.POST("api/v1/deployments/:name/image", deployments.NewDeploymentRouteHandler(appContainer, base.SetImage)).Name = "deploymentsImage"
 
 	// DaemonSets
 	e.GET("api/v1/daemonsets", daemonsets.NewDaemonSetsRouteHandler(appContainer, base.GetList)).Name = "daemonsetsList"
@@ -277,12 +399,14 @@ func workloadRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/daemonsets/:name/yaml", daemonsets.NewDaemonSetsRouteHandler(appContainer, base.GetYaml)).Name = "daemonsetsYaml"
 	e.GET("api/v1/daemonsets/:name/events", daemonsets.NewDaemonSetsRouteHandler(appContainer, base.GetEvents)).Name = "daemonsetsEvents"
 	e.DELETE("api/v1/daemonsets", daemonsets.NewDaemonSetsRouteHandler(appContainer, base.Delete)).Name = "daemonsetsDelete"
+	e.POST("api/v1/daemonsets/:name/image", daemonsets.NewDaemonSetsRouteHandler(appContainer, base.SetImage)).Name = "daemonsetsImage"
 
 	// ReplicaSets
 	e.GET("api/v1/replicasets", replicaset.NewReplicaSetRouteHandler(appContainer, base.GetList)).Name = "replicasetsList"
 	e.GET("api/v1/replicasets/:name", replicaset.NewReplicaSetRouteHandler(appContainer, base.GetDetails)).Name = "replicasetsDetails"
 	e.GET("api/v1/replicasets/:name/yaml", replicaset.NewReplicaSetRouteHandler(appContainer, base.GetYaml)).Name = "replicasetsYaml"
 	e.GET("api/v1/replicasets/:name/events", replicaset.NewReplicaSetRouteHandler(appContainer, base.GetEvents)).Name = "replicasetsEvents"
+	e.GET("api/v1/replicasets/:name/pods", pods.NewPodsRouteHandler(appContainer, pods.GetReplicaSetPods)).Name = "replicasetsPods"
 	e.DELETE("api/v1/replicasets", replicaset.NewReplicaSetRouteHandler(appContainer, base.Delete)).Name = "replicasetsDelete"
 
 	// StatefulSets
@@ -291,12 +415,14 @@ func workloadRoutes(e *echo.Echo, appContainer container.Container) {
 	e.GET("api/v1/statefulsets/:name/yaml", statefulset.NewStatefulSetRouteHandler(appContainer, base.GetYaml)).Name = "statefulsetsYaml"
 	e.GET("api/v1/statefulsets/:name/events", statefulset.NewStatefulSetRouteHandler(appContainer, base.GetEvents)).Name = "statefulsetsEvents"
 	e.DELETE("api/v1/statefulsets", statefulset.NewStatefulSetRouteHandler(appContainer, base.Delete)).Name = "statefulsetsDelete"
+	e.POST("api/v1/statefulsets/:name/image", statefulset.NewStatefulSetRouteHandler(appContainer, base.SetImage)).Name = "statefulsetsImage"
 
 	// Jobs
 	e.GET("api/v1/jobs", jobs.NewJobsRouteHandler(appContainer, base.GetList)).Name = "jobsList"
 	e.GET("api/v1/jobs/:name", jobs.NewJobsRouteHandler(appContainer, base.GetDetails)).Name = "jobsDetails"
 	e.GET("api/v1/jobs/:name/yaml", jobs.NewJobsRouteHandler(appContainer, base.GetYaml)).Name = "jobsYaml"
 	e.GET("api/v1/jobs/:name/events", jobs.NewJobsRouteHandler(appContainer, base.GetEvents)).Name = "jobsEvents"
+	e.GET("api/v1/jobs/:name/run", pods.NewPodsRouteHandler(appContainer, pods.StreamJobRun)).Name = "jobsRun"
 	e.DELETE("api/v1/jobs", jobs.NewJobsRouteHandler(appContainer, base.Delete)).Name = "jobsDelete"
 
 	// CronJobs
@@ -362,6 +488,7 @@ func setCORSConfig(e *echo.Echo) {
 			echo.HeaderOrigin,
 			echo.HeaderCacheControl,
 			echo.HeaderXRequestedWith,
+			appmiddleware.HeaderOperationID,
 		},
 		AllowMethods: []string{
 			http.MethodGet,
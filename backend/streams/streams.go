@@ -0,0 +1,85 @@
+// Package streams tracks open event streams per kubeconfig so that config
+// management can tell whether a config is in use and close its streams.
+package streams
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	mu       sync.Mutex
+	nextID   uint64
+	open     = make(map[string]map[uint64]context.CancelFunc)
+	shutdown bool
+)
+
+// Register records a stream for config. The returned context is cancelled by
+// CancelAll or Shutdown; release must be called once the stream ends.
+func Register(ctx context.Context, config string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	mu.Lock()
+	if shutdown {
+		mu.Unlock()
+		cancel()
+		return ctx, cancel
+	}
+	nextID++
+	id := nextID
+	if open[config] == nil {
+		open[config] = make(map[uint64]context.CancelFunc)
+	}
+	open[config][id] = cancel
+	mu.Unlock()
+
+	return ctx, func() {
+		mu.Lock()
+		delete(open[config], id)
+		if len(open[config]) == 0 {
+			delete(open, config)
+		}
+		mu.Unlock()
+		cancel()
+	}
+}
+
+// Active returns the number of open streams for config.
+func Active(config string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(open[config])
+}
+
+// CancelAll closes every open stream for config and returns how many there were.
+func CancelAll(config string) int {
+	mu.Lock()
+	cancels := open[config]
+	delete(open, config)
+	mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return len(cancels)
+}
+
+// Shutdown closes every open stream and returns how many there were. Streams
+// registered after are closed right away, so a server shutting down does not
+// wait for clients that would otherwise never disconnect.
+func Shutdown() int {
+	mu.Lock()
+	shutdown = true
+	all := open
+	open = make(map[string]map[uint64]context.CancelFunc)
+	mu.Unlock()
+
+	n := 0
+	for _, cancels := range all {
+		for _, cancel := range cancels {
+			cancel()
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,49 @@
+package streams
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreams(t *testing.T) {
+	ctx1, release1 := Register(context.Background(), "prod")
+	ctx2, _ := Register(context.Background(), "prod")
+	ctx3, release3 := Register(context.Background(), "dev")
+	defer release3()
+
+	assert.Equal(t, 2, Active("prod"))
+	release1()
+	assert.Error(t, ctx1.Err())
+	assert.Equal(t, 1, Active("prod"))
+
+	assert.Equal(t, 1, CancelAll("prod"))
+	assert.Error(t, ctx2.Err())
+	assert.Equal(t, 0, Active("prod"))
+
+	assert.NoError(t, ctx3.Err())
+	assert.Equal(t, 1, Active("dev"))
+}
+
+func TestShutdown(t *testing.T) {
+	defer func() {
+		mu.Lock()
+		shutdown = false
+		mu.Unlock()
+	}()
+	ctx1, release1 := Register(context.Background(), "prod")
+	defer release1()
+	ctx2, release2 := Register(context.Background(), "dev")
+	defer release2()
+
+	assert.Equal(t, 2, Shutdown())
+	assert.Error(t, ctx1.Err())
+	assert.Error(t, ctx2.Err())
+	assert.Equal(t, 0, Active("prod"))
+
+	ctx3, release3 := Register(context.Background(), "prod")
+	assert.Error(t, ctx3.Err())
+	assert.Equal(t, 0, Active("prod"))
+	release3()
+}
@@ -16,6 +16,8 @@ type InitialState = {
 type UpdateYamlParams = {
   data: string;
   queryParams: string;
+  conflictStrategy?: 'fail' | 'force' | 'merge';
+  strict?: boolean;
 };
 
 const initialState: InitialState = {
@@ -26,10 +28,16 @@ const initialState: InitialState = {
   error: null,
 };
 
-const updateYaml = createAsyncThunk('yaml/updateYaml', ({ data, queryParams }: UpdateYamlParams, thunkAPI) => {
+const updateYaml = createAsyncThunk('yaml/updateYaml', ({ data, queryParams, conflictStrategy, strict }: UpdateYamlParams, thunkAPI) => {
   const url = `${API_VERSION}/app/apply?${queryParams}`;
   const formdata = new FormData();
   formdata.append('yaml', data);
+  if (conflictStrategy) {
+    formdata.append('conflictStrategy', conflictStrategy);
+  }
+  if (strict) {
+    formdata.append('strict', 'true');
+  }
   
   return kwFetch(url, {
     body: formdata,
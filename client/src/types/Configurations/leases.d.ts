@@ -9,6 +9,8 @@ type LeasesListHeader = {
 
 type LeasesReponse = {
   hasUpdated: boolean;
+  renewTime: string | null;
+  stale: boolean;
 } & LeasesListHeader
 
 export {
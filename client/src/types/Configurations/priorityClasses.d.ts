@@ -8,6 +8,7 @@ type PriorityClassesHeaders = {
 
 type PriorityClassesReponse = {
   hasUpdated: boolean;
+  description: string;
 } & PriorityClassesHeaders;
 
 type PriorityClassDetailsMetadata = {
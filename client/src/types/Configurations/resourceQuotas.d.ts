@@ -6,6 +6,8 @@ type ResourceQuotasHeaders = {
 
 type ResourceQuotasResponse = {
   hasUpdated: boolean;
+  request: string;
+  limit: string;
 } & ResourceQuotasHeaders;
 
 type ResourceQuotaDetailsMetadata = {
@@ -8,6 +8,9 @@ type StorageClassesHeaders = {
 
 type StorageClassesResponse = {
   hasUpdated: boolean;
+  isDefault: boolean;
+  allowVolumeExpansion: boolean;
+  parameters: Record<string, string>;
 } & StorageClassesHeaders;
 
 type StorageClassDetailsMetadata = {
@@ -3,7 +3,8 @@ type DeploymentsResponse = {
   name: string;
   replicas: string;
   spec: {
-    replicas: number
+    replicas: number;
+    paused: boolean;
   },
   status: {
     observedGeneration: number;
@@ -167,9 +167,19 @@ type PodSocketResponse = {
   containerName: string;
   timestamp: string;
   log: string;
+  streamId?: number;
+  seq?: number;
   containerChange?: boolean;
 };
 
+// Sent once as the "meta" event of the logs stream, before any line.
+type PodLogStreamMeta = {
+  streams: {
+    streamId: number;
+    containerName: string;
+  }[];
+};
+
 export {
   ContainerCardProps,
   PodDetails,
@@ -177,6 +187,7 @@ export {
   PodDetailsMetadata,
   PodDetailsSpec,
   PodDetailsStatus,
+  PodLogStreamMeta,
   Pods,
   PodsHeaders,
   PodSocketResponse,